@@ -427,6 +427,17 @@ const components = {
         conformanceSetup: 'docker-compose.sh vernemq',
         sourcePkg: ['pubsub/mqtt3'],
     },
+    'pubsub.postgresql.docker': {
+        conformance: true,
+        conformanceSetup: 'docker-compose.sh postgresql',
+        sourcePkg: [
+            'pubsub/postgresql',
+            'common/authentication/postgresql',
+            'common/component/postgresql/interfaces',
+            'common/component/sql',
+            'common/component/sql/migrations',
+        ],
+    },
     'pubsub.pulsar': {
         conformance: true,
         certification: true,
@@ -452,6 +463,15 @@ const components = {
         conformanceSetup: 'docker-compose.sh solace',
         conformanceLogs: 'docker-compose-logs.sh solace',
     },
+    'pubsub.sqlite': {
+        conformance: true,
+        sourcePkg: [
+            'pubsub/sqlite',
+            'common/authentication/sqlite',
+            'common/component/sql',
+            'common/component/sql/migrations',
+        ],
+    },
     'secretstores.azure.keyvault': {
         certification: true,
         requiredSecrets: [
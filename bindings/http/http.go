@@ -28,6 +28,8 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dapr/components-contrib/bindings"
@@ -59,6 +61,12 @@ type HTTPSource struct {
 	client        *http.Client
 	errorIfNot2XX bool
 	logger        logger.Logger
+
+	// Address the webhook server listens on, once it's started
+	webhookAddr atomic.Pointer[net.Addr]
+	closed      atomic.Bool
+	closeCh     chan struct{}
+	wg          sync.WaitGroup
 }
 
 type httpMetadata struct {
@@ -76,20 +84,46 @@ type httpMetadata struct {
 	// Default: 100MB
 	MaxResponseBodySize kitmd.ByteSize `mapstructure:"maxResponseBodySize"`
 
+	// Input binding (webhook) properties.
+	WebhookPort                 string         `mapstructure:"webhookPort"`
+	WebhookPath                 string         `mapstructure:"webhookPath"`
+	WebhookBearerToken          string         `mapstructure:"webhookBearerToken"`
+	WebhookAllowUnauthenticated bool           `mapstructure:"webhookAllowUnauthenticated"`
+	WebhookSignatureSecret      string         `mapstructure:"webhookSignatureSecret"`
+	WebhookSignatureHeader      string         `mapstructure:"webhookSignatureHeader"`
+	WebhookSignatureAlgorithm   string         `mapstructure:"webhookSignatureAlgorithm"`
+	WebhookSignatureEncoding    string         `mapstructure:"webhookSignatureEncoding"`
+	WebhookSignaturePrefix      string         `mapstructure:"webhookSignaturePrefix"`
+	WebhookSignatureKey         string         `mapstructure:"webhookSignatureKey"`
+	WebhookSignedPayload        string         `mapstructure:"webhookSignedPayload"`
+	WebhookTimestampHeader      string         `mapstructure:"webhookTimestampHeader"`
+	WebhookTimestampKey         string         `mapstructure:"webhookTimestampKey"`
+	WebhookTimestampTolerance   time.Duration  `mapstructure:"webhookTimestampTolerance"`
+	WebhookMaxBodySize          kitmd.ByteSize `mapstructure:"webhookMaxBodySize"`
+
 	maxResponseBodySizeBytes int64
+	webhookMaxBodySizeBytes  int64
 }
 
 // NewHTTP returns a new HTTPSource.
-func NewHTTP(logger logger.Logger) bindings.OutputBinding {
+func NewHTTP(logger logger.Logger) bindings.InputOutputBinding {
 	return &HTTPSource{
-		logger: logger,
+		logger:  logger,
+		closeCh: make(chan struct{}),
 	}
 }
 
 // Init performs metadata parsing.
 func (h *HTTPSource) Init(_ context.Context, meta bindings.Metadata) error {
 	h.metadata = httpMetadata{
-		MaxResponseBodySize: kitmd.NewByteSize(defaultMaxResponseBodySizeBytes),
+		MaxResponseBodySize:       kitmd.NewByteSize(defaultMaxResponseBodySizeBytes),
+		WebhookPath:               defaultWebhookPath,
+		WebhookSignatureHeader:    defaultWebhookSignatureHeader,
+		WebhookSignatureAlgorithm: defaultWebhookSignatureAlgorithm,
+		WebhookSignatureEncoding:  defaultWebhookSignatureEncoding,
+		WebhookSignedPayload:      webhookPayloadBody,
+		WebhookTimestampTolerance: defaultWebhookTimestampTolerance,
+		WebhookMaxBodySize:        kitmd.NewByteSize(defaultWebhookMaxBodySizeBytes),
 	}
 	err := kitmd.DecodeMetadata(meta.Properties, &h.metadata)
 	if err != nil {
@@ -121,6 +155,11 @@ func (h *HTTPSource) Init(_ context.Context, meta bindings.Metadata) error {
 		return fmt.Errorf("invalid value for maxResponseBodySize: %w", err)
 	}
 
+	err = h.metadata.validateWebhook()
+	if err != nil {
+		return err
+	}
+
 	// See guidance on proper HTTP client settings here:
 	// https://medium.com/@nate510/don-t-use-go-s-default-http-client-4804cb19f779
 	dialer := &net.Dialer{
@@ -380,5 +419,9 @@ func (h *HTTPSource) GetComponentMetadata() (metadataInfo metadata.MetadataMap)
 }
 
 func (h *HTTPSource) Close() error {
+	if h.closed.CompareAndSwap(false, true) {
+		close(h.closeCh)
+	}
+	h.wg.Wait()
 	return nil
 }
@@ -0,0 +1,292 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec // Used by webhooks that still sign payloads with HMAC-SHA1
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dapr/components-contrib/bindings"
+)
+
+const (
+	defaultWebhookPath               = "/"
+	defaultWebhookSignatureHeader    = "X-Signature"
+	defaultWebhookSignatureAlgorithm = "sha256"
+	defaultWebhookSignatureEncoding  = "hex"
+	defaultWebhookTimestampTolerance = 5 * time.Minute
+	defaultWebhookMaxBodySizeBytes   = 4 << 20 // 4 MB
+
+	webhookPayloadBody      = "{body}"
+	webhookPayloadTimestamp = "{timestamp}"
+
+	webhookReadHeaderTimeout = 10 * time.Second
+	webhookShutdownTimeout   = 5 * time.Second
+)
+
+var webhookSignatureAlgorithms = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// validateWebhook validates the properties of the input binding.
+func (m *httpMetadata) validateWebhook() (err error) {
+	m.webhookMaxBodySizeBytes, err = m.WebhookMaxBodySize.GetBytes()
+	if err != nil {
+		return fmt.Errorf("invalid value for webhookMaxBodySize: %w", err)
+	}
+
+	// The timestamp must be signed, or requests could be replayed with a new timestamp
+	if m.WebhookTimestampHeader != "" {
+		if m.WebhookTimestampTolerance <= 0 {
+			return errors.New("webhookTimestampTolerance must be greater than zero")
+		}
+		if m.WebhookSignatureSecret == "" || !strings.Contains(m.WebhookSignedPayload, webhookPayloadTimestamp) {
+			return fmt.Errorf("webhookSignatureSecret is required and webhookSignedPayload must contain %s when webhookTimestampHeader is set", webhookPayloadTimestamp)
+		}
+	}
+
+	// Anyone who can reach the port could deliver requests to the app, unless it's explicitly allowed
+	if m.WebhookPort != "" && m.WebhookSignatureSecret == "" && m.WebhookBearerToken == "" && !m.WebhookAllowUnauthenticated {
+		return errors.New("webhookSignatureSecret or webhookBearerToken is required for the input binding, unless webhookAllowUnauthenticated is true")
+	}
+
+	if m.WebhookSignatureSecret == "" {
+		return nil
+	}
+	m.WebhookSignatureAlgorithm = strings.ToLower(m.WebhookSignatureAlgorithm)
+	if _, ok := webhookSignatureAlgorithms[m.WebhookSignatureAlgorithm]; !ok {
+		return fmt.Errorf("invalid value for webhookSignatureAlgorithm: %s", m.WebhookSignatureAlgorithm)
+	}
+	m.WebhookSignatureEncoding = strings.ToLower(m.WebhookSignatureEncoding)
+	if m.WebhookSignatureEncoding != "hex" && m.WebhookSignatureEncoding != "base64" {
+		return fmt.Errorf("invalid value for webhookSignatureEncoding: %s", m.WebhookSignatureEncoding)
+	}
+	if strings.Count(m.WebhookSignedPayload, webhookPayloadBody) != 1 {
+		return fmt.Errorf("webhookSignedPayload must contain %s once", webhookPayloadBody)
+	}
+	if strings.Contains(m.WebhookSignedPayload, webhookPayloadTimestamp) && m.WebhookTimestampHeader == "" {
+		return fmt.Errorf("webhookTimestampHeader is required when webhookSignedPayload contains %s", webhookPayloadTimestamp)
+	}
+	return nil
+}
+
+// Read starts a server that receives webhook requests on the configured port and path, and delivers them to the handler.
+func (h *HTTPSource) Read(ctx context.Context, handler bindings.Handler) error {
+	if h.closed.Load() {
+		return errors.New("binding is closed")
+	}
+	if h.metadata.WebhookPort == "" {
+		return errors.New("metadata property 'webhookPort' is required for the input binding")
+	}
+
+	ln, err := net.Listen("tcp", ":"+h.metadata.WebhookPort)
+	if err != nil {
+		return fmt.Errorf("failed to listen on port %s: %w", h.metadata.WebhookPort, err)
+	}
+	addr := ln.Addr()
+	h.webhookAddr.Store(&addr)
+
+	srv := &http.Server{
+		Handler:           h.webhookHandler(handler),
+		ReadHeaderTimeout: webhookReadHeaderTimeout,
+	}
+
+	// Run the server in background
+	h.wg.Add(2)
+	go func() {
+		defer h.wg.Done()
+		h.logger.Infof("Listening for webhook requests at http://%s%s", addr, h.metadata.WebhookPath)
+		srvErr := srv.Serve(ln)
+		if srvErr != nil && !errors.Is(srvErr, http.ErrServerClosed) {
+			h.logger.Errorf("Error serving webhook requests: %v", srvErr)
+		}
+	}()
+	// Close the server when context is canceled or binding closed.
+	go func() {
+		defer h.wg.Done()
+		select {
+		case <-ctx.Done():
+		case <-h.closeCh:
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), webhookShutdownTimeout)
+		defer cancel()
+		srvErr := srv.Shutdown(shutdownCtx)
+		if srvErr != nil {
+			h.logger.Errorf("Error shutting down webhook server: %v", srvErr)
+			srv.Close()
+		}
+	}()
+
+	return nil
+}
+
+// webhookHandler returns the handler of webhook requests.
+// Requests are delivered with their headers as metadata, and the response is the data returned by the app.
+func (h *HTTPSource) webhookHandler(handler bindings.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != h.metadata.WebhookPath {
+			http.NotFound(w, r)
+			return
+		}
+
+		var body io.Reader = r.Body
+		if h.metadata.webhookMaxBodySizeBytes > 0 {
+			body = http.MaxBytesReader(w, r.Body, h.metadata.webhookMaxBodySizeBytes)
+		}
+		data, err := io.ReadAll(body)
+		if err != nil {
+			if maxBytesErr := (&http.MaxBytesError{}); errors.As(err, &maxBytesErr) {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		err = h.verifyWebhook(r.Header, data, time.Now())
+		if err != nil {
+			h.logger.Debugf("Rejected webhook request: %v", err)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		// Request headers are mapped from `map[string][]string` to `map[string]string`
+		// where headers with multiple values are delimited with ", ".
+		metadata := make(map[string]string, len(r.Header)+3)
+		for key, values := range r.Header {
+			metadata[key] = strings.Join(values, ", ")
+		}
+		if h.metadata.WebhookBearerToken != "" {
+			delete(metadata, "Authorization")
+		}
+		metadata["method"] = r.Method
+		metadata["path"] = r.URL.Path
+		if r.URL.RawQuery != "" {
+			metadata["query"] = r.URL.RawQuery
+		}
+
+		res, err := handler(r.Context(), &bindings.ReadResponse{
+			Data:     data,
+			Metadata: metadata,
+		})
+		if err != nil {
+			h.logger.Errorf("Error handling webhook request: %v", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if len(res) > 0 {
+			_, err = w.Write(res)
+			if err != nil {
+				h.logger.Errorf("Error writing webhook response: %v", err)
+			}
+		}
+	}
+}
+
+// verifyWebhook verifies the bearer token, timestamp and signature of a webhook request, as configured.
+func (h *HTTPSource) verifyWebhook(header http.Header, body []byte, now time.Time) error {
+	if h.metadata.WebhookBearerToken != "" {
+		auth := header.Get("Authorization")
+		if subtle.ConstantTimeCompare([]byte(auth), []byte("Bearer "+h.metadata.WebhookBearerToken)) != 1 {
+			return errors.New("invalid bearer token")
+		}
+	}
+
+	// Requests with a timestamp outside of the tolerance are rejected, so they can't be replayed later
+	var timestamp string
+	if h.metadata.WebhookTimestampHeader != "" {
+		values := webhookHeaderValues(header, h.metadata.WebhookTimestampHeader, h.metadata.WebhookTimestampKey)
+		if len(values) == 0 {
+			return errors.New("missing timestamp")
+		}
+		timestamp = values[0]
+		sec, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid timestamp '%s'", timestamp)
+		}
+		if diff := now.Sub(time.Unix(sec, 0)).Abs(); diff > h.metadata.WebhookTimestampTolerance {
+			return fmt.Errorf("timestamp '%s' is outside of the tolerance", timestamp)
+		}
+	}
+
+	if h.metadata.WebhookSignatureSecret == "" {
+		return nil
+	}
+	signatures := webhookHeaderValues(header, h.metadata.WebhookSignatureHeader, h.metadata.WebhookSignatureKey)
+	if len(signatures) == 0 {
+		return errors.New("missing signature")
+	}
+
+	mac := hmac.New(webhookSignatureAlgorithms[h.metadata.WebhookSignatureAlgorithm], []byte(h.metadata.WebhookSignatureSecret))
+	before, after, _ := strings.Cut(h.metadata.WebhookSignedPayload, webhookPayloadBody)
+	mac.Write([]byte(strings.ReplaceAll(before, webhookPayloadTimestamp, timestamp)))
+	mac.Write(body)
+	mac.Write([]byte(strings.ReplaceAll(after, webhookPayloadTimestamp, timestamp)))
+	expected := mac.Sum(nil)
+
+	// Any of the signatures can match, for example while secrets are rotated
+	for _, signature := range signatures {
+		signature = strings.TrimPrefix(signature, h.metadata.WebhookSignaturePrefix)
+		var (
+			actual []byte
+			err    error
+		)
+		if h.metadata.WebhookSignatureEncoding == "base64" {
+			actual, err = base64.StdEncoding.DecodeString(signature)
+		} else {
+			actual, err = hex.DecodeString(signature)
+		}
+		if err == nil && hmac.Equal(actual, expected) {
+			return nil
+		}
+	}
+	return errors.New("invalid signature")
+}
+
+// webhookHeaderValues returns the values of a header.
+// If key is set, the header is a comma-separated list of key=value pairs, and the values of the pairs with the key are returned.
+func webhookHeaderValues(header http.Header, name string, key string) []string {
+	values := header.Values(name)
+	if key == "" {
+		return values
+	}
+
+	var res []string
+	for _, value := range values {
+		for _, pair := range strings.Split(value, ",") {
+			k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok && k == key {
+				res = append(res, v)
+			}
+		}
+	}
+	return res
+}
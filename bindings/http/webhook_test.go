@@ -0,0 +1,231 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/kit/logger"
+)
+
+func initWebhookBinding(t *testing.T, props map[string]string) *HTTPSource {
+	t.Helper()
+
+	hs := NewHTTP(logger.NewLogger("test")).(*HTTPSource)
+	t.Cleanup(func() {
+		hs.Close()
+	})
+	err := hs.Init(t.Context(), bindings.Metadata{Base: metadata.Base{Properties: props}})
+	require.NoError(t, err)
+	return hs
+}
+
+func signWebhook(secret string, payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func TestWebhookRead(t *testing.T) {
+	hs := initWebhookBinding(t, map[string]string{
+		"webhookPort":            "0",
+		"webhookPath":            "/events",
+		"webhookSignatureSecret": "secret",
+		"webhookSignatureHeader": "X-Hub-Signature-256",
+		"webhookSignaturePrefix": "sha256=",
+	})
+
+	received := make(chan *bindings.ReadResponse, 1)
+	err := hs.Read(t.Context(), func(ctx context.Context, res *bindings.ReadResponse) ([]byte, error) {
+		received <- res
+		if string(res.Data) == "fail" {
+			return nil, errors.New("simulated failure")
+		}
+		return []byte("ok"), nil
+	})
+	require.NoError(t, err)
+	url := "http://" + (*hs.webhookAddr.Load()).String()
+
+	post := func(path string, body string, signature string) *http.Response {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, url+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if signature != "" {
+			req.Header.Set("X-Hub-Signature-256", signature)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			res.Body.Close()
+		})
+		return res
+	}
+
+	t.Run("signed request is delivered", func(t *testing.T) {
+		res := post("/events?a=b", `{"hello":"world"}`, "sha256="+hex.EncodeToString(signWebhook("secret", `{"hello":"world"}`)))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		body, _ := io.ReadAll(res.Body)
+		assert.Equal(t, "ok", string(body))
+
+		msg := <-received
+		assert.Equal(t, `{"hello":"world"}`, string(msg.Data))
+		assert.Equal(t, "application/json", msg.Metadata["Content-Type"])
+		assert.Equal(t, http.MethodPost, msg.Metadata["method"])
+		assert.Equal(t, "/events", msg.Metadata["path"])
+		assert.Equal(t, "a=b", msg.Metadata["query"])
+	})
+
+	t.Run("invalid signature is rejected", func(t *testing.T) {
+		res := post("/events", `{"hello":"world"}`, "sha256="+hex.EncodeToString(signWebhook("other", `{"hello":"world"}`)))
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+		res = post("/events", `{"hello":"world"}`, "")
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		assert.Empty(t, received)
+	})
+
+	t.Run("other paths are not found", func(t *testing.T) {
+		res := post("/other", "", "")
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("handler error", func(t *testing.T) {
+		res := post("/events", "fail", "sha256="+hex.EncodeToString(signWebhook("secret", "fail")))
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		<-received
+	})
+
+	t.Run("server stops when the binding is closed", func(t *testing.T) {
+		require.NoError(t, hs.Close())
+		_, err := http.Post(url+"/events", "text/plain", nil) //nolint:noctx
+		require.Error(t, err)
+	})
+}
+
+func TestWebhookVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+
+	t.Run("bearer token", func(t *testing.T) {
+		hs := initWebhookBinding(t, map[string]string{
+			"webhookBearerToken": "token",
+		})
+
+		require.NoError(t, hs.verifyWebhook(http.Header{"Authorization": {"Bearer token"}}, nil, now))
+		require.Error(t, hs.verifyWebhook(http.Header{"Authorization": {"Bearer other"}}, nil, now))
+		require.Error(t, hs.verifyWebhook(http.Header{}, nil, now))
+	})
+
+	t.Run("slack-style signature with timestamp", func(t *testing.T) {
+		hs := initWebhookBinding(t, map[string]string{
+			"webhookSignatureSecret": "secret",
+			"webhookSignatureHeader": "X-Slack-Signature",
+			"webhookSignaturePrefix": "v0=",
+			"webhookSignedPayload":   "v0:{timestamp}:{body}",
+			"webhookTimestampHeader": "X-Slack-Request-Timestamp",
+		})
+		header := http.Header{
+			"X-Slack-Signature":         {"v0=" + hex.EncodeToString(signWebhook("secret", "v0:"+ts+":body"))},
+			"X-Slack-Request-Timestamp": {ts},
+		}
+
+		require.NoError(t, hs.verifyWebhook(header, []byte("body"), now))
+		require.NoError(t, hs.verifyWebhook(header, []byte("body"), now.Add(4*time.Minute)))
+
+		// Replayed requests are rejected
+		require.ErrorContains(t, hs.verifyWebhook(header, []byte("body"), now.Add(10*time.Minute)), "tolerance")
+
+		// The timestamp is signed
+		header.Set("X-Slack-Request-Timestamp", strconv.FormatInt(now.Unix()+1, 10))
+		require.ErrorContains(t, hs.verifyWebhook(header, []byte("body"), now), "invalid signature")
+
+		header.Del("X-Slack-Request-Timestamp")
+		require.ErrorContains(t, hs.verifyWebhook(header, []byte("body"), now), "missing timestamp")
+	})
+
+	t.Run("stripe-style signature with keys", func(t *testing.T) {
+		hs := initWebhookBinding(t, map[string]string{
+			"webhookSignatureSecret": "secret",
+			"webhookSignatureHeader": "Stripe-Signature",
+			"webhookSignatureKey":    "v1",
+			"webhookSignedPayload":   "{timestamp}.{body}",
+			"webhookTimestampHeader": "Stripe-Signature",
+			"webhookTimestampKey":    "t",
+		})
+		signature := hex.EncodeToString(signWebhook("secret", ts+".body"))
+		other := hex.EncodeToString(signWebhook("other", ts+".body"))
+
+		require.NoError(t, hs.verifyWebhook(http.Header{"Stripe-Signature": {"t=" + ts + ",v1=" + other + ",v1=" + signature}}, []byte("body"), now))
+		require.Error(t, hs.verifyWebhook(http.Header{"Stripe-Signature": {"t=" + ts + ",v1=" + other}}, []byte("body"), now))
+	})
+
+	t.Run("base64 encoding", func(t *testing.T) {
+		hs := initWebhookBinding(t, map[string]string{
+			"webhookSignatureSecret":   "secret",
+			"webhookSignatureEncoding": "base64",
+		})
+
+		header := http.Header{"X-Signature": {base64.StdEncoding.EncodeToString(signWebhook("secret", "body"))}}
+		require.NoError(t, hs.verifyWebhook(header, []byte("body"), now))
+		require.Error(t, hs.verifyWebhook(header, []byte("other"), now))
+	})
+}
+
+func TestWebhookMetadata(t *testing.T) {
+	tests := map[string]map[string]string{
+		"invalid algorithm":           {"webhookSignatureSecret": "secret", "webhookSignatureAlgorithm": "md5"},
+		"invalid encoding":            {"webhookSignatureSecret": "secret", "webhookSignatureEncoding": "base32"},
+		"payload without body":        {"webhookSignatureSecret": "secret", "webhookSignedPayload": "{timestamp}"},
+		"timestamp without header":    {"webhookSignatureSecret": "secret", "webhookSignedPayload": "{timestamp}.{body}"},
+		"non-positive tolerance":      {"webhookTimestampHeader": "X-Timestamp", "webhookTimestampTolerance": "0s"},
+		"timestamp not signed":        {"webhookSignatureSecret": "secret", "webhookTimestampHeader": "X-Timestamp"},
+		"timestamp without secret":    {"webhookBearerToken": "token", "webhookTimestampHeader": "X-Timestamp", "webhookSignedPayload": "{timestamp}.{body}"},
+		"unauthenticated":             {"webhookPort": "0"},
+		"invalid max body size value": {"webhookMaxBodySize": "lots"},
+	}
+	for name, props := range tests {
+		t.Run(name, func(t *testing.T) {
+			hs := NewHTTP(logger.NewLogger("test"))
+			err := hs.Init(t.Context(), bindings.Metadata{Base: metadata.Base{Properties: props}})
+			require.Error(t, err)
+		})
+	}
+
+	t.Run("unauthenticated allowed", func(t *testing.T) {
+		hs := initWebhookBinding(t, map[string]string{"webhookPort": "0", "webhookAllowUnauthenticated": "true"})
+		assert.True(t, hs.metadata.WebhookAllowUnauthenticated)
+	})
+
+	t.Run("port is required to read", func(t *testing.T) {
+		hs := initWebhookBinding(t, map[string]string{})
+		err := hs.Read(t.Context(), nil)
+		require.ErrorContains(t, err, "webhookPort")
+	})
+}
@@ -16,6 +16,7 @@ package kafka
 import (
 	"context"
 	"errors"
+	"maps"
 	"reflect"
 	"strings"
 	"sync"
@@ -25,11 +26,14 @@ import (
 	"github.com/dapr/components-contrib/common/component/kafka"
 	contribMetadata "github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/kit/logger"
+	kitmd "github.com/dapr/kit/metadata"
 )
 
 const (
-	publishTopic = "publishTopic"
-	topics       = "topics"
+	publishTopic    = "publishTopic"
+	topics          = "topics"
+	valueSchemaType = "valueSchemaType"
+	keySchemaType   = "keySchemaType"
 )
 
 type Binding struct {
@@ -40,6 +44,12 @@ type Binding struct {
 	closeCh      chan struct{}
 	closed       atomic.Bool
 	wg           sync.WaitGroup
+
+	// Schema types set in the metadata of the component, used for messages that are received
+	// and for messages that are published without a schema type in the request metadata
+	schemaTypeMetadata map[string]string
+	valueSchemaType    kafka.SchemaType
+	keySchemaType      kafka.SchemaType
 }
 
 // NewKafka returns a new kafka binding instance.
@@ -70,6 +80,21 @@ func (b *Binding) Init(ctx context.Context, metadata bindings.Metadata) error {
 		b.topics = strings.Split(val, ",")
 	}
 
+	b.valueSchemaType, err = kafka.GetValueSchemaType(metadata.Properties)
+	if err != nil {
+		return err
+	}
+	b.keySchemaType, err = kafka.GetKeySchemaType(metadata.Properties)
+	if err != nil {
+		return err
+	}
+	b.schemaTypeMetadata = make(map[string]string, 2)
+	for _, key := range []string{valueSchemaType, keySchemaType} {
+		if val, ok := kitmd.GetMetadataProperty(metadata.Properties, key); ok {
+			b.schemaTypeMetadata[key] = val
+		}
+	}
+
 	return nil
 }
 
@@ -86,7 +111,13 @@ func (b *Binding) Close() (err error) {
 }
 
 func (b *Binding) Invoke(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
-	err := b.kafka.Publish(ctx, b.publishTopic, req.Data, req.Metadata)
+	md := req.Metadata
+	if len(b.schemaTypeMetadata) > 0 {
+		md = make(map[string]string, len(req.Metadata)+len(b.schemaTypeMetadata))
+		maps.Copy(md, b.schemaTypeMetadata)
+		maps.Copy(md, req.Metadata)
+	}
+	err := b.kafka.Publish(ctx, b.publishTopic, req.Data, md)
 	return nil, err
 }
 
@@ -115,6 +146,8 @@ func (b *Binding) Read(ctx context.Context, handler bindings.Handler) error {
 	handlerConfig := kafka.SubscriptionHandlerConfig{
 		IsBulkSubscribe: false,
 		Handler:         adaptHandler(handler),
+		ValueSchemaType: b.valueSchemaType,
+		KeySchemaType:   b.keySchemaType,
 	}
 
 	b.kafka.Subscribe(ctx, handlerConfig, b.topics...)
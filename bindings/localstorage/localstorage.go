@@ -25,6 +25,9 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/google/uuid"
@@ -48,15 +51,32 @@ var disallowedRootPaths = []string{
 	filepath.Clean("/var/run/secrets"),
 }
 
-// LocalStorage allows saving files to disk.
+// LocalStorage allows saving files to disk, and watching for changes to files.
 type LocalStorage struct {
 	metadata *Metadata
 	logger   logger.Logger
+
+	closed  atomic.Bool
+	closeCh chan struct{}
+	wg      sync.WaitGroup
 }
 
 // Metadata defines the metadata.
 type Metadata struct {
 	RootPath string `json:"rootPath"`
+
+	// Input binding properties.
+	// Comma-separated list of the events to watch for: create, modify and delete. Defaults to all.
+	WatchEvents string `json:"watchEvents"`
+	// Comma-separated lists of glob patterns of the files to watch, and of the files to ignore.
+	WatchInclude string `json:"watchInclude"`
+	WatchExclude string `json:"watchExclude"`
+	// Time to wait for more changes to a file before sending its event.
+	WatchDebounce time.Duration `json:"watchDebounce"`
+
+	watchEvents  map[string]struct{}
+	watchInclude []string
+	watchExclude []string
 }
 
 type createResponse struct {
@@ -64,8 +84,11 @@ type createResponse struct {
 }
 
 // NewLocalStorage returns a new LocalStorage instance.
-func NewLocalStorage(logger logger.Logger) bindings.OutputBinding {
-	return &LocalStorage{logger: logger}
+func NewLocalStorage(logger logger.Logger) bindings.InputOutputBinding {
+	return &LocalStorage{
+		logger:  logger,
+		closeCh: make(chan struct{}),
+	}
 }
 
 // Init performs metadata parsing.
@@ -85,7 +108,9 @@ func (ls *LocalStorage) Init(_ context.Context, metadata bindings.Metadata) erro
 }
 
 func (ls *LocalStorage) parseMetadata(meta bindings.Metadata) (*Metadata, error) {
-	var m Metadata
+	m := Metadata{
+		WatchDebounce: defaultWatchDebounce,
+	}
 	err := kitmd.DecodeMetadata(meta.Properties, &m)
 	if err != nil {
 		return nil, err
@@ -96,6 +121,11 @@ func (ls *LocalStorage) parseMetadata(meta bindings.Metadata) (*Metadata, error)
 		return nil, err
 	}
 
+	err = m.parseWatchMetadata()
+	if err != nil {
+		return nil, err
+	}
+
 	return &m, nil
 }
 
@@ -344,5 +374,9 @@ func (ls *LocalStorage) GetComponentMetadata() (metadataInfo metadata.MetadataMa
 }
 
 func (ls *LocalStorage) Close() error {
+	if ls.closed.CompareAndSwap(false, true) {
+		close(ls.closeCh)
+	}
+	ls.wg.Wait()
 	return nil
 }
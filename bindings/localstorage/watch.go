@@ -0,0 +1,333 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localstorage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/dapr/components-contrib/bindings"
+)
+
+const (
+	eventMetadataKey = "event"
+
+	watchEventCreate = "create"
+	watchEventModify = "modify"
+	watchEventDelete = "delete"
+
+	defaultWatchDebounce = 500 * time.Millisecond
+)
+
+// watchEvent is the data of the events sent by the input binding.
+type watchEvent struct {
+	Event    string `json:"event"`
+	FileName string `json:"fileName"`
+}
+
+// pendingWatchEvent is an event waiting for the debounce interval to pass.
+type pendingWatchEvent struct {
+	event string
+	due   time.Time
+}
+
+// parseWatchMetadata parses and validates the properties of the input binding.
+func (m *Metadata) parseWatchMetadata() error {
+	if m.WatchDebounce < 0 {
+		return errors.New("property watchDebounce must not be negative")
+	}
+
+	m.watchEvents = map[string]struct{}{}
+	for _, e := range splitList(m.WatchEvents) {
+		e = strings.ToLower(e)
+		switch e {
+		case watchEventCreate, watchEventModify, watchEventDelete:
+			m.watchEvents[e] = struct{}{}
+		default:
+			return fmt.Errorf("property watchEvents contains an invalid event '%s': must be one of 'create', 'modify' or 'delete'", e)
+		}
+	}
+	if len(m.watchEvents) == 0 {
+		m.watchEvents = map[string]struct{}{watchEventCreate: {}, watchEventModify: {}, watchEventDelete: {}}
+	}
+
+	m.watchInclude = splitList(m.WatchInclude)
+	m.watchExclude = splitList(m.WatchExclude)
+	for _, pattern := range slices.Concat(m.watchInclude, m.watchExclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid glob pattern '%s': %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// matchesWatch returns true if the events of the file are sent, according to the include and exclude patterns.
+// Patterns without a slash are matched against the file name, and other patterns against the path relative to the root path.
+func (m *Metadata) matchesWatch(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	match := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			name := relPath
+			if !strings.Contains(pattern, "/") {
+				name = path.Base(relPath)
+			}
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(m.watchInclude) > 0 && !match(m.watchInclude) {
+		return false
+	}
+	return !match(m.watchExclude)
+}
+
+// Read watches for files that are created, modified or deleted under the root path, and sends an event for each.
+func (ls *LocalStorage) Read(ctx context.Context, handler bindings.Handler) error {
+	if ls.closed.Load() {
+		return errors.New("binding is closed")
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+
+	w := &watcher{
+		ls:      ls,
+		fsw:     fsw,
+		dirs:    map[string]struct{}{},
+		pending: map[string]*pendingWatchEvent{},
+	}
+	err = w.addDir(ls.metadata.RootPath, false)
+	if err != nil {
+		fsw.Close()
+		return err
+	}
+
+	ls.wg.Add(1)
+	go func() {
+		defer ls.wg.Done()
+		defer fsw.Close()
+		w.run(ctx, handler)
+	}()
+
+	return nil
+}
+
+// watcher sends the events of the files under the root path, once no more changes are made to them for the debounce interval.
+// It's only used by the goroutine started by Read.
+type watcher struct {
+	ls      *LocalStorage
+	fsw     *fsnotify.Watcher
+	dirs    map[string]struct{}
+	pending map[string]*pendingWatchEvent
+}
+
+func (w *watcher) run(ctx context.Context, handler bindings.Handler) {
+	timer := time.NewTimer(0)
+	<-timer.C
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.ls.closeCh:
+			return
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			w.handleFSEvent(ev, time.Now())
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			w.ls.logger.Errorf("error watching files under %s: %v", w.ls.metadata.RootPath, err)
+		case <-timer.C:
+		}
+
+		next := w.flush(ctx, handler, time.Now())
+		timer.Stop()
+		if !next.IsZero() {
+			timer.Reset(time.Until(next))
+		}
+	}
+}
+
+// handleFSEvent records the event of a file, or watches the new directories.
+func (w *watcher) handleFSEvent(ev fsnotify.Event, now time.Time) {
+	// Files are only reported if they are under the root path once symlinks are resolved, like with the output binding
+	absPath, relPath, err := getSecureAbsRelPath(w.ls.metadata.RootPath, strings.TrimPrefix(ev.Name, w.ls.metadata.RootPath))
+	if err != nil || absPath != ev.Name || relPath == "." {
+		return
+	}
+
+	var event string
+	switch {
+	case ev.Has(fsnotify.Create):
+		fi, err := os.Lstat(absPath)
+		if err != nil {
+			return
+		}
+		if fi.IsDir() {
+			// Files that were created in the directory before it was watched are reported too
+			err = w.addDir(absPath, true)
+			if err != nil {
+				w.ls.logger.Errorf("error watching directory %s: %v", absPath, err)
+			}
+			return
+		}
+		event = watchEventCreate
+	case ev.Has(fsnotify.Write):
+		event = watchEventModify
+	case ev.Has(fsnotify.Remove), ev.Has(fsnotify.Rename):
+		if _, ok := w.dirs[absPath]; ok {
+			// The watch is removed automatically
+			delete(w.dirs, absPath)
+			return
+		}
+		event = watchEventDelete
+	default:
+		return
+	}
+
+	w.addPending(relPath, event, now)
+}
+
+// addPending records the event of a file, combining it with the event that is pending for it.
+func (w *watcher) addPending(relPath string, event string, now time.Time) {
+	due := now.Add(w.ls.metadata.WatchDebounce)
+	p, ok := w.pending[relPath]
+	if !ok {
+		w.pending[relPath] = &pendingWatchEvent{event: event, due: due}
+		return
+	}
+
+	p.due = due
+	switch {
+	case event == watchEventDelete && p.event == watchEventCreate:
+		// The file was removed before its creation was reported
+		delete(w.pending, relPath)
+	case event == watchEventDelete:
+		p.event = watchEventDelete
+	case event == watchEventCreate && p.event == watchEventDelete:
+		// The file was replaced
+		p.event = watchEventModify
+	}
+}
+
+// addDir watches a directory and its subdirectories.
+// If report is true, create events are recorded for the files in them.
+func (w *watcher) addDir(root string, report bool) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// The directory may have been removed already
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		// Symlinks are not followed
+		if !d.IsDir() {
+			if report && d.Type().IsRegular() {
+				relPath, err := filepath.Rel(w.ls.metadata.RootPath, p)
+				if err == nil {
+					w.addPending(relPath, watchEventCreate, time.Now())
+				}
+			}
+			return nil
+		}
+
+		err = w.fsw.Add(p)
+		if err != nil {
+			return fmt.Errorf("failed to watch directory %s: %w", p, err)
+		}
+		w.dirs[p] = struct{}{}
+		return nil
+	})
+}
+
+// flush sends the events that are due, and returns when the next pending event is due.
+func (w *watcher) flush(ctx context.Context, handler bindings.Handler, now time.Time) (next time.Time) {
+	due := make([]string, 0, len(w.pending))
+	for relPath, p := range w.pending {
+		if p.due.After(now) {
+			if next.IsZero() || p.due.Before(next) {
+				next = p.due
+			}
+			continue
+		}
+		due = append(due, relPath)
+	}
+	slices.SortFunc(due, func(a, b string) int {
+		return w.pending[a].due.Compare(w.pending[b].due)
+	})
+
+	for _, relPath := range due {
+		event := w.pending[relPath].event
+		delete(w.pending, relPath)
+
+		if _, ok := w.ls.metadata.watchEvents[event]; !ok || !w.ls.metadata.matchesWatch(relPath) {
+			continue
+		}
+
+		data, err := json.Marshal(watchEvent{Event: event, FileName: relPath})
+		if err != nil {
+			w.ls.logger.Errorf("error encoding event for file %s: %v", relPath, err)
+			continue
+		}
+		w.ls.logger.Debugf("file %s: %s", event, relPath)
+		_, err = handler(ctx, &bindings.ReadResponse{
+			Data: data,
+			Metadata: map[string]string{
+				eventMetadataKey:    event,
+				fileNameMetadataKey: relPath,
+			},
+		})
+		if err != nil {
+			w.ls.logger.Errorf("error handling %s event for file %s: %v", event, relPath, err)
+		}
+	}
+
+	return next
+}
+
+// splitList splits a comma-separated list, ignoring empty items.
+func splitList(val string) []string {
+	var res []string
+	for _, v := range strings.Split(val, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
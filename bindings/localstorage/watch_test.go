@@ -0,0 +1,184 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localstorage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/kit/logger"
+)
+
+func startWatch(t *testing.T, props map[string]string) (string, <-chan watchEvent) {
+	t.Helper()
+
+	rootPath := t.TempDir()
+	props["rootPath"] = rootPath
+	ls := NewLocalStorage(logger.NewLogger("test"))
+	t.Cleanup(func() {
+		ls.Close()
+	})
+	err := ls.Init(t.Context(), bindings.Metadata{Base: metadata.Base{Properties: props}})
+	require.NoError(t, err)
+
+	events := make(chan watchEvent, 10)
+	err = ls.Read(t.Context(), func(_ context.Context, res *bindings.ReadResponse) ([]byte, error) {
+		var ev watchEvent
+		assert.NoError(t, json.Unmarshal(res.Data, &ev))
+		assert.Equal(t, ev.Event, res.Metadata[eventMetadataKey])
+		assert.Equal(t, ev.FileName, res.Metadata[fileNameMetadataKey])
+		events <- ev
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	return rootPath, events
+}
+
+func receiveWatchEvents(t *testing.T, events <-chan watchEvent, expected ...watchEvent) {
+	t.Helper()
+
+	// Events of different files can be sent in any order, since each change to a file delays its event
+	received := make([]watchEvent, 0, len(expected))
+	for range expected {
+		select {
+		case ev := <-events:
+			received = append(received, ev)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for events %v, received %v", expected, received)
+		}
+	}
+	assert.ElementsMatch(t, expected, received)
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected event %v", ev)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestWatch(t *testing.T) {
+	t.Run("create, modify and delete", func(t *testing.T) {
+		rootPath, events := startWatch(t, map[string]string{"watchDebounce": "50ms"})
+		file := filepath.Join(rootPath, "a.txt")
+
+		require.NoError(t, os.WriteFile(file, []byte("1"), 0o600))
+		receiveWatchEvents(t, events, watchEvent{Event: "create", FileName: "a.txt"})
+
+		require.NoError(t, os.WriteFile(file, []byte("2"), 0o600))
+		receiveWatchEvents(t, events, watchEvent{Event: "modify", FileName: "a.txt"})
+
+		require.NoError(t, os.Remove(file))
+		receiveWatchEvents(t, events, watchEvent{Event: "delete", FileName: "a.txt"})
+	})
+
+	t.Run("changes are debounced", func(t *testing.T) {
+		rootPath, events := startWatch(t, map[string]string{"watchDebounce": "300ms"})
+		file := filepath.Join(rootPath, "a.txt")
+
+		// Writes after the file is created are part of its creation
+		require.NoError(t, os.WriteFile(file, []byte("1"), 0o600))
+		require.NoError(t, os.WriteFile(file, []byte("2"), 0o600))
+		receiveWatchEvents(t, events, watchEvent{Event: "create", FileName: "a.txt"})
+
+		// Files that are created and removed aren't reported
+		tmp := filepath.Join(rootPath, "tmp.txt")
+		require.NoError(t, os.WriteFile(tmp, []byte("1"), 0o600))
+		require.NoError(t, os.Remove(tmp))
+		receiveWatchEvents(t, events)
+	})
+
+	t.Run("subdirectories", func(t *testing.T) {
+		rootPath, events := startWatch(t, map[string]string{"watchDebounce": "50ms"})
+
+		dir := filepath.Join(rootPath, "sub", "dir")
+		require.NoError(t, os.MkdirAll(dir, 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("1"), 0o600))
+		receiveWatchEvents(t, events, watchEvent{Event: "create", FileName: filepath.Join("sub", "dir", "a.txt")})
+
+		require.NoError(t, os.Remove(filepath.Join(dir, "a.txt")))
+		receiveWatchEvents(t, events, watchEvent{Event: "delete", FileName: filepath.Join("sub", "dir", "a.txt")})
+
+		// Directories aren't reported
+		require.NoError(t, os.RemoveAll(filepath.Join(rootPath, "sub")))
+		receiveWatchEvents(t, events)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		rootPath, events := startWatch(t, map[string]string{
+			"watchDebounce": "50ms",
+			"watchEvents":   "create",
+			"watchInclude":  "*.csv, in/*",
+			"watchExclude":  "skip.csv",
+		})
+		require.NoError(t, os.Mkdir(filepath.Join(rootPath, "in"), 0o700))
+
+		require.NoError(t, os.WriteFile(filepath.Join(rootPath, "a.csv"), []byte("1"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(rootPath, "skip.csv"), []byte("1"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(rootPath, "a.txt"), []byte("1"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(rootPath, "in", "b.txt"), []byte("1"), 0o600))
+		receiveWatchEvents(t, events,
+			watchEvent{Event: "create", FileName: "a.csv"},
+			watchEvent{Event: "create", FileName: filepath.Join("in", "b.txt")},
+		)
+
+		require.NoError(t, os.Remove(filepath.Join(rootPath, "a.csv")))
+		receiveWatchEvents(t, events)
+	})
+
+	t.Run("symlinks outside of the root path aren't followed", func(t *testing.T) {
+		rootPath, events := startWatch(t, map[string]string{"watchDebounce": "50ms"})
+		outside := t.TempDir()
+
+		require.NoError(t, os.Symlink(outside, filepath.Join(rootPath, "link")))
+		require.NoError(t, os.WriteFile(filepath.Join(outside, "a.txt"), []byte("1"), 0o600))
+		require.NoError(t, os.Symlink(filepath.Join(outside, "a.txt"), filepath.Join(rootPath, "a.txt")))
+		receiveWatchEvents(t, events)
+	})
+
+	t.Run("closed binding", func(t *testing.T) {
+		ls := NewLocalStorage(logger.NewLogger("test"))
+		err := ls.Init(t.Context(), bindings.Metadata{Base: metadata.Base{Properties: map[string]string{"rootPath": t.TempDir()}}})
+		require.NoError(t, err)
+		require.NoError(t, ls.Close())
+		require.Error(t, ls.Read(t.Context(), nil))
+	})
+}
+
+func TestParseWatchMetadata(t *testing.T) {
+	m := Metadata{}
+	require.NoError(t, m.parseWatchMetadata())
+	assert.Len(t, m.watchEvents, 3)
+
+	m = Metadata{WatchEvents: "Create,delete"}
+	require.NoError(t, m.parseWatchMetadata())
+	assert.Equal(t, map[string]struct{}{"create": {}, "delete": {}}, m.watchEvents)
+
+	m = Metadata{WatchEvents: "rename"}
+	require.Error(t, m.parseWatchMetadata())
+
+	m = Metadata{WatchInclude: "[a-"}
+	require.Error(t, m.parseWatchMetadata())
+
+	m = Metadata{WatchDebounce: -time.Second}
+	require.Error(t, m.parseWatchMetadata())
+}
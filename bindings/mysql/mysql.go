@@ -31,6 +31,7 @@ import (
 	"github.com/go-sql-driver/mysql"
 
 	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/components-contrib/common/component/sql/transactions"
 	"github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/kit/logger"
 	kitmd "github.com/dapr/kit/metadata"
@@ -38,9 +39,10 @@ import (
 
 const (
 	// list of operations.
-	execOperation  bindings.OperationKind = "exec"
-	queryOperation bindings.OperationKind = "query"
-	closeOperation bindings.OperationKind = "close"
+	execOperation        bindings.OperationKind = "exec"
+	queryOperation       bindings.OperationKind = "query"
+	closeOperation       bindings.OperationKind = "close"
+	transactionOperation bindings.OperationKind = "transaction"
 
 	// configurations to connect to Mysql, either a data source name represent by URL.
 	connectionURLKey = "url"
@@ -70,6 +72,17 @@ const (
 
 var rawByteType = reflect.TypeOf(&sql.RawBytes{})
 
+// transactionStatement is a statement of the "transaction" operation.
+type transactionStatement struct {
+	SQL    string `json:"sql"`
+	Params []any  `json:"params"`
+}
+
+// transactionResult is the result of a statement of the "transaction" operation.
+type transactionResult struct {
+	RowsAffected int64 `json:"rowsAffected"`
+}
+
 // Mysql represents MySQL output bindings.
 type Mysql struct {
 	db     *sql.DB
@@ -162,6 +175,11 @@ func (m *Mysql) Invoke(ctx context.Context, req *bindings.InvokeRequest) (*bindi
 		return nil, errors.New("component is closed")
 	}
 
+	// The statements of the "transaction" operation are in the request data
+	if req.Operation == transactionOperation {
+		return m.transaction(ctx, req.Data)
+	}
+
 	if req.Metadata == nil {
 		return nil, errors.New("metadata required")
 	}
@@ -209,8 +227,8 @@ func (m *Mysql) Invoke(ctx context.Context, req *bindings.InvokeRequest) (*bindi
 		resp.Data = d
 
 	default:
-		return nil, fmt.Errorf("invalid operation type: %s. Expected %s, %s, or %s",
-			req.Operation, execOperation, queryOperation, closeOperation)
+		return nil, fmt.Errorf("invalid operation type: %s. Expected %s, %s, %s, or %s",
+			req.Operation, execOperation, queryOperation, transactionOperation, closeOperation)
 	}
 
 	endTime := time.Now().UTC()
@@ -226,6 +244,7 @@ func (m *Mysql) Operations() []bindings.OperationKind {
 		execOperation,
 		queryOperation,
 		closeOperation,
+		transactionOperation,
 	}
 }
 
@@ -269,6 +288,64 @@ func (m *Mysql) exec(ctx context.Context, sql string, params ...any) (int64, err
 	return res.RowsAffected()
 }
 
+// transaction executes the statements in the request data in a single transaction.
+// If any statement fails, the transaction is rolled back and no changes are made.
+func (m *Mysql) transaction(ctx context.Context, data []byte) (*bindings.InvokeResponse, error) {
+	var statements []transactionStatement
+	err := json.Unmarshal(data, &statements)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request data: failed to unserialize into an array of statements: %w", err)
+	}
+	if len(statements) == 0 {
+		return nil, errors.New("invalid request data: at least one statement is required")
+	}
+	for i, s := range statements {
+		if s.SQL == "" {
+			return nil, fmt.Errorf("invalid request data: statement %d is missing property %s", i, commandSQLKey)
+		}
+	}
+
+	startTime := time.Now().UTC()
+	results, err := transactions.ExecuteInTransaction(ctx, m.logger, m.db, func(ctx context.Context, tx *sql.Tx) ([]transactionResult, error) {
+		results := make([]transactionResult, len(statements))
+		for i, s := range statements {
+			res, err := tx.ExecContext(ctx, s.SQL, s.Params...)
+			if err != nil {
+				return nil, fmt.Errorf("error executing statement %d: %w", i, err)
+			}
+			results[i].RowsAffected, err = res.RowsAffected()
+			if err != nil {
+				return nil, fmt.Errorf("error reading rows affected by statement %d: %w", i, err)
+			}
+		}
+		return results, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rowsAffected int64
+	for _, r := range results {
+		rowsAffected += r.RowsAffected
+	}
+	resData, err := json.Marshal(results)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing results: %w", err)
+	}
+
+	endTime := time.Now().UTC()
+	return &bindings.InvokeResponse{
+		Data: resData,
+		Metadata: map[string]string{
+			respOpKey:           string(transactionOperation),
+			respRowsAffectedKey: strconv.FormatInt(rowsAffected, 10),
+			respStartTimeKey:    startTime.Format(time.RFC3339Nano),
+			respEndTimeKey:      endTime.Format(time.RFC3339Nano),
+			respDurationKey:     endTime.Sub(startTime).String(),
+		},
+	}, nil
+}
+
 func initDB(url, pemPath string) (*sql.DB, error) {
 	conf, err := mysql.ParseDSN(url)
 	if err != nil {
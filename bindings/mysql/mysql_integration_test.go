@@ -37,10 +37,11 @@ func TestOperations(t *testing.T) {
 		b := NewMysql(logger.NewLogger("test"))
 		require.NotNil(t, b)
 		l := b.Operations()
-		assert.Len(t, l, 3)
+		assert.Len(t, l, 4)
 		assert.Contains(t, l, execOperation)
 		assert.Contains(t, l, closeOperation)
 		assert.Contains(t, l, queryOperation)
+		assert.Contains(t, l, transactionOperation)
 	})
 }
 
@@ -146,6 +147,30 @@ func TestMysqlIntegration(t *testing.T) {
 		}
 	})
 
+	t.Run("Invoke transaction", func(t *testing.T) {
+		res, err := b.Invoke(t.Context(), &bindings.InvokeRequest{
+			Operation: transactionOperation,
+			Data:      []byte(`[{"sql": "UPDATE foo SET v1 = ? WHERE id = ?", "params": ["tx-1", 1]}, {"sql": "UPDATE foo SET v1 = 'test-1' WHERE id = 1"}]`),
+		})
+		assertResponse(t, res, err)
+		assert.JSONEq(t, `[{"rowsAffected": 1}, {"rowsAffected": 1}]`, string(res.Data))
+
+		// The first update is rolled back
+		_, err = b.Invoke(t.Context(), &bindings.InvokeRequest{
+			Operation: transactionOperation,
+			Data:      []byte(`[{"sql": "UPDATE foo SET v1 = 'tx-2' WHERE id = 1"}, {"sql": "UPDATE missing SET v1 = 'tx-2'"}]`),
+		})
+		require.Error(t, err)
+		res, err = b.Invoke(t.Context(), &bindings.InvokeRequest{
+			Operation: queryOperation,
+			Metadata: map[string]string{
+				commandSQLKey: "SELECT v1 FROM foo WHERE id = 1",
+			},
+		})
+		assertResponse(t, res, err)
+		assert.Contains(t, string(res.Data), `"v1":"test-1"`)
+	})
+
 	t.Run("Invoke select", func(t *testing.T) {
 		res, err := b.Invoke(t.Context(), &bindings.InvokeRequest{
 			Operation: queryOperation,
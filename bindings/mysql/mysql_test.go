@@ -174,6 +174,61 @@ func TestInvoke(t *testing.T) {
 	})
 }
 
+func TestTransaction(t *testing.T) {
+	t.Run("statements are committed", func(t *testing.T) {
+		m, mock, _ := mockDatabase(t)
+		defer m.Close()
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO foo \\(id, v1\\) VALUES \\(\\?, \\?\\)").
+			WithArgs(float64(1), "a").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE foo SET v1 = 'b'").WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		resp, err := m.Invoke(t.Context(), &bindings.InvokeRequest{
+			Operation: transactionOperation,
+			Data: []byte(`[
+				{"sql": "INSERT INTO foo (id, v1) VALUES (?, ?)", "params": [1, "a"]},
+				{"sql": "UPDATE foo SET v1 = 'b'"}
+			]`),
+		})
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"rowsAffected": 1}, {"rowsAffected": 2}]`, string(resp.Data))
+		assert.Equal(t, "3", resp.Metadata[respRowsAffectedKey])
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed statement rolls back", func(t *testing.T) {
+		m, mock, _ := mockDatabase(t)
+		defer m.Close()
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO foo").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE foo").WillReturnError(errors.New("update failed"))
+		mock.ExpectRollback()
+
+		resp, err := m.Invoke(t.Context(), &bindings.InvokeRequest{
+			Operation: transactionOperation,
+			Data:      []byte(`[{"sql": "INSERT INTO foo VALUES (1)"}, {"sql": "UPDATE foo SET v1 = 'b'"}, {"sql": "DELETE FROM foo"}]`),
+		})
+		assert.Nil(t, resp)
+		require.ErrorContains(t, err, "error executing statement 1: update failed")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("invalid request data", func(t *testing.T) {
+		m, mock, _ := mockDatabase(t)
+		defer m.Close()
+		for _, data := range []string{``, `{}`, `[]`, `[{"params": [1]}]`} {
+			_, err := m.Invoke(t.Context(), &bindings.InvokeRequest{
+				Operation: transactionOperation,
+				Data:      []byte(data),
+			})
+			require.Error(t, err, data)
+		}
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func mockDatabase(t *testing.T) (*Mysql, sqlmock.Sqlmock, error) {
 	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
 	if err != nil {
@@ -23,30 +23,46 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/dapr/components-contrib/bindings"
 	awsAuth "github.com/dapr/components-contrib/common/authentication/aws"
 	pgauth "github.com/dapr/components-contrib/common/authentication/postgresql"
+	pginterfaces "github.com/dapr/components-contrib/common/component/postgresql/interfaces"
+	pgtransactions "github.com/dapr/components-contrib/common/component/postgresql/transactions"
 	"github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/kit/logger"
 )
 
 // List of operations.
 const (
-	execOperation  bindings.OperationKind = "exec"
-	queryOperation bindings.OperationKind = "query"
-	closeOperation bindings.OperationKind = "close"
+	execOperation        bindings.OperationKind = "exec"
+	queryOperation       bindings.OperationKind = "query"
+	closeOperation       bindings.OperationKind = "close"
+	transactionOperation bindings.OperationKind = "transaction"
 
 	commandSQLKey  = "sql"
 	commandArgsKey = "params"
 )
 
+// transactionStatement is a statement of the "transaction" operation.
+type transactionStatement struct {
+	SQL    string `json:"sql"`
+	Params []any  `json:"params"`
+}
+
+// transactionResult is the result of a statement of the "transaction" operation.
+type transactionResult struct {
+	RowsAffected int64 `json:"rowsAffected"`
+}
+
 // Postgres represents PostgreSQL output binding.
 type Postgres struct {
-	logger logger.Logger
-	db     *pgxpool.Pool
-	closed atomic.Bool
+	logger  logger.Logger
+	db      pginterfaces.PGXPoolConn
+	timeout time.Duration
+	closed  atomic.Bool
 
 	enableAzureAD bool
 	enableAWSIAM  bool
@@ -100,10 +116,12 @@ func (p *Postgres) Init(ctx context.Context, meta bindings.Metadata) error {
 	// only scoped to postgres creating resources at init.
 	connCtx, connCancel := context.WithTimeout(ctx, m.Timeout)
 	defer connCancel()
-	p.db, err = pgxpool.NewWithConfig(connCtx, poolConfig)
+	pool, err := pgxpool.NewWithConfig(connCtx, poolConfig)
 	if err != nil {
 		return fmt.Errorf("unable to connect to the DB: %w", err)
 	}
+	p.db = pool
+	p.timeout = m.Timeout
 
 	pingCtx, pingCancel := context.WithTimeout(ctx, m.Timeout)
 	defer pingCancel()
@@ -121,6 +139,7 @@ func (p *Postgres) Operations() []bindings.OperationKind {
 		execOperation,
 		queryOperation,
 		closeOperation,
+		transactionOperation,
 	}
 }
 
@@ -140,6 +159,11 @@ func (p *Postgres) Invoke(ctx context.Context, req *bindings.InvokeRequest) (res
 		return nil, errors.New("component is closed")
 	}
 
+	// The statements of the "transaction" operation are in the request data
+	if req.Operation == transactionOperation {
+		return p.transaction(ctx, req.Data)
+	}
+
 	if req.Metadata == nil {
 		return nil, errors.New("metadata required")
 	}
@@ -186,8 +210,8 @@ func (p *Postgres) Invoke(ctx context.Context, req *bindings.InvokeRequest) (res
 
 	default:
 		return nil, fmt.Errorf(
-			"invalid operation type: %s. Expected %s, %s, or %s",
-			req.Operation, execOperation, queryOperation, closeOperation,
+			"invalid operation type: %s. Expected %s, %s, %s, or %s",
+			req.Operation, execOperation, queryOperation, transactionOperation, closeOperation,
 		)
 	}
 
@@ -250,6 +274,61 @@ func (p *Postgres) exec(ctx context.Context, sql string, args ...any) (result in
 	return res.RowsAffected(), nil
 }
 
+// transaction executes the statements in the request data in a single transaction.
+// If any statement fails, the transaction is rolled back and no changes are made.
+func (p *Postgres) transaction(ctx context.Context, data []byte) (*bindings.InvokeResponse, error) {
+	var statements []transactionStatement
+	err := json.Unmarshal(data, &statements)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request data: failed to unserialize into an array of statements: %w", err)
+	}
+	if len(statements) == 0 {
+		return nil, errors.New("invalid request data: at least one statement is required")
+	}
+	for i, s := range statements {
+		if s.SQL == "" {
+			return nil, fmt.Errorf("invalid request data: statement %d is missing property %s", i, commandSQLKey)
+		}
+	}
+
+	startTime := time.Now().UTC()
+	results, err := pgtransactions.ExecuteInTransaction(ctx, p.logger, p.db, p.timeout, func(ctx context.Context, tx pgx.Tx) ([]transactionResult, error) {
+		results := make([]transactionResult, len(statements))
+		for i, s := range statements {
+			res, err := tx.Exec(ctx, s.SQL, s.Params...)
+			if err != nil {
+				return nil, fmt.Errorf("error executing statement %d: %w", i, err)
+			}
+			results[i].RowsAffected = res.RowsAffected()
+		}
+		return results, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rowsAffected int64
+	for _, r := range results {
+		rowsAffected += r.RowsAffected
+	}
+	resData, err := json.Marshal(results)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing results: %w", err)
+	}
+
+	endTime := time.Now().UTC()
+	return &bindings.InvokeResponse{
+		Data: resData,
+		Metadata: map[string]string{
+			"operation":     string(transactionOperation),
+			"rows-affected": strconv.FormatInt(rowsAffected, 10),
+			"start-time":    startTime.Format(time.RFC3339Nano),
+			"end-time":      endTime.Format(time.RFC3339Nano),
+			"duration":      endTime.Sub(startTime).String(),
+		},
+	}, nil
+}
+
 // GetComponentMetadata returns the metadata of the component.
 func (p *Postgres) GetComponentMetadata() (metadataInfo metadata.MetadataMap) {
 	metadataStruct := psqlMetadata{}
@@ -17,9 +17,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"regexp"
 	"testing"
 	"time"
 
+	"github.com/pashagolub/pgxmock/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -45,7 +47,66 @@ func TestOperations(t *testing.T) {
 		b := NewPostgres(nil)
 		assert.NotNil(t, b)
 		l := b.Operations()
-		assert.Len(t, l, 3)
+		assert.Len(t, l, 4)
+	})
+}
+
+func TestTransaction(t *testing.T) {
+	newMock := func(t *testing.T) (*Postgres, pgxmock.PgxPoolIface) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		t.Cleanup(mock.Close)
+		return &Postgres{logger: logger.NewLogger("test"), db: mock, timeout: time.Second}, mock
+	}
+
+	t.Run("statements are committed", func(t *testing.T) {
+		p, mock := newMock(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO foo (id, v1) VALUES ($1, $2)")).
+			WithArgs(float64(1), "a").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE foo SET v1 = 'b'")).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+		mock.ExpectCommit()
+
+		res, err := p.Invoke(t.Context(), &bindings.InvokeRequest{
+			Operation: transactionOperation,
+			Data: []byte(`[
+				{"sql": "INSERT INTO foo (id, v1) VALUES ($1, $2)", "params": [1, "a"]},
+				{"sql": "UPDATE foo SET v1 = 'b'"}
+			]`),
+		})
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"rowsAffected": 1}, {"rowsAffected": 2}]`, string(res.Data))
+		assert.Equal(t, "3", res.Metadata["rows-affected"])
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed statement rolls back", func(t *testing.T) {
+		p, mock := newMock(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO foo").WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("UPDATE foo").WillReturnError(errors.New("update failed"))
+		mock.ExpectRollback()
+
+		_, err := p.Invoke(t.Context(), &bindings.InvokeRequest{
+			Operation: transactionOperation,
+			Data:      []byte(`[{"sql": "INSERT INTO foo VALUES (1)"}, {"sql": "UPDATE foo SET v1 = 'b'"}, {"sql": "DELETE FROM foo"}]`),
+		})
+		require.ErrorContains(t, err, "error executing statement 1: update failed")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("invalid request data", func(t *testing.T) {
+		p, mock := newMock(t)
+		for _, data := range []string{``, `{}`, `[]`, `[{"params": [1]}]`} {
+			_, err := p.Invoke(t.Context(), &bindings.InvokeRequest{
+				Operation: transactionOperation,
+				Data:      []byte(data),
+			})
+			require.Error(t, err, data)
+		}
+		require.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
@@ -106,6 +167,28 @@ func TestPostgresIntegration(t *testing.T) {
 		}
 	})
 
+	t.Run("Invoke transaction", func(t *testing.T) {
+		res, err := b.Invoke(ctx, &bindings.InvokeRequest{
+			Operation: transactionOperation,
+			Data:      []byte(`[{"sql": "UPDATE foo SET v1 = $1 WHERE id = $2", "params": ["tx-1", 1]}, {"sql": "UPDATE foo SET v1 = 'test-1' WHERE id = 1"}]`),
+		})
+		assertResponse(t, res, err)
+		assert.JSONEq(t, `[{"rowsAffected": 1}, {"rowsAffected": 1}]`, string(res.Data))
+
+		// The first update is rolled back
+		_, err = b.Invoke(ctx, &bindings.InvokeRequest{
+			Operation: transactionOperation,
+			Data:      []byte(`[{"sql": "UPDATE foo SET v1 = 'tx-2' WHERE id = 1"}, {"sql": "UPDATE missing SET v1 = 'tx-2'"}]`),
+		})
+		require.Error(t, err)
+		res, err = b.Invoke(ctx, &bindings.InvokeRequest{
+			Operation: queryOperation,
+			Metadata:  map[string]string{commandSQLKey: "SELECT v1 FROM foo WHERE id = 1"},
+		})
+		assertResponse(t, res, err)
+		assert.JSONEq(t, `[["test-1"]]`, string(res.Data))
+	})
+
 	t.Run("Invoke select", func(t *testing.T) {
 		req.Operation = queryOperation
 		req.Metadata[commandSQLKey] = testSelect
@@ -180,6 +180,35 @@ func (c *Client) delete(path string) error {
 	return nil
 }
 
+func (c *Client) rename(oldPath string, newPath string) error {
+	fn := func() error {
+		dir := sysPath.Dir(newPath)
+		if _, statErr := c.sftpClient.Stat(dir); statErr != nil {
+			if !isDirNotExistError(statErr) {
+				return fmt.Errorf("error checking dir %s: %w", dir, statErr)
+			}
+			if mkdirErr := c.sftpClient.MkdirAll(dir); mkdirErr != nil {
+				return fmt.Errorf("error create dir %s: %w", dir, mkdirErr)
+			}
+		}
+
+		// posix-rename replaces an existing file, which plain SFTP rename doesn't allow
+		var err error
+		if _, ok := c.sftpClient.HasExtension("posix-rename@openssh.com"); ok {
+			err = c.sftpClient.PosixRename(oldPath, newPath)
+		} else {
+			err = c.sftpClient.Rename(oldPath, newPath)
+		}
+		if err != nil {
+			return fmt.Errorf("error rename file %s to %s: %w", oldPath, newPath, err)
+		}
+
+		return nil
+	}
+
+	return c.withReconnection(fn)
+}
+
 func (c *Client) ping() error {
 	_, err := c.sftpClient.Getwd()
 	if err != nil {
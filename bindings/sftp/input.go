@@ -0,0 +1,147 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sftp
+
+import (
+	"context"
+	"errors"
+	"os"
+	sysPath "path"
+	"slices"
+	"strings"
+	"time"
+
+	sftpClient "github.com/pkg/sftp"
+
+	"github.com/dapr/components-contrib/bindings"
+)
+
+// Read polls the root path for new files and delivers each of them to the handler.
+// Files are moved to the processing path before they are delivered, so that each file is delivered by one instance only.
+// They are then moved to the processed path if the handler succeeds, or to the failed path otherwise.
+func (sftp *Sftp) Read(ctx context.Context, handler bindings.Handler) error {
+	if sftp.closed.Load() {
+		return errors.New("binding is closed")
+	}
+	if sftp.metadata.RootPath == "" {
+		return errors.New("sftp binding error: metadata property 'rootPath' is required for the input binding")
+	}
+
+	sftp.wg.Add(1)
+	go func() {
+		defer sftp.wg.Done()
+
+		ticker := time.NewTicker(sftp.metadata.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			sftp.poll(ctx, handler, time.Now())
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-sftp.closeCh:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+// poll delivers the files that are in the root path, from the oldest to the newest.
+// Files that can't be moved to the processing path stay in the root path, and are delivered again by the next poll.
+func (sftp *Sftp) poll(ctx context.Context, handler bindings.Handler, now time.Time) {
+	files, err := sftp.c.list(sftp.metadata.RootPath)
+	if err != nil {
+		sftp.logger.Errorf("sftp binding error: error read dir %s: %v", sftp.metadata.RootPath, err)
+		return
+	}
+
+	// Subdirectories, which include the processed and failed paths by default, are ignored
+	files = slices.DeleteFunc(files, func(fi os.FileInfo) bool {
+		return !fi.Mode().IsRegular() || now.Sub(fi.ModTime()) < sftp.metadata.MinFileAge
+	})
+	slices.SortFunc(files, func(a, b os.FileInfo) int {
+		if c := a.ModTime().Compare(b.ModTime()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	for _, fi := range files {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sftp.closeCh:
+			return
+		default:
+		}
+
+		sftp.deliver(ctx, handler, fi.Name())
+	}
+}
+
+// deliver claims a file, sends it to the handler and moves it according to the result.
+func (sftp *Sftp) deliver(ctx context.Context, handler bindings.Handler, fileName string) {
+	// Only one instance can move the file out of the root path, so other instances skip it
+	path := sftpClient.Join(sftp.metadata.RootPath, fileName)
+	claimedPath := sftp.metadata.targetPath(sftp.metadata.ProcessingPath, fileName)
+	err := sftp.c.rename(path, claimedPath)
+	if err != nil {
+		if isDirNotExistError(err) {
+			sftp.logger.Debugf("sftp binding: file %s was claimed by another instance", path)
+			return
+		}
+		sftp.logger.Errorf("sftp binding error: error claiming file %s: %v", path, err)
+		return
+	}
+
+	target := sftp.metadata.ProcessedPath
+	data, err := sftp.c.get(claimedPath)
+	if err == nil {
+		_, err = handler(ctx, &bindings.ReadResponse{
+			Data: data,
+			Metadata: map[string]string{
+				metadataFileName: fileName,
+			},
+		})
+		if err != nil {
+			sftp.logger.Errorf("sftp binding error: error handling file %s: %v", path, err)
+			target = sftp.metadata.FailedPath
+		}
+	} else {
+		sftp.logger.Errorf("sftp binding error: error reading file %s: %v", claimedPath, err)
+		target = sftp.metadata.FailedPath
+	}
+
+	// Files that can't be moved stay in the processing path, so they aren't delivered again
+	newPath := sftp.metadata.targetPath(target, fileName)
+	err = sftp.c.rename(claimedPath, newPath)
+	if err != nil {
+		sftp.logger.Errorf("sftp binding error: file %s stays in the processing path: %v", claimedPath, err)
+		return
+	}
+	sftp.logger.Debugf("sftp binding: moved file %s to %s", path, newPath)
+}
+
+// targetPath returns the path a file is moved to.
+// Relative target directories are resolved against the root path.
+func (metadata sftpMetadata) targetPath(dir string, fileName string) string {
+	if !sysPath.IsAbs(dir) {
+		dir = sftpClient.Join(metadata.RootPath, dir)
+	}
+	return sftpClient.Join(dir, fileName)
+}
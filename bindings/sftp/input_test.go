@@ -0,0 +1,308 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sftp
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	sftpClient "github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/kit/logger"
+)
+
+// startTestServer starts an SFTP server that keeps files in memory, and returns its address.
+func startTestServer(t *testing.T) string {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if c.User() == "foo" && string(pass) == "pass" {
+				return nil, nil
+			}
+			return nil, errors.New("invalid credentials")
+		},
+	}
+	config.AddHostKey(signer)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		ln.Close()
+	})
+
+	// All connections share the same files
+	handlers := sftpClient.InMemHandler()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestConn(conn, config, handlers)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func serveTestConn(conn net.Conn, config *ssh.ServerConfig, handlers sftpClient.Handlers) {
+	defer conn.Close()
+
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newCh := range chans {
+		if newCh.ChannelType() != "session" {
+			newCh.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		ch, chReqs, err := newCh.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range chReqs {
+				ok := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+				req.Reply(ok, nil)
+				if ok {
+					srv := sftpClient.NewRequestServer(ch, handlers)
+					srv.Serve()
+					srv.Close()
+					return
+				}
+			}
+		}()
+	}
+}
+
+func TestRead(t *testing.T) {
+	addr := startTestServer(t)
+
+	initBinding := func(t *testing.T, props map[string]string) *Sftp {
+		t.Helper()
+
+		props["address"] = addr
+		props["username"] = "foo"
+		props["password"] = "pass"
+		props["insecureIgnoreHostKey"] = "true"
+		s := NewSftp(logger.NewLogger("test")).(*Sftp)
+		t.Cleanup(func() {
+			s.Close()
+		})
+		err := s.Init(t.Context(), bindings.Metadata{Base: metadata.Base{Properties: props}})
+		require.NoError(t, err)
+		return s
+	}
+
+	upload := func(t *testing.T, s *Sftp, fileName string, data string) {
+		t.Helper()
+
+		_, err := s.Invoke(t.Context(), &bindings.InvokeRequest{
+			Operation: bindings.CreateOperation,
+			Data:      []byte(data),
+			Metadata:  map[string]string{metadataFileName: fileName},
+		})
+		require.NoError(t, err)
+	}
+
+	list := func(t require.TestingT, s *Sftp, rootPath string) []listResponse {
+		res, err := s.Invoke(context.Background(), &bindings.InvokeRequest{
+			Operation: bindings.ListOperation,
+			Metadata:  map[string]string{metadataRootPath: rootPath},
+		})
+		require.NoError(t, err)
+		var files []listResponse
+		require.NoError(t, json.Unmarshal(res.Data, &files))
+		return files
+	}
+
+	t.Run("files are moved after processing", func(t *testing.T) {
+		s := initBinding(t, map[string]string{
+			"rootPath":     "/inbox",
+			"pollInterval": "50ms",
+		})
+		upload(t, s, "a.txt", "hello")
+		upload(t, s, "fail.txt", "world")
+		upload(t, s, "sub/b.txt", "ignored")
+
+		received := make(chan *bindings.ReadResponse, 10)
+		err := s.Read(t.Context(), func(_ context.Context, res *bindings.ReadResponse) ([]byte, error) {
+			received <- res
+			if res.Metadata[metadataFileName] == "fail.txt" {
+				return nil, errors.New("simulated failure")
+			}
+			return nil, nil
+		})
+		require.NoError(t, err)
+
+		got := map[string]string{}
+		for range 2 {
+			select {
+			case res := <-received:
+				got[res.Metadata[metadataFileName]] = string(res.Data)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for files, received %v", got)
+			}
+		}
+		assert.Equal(t, map[string]string{"a.txt": "hello", "fail.txt": "world"}, got)
+
+		assert.EventuallyWithT(t, func(c *assert.CollectT) {
+			assert.Equal(c, []listResponse{
+				{FileName: "failed", IsDirectory: true},
+				{FileName: "processed", IsDirectory: true},
+				{FileName: "processing", IsDirectory: true},
+				{FileName: "sub", IsDirectory: true},
+			}, list(c, s, "/inbox"))
+		}, 5*time.Second, 50*time.Millisecond)
+		assert.Empty(t, list(t, s, "/inbox/processing"))
+		assert.Equal(t, []listResponse{{FileName: "a.txt"}}, list(t, s, "/inbox/processed"))
+		assert.Equal(t, []listResponse{{FileName: "fail.txt"}}, list(t, s, "/inbox/failed"))
+
+		// Files are delivered once
+		select {
+		case res := <-received:
+			t.Fatalf("unexpected file %s", res.Metadata[metadataFileName])
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		// New files are delivered by the next poll
+		upload(t, s, "c.txt", "again")
+		select {
+		case res := <-received:
+			assert.Equal(t, "c.txt", res.Metadata[metadataFileName])
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for new file")
+		}
+	})
+
+	t.Run("absolute target paths", func(t *testing.T) {
+		s := initBinding(t, map[string]string{
+			"rootPath":      "/in",
+			"pollInterval":  "50ms",
+			"processedPath": "/done",
+		})
+		upload(t, s, "a.txt", "hello")
+
+		err := s.Read(t.Context(), func(context.Context, *bindings.ReadResponse) ([]byte, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+
+		assert.EventuallyWithT(t, func(c *assert.CollectT) {
+			assert.Equal(c, []listResponse{{FileName: "a.txt"}}, list(c, s, "/done"))
+		}, 5*time.Second, 50*time.Millisecond)
+		assert.Equal(t, []listResponse{{FileName: "processing", IsDirectory: true}}, list(t, s, "/in"))
+	})
+
+	t.Run("files are delivered by one instance", func(t *testing.T) {
+		props := map[string]string{
+			"rootPath":     "/shared",
+			"pollInterval": "10ms",
+		}
+		s1 := initBinding(t, props)
+		s2 := initBinding(t, props)
+		for _, name := range []string{"a.txt", "b.txt", "c.txt", "d.txt", "e.txt"} {
+			upload(t, s1, name, name)
+		}
+
+		received := make(chan string, 10)
+		handler := func(_ context.Context, res *bindings.ReadResponse) ([]byte, error) {
+			received <- res.Metadata[metadataFileName]
+			return nil, nil
+		}
+		require.NoError(t, s1.Read(t.Context(), handler))
+		require.NoError(t, s2.Read(t.Context(), handler))
+
+		assert.EventuallyWithT(t, func(c *assert.CollectT) {
+			assert.Len(c, list(c, s1, "/shared/processed"), 5)
+		}, 5*time.Second, 50*time.Millisecond)
+		time.Sleep(100 * time.Millisecond)
+		assert.Len(t, received, 5)
+	})
+
+	t.Run("files that can't be moved are not delivered again", func(t *testing.T) {
+		// The processed path is the claimed file, so files can't be moved into it
+		s := initBinding(t, map[string]string{
+			"rootPath":      "/stuck",
+			"pollInterval":  "10ms",
+			"processedPath": "/stuck/processing/a.txt",
+		})
+		upload(t, s, "a.txt", "hello")
+
+		received := make(chan string, 10)
+		err := s.Read(t.Context(), func(_ context.Context, res *bindings.ReadResponse) ([]byte, error) {
+			received <- res.Metadata[metadataFileName]
+			return nil, nil
+		})
+		require.NoError(t, err)
+
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for file")
+		}
+		time.Sleep(100 * time.Millisecond)
+		assert.Empty(t, received)
+		assert.Equal(t, []listResponse{{FileName: "a.txt"}}, list(t, s, "/stuck/processing"))
+	})
+
+	t.Run("closed binding", func(t *testing.T) {
+		s := initBinding(t, map[string]string{"rootPath": "/closed"})
+		require.NoError(t, s.Close())
+		require.Error(t, s.Read(t.Context(), nil))
+	})
+}
+
+func TestParseInputMetadata(t *testing.T) {
+	s := Sftp{}
+	m, err := s.parseMetadata(bindings.Metadata{Base: metadata.Base{Properties: map[string]string{"rootPath": "/root"}}})
+	require.NoError(t, err)
+	assert.Equal(t, defaultPollInterval, m.PollInterval)
+	assert.Equal(t, "/root/processed/a.txt", m.targetPath(m.ProcessedPath, "a.txt"))
+	assert.Equal(t, "/root/processing/a.txt", m.targetPath(m.ProcessingPath, "a.txt"))
+
+	m, err = s.parseMetadata(bindings.Metadata{Base: metadata.Base{Properties: map[string]string{
+		"rootPath":   "/root",
+		"failedPath": "errors",
+		"minFileAge": "1m",
+	}}})
+	require.NoError(t, err)
+	assert.Equal(t, "/root/errors/a.txt", m.targetPath(m.FailedPath, "a.txt"))
+	assert.Equal(t, time.Minute, m.MinFileAge)
+
+	_, err = s.parseMetadata(bindings.Metadata{Base: metadata.Base{Properties: map[string]string{"pollInterval": "0s"}}})
+	require.Error(t, err)
+	_, err = s.parseMetadata(bindings.Metadata{Base: metadata.Base{Properties: map[string]string{"minFileAge": "-1s"}}})
+	require.Error(t, err)
+}
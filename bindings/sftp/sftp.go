@@ -19,6 +19,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	sftpClient "github.com/pkg/sftp"
 	"golang.org/x/crypto/ssh"
@@ -33,6 +36,11 @@ import (
 const (
 	metadataRootPath = "rootPath"
 	metadataFileName = "fileName"
+
+	defaultPollInterval   = 10 * time.Second
+	defaultProcessingPath = "processing"
+	defaultProcessedPath  = "processed"
+	defaultFailedPath     = "failed"
 )
 
 // Sftp is a binding for file operations on sftp server.
@@ -40,6 +48,9 @@ type Sftp struct {
 	metadata *sftpMetadata
 	logger   logger.Logger
 	c        *Client
+	closed   atomic.Bool
+	closeCh  chan struct{}
+	wg       sync.WaitGroup
 }
 
 // sftpMetadata defines the sftp metadata.
@@ -54,6 +65,13 @@ type sftpMetadata struct {
 	KnownHostsFile        string `json:"knownHostsFile"`
 	InsecureIgnoreHostKey bool   `json:"insecureIgnoreHostKey"`
 	SequentialMode        bool   `json:"sequentialMode"`
+
+	// Properties of the input binding
+	PollInterval   time.Duration `json:"pollInterval"`
+	ProcessingPath string        `json:"processingPath"`
+	ProcessedPath  string        `json:"processedPath"`
+	FailedPath     string        `json:"failedPath"`
+	MinFileAge     time.Duration `json:"minFileAge"`
 }
 
 type createResponse struct {
@@ -65,8 +83,11 @@ type listResponse struct {
 	IsDirectory bool   `json:"isDirectory"`
 }
 
-func NewSftp(logger logger.Logger) bindings.OutputBinding {
-	return &Sftp{logger: logger}
+func NewSftp(logger logger.Logger) bindings.InputOutputBinding {
+	return &Sftp{
+		logger:  logger,
+		closeCh: make(chan struct{}),
+	}
 }
 
 func (sftp *Sftp) Init(_ context.Context, metadata bindings.Metadata) error {
@@ -138,12 +159,24 @@ func (sftp *Sftp) Init(_ context.Context, metadata bindings.Metadata) error {
 }
 
 func (sftp *Sftp) parseMetadata(meta bindings.Metadata) (*sftpMetadata, error) {
-	var m sftpMetadata
+	m := sftpMetadata{
+		PollInterval:   defaultPollInterval,
+		ProcessingPath: defaultProcessingPath,
+		ProcessedPath:  defaultProcessedPath,
+		FailedPath:     defaultFailedPath,
+	}
 	err := kitmd.DecodeMetadata(meta.Properties, &m)
 	if err != nil {
 		return nil, err
 	}
 
+	if m.PollInterval <= 0 {
+		return nil, errors.New("property pollInterval must be greater than zero")
+	}
+	if m.MinFileAge < 0 {
+		return nil, errors.New("property minFileAge must not be negative")
+	}
+
 	return &m, nil
 }
 
@@ -282,6 +315,14 @@ func (sftp *Sftp) Invoke(ctx context.Context, req *bindings.InvokeRequest) (*bin
 }
 
 func (sftp *Sftp) Close() error {
+	if sftp.closed.CompareAndSwap(false, true) {
+		close(sftp.closeCh)
+	}
+	sftp.wg.Wait()
+
+	if sftp.c == nil {
+		return nil
+	}
 	return sftp.c.Close()
 }
 
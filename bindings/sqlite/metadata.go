@@ -0,0 +1,85 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	authSqlite "github.com/dapr/components-contrib/common/authentication/sqlite"
+	"github.com/dapr/kit/logger"
+	kitmd "github.com/dapr/kit/metadata"
+)
+
+type sqliteMetadata struct {
+	authSqlite.SqliteAuthMetadata `mapstructure:",squash"`
+
+	// ReadOnly rejects the operations that can change the database.
+	ReadOnly bool `mapstructure:"readOnly"`
+}
+
+func (m *sqliteMetadata) InitWithMetadata(meta map[string]string) error {
+	// Reset the object
+	m.reset()
+
+	// Decode the metadata
+	err := kitmd.DecodeMetadata(meta, &m)
+	if err != nil {
+		return err
+	}
+
+	// Validate and sanitize input
+	err = m.SqliteAuthMetadata.Validate()
+	if err != nil {
+		return err
+	}
+	if m.ReadOnly && m.IsInMemoryDB() {
+		return errors.New("invalid value for 'readOnly': in-memory databases can't be read-only")
+	}
+
+	return nil
+}
+
+// Reset the object
+func (m *sqliteMetadata) reset() {
+	m.SqliteAuthMetadata.Reset()
+
+	m.ReadOnly = false
+}
+
+// GetConnectionString returns the parsed connection string.
+// In read-only mode, the database is opened read-only, so no changes can be made to it even by the query operation.
+func (m *sqliteMetadata) GetConnectionString(log logger.Logger) (string, error) {
+	connString, err := m.SqliteAuthMetadata.GetConnectionString(log, authSqlite.GetConnectionStringOpts{})
+	if err != nil || !m.ReadOnly {
+		return connString, err
+	}
+
+	// Any mode set in the connection string is replaced
+	// The journal mode isn't set, as that requires writing to the database, which may be used by other processes
+	path, rawQuery, _ := strings.Cut(connString, "?")
+	qs, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", fmt.Errorf("invalid connection string: %w", err)
+	}
+	qs.Set("mode", "ro")
+	qs["_pragma"] = slices.DeleteFunc(qs["_pragma"], func(p string) bool {
+		return strings.HasPrefix(p, "journal_mode")
+	})
+
+	return path + "?" + qs.Encode(), nil
+}
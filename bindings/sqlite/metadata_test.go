@@ -0,0 +1,73 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlite
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authSqlite "github.com/dapr/components-contrib/common/authentication/sqlite"
+	"github.com/dapr/kit/logger"
+)
+
+func TestMetadata(t *testing.T) {
+	log := logger.NewLogger("test")
+
+	t.Run("defaults", func(t *testing.T) {
+		m := sqliteMetadata{}
+		err := m.InitWithMetadata(map[string]string{"connectionString": "data.db"})
+		require.NoError(t, err)
+		assert.Equal(t, authSqlite.DefaultTimeout, m.Timeout)
+		assert.False(t, m.ReadOnly)
+
+		connString, err := m.GetConnectionString(log)
+		require.NoError(t, err)
+		assert.NotContains(t, connString, "mode=")
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		m := sqliteMetadata{}
+		err := m.InitWithMetadata(map[string]string{
+			"connectionString": "file:data.db?mode=rwc",
+			"readOnly":         "true",
+			"timeout":          "5s",
+		})
+		require.NoError(t, err)
+		assert.True(t, m.ReadOnly)
+		assert.Equal(t, 5*time.Second, m.Timeout)
+
+		connString, err := m.GetConnectionString(log)
+		require.NoError(t, err)
+		path, rawQuery, _ := strings.Cut(connString, "?")
+		assert.Equal(t, "file:data.db", path)
+		qs, err := url.ParseQuery(rawQuery)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ro"}, qs["mode"])
+		for _, p := range qs["_pragma"] {
+			assert.NotContains(t, p, "journal_mode")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		m := sqliteMetadata{}
+		require.Error(t, m.InitWithMetadata(map[string]string{}))
+		require.Error(t, m.InitWithMetadata(map[string]string{"connectionString": "data.db", "timeout": "1ms"}))
+		require.Error(t, m.InitWithMetadata(map[string]string{"connectionString": ":memory:", "readOnly": "true"}))
+	})
+}
@@ -0,0 +1,380 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/components-contrib/common/component/sql/transactions"
+	"github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	// list of operations.
+	execOperation        bindings.OperationKind = "exec"
+	queryOperation       bindings.OperationKind = "query"
+	transactionOperation bindings.OperationKind = "transaction"
+	closeOperation       bindings.OperationKind = "close"
+
+	// keys from request's metadata.
+	commandSQLKey    = "sql"
+	commandParamsKey = "params"
+
+	// keys from response's metadata.
+	respOpKey           = "operation"
+	respSQLKey          = "sql"
+	respStartTimeKey    = "start-time"
+	respRowsAffectedKey = "rows-affected"
+	respEndTimeKey      = "end-time"
+	respDurationKey     = "duration"
+)
+
+// transactionStatement is a statement of the "transaction" operation.
+type transactionStatement struct {
+	SQL    string          `json:"sql"`
+	Params json.RawMessage `json:"params"`
+}
+
+// transactionResult is the result of a statement of the "transaction" operation.
+type transactionResult struct {
+	RowsAffected int64 `json:"rowsAffected"`
+}
+
+// SQLite represents SQLite output bindings.
+type SQLite struct {
+	db       *sql.DB
+	metadata sqliteMetadata
+	logger   logger.Logger
+	closed   atomic.Bool
+}
+
+// NewSQLite returns a new SQLite output binding.
+func NewSQLite(logger logger.Logger) bindings.OutputBinding {
+	return &SQLite{
+		logger: logger,
+	}
+}
+
+// Init initializes the SQLite binding.
+func (s *SQLite) Init(ctx context.Context, md bindings.Metadata) error {
+	if s.closed.Load() {
+		return errors.New("cannot initialize a previously-closed component")
+	}
+
+	err := s.metadata.InitWithMetadata(md.Properties)
+	if err != nil {
+		return err
+	}
+
+	connString, err := s.metadata.GetConnectionString(s.logger)
+	if err != nil {
+		// Already logged
+		return err
+	}
+
+	s.db, err = sql.Open("sqlite", connString)
+	if err != nil {
+		return fmt.Errorf("failed to create connection: %w", err)
+	}
+
+	// If the database is in-memory, we can't have more than 1 open connection
+	if s.metadata.IsInMemoryDB() {
+		s.db.SetMaxOpenConns(1)
+	}
+
+	pingCtx, pingCancel := context.WithTimeout(ctx, s.metadata.Timeout)
+	defer pingCancel()
+	err = s.db.PingContext(pingCtx)
+	if err != nil {
+		return fmt.Errorf("failed to ping the DB: %w", err)
+	}
+
+	return nil
+}
+
+// Operations returns list of operations supported by SQLite binding.
+func (s *SQLite) Operations() []bindings.OperationKind {
+	return []bindings.OperationKind{
+		execOperation,
+		queryOperation,
+		transactionOperation,
+		closeOperation,
+	}
+}
+
+// Invoke handles all invoke operations.
+func (s *SQLite) Invoke(ctx context.Context, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	if req == nil {
+		return nil, errors.New("invoke request required")
+	}
+
+	// We let the "close" operation here succeed even if the component has been closed already
+	if req.Operation == closeOperation {
+		return nil, s.Close()
+	}
+
+	if s.closed.Load() {
+		return nil, errors.New("component is closed")
+	}
+
+	switch req.Operation { //nolint:exhaustive
+	case execOperation, transactionOperation:
+		if s.metadata.ReadOnly {
+			return nil, fmt.Errorf("operation %s is not allowed: the binding is read-only", req.Operation)
+		}
+	case queryOperation:
+	default:
+		return nil, fmt.Errorf("invalid operation type: %s. Expected %s, %s, %s, or %s",
+			req.Operation, execOperation, queryOperation, transactionOperation, closeOperation)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.metadata.Timeout)
+	defer cancel()
+
+	// The statements of the "transaction" operation are in the request data
+	if req.Operation == transactionOperation {
+		return s.transaction(ctx, req.Data)
+	}
+
+	if req.Metadata == nil {
+		return nil, errors.New("metadata required")
+	}
+
+	query := req.Metadata[commandSQLKey]
+	if query == "" {
+		return nil, fmt.Errorf("required metadata not set: %s", commandSQLKey)
+	}
+
+	// Metadata property "params" contains JSON-encoded parameters, and it's optional
+	// If present, it must be unserializable into a []any object
+	params, err := parseParams([]byte(req.Metadata[commandParamsKey]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata property %s: %w", commandParamsKey, err)
+	}
+
+	startTime := time.Now().UTC()
+	resp := &bindings.InvokeResponse{
+		Metadata: map[string]string{
+			respOpKey:        string(req.Operation),
+			respSQLKey:       query,
+			respStartTimeKey: startTime.Format(time.RFC3339Nano),
+		},
+	}
+
+	if req.Operation == execOperation {
+		r, err := s.exec(ctx, query, params...)
+		if err != nil {
+			return nil, err
+		}
+		resp.Metadata[respRowsAffectedKey] = strconv.FormatInt(r, 10)
+	} else {
+		d, err := s.query(ctx, query, params...)
+		if err != nil {
+			return nil, err
+		}
+		resp.Data = d
+	}
+
+	endTime := time.Now().UTC()
+	resp.Metadata[respEndTimeKey] = endTime.Format(time.RFC3339Nano)
+	resp.Metadata[respDurationKey] = endTime.Sub(startTime).String()
+
+	return resp, nil
+}
+
+// Close will close the DB.
+func (s *SQLite) Close() error {
+	if !s.closed.CompareAndSwap(false, true) {
+		// If this failed, the component has already been closed
+		// We allow multiple calls to close
+		return nil
+	}
+
+	if s.db == nil {
+		return nil
+	}
+	err := s.db.Close()
+	s.db = nil
+	return err
+}
+
+func (s *SQLite) query(ctx context.Context, query string, params ...any) ([]byte, error) {
+	rows, err := s.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	result, err := jsonify(rows)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling query result for query: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *SQLite) exec(ctx context.Context, query string, params ...any) (int64, error) {
+	res, err := s.db.ExecContext(ctx, query, params...)
+	if err != nil {
+		return 0, fmt.Errorf("error executing query: %w", err)
+	}
+
+	return res.RowsAffected()
+}
+
+// transaction executes the statements in the request data in a single transaction.
+// If any statement fails, the transaction is rolled back and no changes are made.
+func (s *SQLite) transaction(ctx context.Context, data []byte) (*bindings.InvokeResponse, error) {
+	var statements []transactionStatement
+	err := json.Unmarshal(data, &statements)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request data: failed to unserialize into an array of statements: %w", err)
+	}
+	if len(statements) == 0 {
+		return nil, errors.New("invalid request data: at least one statement is required")
+	}
+	params := make([][]any, len(statements))
+	for i, st := range statements {
+		if st.SQL == "" {
+			return nil, fmt.Errorf("invalid request data: statement %d is missing property %s", i, commandSQLKey)
+		}
+		params[i], err = parseParams(st.Params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request data: invalid property %s of statement %d: %w", commandParamsKey, i, err)
+		}
+	}
+
+	startTime := time.Now().UTC()
+	results, err := transactions.ExecuteInTransaction(ctx, s.logger, s.db, func(ctx context.Context, tx *sql.Tx) ([]transactionResult, error) {
+		results := make([]transactionResult, len(statements))
+		for i, st := range statements {
+			res, err := tx.ExecContext(ctx, st.SQL, params[i]...)
+			if err != nil {
+				return nil, fmt.Errorf("error executing statement %d: %w", i, err)
+			}
+			results[i].RowsAffected, err = res.RowsAffected()
+			if err != nil {
+				return nil, fmt.Errorf("error reading rows affected by statement %d: %w", i, err)
+			}
+		}
+		return results, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rowsAffected int64
+	for _, r := range results {
+		rowsAffected += r.RowsAffected
+	}
+	resData, err := json.Marshal(results)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing results: %w", err)
+	}
+
+	endTime := time.Now().UTC()
+	return &bindings.InvokeResponse{
+		Data: resData,
+		Metadata: map[string]string{
+			respOpKey:           string(transactionOperation),
+			respRowsAffectedKey: strconv.FormatInt(rowsAffected, 10),
+			respStartTimeKey:    startTime.Format(time.RFC3339Nano),
+			respEndTimeKey:      endTime.Format(time.RFC3339Nano),
+			respDurationKey:     endTime.Sub(startTime).String(),
+		},
+	}, nil
+}
+
+// parseParams parses JSON-encoded parameters, which are optional.
+// Whole numbers are passed as integers, so they are stored as such in columns without a numeric affinity.
+func parseParams(data []byte) ([]any, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var params []any
+	err := dec.Decode(&params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unserialize into an array: %w", err)
+	}
+
+	for i, p := range params {
+		n, ok := p.(json.Number)
+		if !ok {
+			continue
+		}
+		if v, err := n.Int64(); err == nil {
+			params[i] = v
+		} else if v, err := n.Float64(); err == nil {
+			params[i] = v
+		} else {
+			return nil, fmt.Errorf("invalid number %s", n)
+		}
+	}
+
+	return params, nil
+}
+
+// jsonify serializes the rows as an array of objects, with the values of each row keyed by column name.
+func jsonify(rows *sql.Rows) ([]byte, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]map[string]any, 0)
+	for rows.Next() {
+		values := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		err = rows.Scan(ptrs...)
+		if err != nil {
+			return nil, err
+		}
+
+		r := make(map[string]any, len(columns))
+		for i, c := range columns {
+			r[c] = values[i]
+		}
+		ret = append(ret, r)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(ret)
+}
+
+// GetComponentMetadata returns the metadata of the component.
+func (s *SQLite) GetComponentMetadata() (metadataInfo metadata.MetadataMap) {
+	metadataStruct := sqliteMetadata{}
+	metadata.GetMetadataInfoFromStructType(reflect.TypeOf(metadataStruct), &metadataInfo, metadata.BindingType)
+	return
+}
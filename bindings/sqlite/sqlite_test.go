@@ -0,0 +1,214 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/kit/logger"
+)
+
+func initBinding(t *testing.T, props map[string]string) *SQLite {
+	t.Helper()
+
+	s := NewSQLite(logger.NewLogger("test")).(*SQLite)
+	t.Cleanup(func() {
+		s.Close()
+	})
+	err := s.Init(t.Context(), bindings.Metadata{Base: metadata.Base{Properties: props}})
+	require.NoError(t, err)
+	return s
+}
+
+func TestOperations(t *testing.T) {
+	b := NewSQLite(logger.NewLogger("test"))
+	l := b.Operations()
+	assert.Len(t, l, 4)
+	assert.Contains(t, l, execOperation)
+	assert.Contains(t, l, queryOperation)
+	assert.Contains(t, l, transactionOperation)
+	assert.Contains(t, l, closeOperation)
+}
+
+func TestInvoke(t *testing.T) {
+	connString := filepath.Join(t.TempDir(), "test.db")
+	s := initBinding(t, map[string]string{"connectionString": connString})
+
+	exec := func(t *testing.T, sql string, params string) *bindings.InvokeResponse {
+		t.Helper()
+
+		res, err := s.Invoke(t.Context(), &bindings.InvokeRequest{
+			Operation: execOperation,
+			Metadata:  map[string]string{commandSQLKey: sql, commandParamsKey: params},
+		})
+		require.NoError(t, err)
+		return res
+	}
+	query := func(t *testing.T, sql string, params string) string {
+		t.Helper()
+
+		res, err := s.Invoke(t.Context(), &bindings.InvokeRequest{
+			Operation: queryOperation,
+			Metadata:  map[string]string{commandSQLKey: sql, commandParamsKey: params},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, sql, res.Metadata[respSQLKey])
+		assert.NotEmpty(t, res.Metadata[respDurationKey])
+		return string(res.Data)
+	}
+
+	exec(t, "CREATE TABLE foo (id INTEGER PRIMARY KEY, v1 TEXT NOT NULL, v2 REAL, v3 BLOB)", "")
+
+	t.Run("exec operation", func(t *testing.T) {
+		res := exec(t, "INSERT INTO foo (id, v1, v2, v3) VALUES (?, ?, ?, ?), (2, 'b', NULL, NULL)", `[1, "a", 1.5, null]`)
+		assert.Equal(t, "2", res.Metadata[respRowsAffectedKey])
+		assert.Equal(t, string(execOperation), res.Metadata[respOpKey])
+		assert.NotEmpty(t, res.Metadata[respDurationKey])
+	})
+
+	t.Run("query operation", func(t *testing.T) {
+		assert.JSONEq(t, `[{"id": 1, "v1": "a", "v2": 1.5, "v3": null}]`, query(t, "SELECT * FROM foo WHERE id = ?", "[1]"))
+		assert.JSONEq(t, `[]`, query(t, "SELECT * FROM foo WHERE id = 3", ""))
+
+		// Whole numbers are passed as integers
+		exec(t, "UPDATE foo SET v1 = ? WHERE id = 2", "[10]")
+		assert.JSONEq(t, `[{"v1": "10"}]`, query(t, "SELECT v1 FROM foo WHERE id = 2", ""))
+	})
+
+	t.Run("transaction operation", func(t *testing.T) {
+		res, err := s.Invoke(t.Context(), &bindings.InvokeRequest{
+			Operation: transactionOperation,
+			Data: []byte(`[
+				{"sql": "INSERT INTO foo (id, v1) VALUES (?, ?)", "params": [3, "c"]},
+				{"sql": "UPDATE foo SET v2 = 2.5"}
+			]`),
+		})
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"rowsAffected": 1}, {"rowsAffected": 3}]`, string(res.Data))
+		assert.Equal(t, "4", res.Metadata[respRowsAffectedKey])
+		assert.NotEmpty(t, res.Metadata[respDurationKey])
+
+		// Statements are rolled back if any fails
+		_, err = s.Invoke(t.Context(), &bindings.InvokeRequest{
+			Operation: transactionOperation,
+			Data: []byte(`[
+				{"sql": "DELETE FROM foo WHERE id = 3"},
+				{"sql": "INSERT INTO foo (id, v1) VALUES (1, 'duplicate')"}
+			]`),
+		})
+		require.ErrorContains(t, err, "error executing statement 1")
+		assert.JSONEq(t, `[{"id": 3}]`, query(t, "SELECT id FROM foo WHERE id = 3", ""))
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		reqs := map[string]*bindings.InvokeRequest{
+			"missing sql":             {Operation: execOperation, Metadata: map[string]string{}},
+			"invalid params":          {Operation: queryOperation, Metadata: map[string]string{commandSQLKey: "SELECT 1", commandParamsKey: "{}"}},
+			"invalid statement":       {Operation: execOperation, Metadata: map[string]string{commandSQLKey: "SELECT FROM"}},
+			"unsupported operation":   {Operation: "unsupported"},
+			"no statements":           {Operation: transactionOperation, Data: []byte(`[]`)},
+			"statement without sql":   {Operation: transactionOperation, Data: []byte(`[{"params": [1]}]`)},
+			"invalid transaction":     {Operation: transactionOperation, Data: []byte(`{}`)},
+			"invalid statement param": {Operation: transactionOperation, Data: []byte(`[{"sql": "SELECT 1", "params": 1}]`)},
+		}
+		for name, req := range reqs {
+			t.Run(name, func(t *testing.T) {
+				res, err := s.Invoke(t.Context(), req)
+				assert.Nil(t, res)
+				require.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("close operation", func(t *testing.T) {
+		_, err := s.Invoke(t.Context(), &bindings.InvokeRequest{Operation: closeOperation})
+		require.NoError(t, err)
+		_, err = s.Invoke(t.Context(), &bindings.InvokeRequest{
+			Operation: queryOperation,
+			Metadata:  map[string]string{commandSQLKey: "SELECT 1"},
+		})
+		require.ErrorContains(t, err, "closed")
+	})
+}
+
+func TestReadOnly(t *testing.T) {
+	connString := filepath.Join(t.TempDir(), "test.db")
+	rw := initBinding(t, map[string]string{"connectionString": connString})
+	_, err := rw.Invoke(t.Context(), &bindings.InvokeRequest{
+		Operation: transactionOperation,
+		Data:      []byte(`[{"sql": "CREATE TABLE foo (id INTEGER PRIMARY KEY)"}, {"sql": "INSERT INTO foo VALUES (1)"}]`),
+	})
+	require.NoError(t, err)
+
+	ro := initBinding(t, map[string]string{"connectionString": connString, "readOnly": "true"})
+
+	res, err := ro.Invoke(t.Context(), &bindings.InvokeRequest{
+		Operation: queryOperation,
+		Metadata:  map[string]string{commandSQLKey: "SELECT id FROM foo"},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id": 1}]`, string(res.Data))
+
+	// Operations that change the database are rejected
+	for _, op := range []bindings.OperationKind{execOperation, transactionOperation} {
+		_, err = ro.Invoke(t.Context(), &bindings.InvokeRequest{
+			Operation: op,
+			Data:      []byte(`[{"sql": "DELETE FROM foo"}]`),
+			Metadata:  map[string]string{commandSQLKey: "DELETE FROM foo"},
+		})
+		require.ErrorContains(t, err, "read-only")
+	}
+
+	// Changes made by queries are rejected by the database
+	_, err = ro.Invoke(t.Context(), &bindings.InvokeRequest{
+		Operation: queryOperation,
+		Metadata:  map[string]string{commandSQLKey: "DELETE FROM foo RETURNING id"},
+	})
+	require.ErrorContains(t, err, "readonly")
+
+	// The database is opened read-only, so changes are rejected even if the connection allows them
+	_, err = ro.Invoke(t.Context(), &bindings.InvokeRequest{
+		Operation: queryOperation,
+		Metadata:  map[string]string{commandSQLKey: "PRAGMA query_only = 0"},
+	})
+	require.NoError(t, err)
+	_, err = ro.Invoke(t.Context(), &bindings.InvokeRequest{
+		Operation: queryOperation,
+		Metadata:  map[string]string{commandSQLKey: "INSERT INTO foo VALUES (2) RETURNING id"},
+	})
+	require.ErrorContains(t, err, "readonly")
+
+	res, err = rw.Invoke(t.Context(), &bindings.InvokeRequest{
+		Operation: queryOperation,
+		Metadata:  map[string]string{commandSQLKey: "SELECT id FROM foo"},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id": 1}]`, string(res.Data))
+}
+
+func TestInMemory(t *testing.T) {
+	s := initBinding(t, map[string]string{"connectionString": ":memory:"})
+	res, err := s.Invoke(t.Context(), &bindings.InvokeRequest{
+		Operation: queryOperation,
+		Metadata:  map[string]string{commandSQLKey: "SELECT 1 AS one"},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"one": 1}]`, string(res.Data))
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/dapr/components-contrib/bindings"
 	azauth "github.com/dapr/components-contrib/common/authentication/azure"
 	"github.com/dapr/components-contrib/common/component/azure/blobstorage"
+	contribMetadata "github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/components-contrib/pubsub"
 	"github.com/dapr/kit/logger"
 	"github.com/dapr/kit/retry"
@@ -71,6 +72,8 @@ type SubscribeConfig struct {
 	MaxBulkSubAwaitDurationMs       int
 	CheckPointFrequencyPerPartition int
 	Handler                         HandlerFn
+	// StartPosition is the position to start from for the partitions without a stored checkpoint, if set
+	StartPosition *contribMetadata.StartPosition
 }
 
 // NewAzureEventHubs returns a new Azure Event hubs instance.
@@ -246,6 +249,15 @@ func (aeh *AzureEventHubs) Subscribe(subscribeCtx context.Context, config Subscr
 	}
 	topic := config.Topic
 
+	var startPosition *azeventhubs.StartPosition
+	if config.StartPosition != nil {
+		pos, err := newStartPosition(*config.StartPosition)
+		if err != nil {
+			return err
+		}
+		startPosition = &pos
+	}
+
 	// This component has built-in retries because Event Hubs doesn't support N/ACK for messages
 	retryHandler := func(ctx context.Context, events []*azeventhubs.ReceivedEventData) ([]HandlerResponseItem, error) {
 		b := aeh.backOffConfig.NewBackOffWithContext(ctx)
@@ -279,7 +291,7 @@ func (aeh *AzureEventHubs) Subscribe(subscribeCtx context.Context, config Subscr
 	go func() {
 		for {
 			// Get the processor client
-			processor, err := aeh.getProcessorForTopic(subscribeCtx, topic)
+			processor, err := aeh.getProcessorForTopic(subscribeCtx, topic, startPosition)
 			if err != nil {
 				aeh.logger.Errorf("error trying to establish a connection: %w", err)
 			} else {
@@ -515,7 +527,8 @@ func (aeh *AzureEventHubs) getProducerClientForTopic(ctx context.Context, topic
 }
 
 // Creates a processor for a given topic.
-func (aeh *AzureEventHubs) getProcessorForTopic(ctx context.Context, topic string) (*azeventhubs.Processor, error) {
+// If startPosition is set, partitions without a stored checkpoint start from it.
+func (aeh *AzureEventHubs) getProcessorForTopic(ctx context.Context, topic string, startPosition *azeventhubs.StartPosition) (*azeventhubs.Processor, error) {
 	// Get the checkpoint store
 	checkpointStore, err := aeh.getCheckpointStore(ctx)
 	if err != nil {
@@ -572,7 +585,15 @@ func (aeh *AzureEventHubs) getProcessorForTopic(ctx context.Context, topic strin
 	}
 
 	// Create the processor from the consumer client and checkpoint store
-	processor, err := azeventhubs.NewProcessor(consumerClient, checkpointStore, nil)
+	var processorOpts *azeventhubs.ProcessorOptions
+	if startPosition != nil {
+		processorOpts = &azeventhubs.ProcessorOptions{
+			StartPositions: azeventhubs.StartPositions{
+				Default: *startPosition,
+			},
+		}
+	}
+	processor, err := azeventhubs.NewProcessor(consumerClient, checkpointStore, processorOpts)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create the processor: %w", err)
 	}
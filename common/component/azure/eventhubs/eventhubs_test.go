@@ -15,11 +15,15 @@ package eventhubs
 
 import (
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/kit/logger"
+	"github.com/dapr/kit/ptr"
 )
 
 var testLogger = logger.NewLogger("test")
@@ -136,3 +140,22 @@ func TestConstructConnectionStringFromTopic(t *testing.T) {
 		assert.Equal(t, "", c)
 	})
 }
+
+func TestNewStartPosition(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	pos, err := newStartPosition(metadata.StartPosition{From: metadata.StartFromEarliest})
+	require.NoError(t, err)
+	assert.Equal(t, azeventhubs.StartPosition{Earliest: ptr.Of(true)}, pos)
+
+	pos, err = newStartPosition(metadata.StartPosition{From: metadata.StartFromTimestamp, Timestamp: ts})
+	require.NoError(t, err)
+	assert.Equal(t, azeventhubs.StartPosition{EnqueuedTime: &ts, Inclusive: true}, pos)
+
+	pos, err = newStartPosition(metadata.StartPosition{From: metadata.StartFromOffset, Offset: "42"})
+	require.NoError(t, err)
+	assert.Equal(t, azeventhubs.StartPosition{SequenceNumber: ptr.Of(int64(42)), Inclusive: true}, pos)
+
+	_, err = newStartPosition(metadata.StartPosition{From: metadata.StartFromOffset, Offset: "first"})
+	require.Error(t, err)
+}
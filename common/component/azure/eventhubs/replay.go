@@ -0,0 +1,50 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventhubs
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs"
+
+	"github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/kit/ptr"
+)
+
+// newStartPosition returns the position of the partitions that don't have a checkpoint yet.
+// Partitions with a checkpoint resume from it, so restarting or scaling out the subscribers doesn't deliver events again.
+// Offsets are sequence numbers, and the event with that sequence number is received too.
+func newStartPosition(pos metadata.StartPosition) (azeventhubs.StartPosition, error) {
+	var res azeventhubs.StartPosition
+	switch pos.From {
+	case metadata.StartFromEarliest:
+		res.Earliest = ptr.Of(true)
+	case metadata.StartFromLatest:
+		res.Latest = ptr.Of(true)
+	case metadata.StartFromTimestamp:
+		res.EnqueuedTime = ptr.Of(pos.Timestamp)
+		res.Inclusive = true
+	case metadata.StartFromOffset:
+		seq, err := strconv.ParseInt(pos.Offset, 10, 64)
+		if err != nil || seq < 0 {
+			return res, fmt.Errorf("%s value must be a sequence number: actual is '%s'", metadata.StartOffsetMetadataKey, pos.Offset)
+		}
+		res.SequenceNumber = ptr.Of(seq)
+		res.Inclusive = true
+	default:
+		return res, fmt.Errorf("unsupported start position '%s'", pos.From)
+	}
+	return res, nil
+}
@@ -248,9 +248,12 @@ func (c *Client) EnsureTopic(ctx context.Context, topic string) error {
 type SubscribeOptions struct {
 	RequireSessions      bool
 	MaxConcurrentSesions int
+	// Topic where dead-lettered messages are forwarded to, if any.
+	DeadLetterTopic string
 }
 
 // EnsureSubscription creates the topic subscription if it doesn't exist.
+// If the subscription has a dead-letter topic, existing subscriptions are updated to forward dead-lettered messages to it.
 // Returns with nil error if the admin client doesn't exist.
 func (c *Client) EnsureSubscription(ctx context.Context, name string, topic string, opts SubscribeOptions) error {
 	if c.adminClient == nil {
@@ -262,21 +265,48 @@ func (c *Client) EnsureSubscription(ctx context.Context, name string, topic stri
 		return err
 	}
 
-	shouldCreate, err := c.shouldCreateSubscription(ctx, topic, name, opts)
+	// The topic where dead-lettered messages are forwarded must exist before the subscription is created
+	if opts.DeadLetterTopic != "" {
+		err = c.EnsureTopic(ctx, opts.DeadLetterTopic)
+		if err != nil {
+			return err
+		}
+	}
+
+	existing, err := c.getSubscription(ctx, topic, name, opts)
 	if err != nil {
 		return err
 	}
 
-	if shouldCreate {
+	switch {
+	case existing == nil:
 		err = c.createSubscription(ctx, topic, name, opts)
 		if err != nil {
 			return err
 		}
+	case opts.DeadLetterTopic != "" && !forwardsTo(existing.ForwardDeadLetteredMessagesTo, opts.DeadLetterTopic):
+		// Subscriptions created before the dead-letter topic was set, or with a different one, are updated to forward to it
+		existing.ForwardDeadLetteredMessagesTo = &opts.DeadLetterTopic
+		err = c.updateSubscription(ctx, topic, name, *existing)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// forwardsTo returns true if the forwarding target of an entity is the queue or topic with the given name.
+// Service Bus returns forwarding targets as the URL of the entity.
+func forwardsTo(target *string, name string) bool {
+	if target == nil {
+		return false
+	}
+	t := strings.ToLower(strings.TrimSuffix(*target, "/"))
+	name = strings.ToLower(name)
+	return t == name || strings.HasSuffix(t, "/"+name)
+}
+
 // EnsureTopic creates the queue if it doesn't exist.
 // Returns with nil error if the admin client doesn't exist.
 func (c *Client) EnsureQueue(ctx context.Context, queue string) error {
@@ -325,24 +355,25 @@ func (c *Client) createTopic(parentCtx context.Context, topic string) error {
 	return nil
 }
 
-func (c *Client) shouldCreateSubscription(parentCtx context.Context, topic, subscription string, opts SubscribeOptions) (bool, error) {
+// getSubscription returns the properties of a subscription, or nil if it doesn't exist.
+func (c *Client) getSubscription(parentCtx context.Context, topic, subscription string, opts SubscribeOptions) (*sbadmin.SubscriptionProperties, error) {
 	ctx, cancel := context.WithTimeout(parentCtx, time.Second*time.Duration(c.metadata.TimeoutInSec))
 	defer cancel()
 
 	res, err := c.adminClient.GetSubscription(ctx, topic, subscription, nil)
 	if err != nil {
-		return false, fmt.Errorf("could not get subscription %s: %w", subscription, err)
+		return nil, fmt.Errorf("could not get subscription %s: %w", subscription, err)
 	}
 	if res == nil {
 		// If res is nil, the subscription does not exist
-		return true, nil
+		return nil, nil
 	}
 
 	if notEqual(res.RequiresSession, &opts.RequireSessions) {
-		return false, fmt.Errorf("subscription %s already exists but session requirement doesn't match", subscription)
+		return nil, fmt.Errorf("subscription %s already exists but session requirement doesn't match", subscription)
 	}
 
-	return false, nil
+	return &res.SubscriptionProperties, nil
 }
 
 func (c *Client) createSubscription(parentCtx context.Context, topic, subscription string, opts SubscribeOptions) error {
@@ -358,6 +389,17 @@ func (c *Client) createSubscription(parentCtx context.Context, topic, subscripti
 	return nil
 }
 
+func (c *Client) updateSubscription(parentCtx context.Context, topic, subscription string, properties sbadmin.SubscriptionProperties) error {
+	ctx, cancel := context.WithTimeout(parentCtx, time.Second*time.Duration(c.metadata.TimeoutInSec))
+	defer cancel()
+
+	_, err := c.adminClient.UpdateSubscription(ctx, topic, subscription, properties, nil)
+	if err != nil {
+		return fmt.Errorf("could not update subscription %s: %w", subscription, err)
+	}
+	return nil
+}
+
 func (c *Client) shouldCreateQueue(parentCtx context.Context, queue string) (bool, error) {
 	ctx, cancel := context.WithTimeout(parentCtx, time.Second*time.Duration(c.metadata.TimeoutInSec))
 	defer cancel()
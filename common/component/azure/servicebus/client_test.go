@@ -0,0 +1,32 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/kit/ptr"
+)
+
+func TestForwardsTo(t *testing.T) {
+	assert.False(t, forwardsTo(nil, "poison"))
+	assert.False(t, forwardsTo(ptr.Of(""), "poison"))
+	assert.True(t, forwardsTo(ptr.Of("poison"), "poison"))
+	assert.True(t, forwardsTo(ptr.Of("https://myns.servicebus.windows.net/Poison"), "poison"))
+	assert.True(t, forwardsTo(ptr.Of("https://myns.servicebus.windows.net/poison/"), "poison"))
+	assert.False(t, forwardsTo(ptr.Of("https://myns.servicebus.windows.net/other"), "poison"))
+	assert.False(t, forwardsTo(ptr.Of("https://myns.servicebus.windows.net/notpoison"), "poison"))
+}
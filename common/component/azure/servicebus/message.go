@@ -128,6 +128,15 @@ func addMetadataToMessage(asbMsg *azservicebus.Message, metadata map[string]stri
 			if ok {
 				asbMsg.TimeToLive = &ttl
 			}
+		case mdutils.DeliverAtMetadataKey, mdutils.DeliverAfterMetadataKey:
+			deliverAt, ok, err := mdutils.TryGetDeliveryTime(metadata, time.Now())
+			if err != nil {
+				return err
+			}
+			// ScheduledEnqueueTimeUtc takes precedence, if set
+			if ok && asbMsg.ScheduledEnqueueTime == nil {
+				asbMsg.ScheduledEnqueueTime = &deliverAt
+			}
 
 		// Keys with aliases
 		case MessageKeyMessageID, MessageKeyMessageIDAlias:
@@ -22,6 +22,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	azservicebus "github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+
+	mdutils "github.com/dapr/components-contrib/metadata"
 )
 
 var (
@@ -77,6 +79,34 @@ func TestAddMetadataToMessage(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "Schedules messages with the deliverAt metadata key.",
+			metadata: map[string]string{
+				mdutils.DeliverAtMetadataKey: testSampleTime.Format(time.RFC3339),
+			},
+			expectedAzServiceBusMessage: azservicebus.Message{
+				ScheduledEnqueueTime: &testSampleTime,
+			},
+			expectError: false,
+		},
+		{
+			name: "ScheduledEnqueueTimeUtc takes precedence over deliverAfter.",
+			metadata: map[string]string{
+				mdutils.DeliverAfterMetadataKey:   "1h",
+				MessageKeyScheduledEnqueueTimeUtc: testScheduledEnqueueTimeUtc,
+			},
+			expectedAzServiceBusMessage: azservicebus.Message{
+				ScheduledEnqueueTime: &nowUtc,
+			},
+			expectError: false,
+		},
+		{
+			name: "Errors when the delivery time is invalid.",
+			metadata: map[string]string{
+				mdutils.DeliverAfterMetadataKey: "later",
+			},
+			expectError: true,
+		},
 		{
 			name: "Errors when partition key and session id set but not equal.",
 			metadata: map[string]string{
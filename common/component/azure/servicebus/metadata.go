@@ -235,6 +235,10 @@ func (a Metadata) CreateSubscriptionProperties(opts SubscribeOptions) *sbadmin.S
 		properties.RequiresSession = ptr.Of(true)
 	}
 
+	if opts.DeadLetterTopic != "" {
+		properties.ForwardDeadLetteredMessagesTo = ptr.Of(opts.DeadLetterTopic)
+	}
+
 	return properties
 }
 
@@ -1,6 +1,7 @@
 package kafka
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/IBM/sarama"
@@ -68,3 +69,61 @@ func (k *Kafka) latestClients() (*clients, error) {
 		return k.clients, nil
 	}
 }
+
+// offsetGetter looks up the offsets of partitions, and the offsets committed by the consumer group.
+type offsetGetter interface {
+	GetOffset(topic string, partitionID int32, time int64) (int64, error)
+	// GetCommittedOffsets returns the offsets committed by the consumer group for partitions of a topic, which are -1 for partitions without a committed offset.
+	GetCommittedOffsets(topic string, partitions []int32) (map[int32]int64, error)
+}
+
+// saramaOffsetGetter implements offsetGetter with a sarama.Client.
+type saramaOffsetGetter struct {
+	sarama.Client
+	group string
+}
+
+func (g saramaOffsetGetter) GetCommittedOffsets(topic string, partitions []int32) (map[int32]int64, error) {
+	coordinator, err := g.Coordinator(g.group)
+	if err != nil {
+		return nil, err
+	}
+	res, err := coordinator.FetchOffset(sarama.NewOffsetFetchRequest(g.Config().Version, g.group, map[string][]int32{topic: partitions}))
+	if err != nil {
+		return nil, err
+	}
+	if !errors.Is(res.Err, sarama.ErrNoError) {
+		return nil, res.Err
+	}
+
+	offsets := make(map[int32]int64, len(partitions))
+	for _, partition := range partitions {
+		offsets[partition] = -1
+		block := res.GetBlock(topic, partition)
+		if block == nil {
+			continue
+		}
+		if !errors.Is(block.Err, sarama.ErrNoError) {
+			return nil, block.Err
+		}
+		offsets[partition] = block.Offset
+	}
+	return offsets, nil
+}
+
+// latestOffsetGetter returns a client to look up offsets, and a function to close it.
+func (k *Kafka) latestOffsetGetter() (offsetGetter, func(), error) {
+	if k.mockOffsetGetter != nil {
+		return k.mockOffsetGetter, func() {}, nil
+	}
+
+	client, err := sarama.NewClient(k.brokers, k.config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return saramaOffsetGetter{Client: client, group: k.consumerGroup}, func() {
+		if err := client.Close(); err != nil {
+			k.logger.Warnf("failed to close Kafka client: %v", err)
+		}
+	}, nil
+}
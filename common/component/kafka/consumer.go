@@ -25,6 +25,7 @@ import (
 	"github.com/IBM/sarama"
 	"github.com/cenkalti/backoff/v4"
 
+	contribMetadata "github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/kit/retry"
 )
 
@@ -154,6 +155,10 @@ func (consumer *consumer) doBulkCallback(session sarama.ConsumerGroupSession,
 			if err != nil {
 				return err
 			}
+			err = consumer.k.setDeserializedKey(metadata, message, handlerConfig)
+			if err != nil {
+				return err
+			}
 			childMessage := KafkaBulkMessageEntry{
 				EntryId:  strconv.Itoa(i),
 				Event:    messageVal,
@@ -206,6 +211,10 @@ func (consumer *consumer) doCallback(session sarama.ConsumerGroupSession, messag
 		Data:  messageVal,
 	}
 	event.Metadata = GetEventMetadata(message, consumer.k)
+	err = consumer.k.setDeserializedKey(event.Metadata, message, handlerConfig)
+	if err != nil {
+		return err
+	}
 
 	err = handlerConfig.Handler(session.Context(), &event)
 	if err == nil {
@@ -245,14 +254,119 @@ func GetEventMetadata(message *sarama.ConsumerMessage, kafka *Kafka) map[string]
 	return nil
 }
 
+// setDeserializedKey replaces the key in the metadata of an event with the key decoded with the key schema, if the subscription sets a key schema type.
+func (k *Kafka) setDeserializedKey(metadata map[string]string, message *sarama.ConsumerMessage, handlerConfig SubscriptionHandlerConfig) error {
+	if handlerConfig.KeySchemaType == None || message.Key == nil {
+		return nil
+	}
+	key, err := k.DeserializeKey(message, handlerConfig)
+	if err != nil {
+		return err
+	}
+	if k.escapeHeaders {
+		metadata[keyMetadataKey] = url.QueryEscape(string(key))
+	} else {
+		metadata[keyMetadataKey] = string(key)
+	}
+	return nil
+}
+
 func (consumer *consumer) Cleanup(sarama.ConsumerGroupSession) error {
 	return nil
 }
 
-func (consumer *consumer) Setup(sarama.ConsumerGroupSession) error {
+func (consumer *consumer) Setup(session sarama.ConsumerGroupSession) error {
+	return consumer.k.applyStartPositions(session)
+}
+
+// applyStartPositions moves the offsets of the claimed partitions of new subscriptions to their start position.
+// Start positions are applied only to partitions without an offset committed by the consumer group, so when the app restarts or scales out, partitions resume from the committed offset.
+// They are also applied once per subscription, so partitions claimed after a rebalance resume from the committed offset.
+func (k *Kafka) applyStartPositions(session sarama.ConsumerGroupSession) error {
+	k.startPositionsLock.Lock()
+	defer k.startPositionsLock.Unlock()
+
+	if len(k.pendingStartPositions) == 0 {
+		return nil
+	}
+
+	var (
+		getter  offsetGetter
+		applied bool
+	)
+	for topic, partitions := range session.Claims() {
+		pos, ok := k.pendingStartPositions[topic]
+		if !ok || len(partitions) == 0 {
+			continue
+		}
+
+		if getter == nil {
+			var (
+				closeGetter func()
+				err         error
+			)
+			getter, closeGetter, err = k.latestOffsetGetter()
+			if err != nil {
+				return fmt.Errorf("failed to create Kafka client to look up offsets: %w", err)
+			}
+			defer closeGetter()
+		}
+
+		committed, err := getter.GetCommittedOffsets(topic, partitions)
+		if err != nil {
+			return fmt.Errorf("failed to get committed offsets of topic %s: %w", topic, err)
+		}
+
+		started := false
+		for _, partition := range partitions {
+			if committed[partition] >= 0 {
+				k.logger.Infof("Partition %d of topic %s resumes from the offset committed by the consumer group instead of starting from %s", partition, topic, pos.From)
+				continue
+			}
+
+			offset, err := getStartOffset(getter, topic, partition, pos)
+			if err != nil {
+				return fmt.Errorf("failed to get start offset of partition %d of topic %s: %w", partition, topic, err)
+			}
+			// ResetOffset only moves the offset backwards, and MarkOffset only moves it forwards
+			session.ResetOffset(topic, partition, offset, "")
+			session.MarkOffset(topic, partition, offset, "")
+			started = true
+		}
+
+		if started {
+			k.logger.Infof("Subscription to topic %s starts from %s", topic, pos.From)
+			applied = true
+		}
+		delete(k.pendingStartPositions, topic)
+	}
+
+	if applied {
+		session.Commit()
+	}
 	return nil
 }
 
+func getStartOffset(getter offsetGetter, topic string, partition int32, pos contribMetadata.StartPosition) (int64, error) {
+	switch pos.From {
+	case contribMetadata.StartFromEarliest:
+		return getter.GetOffset(topic, partition, sarama.OffsetOldest)
+	case contribMetadata.StartFromLatest:
+		return getter.GetOffset(topic, partition, sarama.OffsetNewest)
+	case contribMetadata.StartFromTimestamp:
+		offset, err := getter.GetOffset(topic, partition, pos.Timestamp.UnixMilli())
+		if err == nil && offset == sarama.OffsetNewest {
+			// No messages were published at or after the timestamp
+			return getter.GetOffset(topic, partition, sarama.OffsetNewest)
+		}
+		return offset, err
+	case contribMetadata.StartFromOffset:
+		return strconv.ParseInt(pos.Offset, 10, 64)
+	default:
+		return 0, fmt.Errorf("unsupported start position '%s'", pos.From)
+	}
+}
+
 // checkBulkSubscribe checks if a bulk handler and config are correctly registered for provided topic
 func (k *Kafka) checkBulkSubscribe(topic string) bool {
 	if bulkHandlerConfig, ok := k.subscribeTopics[topic]; ok &&
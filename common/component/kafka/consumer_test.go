@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/kit/logger"
 	"github.com/dapr/kit/retry"
 )
@@ -362,3 +363,144 @@ func Test_ConsumeClaim(t *testing.T) {
 		})
 	})
 }
+
+// mockOffsetGetter returns the offsets by time, and the offsets committed by the consumer group by topic and partition.
+type mockOffsetGetter struct {
+	offsets   map[int64]int64
+	committed map[string]map[int32]int64
+}
+
+func (m mockOffsetGetter) GetOffset(topic string, partitionID int32, time int64) (int64, error) {
+	offset, ok := m.offsets[time]
+	if !ok {
+		return 0, errors.New("unexpected time")
+	}
+	return offset + int64(partitionID), nil
+}
+
+func (m mockOffsetGetter) GetCommittedOffsets(topic string, partitions []int32) (map[int32]int64, error) {
+	res := make(map[int32]int64, len(partitions))
+	for _, partition := range partitions {
+		offset, ok := m.committed[topic][partition]
+		if !ok {
+			offset = -1
+		}
+		res[partition] = offset
+	}
+	return res, nil
+}
+
+func Test_applyStartPositions(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	getter := mockOffsetGetter{offsets: map[int64]int64{
+		sarama.OffsetOldest: 10,
+		sarama.OffsetNewest: 100,
+		ts.UnixMilli():      50,
+	}}
+
+	tests := []struct {
+		name     string
+		pos      metadata.StartPosition
+		expected int64
+	}{
+		{name: "earliest", pos: metadata.StartPosition{From: metadata.StartFromEarliest}, expected: 10},
+		{name: "latest", pos: metadata.StartPosition{From: metadata.StartFromLatest}, expected: 100},
+		{name: "timestamp", pos: metadata.StartPosition{From: metadata.StartFromTimestamp, Timestamp: ts}, expected: 50},
+		{name: "offset", pos: metadata.StartPosition{From: metadata.StartFromOffset, Offset: "7"}, expected: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := &Kafka{
+				logger:                logger.NewLogger("test"),
+				mockOffsetGetter:      getter,
+				pendingStartPositions: map[string]metadata.StartPosition{"replay": tt.pos},
+			}
+
+			session := &mockConsumerGroupSession{}
+			session.On("Claims").Return(map[string][]int32{"replay": {0}, "other": {0}})
+			session.On("ResetOffset", "replay", int32(0), tt.expected, "").Once()
+			session.On("MarkOffset", "replay", int32(0), tt.expected, "").Once()
+			session.On("Commit").Once()
+
+			require.NoError(t, k.applyStartPositions(session))
+			session.AssertExpectations(t)
+			assert.Empty(t, k.pendingStartPositions)
+
+			// Start positions are only applied once
+			require.NoError(t, k.applyStartPositions(session))
+			session.AssertExpectations(t)
+		})
+	}
+
+	t.Run("no messages after timestamp, start from latest", func(t *testing.T) {
+		k := &Kafka{
+			logger:                logger.NewLogger("test"),
+			mockOffsetGetter:      mockOffsetGetter{offsets: map[int64]int64{sarama.OffsetNewest: 100, ts.UnixMilli(): sarama.OffsetNewest}},
+			pendingStartPositions: map[string]metadata.StartPosition{"replay": {From: metadata.StartFromTimestamp, Timestamp: ts}},
+		}
+
+		session := &mockConsumerGroupSession{}
+		session.On("Claims").Return(map[string][]int32{"replay": {0}})
+		session.On("ResetOffset", "replay", int32(0), int64(100), "").Once()
+		session.On("MarkOffset", "replay", int32(0), int64(100), "").Once()
+		session.On("Commit").Once()
+
+		require.NoError(t, k.applyStartPositions(session))
+		session.AssertExpectations(t)
+	})
+
+	t.Run("partitions with committed offsets resume from them", func(t *testing.T) {
+		k := &Kafka{
+			logger: logger.NewLogger("test"),
+			mockOffsetGetter: mockOffsetGetter{
+				offsets:   getter.offsets,
+				committed: map[string]map[int32]int64{"replay": {0: 42}},
+			},
+			pendingStartPositions: map[string]metadata.StartPosition{"replay": {From: metadata.StartFromEarliest}},
+		}
+
+		session := &mockConsumerGroupSession{}
+		session.On("Claims").Return(map[string][]int32{"replay": {0, 1}})
+		session.On("ResetOffset", "replay", int32(1), int64(11), "").Once()
+		session.On("MarkOffset", "replay", int32(1), int64(11), "").Once()
+		session.On("Commit").Once()
+
+		require.NoError(t, k.applyStartPositions(session))
+		session.AssertExpectations(t)
+		session.AssertNotCalled(t, "ResetOffset", "replay", int32(0), int64(10), "")
+		assert.Empty(t, k.pendingStartPositions)
+	})
+
+	t.Run("all partitions with committed offsets, nothing to commit", func(t *testing.T) {
+		k := &Kafka{
+			logger: logger.NewLogger("test"),
+			mockOffsetGetter: mockOffsetGetter{
+				offsets:   getter.offsets,
+				committed: map[string]map[int32]int64{"replay": {0: 42}},
+			},
+			pendingStartPositions: map[string]metadata.StartPosition{"replay": {From: metadata.StartFromEarliest}},
+		}
+
+		session := &mockConsumerGroupSession{}
+		session.On("Claims").Return(map[string][]int32{"replay": {0}})
+
+		require.NoError(t, k.applyStartPositions(session))
+		session.AssertExpectations(t)
+		assert.Empty(t, k.pendingStartPositions)
+	})
+
+	t.Run("partitions not claimed, keep start position", func(t *testing.T) {
+		k := &Kafka{
+			logger:                logger.NewLogger("test"),
+			mockOffsetGetter:      getter,
+			pendingStartPositions: map[string]metadata.StartPosition{"replay": {From: metadata.StartFromEarliest}},
+		}
+
+		session := &mockConsumerGroupSession{}
+		session.On("Claims").Return(map[string][]int32{"other": {0}})
+
+		require.NoError(t, k.applyStartPositions(session))
+		session.AssertExpectations(t)
+		assert.Len(t, k.pendingStartPositions, 1)
+	})
+}
@@ -16,10 +16,10 @@ package kafka
 import (
 	"bytes"
 	"context"
-	"encoding/binary"
 	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -29,9 +29,12 @@ import (
 	aws2 "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/linkedin/goavro/v2"
 	"github.com/riferrei/srclient"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"google.golang.org/protobuf/reflect/protoreflect"
 
 	"github.com/dapr/components-contrib/common/aws"
 	awsAuth "github.com/dapr/components-contrib/common/aws/auth"
+	contribMetadata "github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/components-contrib/pubsub"
 	"github.com/dapr/kit/logger"
 	kitmd "github.com/dapr/kit/metadata"
@@ -43,6 +46,7 @@ type Kafka struct {
 	// These are used to inject mocked clients for tests
 	mockConsumerGroup sarama.ConsumerGroup
 	mockProducer      sarama.SyncProducer
+	mockOffsetGetter  offsetGetter
 	clients           *clients
 
 	maxMessageBytes int
@@ -64,6 +68,10 @@ type Kafka struct {
 	closed          atomic.Bool
 	wg              sync.WaitGroup
 
+	// Start positions of subscriptions that haven't been applied yet, keyed by topic
+	pendingStartPositions map[string]contribMetadata.StartPosition
+	startPositionsLock    sync.Mutex
+
 	// schema registry settings
 	srClient                   srclient.ISchemaRegistryClient
 	schemaCachingEnabled       bool
@@ -71,6 +79,8 @@ type Kafka struct {
 	latestSchemaCacheTTL       time.Duration
 	latestSchemaCacheWriteLock sync.RWMutex
 	latestSchemaCacheReadLock  sync.Mutex
+	schemaByIDCache            map[int]SchemaCacheEntry
+	schemaByIDCacheLock        sync.RWMutex
 
 	// Whether to encode/decode Avro into Avro JSON or standard JSON
 	useAvroJSON bool
@@ -96,11 +106,16 @@ type SchemaType int
 const (
 	None SchemaType = iota
 	Avro
+	Protobuf
+	JSONSchema
 )
 
 type SchemaCacheEntry struct {
 	schema         *srclient.Schema
+	schemaType     SchemaType
 	codec          *goavro.Codec
+	protobufFile   protoreflect.FileDescriptor
+	jsonSchema     *jsonschema.Schema
 	expirationTime time.Time
 }
 
@@ -113,10 +128,38 @@ func GetValueSchemaType(metadata map[string]string) (SchemaType, error) {
 	return None, nil
 }
 
+func GetKeySchemaType(metadata map[string]string) (SchemaType, error) {
+	schemaTypeStr, ok := kitmd.GetMetadataProperty(metadata, keySchemaType)
+	if ok {
+		v, err := parseSchemaType(schemaTypeStr)
+		return v, err
+	}
+	return None, nil
+}
+
+// GetStartPosition returns the position a subscription starts from, or nil if the subscription resumes from the committed offset.
+func GetStartPosition(metadata map[string]string) (*contribMetadata.StartPosition, error) {
+	pos, ok, err := contribMetadata.TryGetStartPosition(metadata)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if pos.From == contribMetadata.StartFromOffset {
+		offset, err := strconv.ParseInt(pos.Offset, 10, 64)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("%s value must be a non-negative integer: actual is '%s'", contribMetadata.StartOffsetMetadataKey, pos.Offset)
+		}
+	}
+	return &pos, nil
+}
+
 func parseSchemaType(sVal string) (SchemaType, error) {
 	switch strings.ToLower(sVal) {
 	case "avro":
 		return Avro, nil
+	case "protobuf":
+		return Protobuf, nil
+	case "json", "jsonschema":
+		return JSONSchema, nil
 	case "none":
 		return None, nil
 	default:
@@ -235,8 +278,8 @@ func (k *Kafka) Init(ctx context.Context, metadata map[string]string) error {
 	if meta.SchemaRegistryURL != "" {
 		k.logger.Infof("Schema registry URL '%s' provided. Configuring the Schema Registry client.", meta.SchemaRegistryURL)
 		k.srClient = srclient.CreateSchemaRegistryClient(meta.SchemaRegistryURL)
-		k.srClient.CodecCreationEnabled(true)
-		k.srClient.CodecJsonEnabled(!meta.UseAvroJSON)
+		// Codecs are created by the component, as the client would fail to create Avro codecs for Protobuf and JSON schemas
+		k.srClient.CodecCreationEnabled(false)
 		k.useAvroJSON = meta.UseAvroJSON
 		// Empty password is a possibility
 		if meta.SchemaRegistryAPIKey != "" {
@@ -246,6 +289,7 @@ func (k *Kafka) Init(ctx context.Context, metadata map[string]string) error {
 		k.srClient.CachingEnabled(meta.SchemaCachingEnabled)
 		if meta.SchemaCachingEnabled {
 			k.latestSchemaCache = make(map[string]SchemaCacheEntry)
+			k.schemaByIDCache = make(map[int]SchemaCacheEntry)
 			k.logger.Debugf("Schema cache TTL: %v", meta.SchemaLatestVersionCacheTTL)
 			k.latestSchemaCacheTTL = meta.SchemaLatestVersionCacheTTL
 		}
@@ -357,6 +401,11 @@ func getSchemaSubject(topic string) string {
 	return topic + "-value"
 }
 
+func getKeySchemaSubject(topic string) string {
+	// Like values, assumes that subject is named after topic (e.g. `my-topic-key`)
+	return topic + "-key"
+}
+
 func (k *Kafka) DeserializeValue(message *sarama.ConsumerMessage, config SubscriptionHandlerConfig) ([]byte, error) {
 	// Null Data is valid and a tombstone record.
 	// It shouldn't be going through schema validation and decoding
@@ -365,77 +414,158 @@ func (k *Kafka) DeserializeValue(message *sarama.ConsumerMessage, config Subscri
 		return []byte("null"), nil
 	}
 
-	switch config.ValueSchemaType {
+	return k.deserialize(message.Value, config.ValueSchemaType)
+}
+
+// DeserializeKey decodes the key of a message with the key schema type of the subscription.
+func (k *Kafka) DeserializeKey(message *sarama.ConsumerMessage, config SubscriptionHandlerConfig) ([]byte, error) {
+	if message.Key == nil {
+		return nil, nil
+	}
+
+	return k.deserialize(message.Key, config.KeySchemaType)
+}
+
+func (k *Kafka) deserialize(data []byte, schemaType SchemaType) ([]byte, error) {
+	if schemaType == None {
+		return data, nil
+	}
+
+	schemaID, payload, err := parseSchemaRecord(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch schemaType {
 	case Avro:
-		srClient, err := k.getSchemaRegistyClient()
+		entry, err := k.getSchemaByID(schemaID, schemaType)
 		if err != nil {
 			return nil, err
 		}
-		if len(message.Value) < 5 {
-			return nil, errors.New("value is too short")
-		}
-		schemaID := binary.BigEndian.Uint32(message.Value[1:5])
-		schema, err := srClient.GetSchema(int(schemaID))
+		native, _, err := entry.codec.NativeFromBinary(payload)
 		if err != nil {
 			return nil, err
 		}
-		codec := schema.Codec() // The value returned in Avro JSON format
-		native, _, err := codec.NativeFromBinary(message.Value[5:])
+		value, err := entry.codec.TextualFromNative(nil, native)
 		if err != nil {
 			return nil, err
 		}
-		value, err := codec.TextualFromNative(nil, native)
+		return value, nil
+	case Protobuf:
+		entry, err := k.getSchemaByID(schemaID, schemaType)
 		if err != nil {
 			return nil, err
 		}
-		return value, nil
+		return deserializeProtobuf(entry.protobufFile, payload)
+	case JSONSchema:
+		// The payload is the JSON document itself, which was validated by the producer
+		return payload, nil
 	default:
-		return message.Value, nil
+		return data, nil
+	}
+}
+
+// getSchemaByID returns the schema with the given ID, compiled for the schema type.
+// Schemas with an ID never change, so they're cached with no expiration when caching is enabled.
+func (k *Kafka) getSchemaByID(schemaID int, schemaType SchemaType) (SchemaCacheEntry, error) {
+	srClient, err := k.getSchemaRegistyClient()
+	if err != nil {
+		return SchemaCacheEntry{}, err
+	}
+
+	if k.schemaCachingEnabled {
+		k.schemaByIDCacheLock.RLock()
+		cacheEntry, ok := k.schemaByIDCache[schemaID]
+		k.schemaByIDCacheLock.RUnlock()
+		if ok && cacheEntry.schemaType == schemaType {
+			return cacheEntry, nil
+		}
+	}
+
+	schema, err := srClient.GetSchema(schemaID)
+	if err != nil {
+		return SchemaCacheEntry{}, err
+	}
+	entry, err := k.compileSchema(srClient, schema, schemaType)
+	if err != nil {
+		return SchemaCacheEntry{}, err
 	}
+
+	if k.schemaCachingEnabled {
+		k.schemaByIDCacheLock.Lock()
+		if k.schemaByIDCache == nil {
+			k.schemaByIDCache = make(map[int]SchemaCacheEntry)
+		}
+		k.schemaByIDCache[schemaID] = entry
+		k.schemaByIDCacheLock.Unlock()
+	}
+
+	return entry, nil
 }
 
-func (k *Kafka) getLatestSchema(topic string) (*srclient.Schema, *goavro.Codec, error) {
+func (k *Kafka) getLatestSchema(subject string, schemaType SchemaType) (SchemaCacheEntry, error) {
 	srClient, err := k.getSchemaRegistyClient()
 	if err != nil {
-		return nil, nil, err
+		return SchemaCacheEntry{}, err
 	}
 
-	subject := getSchemaSubject(topic)
 	if k.schemaCachingEnabled {
 		k.latestSchemaCacheReadLock.Lock()
 		cacheEntry, ok := k.latestSchemaCache[subject]
 		k.latestSchemaCacheReadLock.Unlock()
 
 		// Cache present and not expired
-		if ok && cacheEntry.expirationTime.After(time.Now()) {
-			return cacheEntry.schema, cacheEntry.codec, nil
+		if ok && cacheEntry.schemaType == schemaType && cacheEntry.expirationTime.After(time.Now()) {
+			return cacheEntry, nil
 		}
 		k.logger.Debugf("Cache not found or expired for subject %s. Fetching from registry...", subject)
 		schema, errSchema := srClient.GetLatestSchema(subject)
 		if errSchema != nil {
-			return nil, nil, errSchema
+			return SchemaCacheEntry{}, errSchema
 		}
 
-		codec, errCodec := k.getCodec(schema)
-		if errCodec != nil {
-			return nil, nil, errCodec
+		entry, errCompile := k.compileSchema(srClient, schema, schemaType)
+		if errCompile != nil {
+			return SchemaCacheEntry{}, errCompile
 		}
+		entry.expirationTime = time.Now().Add(k.latestSchemaCacheTTL)
 		defer k.latestSchemaCacheWriteLock.Unlock()
 		k.latestSchemaCacheWriteLock.Lock()
-		k.latestSchemaCache[subject] = SchemaCacheEntry{schema: schema, codec: codec, expirationTime: time.Now().Add(k.latestSchemaCacheTTL)}
+		k.latestSchemaCache[subject] = entry
 
-		return schema, codec, nil
+		return entry, nil
 	}
-	schema, err := srClient.GetLatestSchema(getSchemaSubject(topic))
+	schema, err := srClient.GetLatestSchema(subject)
 	if err != nil {
-		return nil, nil, err
+		return SchemaCacheEntry{}, err
 	}
-	codec, errCodec := k.getCodec(schema)
-	if errCodec != nil {
-		return nil, nil, errCodec
+
+	return k.compileSchema(srClient, schema, schemaType)
+}
+
+// compileSchema prepares a schema from the registry to serialize and deserialize data with the given schema type.
+func (k *Kafka) compileSchema(srClient srclient.ISchemaRegistryClient, schema *srclient.Schema, schemaType SchemaType) (entry SchemaCacheEntry, err error) {
+	err = checkRegistrySchemaType(schema, schemaType)
+	if err != nil {
+		return SchemaCacheEntry{}, err
 	}
 
-	return schema, codec, nil
+	entry = SchemaCacheEntry{
+		schema:     schema,
+		schemaType: schemaType,
+	}
+	switch schemaType {
+	case Avro:
+		entry.codec, err = k.getCodec(schema)
+	case Protobuf:
+		entry.protobufFile, err = compileProtobufSchema(srClient, schema)
+	case JSONSchema:
+		entry.jsonSchema, err = compileJSONSchema(srClient, schema)
+	}
+	if err != nil {
+		return SchemaCacheEntry{}, err
+	}
+	return entry, nil
 }
 
 func (k *Kafka) getSchemaRegistyClient() (srclient.ISchemaRegistryClient, error) {
@@ -469,33 +599,47 @@ func (k *Kafka) SerializeValue(topic string, data []byte, metadata map[string]st
 		return nil, err
 	}
 
-	switch valueSchemaType {
+	return k.serialize(getSchemaSubject(topic), valueSchemaType, data)
+}
+
+func (k *Kafka) serialize(subject string, schemaType SchemaType, data []byte) ([]byte, error) {
+	if schemaType == None {
+		return data, nil
+	}
+
+	entry, err := k.getLatestSchema(subject, schemaType)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload []byte
+	switch schemaType {
 	case Avro:
-		schema, codec, err := k.getLatestSchema(topic)
+		native, _, err := entry.codec.NativeFromTextual(data)
 		if err != nil {
 			return nil, err
 		}
 
-		native, _, err := codec.NativeFromTextual(data)
+		payload, err = entry.codec.BinaryFromNative(nil, native)
 		if err != nil {
 			return nil, err
 		}
-
-		valueBytes, err := codec.BinaryFromNative(nil, native)
+	case Protobuf:
+		payload, err = serializeProtobuf(entry.protobufFile, data)
 		if err != nil {
 			return nil, err
 		}
-		schemaIDBytes := make([]byte, 4)
-		binary.BigEndian.PutUint32(schemaIDBytes, uint32(schema.ID())) //nolint:gosec
-
-		recordValue := make([]byte, 0, len(schemaIDBytes)+len(valueBytes)+1)
-		recordValue = append(recordValue, byte(0))
-		recordValue = append(recordValue, schemaIDBytes...)
-		recordValue = append(recordValue, valueBytes...)
-		return recordValue, nil
+	case JSONSchema:
+		err = validateJSONSchema(entry.jsonSchema, data)
+		if err != nil {
+			return nil, err
+		}
+		payload = data
 	default:
 		return data, nil
 	}
+
+	return formatSchemaRecord(entry.schema.ID(), payload), nil
 }
 
 // EventHandler is the handler used to handle the subscribed event.
@@ -511,6 +655,8 @@ type SubscriptionHandlerConfig struct {
 	BulkHandler     BulkEventHandler
 	Handler         EventHandler
 	ValueSchemaType SchemaType
+	KeySchemaType   SchemaType
+	StartPosition   *contribMetadata.StartPosition
 }
 
 // NewEvent is an event arriving from a message bus instance.
@@ -39,6 +39,18 @@ func TestGetValueSchemaType(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("valueSchemaType='Protobuf', return Protobuf", func(t *testing.T) {
+		act, err := GetValueSchemaType(map[string]string{"valueSchemaType": "Protobuf"})
+		require.Equal(t, Protobuf, act)
+		require.NoError(t, err)
+	})
+
+	t.Run("valueSchemaType='JSON', return JSONSchema", func(t *testing.T) {
+		act, err := GetValueSchemaType(map[string]string{"valueSchemaType": "JSON"})
+		require.Equal(t, JSONSchema, act)
+		require.NoError(t, err)
+	})
+
 	t.Run("valueSchemaType='None', return None", func(t *testing.T) {
 		act, err := GetValueSchemaType(map[string]string{"valueSchemaType": "None"})
 		require.Equal(t, None, act)
@@ -51,6 +63,44 @@ func TestGetValueSchemaType(t *testing.T) {
 	})
 }
 
+func TestGetKeySchemaType(t *testing.T) {
+	t.Run("No keySchemaType, return None", func(t *testing.T) {
+		act, err := GetKeySchemaType(map[string]string{"valueSchemaType": "Avro"})
+		require.Equal(t, None, act)
+		require.NoError(t, err)
+	})
+
+	t.Run("keySchemaType='Avro', return Avro", func(t *testing.T) {
+		act, err := GetKeySchemaType(map[string]string{"keySchemaType": "Avro"})
+		require.Equal(t, Avro, act)
+		require.NoError(t, err)
+	})
+
+	t.Run("keySchemaType='XXX', return Error", func(t *testing.T) {
+		_, err := GetKeySchemaType(map[string]string{"keySchemaType": "XXX"})
+		require.Error(t, err)
+	})
+}
+
+func TestGetStartPosition(t *testing.T) {
+	t.Run("No startFrom, return nil", func(t *testing.T) {
+		act, err := GetStartPosition(map[string]string{})
+		require.Nil(t, act)
+		require.NoError(t, err)
+	})
+
+	t.Run("startFrom='offset', return offset", func(t *testing.T) {
+		act, err := GetStartPosition(map[string]string{"startFrom": "offset", "startOffset": "42"})
+		require.NoError(t, err)
+		require.Equal(t, "42", act.Offset)
+	})
+
+	t.Run("startOffset='XXX', return Error", func(t *testing.T) {
+		_, err := GetStartPosition(map[string]string{"startFrom": "offset", "startOffset": "XXX"})
+		require.Error(t, err)
+	})
+}
+
 var (
 	now         = time.Now()
 	testSchema1 = `{
@@ -96,6 +146,7 @@ func TestDeserializeValue(t *testing.T) {
 		srClient:             registryAvroJSON,
 		schemaCachingEnabled: true,
 		logger:               logger.NewLogger("kafka_test"),
+		useAvroJSON:          true,
 	}
 	kAvroJSON.srClient.CodecJsonEnabled(false)
 	schemaAvroJSON, _ := registryAvroJSON.CreateSchema("my-topic-value", testSchema1, srclient.Avro)
@@ -50,6 +50,7 @@ const (
 	consumerFetchDefault                     = "consumerFetchDefault"
 	channelBufferSize                        = "channelBufferSize"
 	valueSchemaType                          = "valueSchemaType"
+	keySchemaType                            = "keySchemaType"
 	compression                              = "compression"
 	consumerGroupRebalanceStrategyRange      = "range"
 	consumerGroupRebalanceStrategySticky     = "sticky"
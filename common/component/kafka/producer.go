@@ -21,6 +21,7 @@ import (
 
 	"github.com/IBM/sarama"
 
+	contribMetadata "github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/components-contrib/pubsub"
 )
 
@@ -47,6 +48,32 @@ func GetSyncProducer(config sarama.Config, brokers []string, maxMessageBytes int
 	return producer, nil
 }
 
+// setOrderingKey uses the ordering key as the key of the message, unless the key is set explicitly.
+// Messages with the same key are written to the same partition, so they are delivered in order.
+func setOrderingKey(msg *sarama.ProducerMessage, metadata map[string]string) {
+	if msg.Key == nil && metadata[contribMetadata.OrderingKeyMetadataKey] != "" {
+		msg.Key = sarama.StringEncoder(metadata[contribMetadata.OrderingKeyMetadataKey])
+	}
+}
+
+// serializeMessageKey encodes the key of the message with the key schema, if the metadata sets a key schema type.
+func (k *Kafka) serializeMessageKey(msg *sarama.ProducerMessage, metadata map[string]string) error {
+	keySchemaType, err := GetKeySchemaType(metadata)
+	if err != nil || keySchemaType == None || msg.Key == nil {
+		return err
+	}
+	key, err := msg.Key.Encode()
+	if err != nil {
+		return err
+	}
+	key, err = k.serialize(getKeySchemaSubject(msg.Topic), keySchemaType, key)
+	if err != nil {
+		return err
+	}
+	msg.Key = sarama.ByteEncoder(key)
+	return nil
+}
+
 // Publish message to Kafka cluster.
 func (k *Kafka) Publish(_ context.Context, topic string, data []byte, metadata map[string]string) error {
 	clients, err := k.latestClients()
@@ -89,6 +116,12 @@ func (k *Kafka) Publish(_ context.Context, topic string, data []byte, metadata m
 		})
 	}
 
+	setOrderingKey(msg, metadata)
+	err = k.serializeMessageKey(msg, metadata)
+	if err != nil {
+		return err
+	}
+
 	partition, offset, err := clients.producer.SendMessage(msg)
 
 	k.logger.Debugf("Partition: %v, offset: %v", partition, offset)
@@ -157,6 +190,12 @@ func (k *Kafka) BulkPublish(_ context.Context, topic string, entries []pubsub.Bu
 			})
 		}
 
+		setOrderingKey(msg, entry.Metadata)
+		err = k.serializeMessageKey(msg, entry.Metadata)
+		if err != nil {
+			return k.mapKafkaProducerErrors(err, entries), err
+		}
+
 		msgs = append(msgs, msg)
 	}
 
@@ -96,6 +96,38 @@ func TestPublish(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("produce message with partition key when orderingKey in metadata", func(t *testing.T) {
+		// arrange
+		metadata := map[string]string{
+			"a":           "a",
+			"orderingKey": "key",
+		}
+		messageAsserter := createMessageAsserter(t, sarama.StringEncoder("key"), metadata)
+		k := arrangeKafkaWithAssertions(t, messageAsserter)
+
+		// act
+		err := k.Publish(ctx, "a", []byte("a"), metadata)
+
+		// assert
+		require.NoError(t, err)
+	})
+
+	t.Run("partitionKey takes precedence over orderingKey", func(t *testing.T) {
+		// arrange
+		metadata := map[string]string{
+			"partitionKey": "key",
+			"orderingKey":  "other",
+		}
+		messageAsserter := createMessageAsserter(t, sarama.StringEncoder("key"), metadata)
+		k := arrangeKafkaWithAssertions(t, messageAsserter)
+
+		// act
+		err := k.Publish(ctx, "a", []byte("a"), metadata)
+
+		// assert
+		require.NoError(t, err)
+	})
+
 	t.Run("produce message with excluded headers", func(t *testing.T) {
 		// arrange
 		metadataIn := map[string]string{
@@ -0,0 +1,218 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/riferrei/srclient"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Records serialized with a schema from the registry start with a magic byte, followed by the ID of the schema.
+const (
+	schemaRecordMagicByte  = byte(0)
+	schemaRecordHeaderSize = 5
+)
+
+func formatSchemaRecord(schemaID int, payload []byte) []byte {
+	record := make([]byte, schemaRecordHeaderSize, schemaRecordHeaderSize+len(payload))
+	record[0] = schemaRecordMagicByte
+	binary.BigEndian.PutUint32(record[1:schemaRecordHeaderSize], uint32(schemaID)) //nolint:gosec
+	return append(record, payload...)
+}
+
+func parseSchemaRecord(record []byte) (schemaID int, payload []byte, err error) {
+	if len(record) < schemaRecordHeaderSize {
+		return 0, nil, errors.New("value is too short")
+	}
+	if record[0] != schemaRecordMagicByte {
+		return 0, nil, fmt.Errorf("unknown magic byte %d", record[0])
+	}
+	return int(binary.BigEndian.Uint32(record[1:schemaRecordHeaderSize])), record[schemaRecordHeaderSize:], nil
+}
+
+// checkRegistrySchemaType returns an error if the schema in the registry is not of the given type.
+func checkRegistrySchemaType(schema *srclient.Schema, schemaType SchemaType) error {
+	// The registry omits the type of Avro schemas
+	registryType := srclient.Avro
+	if t := schema.SchemaType(); t != nil && *t != "" {
+		registryType = *t
+	}
+
+	var expected srclient.SchemaType
+	switch schemaType {
+	case Avro:
+		expected = srclient.Avro
+	case Protobuf:
+		expected = srclient.Protobuf
+	case JSONSchema:
+		expected = srclient.Json
+	}
+	if registryType != expected {
+		return fmt.Errorf("schema %d is of type %s, expected %s", schema.ID(), registryType, expected)
+	}
+	return nil
+}
+
+// getReferencedSchemas adds the schemas referenced by a schema to sources, recursively, keyed by the name used to reference them.
+func getReferencedSchemas(srClient srclient.ISchemaRegistryClient, references []srclient.Reference, sources map[string]string) error {
+	for _, ref := range references {
+		if _, ok := sources[ref.Name]; ok {
+			continue
+		}
+		schema, err := srClient.GetSchemaByVersion(ref.Subject, ref.Version)
+		if err != nil {
+			return fmt.Errorf("failed to get referenced schema '%s': %w", ref.Name, err)
+		}
+		sources[ref.Name] = schema.Schema()
+		err = getReferencedSchemas(srClient, schema.References(), sources)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compileProtobufSchema compiles a Protobuf schema from the registry, together with the schemas it imports.
+func compileProtobufSchema(srClient srclient.ISchemaRegistryClient, schema *srclient.Schema) (protoreflect.FileDescriptor, error) {
+	sources := make(map[string]string, len(schema.References())+1)
+	err := getReferencedSchemas(srClient, schema.References(), sources)
+	if err != nil {
+		return nil, err
+	}
+	fileName := fmt.Sprintf("schema-registry-%d.proto", schema.ID())
+	sources[fileName] = schema.Schema()
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(sources),
+		}),
+	}
+	files, err := compiler.Compile(context.Background(), fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile Protobuf schema %d: %w", schema.ID(), err)
+	}
+	if files[0].Messages().Len() == 0 {
+		return nil, fmt.Errorf("schema %d doesn't define any Protobuf message", schema.ID())
+	}
+	return files[0], nil
+}
+
+// serializeProtobuf encodes a JSON document as the first message type of the schema.
+// The payload starts with the path of the message type in the schema, which for the first message is a single 0.
+func serializeProtobuf(file protoreflect.FileDescriptor, data []byte) ([]byte, error) {
+	msg := dynamicpb.NewMessage(file.Messages().Get(0))
+	err := protojson.Unmarshal(data, msg)
+	if err != nil {
+		return nil, err
+	}
+	return proto.MarshalOptions{}.MarshalAppend([]byte{0}, msg)
+}
+
+// deserializeProtobuf decodes a Protobuf payload as JSON, using the message type at the path the payload starts with.
+func deserializeProtobuf(file protoreflect.FileDescriptor, payload []byte) ([]byte, error) {
+	indexes, payload, err := readProtobufMessageIndexes(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var desc protoreflect.MessageDescriptor
+	messages := file.Messages()
+	for _, i := range indexes {
+		if i >= messages.Len() {
+			return nil, fmt.Errorf("message type %v not found in Protobuf schema", indexes)
+		}
+		desc = messages.Get(i)
+		messages = desc.Messages()
+	}
+
+	msg := dynamicpb.NewMessage(desc)
+	err = proto.Unmarshal(payload, msg)
+	if err != nil {
+		return nil, err
+	}
+	return protojson.Marshal(msg)
+}
+
+// readProtobufMessageIndexes reads the path of the message type from the beginning of a Protobuf payload.
+// The path is made of the number of indexes, followed by the indexes of the message and of its parents, as zig-zag varints.
+// A path with no indexes refers to the first message type.
+func readProtobufMessageIndexes(payload []byte) ([]int, []byte, error) {
+	count, n := binary.Varint(payload)
+	if n <= 0 || count < 0 || count > int64(len(payload)-n) {
+		return nil, nil, errors.New("invalid Protobuf message indexes")
+	}
+	payload = payload[n:]
+	if count == 0 {
+		return []int{0}, payload, nil
+	}
+
+	indexes := make([]int, count)
+	for i := range indexes {
+		index, n := binary.Varint(payload)
+		if n <= 0 || index < 0 {
+			return nil, nil, errors.New("invalid Protobuf message indexes")
+		}
+		indexes[i] = int(index)
+		payload = payload[n:]
+	}
+	return indexes, payload, nil
+}
+
+// compileJSONSchema compiles a JSON schema from the registry, together with the schemas it references.
+func compileJSONSchema(srClient srclient.ISchemaRegistryClient, schema *srclient.Schema) (*jsonschema.Schema, error) {
+	sources := make(map[string]string, len(schema.References())+1)
+	err := getReferencedSchemas(srClient, schema.References(), sources)
+	if err != nil {
+		return nil, err
+	}
+	fileName := fmt.Sprintf("schema-registry-%d.json", schema.ID())
+	sources[fileName] = schema.Schema()
+
+	compiler := jsonschema.NewCompiler()
+	for name, source := range sources {
+		err = compiler.AddResource(name, strings.NewReader(source))
+		if err != nil {
+			return nil, fmt.Errorf("failed to add JSON schema '%s': %w", name, err)
+		}
+	}
+	compiled, err := compiler.Compile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile JSON schema %d: %w", schema.ID(), err)
+	}
+	return compiled, nil
+}
+
+func validateJSONSchema(schema *jsonschema.Schema, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	err := dec.Decode(&doc)
+	if err != nil {
+		return fmt.Errorf("invalid JSON document: %w", err)
+	}
+	return schema.Validate(doc)
+}
@@ -0,0 +1,239 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/golang/mock/gomock"
+	"github.com/riferrei/srclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	mock_srclient "github.com/dapr/components-contrib/common/component/kafka/mocks"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	testProtobufSchema = `syntax = "proto3";
+package orders;
+
+import "google/protobuf/timestamp.proto";
+import "customer.proto";
+
+message Order {
+  message Item {
+    string sku = 1;
+    int32 quantity = 2;
+  }
+  string id = 1;
+  repeated Item items = 2;
+  google.protobuf.Timestamp created_at = 3;
+  customers.Customer customer = 4;
+}
+
+message Refund {
+  string order_id = 1;
+}`
+	testProtobufCustomerSchema = `syntax = "proto3";
+package customers;
+
+message Customer {
+  string name = 1;
+}`
+	testJSONSchema = `{
+	"type": "object",
+	"properties": {
+		"id": {"type": "string"},
+		"address": {"$ref": "address.json"}
+	},
+	"required": ["id"]
+}`
+	testJSONAddressSchema = `{
+	"type": "object",
+	"properties": {
+		"city": {"type": "string"}
+	},
+	"required": ["city"]
+}`
+)
+
+func newTestSchema(t *testing.T, id int, schema string, schemaType srclient.SchemaType, references ...srclient.Reference) *srclient.Schema {
+	t.Helper()
+
+	s, err := srclient.NewSchema(id, schema, schemaType, 1, references, nil, nil)
+	require.NoError(t, err)
+	return s
+}
+
+func newSchemaRegistryKafka(m srclient.ISchemaRegistryClient) *Kafka {
+	return &Kafka{
+		srClient:             m,
+		schemaCachingEnabled: true,
+		latestSchemaCache:    make(map[string]SchemaCacheEntry),
+		latestSchemaCacheTTL: time.Minute,
+		logger:               logger.NewLogger("kafka_test"),
+	}
+}
+
+func TestProtobufSchema(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := mock_srclient.NewMockISchemaRegistryClient(ctrl)
+
+	schema := newTestSchema(t, 3, testProtobufSchema, srclient.Protobuf, srclient.Reference{Name: "customer.proto", Subject: "customer-value", Version: 1})
+	customerSchema := newTestSchema(t, 2, testProtobufCustomerSchema, srclient.Protobuf)
+	k := newSchemaRegistryKafka(m)
+
+	t.Run("serialize and deserialize value", func(t *testing.T) {
+		// Schemas are compiled once for serialization and once for deserialization, then cached
+		m.EXPECT().GetLatestSchema(gomock.Eq("orders-value")).Return(schema, nil).Times(1)
+		m.EXPECT().GetSchema(gomock.Eq(3)).Return(schema, nil).Times(1)
+		m.EXPECT().GetSchemaByVersion(gomock.Eq("customer-value"), gomock.Eq(1)).Return(customerSchema, nil).Times(2)
+
+		value := `{"id":"o-1","items":[{"sku":"cupcake","quantity":2}],"createdAt":"2025-01-02T03:04:05Z","customer":{"name":"Jane"}}`
+		for range 2 {
+			act, err := k.SerializeValue("orders", []byte(value), map[string]string{"valueSchemaType": "Protobuf"})
+			require.NoError(t, err)
+
+			// Magic byte, schema ID, and message indexes of the first message
+			require.Equal(t, []byte{0, 0, 0, 0, 3, 0}, act[:6])
+
+			res, err := k.DeserializeValue(&sarama.ConsumerMessage{Topic: "orders", Value: act}, SubscriptionHandlerConfig{ValueSchemaType: Protobuf})
+			require.NoError(t, err)
+			assertJSONEqual(t, value, res)
+		}
+	})
+
+	t.Run("deserialize other message types", func(t *testing.T) {
+		// Refund is the second message type in the schema
+		refund := protowire.AppendTag(nil, 1, protowire.BytesType)
+		refund = protowire.AppendString(refund, "o-1")
+		indexes := binary.AppendVarint(binary.AppendVarint(nil, 1), 1)
+		res, err := k.DeserializeValue(&sarama.ConsumerMessage{Value: formatSchemaRecord(3, append(indexes, refund...))}, SubscriptionHandlerConfig{ValueSchemaType: Protobuf})
+		require.NoError(t, err)
+		assertJSONEqual(t, `{"orderId":"o-1"}`, res)
+
+		// Item is the first message type nested in Order
+		item := protowire.AppendTag(nil, 1, protowire.BytesType)
+		item = protowire.AppendString(item, "cupcake")
+		indexes = binary.AppendVarint(binary.AppendVarint(binary.AppendVarint(nil, 2), 0), 0)
+		res, err = k.DeserializeValue(&sarama.ConsumerMessage{Value: formatSchemaRecord(3, append(indexes, item...))}, SubscriptionHandlerConfig{ValueSchemaType: Protobuf})
+		require.NoError(t, err)
+		assertJSONEqual(t, `{"sku":"cupcake"}`, res)
+
+		// Message type that doesn't exist
+		indexes = binary.AppendVarint(binary.AppendVarint(nil, 1), 5)
+		_, err = k.DeserializeValue(&sarama.ConsumerMessage{Value: formatSchemaRecord(3, indexes)}, SubscriptionHandlerConfig{ValueSchemaType: Protobuf})
+		require.Error(t, err)
+	})
+
+	t.Run("invalid value, return error", func(t *testing.T) {
+		_, err := k.SerializeValue("orders", []byte(`{"unknown":"field"}`), map[string]string{"valueSchemaType": "Protobuf"})
+		require.Error(t, err)
+	})
+
+	t.Run("schema of a different type, return error", func(t *testing.T) {
+		k := newSchemaRegistryKafka(m)
+		m.EXPECT().GetLatestSchema(gomock.Eq("cupcakes-value")).Return(newTestSchema(t, 4, testSchema1, srclient.Avro), nil).Times(1)
+		_, err := k.SerializeValue("cupcakes", []byte(`{"flavor":"chocolate"}`), map[string]string{"valueSchemaType": "Protobuf"})
+		require.ErrorContains(t, err, "expected PROTOBUF")
+	})
+
+	t.Run("invalid message indexes, return error", func(t *testing.T) {
+		_, err := k.DeserializeValue(&sarama.ConsumerMessage{Value: formatSchemaRecord(3, []byte{0x7f})}, SubscriptionHandlerConfig{ValueSchemaType: Protobuf})
+		require.Error(t, err)
+	})
+}
+
+func TestJSONSchema(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := mock_srclient.NewMockISchemaRegistryClient(ctrl)
+
+	schema := newTestSchema(t, 5, testJSONSchema, srclient.Json, srclient.Reference{Name: "address.json", Subject: "address-value", Version: 1})
+	addressSchema := newTestSchema(t, 6, testJSONAddressSchema, srclient.Json)
+	m.EXPECT().GetLatestSchema(gomock.Eq("customers-value")).Return(schema, nil).Times(1)
+	m.EXPECT().GetSchemaByVersion(gomock.Eq("address-value"), gomock.Eq(1)).Return(addressSchema, nil).Times(1)
+	k := newSchemaRegistryKafka(m)
+
+	t.Run("valid value, serialize with schema ID", func(t *testing.T) {
+		value := []byte(`{"id":"c-1","address":{"city":"Rome"}}`)
+		act, err := k.SerializeValue("customers", value, map[string]string{"valueSchemaType": "JSON"})
+		require.NoError(t, err)
+		require.Equal(t, formatSchemaRecord(5, value), act)
+
+		res, err := k.DeserializeValue(&sarama.ConsumerMessage{Value: act}, SubscriptionHandlerConfig{ValueSchemaType: JSONSchema})
+		require.NoError(t, err)
+		require.Equal(t, value, res)
+	})
+
+	t.Run("invalid value, return error", func(t *testing.T) {
+		_, err := k.SerializeValue("customers", []byte(`{"id":"c-1","address":{}}`), map[string]string{"valueSchemaType": "JSON"})
+		require.Error(t, err)
+
+		_, err = k.SerializeValue("customers", []byte(`not json`), map[string]string{"valueSchemaType": "JSON"})
+		require.Error(t, err)
+	})
+
+	t.Run("unknown magic byte, return error", func(t *testing.T) {
+		_, err := k.DeserializeValue(&sarama.ConsumerMessage{Value: []byte(`{"id":"c-1"}`)}, SubscriptionHandlerConfig{ValueSchemaType: JSONSchema})
+		require.ErrorContains(t, err, "unknown magic byte")
+	})
+}
+
+func TestKeySchema(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := mock_srclient.NewMockISchemaRegistryClient(ctrl)
+
+	schema := newTestSchema(t, 7, `{"type": "string"}`, srclient.Json)
+	m.EXPECT().GetLatestSchema(gomock.Eq("a-key")).Return(schema, nil).Times(1)
+	expectedKey := formatSchemaRecord(7, []byte(`"my-key"`))
+
+	t.Run("publish serializes the key with the key schema", func(t *testing.T) {
+		metadata := map[string]string{
+			"partitionKey":  `"my-key"`,
+			"keySchemaType": "JSON",
+		}
+		k := arrangeKafkaWithAssertions(t, createMessageAsserter(t, sarama.ByteEncoder(expectedKey), metadata))
+		k.srClient = m
+		k.schemaCachingEnabled = true
+		k.latestSchemaCache = make(map[string]SchemaCacheEntry)
+		k.latestSchemaCacheTTL = time.Minute
+
+		err := k.Publish(t.Context(), "a", []byte("a"), metadata)
+		require.NoError(t, err)
+	})
+
+	t.Run("received key is deserialized with the key schema", func(t *testing.T) {
+		k := newSchemaRegistryKafka(m)
+		message := &sarama.ConsumerMessage{Topic: "a", Key: expectedKey, Value: []byte("a")}
+		metadata := GetEventMetadata(message, k)
+		err := k.setDeserializedKey(metadata, message, SubscriptionHandlerConfig{KeySchemaType: JSONSchema})
+		require.NoError(t, err)
+		assert.Equal(t, `"my-key"`, metadata[keyMetadataKey])
+	})
+
+	t.Run("key schema type not set, leave key as is", func(t *testing.T) {
+		k := newSchemaRegistryKafka(m)
+		message := &sarama.ConsumerMessage{Topic: "a", Key: expectedKey, Value: []byte("a")}
+		metadata := GetEventMetadata(message, k)
+		err := k.setDeserializedKey(metadata, message, SubscriptionHandlerConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, string(expectedKey), metadata[keyMetadataKey])
+	})
+}
+
+func assertJSONEqual(t *testing.T, expected string, actual []byte) {
+	t.Helper()
+
+	// Protobuf JSON encoding doesn't guarantee stable formatting, so documents are compared as maps
+	var expMap, actMap map[string]any
+	require.NoError(t, json.Unmarshal([]byte(expected), &expMap))
+	require.NoError(t, json.Unmarshal(actual, &actMap))
+	assert.Equal(t, expMap, actMap)
+}
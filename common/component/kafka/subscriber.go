@@ -18,6 +18,7 @@ import (
 	"errors"
 	"time"
 
+	contribMetadata "github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/components-contrib/pubsub"
 )
 
@@ -29,6 +30,16 @@ func (k *Kafka) Subscribe(ctx context.Context, handlerConfig SubscriptionHandler
 	for _, topic := range topics {
 		k.subscribeTopics[topic] = handlerConfig
 	}
+	if handlerConfig.StartPosition != nil {
+		k.startPositionsLock.Lock()
+		if k.pendingStartPositions == nil {
+			k.pendingStartPositions = make(map[string]contribMetadata.StartPosition, len(topics))
+		}
+		for _, topic := range topics {
+			k.pendingStartPositions[topic] = *handlerConfig.StartPosition
+		}
+		k.startPositionsLock.Unlock()
+	}
 
 	k.logger.Debugf("Subscribing to topic: %v", topics)
 
@@ -64,9 +75,12 @@ func (k *Kafka) Subscribe(ctx context.Context, handlerConfig SubscriptionHandler
 
 		k.logger.Debugf("Unsubscribing to topic: %v", topics)
 
+		k.startPositionsLock.Lock()
 		for _, topic := range topics {
 			delete(k.subscribeTopics, topic)
+			delete(k.pendingStartPositions, topic)
 		}
+		k.startPositionsLock.Unlock()
 
 		k.reloadConsumerGroup()
 		postAction()
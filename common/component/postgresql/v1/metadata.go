@@ -15,10 +15,12 @@ package postgresql
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/dapr/components-contrib/common/authentication/aws"
 	pgauth "github.com/dapr/components-contrib/common/authentication/postgresql"
+	commonsql "github.com/dapr/components-contrib/common/component/sql"
 	"github.com/dapr/components-contrib/state"
 	"github.com/dapr/kit/metadata"
 	"github.com/dapr/kit/ptr"
@@ -38,6 +40,9 @@ type pgMetadata struct {
 	MetadataTableName string         `mapstructure:"metadataTableName"` // Could be in the format "schema.table" or just "table"
 	Timeout           time.Duration  `mapstructure:"timeout" mapstructurealiases:"timeoutInSeconds"`
 	CleanupInterval   *time.Duration `mapstructure:"cleanupInterval" mapstructurealiases:"cleanupIntervalInSeconds"`
+	IndexedFields     []string       `mapstructure:"indexedFields"` // Paths of the fields of values to index, in the format "person.org"
+
+	indexedFields []commonsql.IndexedField
 
 	aws.DeprecatedPostgresIAM `mapstructure:",squash"`
 }
@@ -49,6 +54,8 @@ func (m *pgMetadata) InitWithMetadata(meta state.Metadata, opts pgauth.InitWithM
 	m.MetadataTableName = defaultMetadataTableName
 	m.CleanupInterval = ptr.Of(defaultCleanupInternal)
 	m.Timeout = defaultTimeout
+	m.IndexedFields = nil
+	m.indexedFields = nil
 
 	// Decode the metadata
 	err := metadata.DecodeMetadata(meta.Properties, &m)
@@ -78,5 +85,11 @@ func (m *pgMetadata) InitWithMetadata(meta state.Metadata, opts pgauth.InitWithM
 		m.CleanupInterval = nil
 	}
 
+	// Indexed fields
+	m.indexedFields, err = commonsql.ParseIndexedFields(m.IndexedFields)
+	if err != nil {
+		return fmt.Errorf("invalid value for 'indexedFields': %w", err)
+	}
+
 	return nil
 }
@@ -255,4 +255,31 @@ func TestMetadata(t *testing.T) {
 		require.NotNil(t, m.CleanupInterval)
 		assert.Equal(t, defaultCleanupInternal, *m.CleanupInterval)
 	})
+
+	t.Run("indexed fields", func(t *testing.T) {
+		m := pgMetadata{}
+		props := map[string]string{
+			"connectionString": "foo=bar",
+			"indexedFields":    "person.org, state",
+		}
+
+		opts := postgresql.InitWithMetadataOpts{}
+		err := m.InitWithMetadata(state.Metadata{Base: metadata.Base{Properties: props}}, opts)
+		require.NoError(t, err)
+		require.Len(t, m.indexedFields, 2)
+		assert.Equal(t, "person.org", m.indexedFields[0].Path)
+		assert.Equal(t, "state", m.indexedFields[1].Path)
+	})
+
+	t.Run("invalid indexed fields", func(t *testing.T) {
+		m := pgMetadata{}
+		props := map[string]string{
+			"connectionString": "foo=bar",
+			"indexedFields":    "person.org'",
+		}
+
+		opts := postgresql.InitWithMetadataOpts{}
+		err := m.InitWithMetadata(state.Metadata{Base: metadata.Base{Properties: props}}, opts)
+		require.ErrorContains(t, err, "indexedFields")
+	})
 }
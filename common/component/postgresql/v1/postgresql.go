@@ -72,6 +72,7 @@ type MigrateOptions struct {
 	Logger            logger.Logger
 	StateTableName    string
 	MetadataTableName string
+	IndexedFields     []commonsql.IndexedField
 }
 
 type SetQueryOptions struct {
@@ -143,6 +144,7 @@ func (p *PostgreSQL) Init(ctx context.Context, meta state.Metadata) error {
 		Logger:            p.logger,
 		StateTableName:    p.metadata.TableName,
 		MetadataTableName: p.metadata.MetadataTableName,
+		IndexedFields:     p.metadata.indexedFields,
 	})
 	if err != nil {
 		return err
@@ -16,6 +16,7 @@ package postgresql
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -82,6 +83,8 @@ type Query struct {
 	skip       *int64
 	tableName  string
 	etagColumn string
+	aggregate  bool
+	fields     []string
 }
 
 func (q *Query) VisitEQ(f *query.EQ) (string, error) {
@@ -217,7 +220,17 @@ func (q *Query) VisitOR(f *query.OR) (string, error) {
 }
 
 func (q *Query) Finalize(filters string, qq *query.Query) error {
-	q.query = fmt.Sprintf("SELECT key, value, %s as etag FROM "+q.tableName, q.etagColumn)
+	value := "value"
+	if len(qq.Fields) > 0 {
+		// Each field is selected as an array that is empty if the field is missing, so missing fields can be told apart from null values
+		q.fields = qq.Fields
+		paths := make([]string, len(qq.Fields))
+		for i, key := range qq.Fields {
+			paths[i] = "jsonb_path_query_array(value, $" + strconv.Itoa(q.addParamValueAndReturnPosition(fieldJSONPath(key))) + "::jsonpath)"
+		}
+		value = "jsonb_build_array(" + strings.Join(paths, ", ") + ")"
+	}
+	q.query = fmt.Sprintf("SELECT key, %s, %s as etag FROM "+q.tableName, value, q.etagColumn)
 
 	if filters != "" {
 		q.query += " WHERE " + filters
@@ -237,6 +250,10 @@ func (q *Query) Finalize(filters string, qq *query.Query) error {
 		}
 	}
 
+	return q.finalizePage(qq)
+}
+
+func (q *Query) finalizePage(qq *query.Query) error {
 	if qq.Page.Limit > 0 {
 		q.query += " LIMIT " + strconv.Itoa(qq.Page.Limit)
 		q.limit = qq.Page.Limit
@@ -254,6 +271,67 @@ func (q *Query) Finalize(filters string, qq *query.Query) error {
 	return nil
 }
 
+func (q *Query) VisitCOUNT(*query.COUNT) (string, error) {
+	return "COUNT(*)", nil
+}
+
+func (q *Query) VisitSUM(a *query.SUM) (string, error) {
+	return "SUM((" + translateFieldToFilter(a.Key) + ")::numeric)", nil
+}
+
+func (q *Query) VisitMIN(a *query.MIN) (string, error) {
+	return "MIN((" + translateFieldToFilter(a.Key) + ")::numeric)", nil
+}
+
+func (q *Query) VisitMAX(a *query.MAX) (string, error) {
+	return "MAX((" + translateFieldToFilter(a.Key) + ")::numeric)", nil
+}
+
+// FinalizeAggregate builds a query that returns a JSON object for each group, with the values of the group-by keys and of the aggregations.
+func (q *Query) FinalizeAggregate(filters string, aggregations []string, qq *query.Query) error {
+	q.aggregate = true
+
+	// Names of the fields in the result are passed as parameters, as they are user input
+	fields := make([]string, 0, len(qq.Aggregate.GroupBy)+len(aggregations))
+	groupBy := make([]string, len(qq.Aggregate.GroupBy))
+	exprs := make(map[string]string, len(qq.Aggregate.GroupBy)+len(aggregations))
+	for i, key := range qq.Aggregate.GroupBy {
+		groupBy[i] = translateFieldToFilter(key)
+		exprs[key] = groupBy[i]
+		fields = append(fields, "$"+strconv.Itoa(q.addParamValueAndReturnPosition(key))+"::text, "+groupBy[i])
+	}
+	for i, a := range qq.Aggregate.Aggregations {
+		exprs[a.GetAlias()] = aggregations[i]
+		fields = append(fields, "$"+strconv.Itoa(q.addParamValueAndReturnPosition(a.GetAlias()))+"::text, "+aggregations[i])
+	}
+
+	q.query = "SELECT jsonb_build_object(" + strings.Join(fields, ", ") + ") FROM " + q.tableName
+
+	if filters != "" {
+		q.query += " WHERE " + filters
+	}
+
+	if len(groupBy) > 0 {
+		q.query += " GROUP BY " + strings.Join(groupBy, ", ")
+	}
+
+	if len(qq.Sort) > 0 {
+		q.query += " ORDER BY "
+
+		for sortIndex, sortItem := range qq.Sort {
+			if sortIndex > 0 {
+				q.query += ", "
+			}
+			q.query += exprs[sortItem.Key]
+			if sortItem.Order != "" {
+				q.query += " " + sortItem.Order
+			}
+		}
+	}
+
+	return q.finalizePage(qq)
+}
+
 func (q *Query) execute(ctx context.Context, db pginterfaces.DBQuerier) ([]state.QueryItem, string, error) {
 	rows, err := db.Query(ctx, q.query, q.params...)
 	if err != nil {
@@ -268,6 +346,14 @@ func (q *Query) execute(ctx context.Context, db pginterfaces.DBQuerier) ([]state
 			data []byte
 			etag uint32
 		)
+		if q.aggregate {
+			// Results of aggregations have no key and no etag
+			if err = rows.Scan(&data); err != nil {
+				return nil, "", err
+			}
+			ret = append(ret, state.QueryItem{Data: data})
+			continue
+		}
 		if err = rows.Scan(&key, &data, &etag); err != nil {
 			return nil, "", err
 		}
@@ -276,6 +362,12 @@ func (q *Query) execute(ctx context.Context, db pginterfaces.DBQuerier) ([]state
 			Data: data,
 			ETag: ptr.Of(strconv.FormatUint(uint64(etag), 10)),
 		}
+		if len(q.fields) > 0 {
+			result.Data, err = q.projectedValue(data)
+			if err != nil {
+				result.Error = err.Error()
+			}
+		}
 		ret = append(ret, result)
 	}
 
@@ -295,11 +387,44 @@ func (q *Query) execute(ctx context.Context, db pginterfaces.DBQuerier) ([]state
 	return ret, token, nil
 }
 
+// projectedValue returns the JSON object with the selected fields, from the array of fields returned by the query.
+func (q *Query) projectedValue(data []byte) ([]byte, error) {
+	var values [][]any
+	err := json.Unmarshal(data, &values)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != len(q.fields) {
+		return nil, fmt.Errorf("expected %d fields, got %d", len(q.fields), len(values))
+	}
+
+	res := make(map[string]any, len(q.fields))
+	for i, key := range q.fields {
+		if len(values[i]) > 0 {
+			query.SetField(res, key, values[i][0])
+		}
+	}
+	return json.Marshal(res)
+}
+
+// fieldJSONPath returns the SQL/JSON path of the field with the given key, in dot notation.
+func fieldJSONPath(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		// Quoting as JSON strings also escapes the parts for the SQL/JSON path language
+		quoted, _ := json.Marshal(part)
+		parts[i] = string(quoted)
+	}
+	return "$." + strings.Join(parts, ".")
+}
+
 func (q *Query) addParamValueAndReturnPosition(value interface{}) int {
 	q.params = append(q.params, fmt.Sprintf("%v", value))
 	return len(q.params)
 }
 
+// translateFieldToFilter returns the expression used to filter or sort by the field with the given key.
+// It must match the expression of the indexes of indexed fields, or queries can't use them.
 func translateFieldToFilter(key string) string {
 	// add preceding "value"
 	key = "value." + key
@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	commonsql "github.com/dapr/components-contrib/common/component/sql"
 	"github.com/dapr/components-contrib/state/query"
 )
 
@@ -79,3 +80,57 @@ func TestPostgresqlQueryBuildQuery(t *testing.T) {
 		assert.Equal(t, test.query, q.query)
 	}
 }
+
+func TestPostgresqlQueryIndexedFields(t *testing.T) {
+	// Queries filter and sort by the expressions of the indexes, so they can use them
+	fields, err := commonsql.ParseIndexedFields([]string{"person.org", "state"})
+	require.NoError(t, err)
+	for _, f := range fields {
+		assert.Equal(t, f.PostgresExpression(), translateFieldToFilter(f.Path))
+	}
+}
+
+func TestPostgresqlQueryBuildQueryAggregate(t *testing.T) {
+	data, err := os.ReadFile("../../../../tests/state/query/q9-aggregate.json")
+	require.NoError(t, err)
+	var qq query.Query
+	err = json.Unmarshal(data, &qq)
+	require.NoError(t, err)
+
+	q := &Query{
+		tableName:  defaultTableName,
+		etagColumn: "xmin",
+	}
+	qbuilder := query.NewQueryBuilder(q)
+	err = qbuilder.BuildQuery(&qq)
+	require.NoError(t, err)
+	assert.True(t, q.aggregate)
+	assert.Equal(t, "SELECT jsonb_build_object($2::text, value->>'status', $3::text, value->'person'->>'org', $4::text, COUNT(*), $5::text, SUM((value->>'amount')::numeric), $6::text, MIN((value->>'amount')::numeric), $7::text, MAX((value->>'amount')::numeric)) FROM state WHERE value->>'status'!=$1 GROUP BY value->>'status', value->'person'->>'org' ORDER BY SUM((value->>'amount')::numeric) DESC, value->>'status' LIMIT 2", q.query)
+	assert.Equal(t, []any{"CANCELED", "status", "person.org", "orders", "total", "smallest", "largest"}, q.params)
+}
+
+func TestPostgresqlQueryBuildQueryFields(t *testing.T) {
+	data, err := os.ReadFile("../../../../tests/state/query/q10-fields.json")
+	require.NoError(t, err)
+	var qq query.Query
+	err = json.Unmarshal(data, &qq)
+	require.NoError(t, err)
+
+	q := &Query{
+		tableName:  defaultTableName,
+		etagColumn: "xmin",
+	}
+	qbuilder := query.NewQueryBuilder(q)
+	err = qbuilder.BuildQuery(&qq)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT key, jsonb_build_array(jsonb_path_query_array(value, $2::jsonpath), jsonb_path_query_array(value, $3::jsonpath)), xmin as etag FROM state WHERE value->>'state'=$1 ORDER BY value->'person'->>'name' LIMIT 2", q.query)
+	assert.Equal(t, []any{"CA", `$."person"."name"`, `$."state"`}, q.params)
+
+	res, err := q.projectedValue([]byte(`[["Adam"], []]`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"person":{"name":"Adam"}}`, string(res))
+
+	res, err = q.projectedValue([]byte(`[[null], ["CA"]]`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"person":{"name":null},"state":"CA"}`, string(res))
+}
@@ -9,6 +9,7 @@ import (
 const (
 	MetadataKeyMessageID     = "messageID"
 	MetadataKeyCorrelationID = "correlationID"
+	MetadataKeyReplyTo       = "replyTo"
 	MetadataKeyContentType   = "contentType"
 	MetadataKeyType          = "type"
 	MetadataKeyPriority      = "priority"
@@ -46,6 +47,10 @@ func ApplyMetadataToPublishing(metadata map[string]string, publishing *amqp.Publ
 		publishing.CorrelationId = correlationID
 	}
 
+	if replyTo, ok := TryGetProperty(metadata, MetadataKeyReplyTo); ok {
+		publishing.ReplyTo = replyTo
+	}
+
 	if aType, ok := TryGetProperty(metadata, MetadataKeyType); ok {
 		publishing.Type = aType
 	}
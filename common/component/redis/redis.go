@@ -63,6 +63,13 @@ type RedisXPendingExt struct {
 	RetryCount int64
 }
 
+// RedisPubSubMessage is a message received on a Redis Pub/Sub channel.
+type RedisPubSubMessage struct {
+	Channel string
+	Pattern string
+	Payload string
+}
+
 type RedisPipeliner interface {
 	Exec(ctx context.Context) error
 	Do(ctx context.Context, args ...interface{})
@@ -80,6 +87,7 @@ type RedisClient interface {
 	Close() error
 	PingResult(ctx context.Context) (string, error)
 	ConfigurationSubscribe(ctx context.Context, args *ConfigurationSubscribeArgs)
+	PSubscribe(ctx context.Context, patterns ...string) (<-chan RedisPubSubMessage, error)
 	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (*bool, error)
 	EvalInt(ctx context.Context, script string, keys []string, args ...interface{}) (*int, error, error)
 	XAdd(ctx context.Context, stream string, maxLenApprox int64, streamTTL string, values map[string]interface{}) (string, error)
@@ -96,6 +96,8 @@ type Settings struct {
 	// == state only properties ==
 	TTLInSeconds *int   `mapstructure:"ttlInSeconds" mdonly:"state"`
 	QueryIndexes string `mapstructure:"queryIndexes" mdonly:"state"`
+	// Adds the flags required to watch keys to the notify-keyspace-events configuration of the server
+	EnableKeyspaceEvents bool `mapstructure:"enableKeyspaceEvents" mdonly:"state"`
 
 	// == pubsub only properties ==
 	// The consumer identifier
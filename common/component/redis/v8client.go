@@ -95,6 +95,44 @@ func (c v8Client) ConfigurationSubscribe(ctx context.Context, args *Configuratio
 	}
 }
 
+// PSubscribe subscribes to the channels matching the given patterns.
+// It returns once the subscription is confirmed; messages are then delivered on the returned channel until ctx is canceled.
+func (c v8Client) PSubscribe(ctx context.Context, patterns ...string) (<-chan RedisPubSubMessage, error) {
+	p := c.client.PSubscribe(ctx, patterns...)
+
+	// Wait for the confirmation of the subscription
+	_, err := p.Receive(ctx)
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+
+	ch := make(chan RedisPubSubMessage)
+	go func() {
+		defer close(ch)
+		defer p.Close()
+
+		msgCh := p.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgCh:
+				if !ok {
+					return
+				}
+				select {
+				case ch <- RedisPubSubMessage{Channel: msg.Channel, Pattern: msg.Pattern, Payload: msg.Payload}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
 func (c v8Client) Del(ctx context.Context, keys ...string) error {
 	err := c.client.Del(ctx, keys...).Err()
 	if err != nil {
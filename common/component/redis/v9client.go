@@ -73,6 +73,44 @@ func (c v9Client) DoRead(ctx context.Context, args ...interface{}) (interface{},
 	return c.client.Do(ctx, args...).Result()
 }
 
+// PSubscribe subscribes to the channels matching the given patterns.
+// It returns once the subscription is confirmed; messages are then delivered on the returned channel until ctx is canceled.
+func (c v9Client) PSubscribe(ctx context.Context, patterns ...string) (<-chan RedisPubSubMessage, error) {
+	p := c.client.PSubscribe(ctx, patterns...)
+
+	// Wait for the confirmation of the subscription
+	_, err := p.Receive(ctx)
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+
+	ch := make(chan RedisPubSubMessage)
+	go func() {
+		defer close(ch)
+		defer p.Close()
+
+		msgCh := p.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgCh:
+				if !ok {
+					return
+				}
+				select {
+				case ch <- RedisPubSubMessage{Channel: msg.Channel, Pattern: msg.Pattern, Payload: msg.Payload}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
 func (c v9Client) Del(ctx context.Context, keys ...string) error {
 	err := c.client.Del(ctx, keys...).Err()
 	if err != nil {
@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// IndexedFieldColumnPrefix is the prefix of the names of the generated columns and indexes used to index fields of the values in a state table.
+// They are created by the migrations, but they are not dropped when they are not listed in the component's metadata anymore.
+const IndexedFieldColumnPrefix = "dapr_idx_"
+
+// Segments of the paths are embedded in the definition of the generated columns and indexes, so they are restricted to a safe set of characters.
+var indexedFieldSegmentRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// IndexedField is a field of the JSON values stored in a state table that is indexed, using an expression index or a generated column.
+type IndexedField struct {
+	// Path of the field, in the dot notation used by queries, for example "person.org".
+	Path string
+}
+
+// ParseIndexedFields parses the list of paths of the fields to index, as set in the component's metadata.
+func ParseIndexedFields(paths []string) ([]IndexedField, error) {
+	res := make([]IndexedField, 0, len(paths))
+	found := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		for _, s := range strings.Split(p, ".") {
+			if !indexedFieldSegmentRegex.MatchString(s) {
+				return nil, fmt.Errorf("invalid path of indexed field '%s': segments can only contain letters, numbers, underscores, and dashes", p)
+			}
+		}
+		if _, ok := found[p]; ok {
+			continue
+		}
+		found[p] = struct{}{}
+		res = append(res, IndexedField{Path: p})
+	}
+	return res, nil
+}
+
+// Segments returns the segments of the path of the field.
+func (f IndexedField) Segments() []string {
+	return strings.Split(f.Path, ".")
+}
+
+// ColumnName returns the name of the generated column, which is also the prefix of the name of the index.
+// It includes a hash of the path, so different paths never map to the same column.
+func (f IndexedField) ColumnName() string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '_'
+		}
+	}, f.Path)
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	return IndexedFieldColumnPrefix + name + "_" + hashString(f.Path)
+}
+
+// IndexName returns the name of the index on a field in the given table, where columnName is returned by ColumnName.
+// Because index names are shared by all tables in a schema, it includes a hash of the table name.
+func IndexName(columnName string, tableName string) string {
+	return columnName + "_" + hashString(tableName)
+}
+
+// PostgresExpression returns the PostgreSQL expression of the value of the field as text, in the JSONB value column.
+// Expression indexes are only used by queries that filter or sort by the same expression.
+func (f IndexedField) PostgresExpression() string {
+	expr := "value"
+	segments := f.Segments()
+	for i, s := range segments {
+		if i == len(segments)-1 {
+			expr += "->>'" + s + "'"
+		} else {
+			expr += "->'" + s + "'"
+		}
+	}
+	return expr
+}
+
+// IndexedColumns returns a map of the paths of the indexed fields to the names of the generated columns.
+func IndexedColumns(fields []IndexedField) map[string]string {
+	res := make(map[string]string, len(fields))
+	for _, f := range fields {
+		res[f.Path] = f.ColumnName()
+	}
+	return res
+}
+
+func hashString(s string) string {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return strconv.FormatUint(uint64(h.Sum32()), 16)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIndexedFields(t *testing.T) {
+	t.Run("valid paths", func(t *testing.T) {
+		fields, err := ParseIndexedFields([]string{" person.org ", "", "state", "person.org", "my-field_1"})
+		require.NoError(t, err)
+		require.Len(t, fields, 3)
+		assert.Equal(t, "person.org", fields[0].Path)
+		assert.Equal(t, []string{"person", "org"}, fields[0].Segments())
+		assert.Equal(t, "state", fields[1].Path)
+		assert.Equal(t, "my-field_1", fields[2].Path)
+	})
+
+	t.Run("invalid paths", func(t *testing.T) {
+		for _, p := range []string{"person..org", ".state", "state.", "person.o'rg", "a b"} {
+			_, err := ParseIndexedFields([]string{p})
+			require.Errorf(t, err, "expected error for path %q", p)
+		}
+	})
+}
+
+func TestIndexedFieldColumnName(t *testing.T) {
+	a := IndexedField{Path: "person.org"}.ColumnName()
+	b := IndexedField{Path: "person_org"}.ColumnName()
+	assert.True(t, strings.HasPrefix(a, IndexedFieldColumnPrefix+"person_org_"))
+	assert.NotEqual(t, a, b)
+	assert.Equal(t, a, IndexedField{Path: "person.org"}.ColumnName())
+
+	long := IndexedField{Path: strings.Repeat("a", 100)}.ColumnName()
+	assert.LessOrEqual(t, len(IndexName(long, "schema.table")), 63)
+}
+
+func TestIndexedFieldPostgresExpression(t *testing.T) {
+	assert.Equal(t, "value->>'state'", IndexedField{Path: "state"}.PostgresExpression())
+	assert.Equal(t, "value->'person'->>'org'", IndexedField{Path: "person.org"}.PostgresExpression())
+}
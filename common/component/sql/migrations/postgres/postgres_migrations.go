@@ -17,6 +17,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgerrcode"
@@ -33,10 +34,33 @@ type Migrations struct {
 	Logger            logger.Logger
 	MetadataTableName string
 	MetadataKey       string
+
+	// Optional name of the state table and list of fields of its values to index.
+	// Indexes are created every time migrations are performed, after the migration lock is released.
+	StateTableName string
+	IndexedFields  []commonsql.IndexedField
 }
 
 // Perform the required migrations
 func (m Migrations) Perform(ctx context.Context, migrationFns []commonsql.MigrationFn) error {
+	err := m.migrate(ctx, migrationFns)
+	if err != nil {
+		return err
+	}
+
+	// Indexes are built concurrently, which can't be done in a transaction and can take a long time on large tables
+	// So, they are created without holding the migration lock
+	if m.StateTableName != "" {
+		err = m.EnsureIndexedFields(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to update indexed fields: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (m Migrations) migrate(ctx context.Context, migrationFns []commonsql.MigrationFn) error {
 	// Ensure the metadata table exists
 	// This query uses an "IF NOT EXISTS" so it's safe to be created concurrently
 	err := m.EnsureMetadataTable(ctx)
@@ -89,7 +113,7 @@ func (m Migrations) Perform(ctx context.Context, migrationFns []commonsql.Migrat
 	}
 	m.Logger.Debug("Migration lock acquired")
 
-	return commonsql.Migrate(ctx, commonsql.AdaptPgxConn(m.DB), commonsql.MigrationOptions{
+	err = commonsql.Migrate(ctx, commonsql.AdaptPgxConn(m.DB), commonsql.MigrationOptions{
 		Logger: m.Logger,
 		// Yes, we are using fmt.Sprintf for adding a value in a query.
 		// This comes from a constant hardcoded at development-time, and cannot be influenced by users. So, no risk of SQL injections here.
@@ -100,6 +124,90 @@ func (m Migrations) Perform(ctx context.Context, migrationFns []commonsql.Migrat
 		},
 		Migrations: migrationFns,
 	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EnsureIndexedFields creates the indexes for the fields that are indexed.
+// Indexes are created on the expressions used by queries, with CREATE INDEX CONCURRENTLY, so the table is neither rewritten nor locked for writes.
+// Indexes of fields that aren't indexed anymore are not dropped, as the same table could be used by components with different indexed fields.
+// It is invoked by Perform when StateTableName is set, and it can be used by components that don't use the migrations framework.
+func (m Migrations) EnsureIndexedFields(ctx context.Context) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	rows, err := m.DB.Query(queryCtx,
+		`SELECT c.relname, i.indisvalid FROM pg_index i JOIN pg_class c ON c.oid = i.indexrelid WHERE i.indrelid = to_regclass($1) AND c.relname LIKE $2`,
+		m.StateTableName, strings.ReplaceAll(commonsql.IndexedFieldColumnPrefix, "_", `\_`)+"%",
+	)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to list indexes: %w", err)
+	}
+	existing := map[string]bool{}
+	for rows.Next() {
+		var (
+			name  string
+			valid bool
+		)
+		err = rows.Scan(&name, &valid)
+		if err != nil {
+			rows.Close()
+			cancel()
+			return fmt.Errorf("failed to list indexes: %w", err)
+		}
+		existing[name] = valid
+	}
+	rows.Close()
+	cancel()
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to list indexes: %w", err)
+	}
+
+	// Table names can be in the format "schema.table", and index names must not include the schema
+	tableName := m.StateTableName
+	if idx := strings.LastIndexByte(tableName, '.'); idx >= 0 {
+		tableName = tableName[idx+1:]
+	}
+
+	wanted := make(map[string]struct{}, len(m.IndexedFields))
+	for _, f := range m.IndexedFields {
+		name := commonsql.IndexName(f.ColumnName(), tableName)
+		wanted[name] = struct{}{}
+		valid, ok := existing[name]
+		if ok {
+			// Indexes that failed to build are left invalid, and they could also still be built by another instance, so they're not dropped here
+			if !valid {
+				m.Logger.Warnf("Index '%s' on field '%s' in table '%s' is not valid and is not used by queries: if it's not being created, drop it with 'DROP INDEX CONCURRENTLY %s' to create it again", name, f.Path, m.StateTableName, name)
+			}
+			continue
+		}
+
+		// There's no timeout, as building the index can take a long time on large tables, and it doesn't block reads nor writes
+		m.Logger.Infof("Creating index '%s' on field '%s' in table '%s'", name, f.Path, m.StateTableName)
+		_, err = m.DB.Exec(ctx, fmt.Sprintf(
+			`CREATE INDEX CONCURRENTLY IF NOT EXISTS %s ON %s ((%s))`,
+			name, m.StateTableName, f.PostgresExpression(),
+		))
+		if err != nil {
+			// Another instance created the same index at the same time
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && (pgErr.Code == pgerrcode.DuplicateTable || pgErr.Code == pgerrcode.UniqueViolation) {
+				m.Logger.Debugf("Index '%s' was created concurrently: %v", name, err)
+				continue
+			}
+			return fmt.Errorf("failed to create index for field '%s': %w", f.Path, err)
+		}
+	}
+
+	for name := range existing {
+		if _, ok := wanted[name]; !ok {
+			m.Logger.Infof("Index '%s' in table '%s' is not on an indexed field anymore: drop it with 'DROP INDEX CONCURRENTLY %s' if it's not used", name, m.StateTableName, name)
+		}
+	}
+
+	return nil
 }
 
 func (m Migrations) EnsureMetadataTable(ctx context.Context) (err error) {
@@ -17,6 +17,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	commonsql "github.com/dapr/components-contrib/common/component/sql"
@@ -30,6 +31,11 @@ type Migrations struct {
 	MetadataTableName string
 	MetadataKey       string
 
+	// Optional name of the state table and list of fields of its values to index using generated columns.
+	// Generated columns and their indexes are reconciled every time migrations are performed, within the same transaction.
+	StateTableName string
+	IndexedFields  []commonsql.IndexedField
+
 	conn *sql.Conn
 }
 
@@ -104,6 +110,13 @@ func (m *Migrations) Perform(ctx context.Context, migrationFns []commonsql.Migra
 		return err
 	}
 
+	if m.StateTableName != "" {
+		err = m.ensureIndexedFields(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to update indexed fields: %w", err)
+		}
+	}
+
 	// Commit the transaction
 	queryCtx, cancel = context.WithTimeout(ctx, time.Minute)
 	_, err = m.conn.ExecContext(queryCtx, "COMMIT TRANSACTION")
@@ -118,6 +131,74 @@ func (m *Migrations) Perform(ctx context.Context, migrationFns []commonsql.Migra
 	return nil
 }
 
+// ensureIndexedFields creates the generated columns and indexes for the fields that are indexed.
+func (m *Migrations) ensureIndexedFields(ctx context.Context) error {
+	// pragma_table_xinfo is used because pragma_table_info does not return generated columns
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	rows, err := m.conn.QueryContext(queryCtx,
+		`SELECT name FROM pragma_table_xinfo(?) WHERE name LIKE ? ESCAPE '\'`,
+		m.StateTableName, strings.ReplaceAll(commonsql.IndexedFieldColumnPrefix, "_", `\_`)+"%",
+	)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to list generated columns: %w", err)
+	}
+	existing := map[string]struct{}{}
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			rows.Close()
+			cancel()
+			return fmt.Errorf("failed to list generated columns: %w", err)
+		}
+		existing[name] = struct{}{}
+	}
+	rows.Close()
+	cancel()
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to list generated columns: %w", err)
+	}
+
+	for _, f := range m.IndexedFields {
+		column := f.ColumnName()
+		if _, ok := existing[column]; ok {
+			delete(existing, column)
+			continue
+		}
+
+		// Columns added with ALTER TABLE can only be virtual, but their values are stored in the index
+		// Values that are not valid JSON (such as binary values, which are stored base64-encoded) are indexed as NULL
+		m.Logger.Infof("Adding generated column '%s' to index field '%s' in table '%s'", column, f.Path, m.StateTableName)
+		queryCtx, cancel = context.WithTimeout(ctx, time.Minute)
+		_, err = m.conn.ExecContext(queryCtx, fmt.Sprintf(
+			`ALTER TABLE %s ADD COLUMN %s GENERATED ALWAYS AS (CASE WHEN json_valid(value) THEN json_extract(value, '$."%s"') END) VIRTUAL`,
+			m.StateTableName, column, strings.Join(f.Segments(), `"."`),
+		))
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to add generated column for field '%s': %w", f.Path, err)
+		}
+
+		queryCtx, cancel = context.WithTimeout(ctx, time.Minute)
+		_, err = m.conn.ExecContext(queryCtx, fmt.Sprintf(
+			`CREATE INDEX IF NOT EXISTS %s ON %s (%s)`,
+			commonsql.IndexName(column, m.StateTableName), m.StateTableName, column,
+		))
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to create index for field '%s': %w", f.Path, err)
+		}
+	}
+
+	// Columns of fields that aren't indexed anymore are not dropped, as dropping a column rewrites the table
+	for column := range existing {
+		m.Logger.Infof("Generated column '%s' in table '%s' is not on an indexed field anymore: drop its index '%s' and then the column if it's not used", column, m.StateTableName, commonsql.IndexName(column, m.StateTableName))
+	}
+
+	return nil
+}
+
 // GetConn returns the active connection.
 func (m *Migrations) GetConn() *sql.Conn {
 	return m.conn
@@ -30,6 +30,36 @@ const (
 	TTLMetadataKey          = "ttl"
 	TTLInSecondsMetadataKey = "ttlInSeconds"
 
+	// DeliverAtMetadataKey defines the metadata key for setting the time when a message is delivered (in RFC3339 format).
+	DeliverAtMetadataKey = "deliverAt"
+	// DeliverAfterMetadataKey defines the metadata key for setting a delay before a message is delivered (as a Go duration or number of seconds).
+	DeliverAfterMetadataKey = "deliverAfter"
+
+	// OrderingKeyMetadataKey defines the metadata key for setting the ordering key of a message.
+	// Pubsub components that support ordered delivery deliver messages with the same ordering key in the order they were published.
+	OrderingKeyMetadataKey = "orderingKey"
+
+	// DeadLetterTopicMetadataKey defines the subscription metadata key for setting the topic where messages that can't be delivered are sent.
+	// Pubsub components that support dead-lettering route these messages to the topic natively.
+	DeadLetterTopicMetadataKey = "deadLetterTopic"
+
+	// StartFromMetadataKey defines the subscription metadata key for setting where a subscription starts receiving messages.
+	// The value is one of "earliest", "latest", "timestamp" (set with StartTimestampMetadataKey) or "offset" (set with StartOffsetMetadataKey).
+	// It applies only to consumer groups that don't have a stored position yet.
+	StartFromMetadataKey = "startFrom"
+	// StartTimestampMetadataKey defines the subscription metadata key for setting the time a subscription starts from (in RFC3339 format).
+	StartTimestampMetadataKey = "startTimestamp"
+	// StartOffsetMetadataKey defines the subscription metadata key for setting the position a subscription starts from.
+	// The format of the value depends on the component, for example a Kafka offset or a Redis stream ID.
+	StartOffsetMetadataKey = "startOffset"
+
+	// CorrelationIDMetadataKey defines the metadata key for setting the ID that correlates a reply with its request.
+	// Pubsub components that support request/reply copy it from the request to the reply.
+	CorrelationIDMetadataKey = "correlationID"
+	// ReplyToMetadataKey defines the metadata key for setting the topic where the reply to a request is published.
+	// Responders publish the reply to this topic with the same correlation ID as the request.
+	ReplyToMetadataKey = "replyTo"
+
 	// RawPayloadKey defines the metadata key for forcing raw payload in pubsub.
 	RawPayloadKey = "rawPayload"
 
@@ -78,6 +108,91 @@ func TryGetTTL(props map[string]string) (time.Duration, bool, error) {
 	return duration, true, nil
 }
 
+// TryGetDeliveryTime tries to get the time when a message is delivered, for pubsub components that support delayed delivery.
+// The time is set with either DeliverAtMetadataKey or DeliverAfterMetadataKey, which is relative to now.
+func TryGetDeliveryTime(props map[string]string, now time.Time) (time.Time, bool, error) {
+	at, after := props[DeliverAtMetadataKey], props[DeliverAfterMetadataKey]
+	switch {
+	case at != "" && after != "":
+		return time.Time{}, false, fmt.Errorf("only one of %s and %s can be set", DeliverAtMetadataKey, DeliverAfterMetadataKey)
+	case at != "":
+		t, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%s value must be a time in RFC3339 format: actual is '%s'", DeliverAtMetadataKey, at)
+		}
+		return t, true, nil
+	case after != "":
+		d, err := time.ParseDuration(after)
+		if err != nil {
+			// Assume the value is in seconds
+			secs, err := strconv.ParseInt(after, 10, 64)
+			if err != nil {
+				return time.Time{}, false, fmt.Errorf("%s value must be a valid duration: actual is '%s'", DeliverAfterMetadataKey, after)
+			}
+			d = time.Duration(secs) * time.Second
+		}
+		if d < 0 {
+			return time.Time{}, false, fmt.Errorf("%s value must not be negative: actual is '%s'", DeliverAfterMetadataKey, after)
+		}
+		return now.Add(d), true, nil
+	default:
+		return time.Time{}, false, nil
+	}
+}
+
+// StartFrom is where a subscription starts receiving messages.
+type StartFrom string
+
+const (
+	// StartFromEarliest starts from the oldest message that is still retained.
+	StartFromEarliest StartFrom = "earliest"
+	// StartFromLatest starts from messages published after the subscription is created.
+	StartFromLatest StartFrom = "latest"
+	// StartFromTimestamp starts from the first message published at or after a time.
+	StartFromTimestamp StartFrom = "timestamp"
+	// StartFromOffset starts from a component-specific position.
+	StartFromOffset StartFrom = "offset"
+)
+
+// StartPosition is the position a subscription starts receiving messages from.
+type StartPosition struct {
+	From StartFrom
+	// Timestamp is set when From is StartFromTimestamp.
+	Timestamp time.Time
+	// Offset is set when From is StartFromOffset.
+	Offset string
+}
+
+// TryGetStartPosition tries to get the position a subscription starts from, for pubsub components that support replay.
+// The position is set with StartFromMetadataKey, and with StartTimestampMetadataKey or StartOffsetMetadataKey when required.
+func TryGetStartPosition(props map[string]string) (StartPosition, bool, error) {
+	val, ok := props[StartFromMetadataKey]
+	if !ok || val == "" {
+		return StartPosition{}, false, nil
+	}
+
+	pos := StartPosition{From: StartFrom(strings.ToLower(val))}
+	switch pos.From {
+	case StartFromEarliest, StartFromLatest:
+		// Nop
+	case StartFromTimestamp:
+		ts := props[StartTimestampMetadataKey]
+		t, err := time.Parse(time.RFC3339, ts)
+		if err != nil {
+			return StartPosition{}, false, fmt.Errorf("%s value must be a time in RFC3339 format when %s is '%s': actual is '%s'", StartTimestampMetadataKey, StartFromMetadataKey, StartFromTimestamp, ts)
+		}
+		pos.Timestamp = t
+	case StartFromOffset:
+		pos.Offset = props[StartOffsetMetadataKey]
+		if pos.Offset == "" {
+			return StartPosition{}, false, fmt.Errorf("%s value is required when %s is '%s'", StartOffsetMetadataKey, StartFromMetadataKey, StartFromOffset)
+		}
+	default:
+		return StartPosition{}, false, fmt.Errorf("%s value must be one of 'earliest', 'latest', 'timestamp' or 'offset': actual is '%s'", StartFromMetadataKey, val)
+	}
+	return pos, true, nil
+}
+
 // TryGetPriority tries to get the priority for binding and any other building block.
 func TryGetPriority(props map[string]string) (uint8, bool, error) {
 	if val, ok := props[PriorityMetadataKey]; ok && val != "" {
@@ -130,6 +130,104 @@ func TestTryGetTTL(t *testing.T) {
 	}
 }
 
+func TestTryGetDeliveryTime(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("no delivery time", func(t *testing.T) {
+		_, ok, err := TryGetDeliveryTime(map[string]string{}, now)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("deliver at", func(t *testing.T) {
+		d, ok, err := TryGetDeliveryTime(map[string]string{DeliverAtMetadataKey: "2025-01-02T04:00:00Z"}, now)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2025, 1, 2, 4, 0, 0, 0, time.UTC), d)
+	})
+
+	t.Run("deliver after duration", func(t *testing.T) {
+		d, ok, err := TryGetDeliveryTime(map[string]string{DeliverAfterMetadataKey: "1m30s"}, now)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, now.Add(90*time.Second), d)
+	})
+
+	t.Run("deliver after seconds", func(t *testing.T) {
+		d, ok, err := TryGetDeliveryTime(map[string]string{DeliverAfterMetadataKey: "20"}, now)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, now.Add(20*time.Second), d)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		for _, md := range []map[string]string{
+			{DeliverAtMetadataKey: "tomorrow"},
+			{DeliverAfterMetadataKey: "soon"},
+			{DeliverAfterMetadataKey: "-1s"},
+			{DeliverAtMetadataKey: "2025-01-02T04:00:00Z", DeliverAfterMetadataKey: "1s"},
+		} {
+			_, ok, err := TryGetDeliveryTime(md, now)
+			require.Error(t, err)
+			assert.False(t, ok)
+		}
+	})
+}
+
+func TestTryGetStartPosition(t *testing.T) {
+	t.Run("no start position", func(t *testing.T) {
+		_, ok, err := TryGetStartPosition(map[string]string{})
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("earliest and latest", func(t *testing.T) {
+		pos, ok, err := TryGetStartPosition(map[string]string{StartFromMetadataKey: "earliest"})
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, StartPosition{From: StartFromEarliest}, pos)
+
+		pos, ok, err = TryGetStartPosition(map[string]string{StartFromMetadataKey: "Latest"})
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, StartPosition{From: StartFromLatest}, pos)
+	})
+
+	t.Run("timestamp", func(t *testing.T) {
+		pos, ok, err := TryGetStartPosition(map[string]string{
+			StartFromMetadataKey:      "timestamp",
+			StartTimestampMetadataKey: "2025-01-02T03:04:05Z",
+		})
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, StartFromTimestamp, pos.From)
+		assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), pos.Timestamp)
+	})
+
+	t.Run("offset", func(t *testing.T) {
+		pos, ok, err := TryGetStartPosition(map[string]string{
+			StartFromMetadataKey:   "offset",
+			StartOffsetMetadataKey: "42",
+		})
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, StartPosition{From: StartFromOffset, Offset: "42"}, pos)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		for _, md := range []map[string]string{
+			{StartFromMetadataKey: "beginning"},
+			{StartFromMetadataKey: "timestamp"},
+			{StartFromMetadataKey: "timestamp", StartTimestampMetadataKey: "yesterday"},
+			{StartFromMetadataKey: "offset"},
+		} {
+			_, ok, err := TryGetStartPosition(md)
+			require.Error(t, err)
+			assert.False(t, ok)
+		}
+	})
+}
+
 func TestIsRawPayload(t *testing.T) {
 	t.Run("Metadata not found", func(t *testing.T) {
 		val, err := IsRawPayload(map[string]string{
@@ -48,7 +48,7 @@ func (aeh *AzureEventHubs) Init(_ context.Context, metadata pubsub.Metadata) err
 }
 
 func (aeh *AzureEventHubs) Features() []pubsub.Feature {
-	return []pubsub.Feature{pubsub.FeatureBulkPublish}
+	return []pubsub.Feature{pubsub.FeatureBulkPublish, pubsub.FeatureReplay}
 }
 
 // Publish sends a message to Azure Event Hubs.
@@ -135,6 +135,10 @@ func (aeh *AzureEventHubs) Subscribe(ctx context.Context, req pubsub.SubscribeRe
 	}
 
 	checkPointFrequencyPerPartition := commonutils.GetIntValFromString(req.Metadata["checkPointFrequencyPerPartition"], impl.DefaultCheckpointFrequencyPerPartition)
+	startPosition, err := getStartPosition(req.Metadata)
+	if err != nil {
+		return err
+	}
 
 	pubsubHandler := aeh.GetPubSubHandlerFunc(topic, getAllProperties, handler)
 
@@ -144,6 +148,7 @@ func (aeh *AzureEventHubs) Subscribe(ctx context.Context, req pubsub.SubscribeRe
 		MaxBulkSubAwaitDurationMs:       impl.DefaultMaxBulkSubAwaitDurationMs,
 		CheckPointFrequencyPerPartition: checkPointFrequencyPerPartition,
 		Handler:                         pubsubHandler,
+		StartPosition:                   startPosition,
 	}
 	// Start the subscription
 	// This is non-blocking
@@ -163,6 +168,10 @@ func (aeh *AzureEventHubs) BulkSubscribe(ctx context.Context, req pubsub.Subscri
 		getAllProperties = aeh.GetAllMessageProperties()
 	}
 	checkPointFrequencyPerPartition := commonutils.GetIntValFromString(req.Metadata["checkPointFrequencyPerPartition"], impl.DefaultCheckpointFrequencyPerPartition)
+	startPosition, err := getStartPosition(req.Metadata)
+	if err != nil {
+		return err
+	}
 	maxBulkSubCount := commonutils.GetIntValOrDefault(req.BulkSubscribeConfig.MaxMessagesCount, impl.DefaultMaxBulkSubCount)
 	maxBulkSubAwaitDurationMs := commonutils.GetIntValOrDefault(req.BulkSubscribeConfig.MaxAwaitDurationMs, impl.DefaultMaxBulkSubAwaitDurationMs)
 
@@ -174,6 +183,7 @@ func (aeh *AzureEventHubs) BulkSubscribe(ctx context.Context, req pubsub.Subscri
 		MaxBulkSubAwaitDurationMs:       maxBulkSubAwaitDurationMs,
 		CheckPointFrequencyPerPartition: checkPointFrequencyPerPartition,
 		Handler:                         bulkPubsubHandler,
+		StartPosition:                   startPosition,
 	}
 
 	// Start the subscription
@@ -191,3 +201,12 @@ func (aeh *AzureEventHubs) GetComponentMetadata() (metadataInfo metadata.Metadat
 	metadata.GetMetadataInfoFromStructType(reflect.TypeOf(metadataStruct), &metadataInfo, metadata.PubSubType)
 	return
 }
+
+// getStartPosition returns the position the partitions without a stored checkpoint start from, or nil if not set.
+func getStartPosition(md map[string]string) (*metadata.StartPosition, error) {
+	pos, ok, err := metadata.TryGetStartPosition(md)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &pos, nil
+}
@@ -223,6 +223,7 @@ func (a *azureServiceBus) Features() []pubsub.Feature {
 	return []pubsub.Feature{
 		pubsub.FeatureMessageTTL,
 		pubsub.FeatureBulkPublish,
+		pubsub.FeatureDelayedDelivery,
 	}
 }
 
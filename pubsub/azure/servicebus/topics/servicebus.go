@@ -108,6 +108,7 @@ func (a *azureServiceBus) Subscribe(subscribeCtx context.Context, req pubsub.Sub
 	return a.doSubscribe(subscribeCtx, req, sub, handlerFn, impl.SubscribeOptions{
 		RequireSessions:      requireSessions,
 		MaxConcurrentSesions: maxConcurrentSessions,
+		DeadLetterTopic:      req.Metadata[metadata.DeadLetterTopicMetadataKey],
 	})
 }
 
@@ -140,6 +141,7 @@ func (a *azureServiceBus) BulkSubscribe(subscribeCtx context.Context, req pubsub
 	return a.doSubscribe(subscribeCtx, req, sub, handlerFn, impl.SubscribeOptions{
 		RequireSessions:      requireSessions,
 		MaxConcurrentSesions: maxConcurrentSessions,
+		DeadLetterTopic:      req.Metadata[metadata.DeadLetterTopicMetadataKey],
 	})
 }
 
@@ -163,6 +165,11 @@ func (a *azureServiceBus) doSubscribe(
 		}
 	}()
 
+	// Without entity management, dead-lettered messages are forwarded only if the subscription is configured to do so in Service Bus
+	if opts.DeadLetterTopic != "" && a.metadata.DisableEntityManagement {
+		a.logger.Warnf("Entity management is disabled, so subscription %s to topic %s is not configured to forward dead-lettered messages to %s: they are forwarded only if the subscription is already configured to", a.metadata.ConsumerID, req.Topic, opts.DeadLetterTopic)
+	}
+
 	// Does nothing if DisableEntityManagement is true
 	err := a.client.EnsureSubscription(subscribeCtx, a.metadata.ConsumerID, req.Topic, opts)
 	if err != nil {
@@ -221,6 +228,9 @@ func (a *azureServiceBus) Features() []pubsub.Feature {
 	return []pubsub.Feature{
 		pubsub.FeatureMessageTTL,
 		pubsub.FeatureBulkPublish,
+		pubsub.FeatureDelayedDelivery,
+		// Messages that exceed the max delivery count are forwarded to the dead-letter topic by Service Bus
+		pubsub.FeatureDeadLetter,
 	}
 }
 
@@ -13,7 +13,10 @@ limitations under the License.
 
 package pubsub
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+)
 
 // ConcurrencyMode is a pub/sub metadata setting that allows to specify whether messages are delivered in a serial or parallel execution.
 type ConcurrencyMode string
@@ -40,3 +43,64 @@ func Concurrency(metadata map[string]string) (ConcurrencyMode, error) {
 
 	return Parallel, nil
 }
+
+// OrderedDispatcher runs functions concurrently, except functions dispatched with the same ordering key, which run one at a time in the order they were dispatched.
+// Components use it to invoke handlers in parallel while still delivering messages with the same ordering key in order.
+type OrderedDispatcher struct {
+	lock   sync.Mutex
+	queues map[string][]func()
+	wg     sync.WaitGroup
+}
+
+// NewOrderedDispatcher returns a new OrderedDispatcher.
+func NewOrderedDispatcher() *OrderedDispatcher {
+	return &OrderedDispatcher{
+		queues: map[string][]func(){},
+	}
+}
+
+// Dispatch runs fn in the background, after all the functions dispatched before it with the same key have returned.
+// Functions dispatched with an empty key are not ordered.
+func (d *OrderedDispatcher) Dispatch(key string, fn func()) {
+	d.wg.Add(1)
+	if key == "" {
+		go func() {
+			defer d.wg.Done()
+			fn()
+		}()
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	queue, running := d.queues[key]
+	d.queues[key] = append(queue, fn)
+	if !running {
+		go d.run(key)
+	}
+}
+
+// run runs the functions dispatched with the key until there are none left.
+func (d *OrderedDispatcher) run(key string) {
+	for {
+		d.lock.Lock()
+		queue := d.queues[key]
+		if len(queue) == 0 {
+			delete(d.queues, key)
+			d.lock.Unlock()
+			return
+		}
+		fn := queue[0]
+		queue[0] = nil
+		d.queues[key] = queue[1:]
+		d.lock.Unlock()
+
+		fn()
+		d.wg.Done()
+	}
+}
+
+// Wait blocks until all the dispatched functions have returned.
+func (d *OrderedDispatcher) Wait() {
+	d.wg.Wait()
+}
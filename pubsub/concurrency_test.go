@@ -14,7 +14,10 @@ limitations under the License.
 package pubsub
 
 import (
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -50,3 +53,65 @@ func TestConcurrency(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestOrderedDispatcher(t *testing.T) {
+	t.Run("functions with the same key run in order", func(t *testing.T) {
+		d := NewOrderedDispatcher()
+
+		var lock sync.Mutex
+		running := map[string]bool{}
+		received := map[string][]int{}
+		for i := range 30 {
+			key := "key" + strconv.Itoa(i%3)
+			d.Dispatch(key, func() {
+				lock.Lock()
+				assert.False(t, running[key], "functions with the same key must not run concurrently")
+				running[key] = true
+				lock.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				lock.Lock()
+				running[key] = false
+				received[key] = append(received[key], i)
+				lock.Unlock()
+			})
+		}
+		d.Wait()
+
+		require.Len(t, received, 3)
+		for k, seq := range received {
+			n, _ := strconv.Atoi(k[len("key"):])
+			expect := make([]int, 0, 10)
+			for i := n; i < 30; i += 3 {
+				expect = append(expect, i)
+			}
+			assert.Equal(t, expect, seq, "key %s", k)
+		}
+	})
+
+	t.Run("functions with different keys run concurrently", func(t *testing.T) {
+		d := NewOrderedDispatcher()
+
+		// Each function blocks until all of them have started
+		var wg sync.WaitGroup
+		wg.Add(3)
+		for _, key := range []string{"a", "b", ""} {
+			d.Dispatch(key, func() {
+				wg.Done()
+				wg.Wait()
+			})
+		}
+
+		done := make(chan struct{})
+		go func() {
+			d.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "functions did not run concurrently")
+		}
+	})
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/kit/logger"
+)
+
+// DefaultDeduplicatorKeyPrefix is the default prefix of the state keys of a Deduplicator.
+const DefaultDeduplicatorKeyPrefix = "pubsub-dedup||"
+
+// ErrMessageInProgress is returned by deduplicating handlers when a message with the same ID is being processed.
+// The message is redelivered by the component, and skipped if the other delivery was processed successfully.
+var ErrMessageInProgress = errors.New("a message with the same ID is being processed")
+
+// DeduplicatorOptions contains the options of a Deduplicator.
+type DeduplicatorOptions struct {
+	// TTL is how long the IDs of processed messages are stored for.
+	// If zero, they are stored until the state store evicts them.
+	TTL time.Duration
+	// KeyPrefix is prepended to the state keys. Defaults to DefaultDeduplicatorKeyPrefix.
+	KeyPrefix string
+	// Logger is used to log errors that don't fail the processing of a message.
+	Logger logger.Logger
+}
+
+// DeduplicatorStats contains the number of messages seen by a Deduplicator.
+type DeduplicatorStats struct {
+	// Hits is the number of messages that were skipped because they were already processed.
+	Hits uint64
+	// Misses is the number of messages that were passed to the handler.
+	Misses uint64
+}
+
+// Deduplicator wraps subscription handlers so messages that were already processed are skipped, for pubsub components with at-least-once delivery.
+// Messages are identified by the ID of their CloudEvent within their topic, and the IDs of processed messages are stored in a state store.
+// Messages that aren't CloudEvents are always passed to the handler.
+type Deduplicator struct {
+	store     state.Store
+	ttl       time.Duration
+	keyPrefix string
+	logger    logger.Logger
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+
+	lock       sync.Mutex
+	inProgress map[string]struct{}
+}
+
+// NewDeduplicator returns a new Deduplicator that stores the IDs of processed messages in store.
+// If opts.TTL is set, the state store must support TTLs, or the IDs would never expire.
+func NewDeduplicator(store state.Store, opts DeduplicatorOptions) (*Deduplicator, error) {
+	if opts.TTL > 0 && !state.FeatureTTL.IsPresent(store.Features()) {
+		return nil, errors.New("state store must support TTLs to store the IDs of processed messages with a TTL")
+	}
+
+	d := &Deduplicator{
+		store:      store,
+		ttl:        opts.TTL,
+		keyPrefix:  opts.KeyPrefix,
+		logger:     opts.Logger,
+		inProgress: map[string]struct{}{},
+	}
+	if d.keyPrefix == "" {
+		d.keyPrefix = DefaultDeduplicatorKeyPrefix
+	}
+	if d.logger == nil {
+		d.logger = logger.NewLogger("dapr.contrib.pubsub.dedup")
+	}
+	return d, nil
+}
+
+// Stats returns the number of messages seen by the handlers of the Deduplicator.
+func (d *Deduplicator) Stats() DeduplicatorStats {
+	return DeduplicatorStats{
+		Hits:   d.hits.Load(),
+		Misses: d.misses.Load(),
+	}
+}
+
+// Handler returns a handler that invokes handler only for messages that weren't processed successfully before.
+func (d *Deduplicator) Handler(handler Handler) Handler {
+	return func(ctx context.Context, msg *NewMessage) error {
+		key, ok := d.key(msg.Topic, msg.Data)
+		if !ok {
+			return handler(ctx, msg)
+		}
+
+		if !d.startProcessing(key) {
+			return ErrMessageInProgress
+		}
+		defer d.doneProcessing(key)
+
+		processed, err := d.isProcessed(ctx, key)
+		if err != nil {
+			return err
+		}
+		if processed {
+			d.hits.Add(1)
+			return nil
+		}
+
+		d.misses.Add(1)
+		err = handler(ctx, msg)
+		if err != nil {
+			return err
+		}
+		d.markProcessed(ctx, key)
+		return nil
+	}
+}
+
+// BulkHandler returns a bulk handler that invokes handler only with the entries that weren't processed successfully before.
+// Entries that were already processed are reported as successful.
+func (d *Deduplicator) BulkHandler(handler BulkHandler) BulkHandler {
+	return func(ctx context.Context, msg *BulkMessage) ([]BulkSubscribeResponseEntry, error) {
+		// Entries that aren't passed to the handler, with a nil error if they were already processed
+		skipped := make(map[string]error, len(msg.Entries))
+		keys := make(map[string]string, len(msg.Entries))
+		started := make([]string, 0, len(msg.Entries))
+		defer func() {
+			for _, key := range started {
+				d.doneProcessing(key)
+			}
+		}()
+
+		entries := make([]BulkMessageEntry, 0, len(msg.Entries))
+		for _, entry := range msg.Entries {
+			key, ok := d.key(msg.Topic, entry.Event)
+			if !ok {
+				entries = append(entries, entry)
+				continue
+			}
+			if !d.startProcessing(key) {
+				skipped[entry.EntryId] = ErrMessageInProgress
+				continue
+			}
+			started = append(started, key)
+
+			processed, err := d.isProcessed(ctx, key)
+			switch {
+			case err != nil:
+				skipped[entry.EntryId] = err
+			case processed:
+				d.hits.Add(1)
+				skipped[entry.EntryId] = nil
+			default:
+				d.misses.Add(1)
+				keys[entry.EntryId] = key
+				entries = append(entries, entry)
+			}
+		}
+
+		var (
+			res []BulkSubscribeResponseEntry
+			err error
+		)
+		if len(entries) > 0 {
+			handlerMsg := *msg
+			handlerMsg.Entries = entries
+			res, err = handler(ctx, &handlerMsg)
+		}
+		handlerErrs := make(map[string]error, len(res))
+		for _, r := range res {
+			handlerErrs[r.EntryId] = r.Error
+		}
+
+		failed := err != nil
+		statuses := make([]BulkSubscribeResponseEntry, 0, len(msg.Entries))
+		for _, entry := range msg.Entries {
+			entryErr, ok := skipped[entry.EntryId]
+			if !ok {
+				entryErr, ok = handlerErrs[entry.EntryId]
+				if !ok {
+					// Entries without a status failed only if the handler failed
+					entryErr = err
+				}
+				if key, ok := keys[entry.EntryId]; ok && entryErr == nil {
+					d.markProcessed(ctx, key)
+				}
+			}
+			if entryErr != nil {
+				failed = true
+			}
+			statuses = append(statuses, BulkSubscribeResponseEntry{
+				EntryId: entry.EntryId,
+				Error:   entryErr,
+			})
+		}
+
+		if failed && err == nil {
+			err = errors.New("failed to process some entries of the bulk message")
+		}
+		return statuses, err
+	}
+}
+
+// key returns the state key for a message, if it's a CloudEvent with an ID.
+func (d *Deduplicator) key(topic string, data []byte) (string, bool) {
+	var event map[string]any
+	if json.Unmarshal(data, &event) != nil {
+		return "", false
+	}
+	if _, ok := event[SpecVersionField]; !ok {
+		return "", false
+	}
+	id, _ := event[IDField].(string)
+	if id == "" {
+		return "", false
+	}
+	return d.keyPrefix + topic + "||" + id, true
+}
+
+func (d *Deduplicator) startProcessing(key string) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if _, ok := d.inProgress[key]; ok {
+		return false
+	}
+	d.inProgress[key] = struct{}{}
+	return true
+}
+
+func (d *Deduplicator) doneProcessing(key string) {
+	d.lock.Lock()
+	delete(d.inProgress, key)
+	d.lock.Unlock()
+}
+
+func (d *Deduplicator) isProcessed(ctx context.Context, key string) (bool, error) {
+	res, err := d.store.Get(ctx, &state.GetRequest{Key: key})
+	if err != nil {
+		return false, fmt.Errorf("failed to look up message %s in the state store: %w", key, err)
+	}
+	return res != nil && res.Data != nil, nil
+}
+
+// markProcessed stores the key of a message that was processed successfully.
+// Errors are logged and not returned, as the message was processed: if the key isn't stored, a redelivery of the message is processed again.
+func (d *Deduplicator) markProcessed(ctx context.Context, key string) {
+	req := &state.SetRequest{
+		Key:   key,
+		Value: time.Now().UTC().Format(time.RFC3339),
+	}
+	if d.ttl > 0 {
+		req.Metadata = map[string]string{
+			metadata.TTLInSecondsMetadataKey: strconv.FormatInt(max(int64(d.ttl/time.Second), 1), 10),
+		}
+	}
+	err := d.store.Set(ctx, req)
+	if err != nil {
+		d.logger.Warnf("Failed to store processed message %s in the state store: %v", key, err)
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/state"
+	inmemory "github.com/dapr/components-contrib/state/in-memory"
+	"github.com/dapr/kit/logger"
+)
+
+func newDedupTestStore(t *testing.T) state.Store {
+	t.Helper()
+
+	store := inmemory.NewInMemoryStateStore(logger.NewLogger("test"))
+	require.NoError(t, store.Init(t.Context(), state.Metadata{}))
+	t.Cleanup(func() {
+		store.Close()
+	})
+	return store
+}
+
+func newDedupTestEvent(id string) []byte {
+	return []byte(`{"specversion":"1.0","id":"` + id + `","source":"test","type":"test","data":"hello"}`)
+}
+
+// storeWithoutTTL is a state store that doesn't support TTLs.
+type storeWithoutTTL struct {
+	state.Store
+}
+
+func (s storeWithoutTTL) Features() []state.Feature {
+	return []state.Feature{state.FeatureETag}
+}
+
+func TestNewDeduplicator(t *testing.T) {
+	store := storeWithoutTTL{Store: newDedupTestStore(t)}
+
+	_, err := NewDeduplicator(store, DeduplicatorOptions{TTL: time.Hour})
+	require.Error(t, err)
+
+	_, err = NewDeduplicator(store, DeduplicatorOptions{})
+	require.NoError(t, err)
+}
+
+func TestDeduplicatorHandler(t *testing.T) {
+	store := newDedupTestStore(t)
+	d, err := NewDeduplicator(store, DeduplicatorOptions{TTL: time.Hour})
+	require.NoError(t, err)
+
+	var calls int
+	fail := false
+	handler := d.Handler(func(ctx context.Context, msg *NewMessage) error {
+		calls++
+		if fail {
+			return errors.New("simulated failure")
+		}
+		return nil
+	})
+
+	t.Run("duplicate messages are skipped", func(t *testing.T) {
+		for range 3 {
+			err := handler(t.Context(), &NewMessage{Topic: "orders", Data: newDedupTestEvent("1")})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, DeduplicatorStats{Hits: 2, Misses: 1}, d.Stats())
+
+		res, err := store.Get(t.Context(), &state.GetRequest{Key: DefaultDeduplicatorKeyPrefix + "orders||1"})
+		require.NoError(t, err)
+		assert.NotNil(t, res.Data)
+	})
+
+	t.Run("message IDs are scoped by topic", func(t *testing.T) {
+		calls = 0
+		err := handler(t.Context(), &NewMessage{Topic: "refunds", Data: newDedupTestEvent("1")})
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("failed messages are processed again", func(t *testing.T) {
+		calls = 0
+		fail = true
+		err := handler(t.Context(), &NewMessage{Topic: "orders", Data: newDedupTestEvent("2")})
+		require.Error(t, err)
+
+		fail = false
+		err = handler(t.Context(), &NewMessage{Topic: "orders", Data: newDedupTestEvent("2")})
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("messages that aren't CloudEvents are always processed", func(t *testing.T) {
+		calls = 0
+		for range 2 {
+			err := handler(t.Context(), &NewMessage{Topic: "orders", Data: []byte(`{"id":"3"}`)})
+			require.NoError(t, err)
+			err = handler(t.Context(), &NewMessage{Topic: "orders", Data: []byte("hello")})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("message in progress", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		slow := d.Handler(func(ctx context.Context, msg *NewMessage) error {
+			close(started)
+			<-release
+			return nil
+		})
+
+		errCh := make(chan error)
+		go func() {
+			errCh <- slow(t.Context(), &NewMessage{Topic: "orders", Data: newDedupTestEvent("4")})
+		}()
+		<-started
+
+		err := handler(t.Context(), &NewMessage{Topic: "orders", Data: newDedupTestEvent("4")})
+		require.ErrorIs(t, err, ErrMessageInProgress)
+
+		close(release)
+		require.NoError(t, <-errCh)
+	})
+}
+
+func TestDeduplicatorBulkHandler(t *testing.T) {
+	d, err := NewDeduplicator(newDedupTestStore(t), DeduplicatorOptions{})
+	require.NoError(t, err)
+
+	var received []string
+	handler := d.BulkHandler(func(ctx context.Context, msg *BulkMessage) ([]BulkSubscribeResponseEntry, error) {
+		res := make([]BulkSubscribeResponseEntry, 0, len(msg.Entries))
+		var err error
+		for _, entry := range msg.Entries {
+			received = append(received, entry.EntryId)
+			res = append(res, BulkSubscribeResponseEntry{EntryId: entry.EntryId})
+			if entry.EntryId == "fail" {
+				res[len(res)-1].Error = errors.New("simulated failure")
+				err = errors.New("simulated failure")
+			}
+		}
+		return res, err
+	})
+
+	res, err := handler(t.Context(), &BulkMessage{
+		Topic: "orders",
+		Entries: []BulkMessageEntry{
+			{EntryId: "a", Event: newDedupTestEvent("a")},
+			{EntryId: "fail", Event: newDedupTestEvent("fail")},
+		},
+	})
+	require.Error(t, err)
+	require.Len(t, res, 2)
+	require.NoError(t, res[0].Error)
+	require.Error(t, res[1].Error)
+
+	// Only the entries that weren't processed successfully are passed to the handler again
+	received = nil
+	res, err = handler(t.Context(), &BulkMessage{
+		Topic: "orders",
+		Entries: []BulkMessageEntry{
+			{EntryId: "a", Event: newDedupTestEvent("a")},
+			{EntryId: "fail", Event: newDedupTestEvent("fail")},
+			{EntryId: "b", Event: newDedupTestEvent("b")},
+		},
+	})
+	require.Error(t, err)
+	assert.Equal(t, []string{"fail", "b"}, received)
+	require.Len(t, res, 3)
+	assert.Equal(t, "a", res[0].EntryId)
+	require.NoError(t, res[0].Error)
+	require.Error(t, res[1].Error)
+	require.NoError(t, res[2].Error)
+	assert.Equal(t, DeduplicatorStats{Hits: 1, Misses: 4}, d.Stats())
+}
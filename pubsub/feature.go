@@ -23,6 +23,18 @@ const (
 	// FeatureSubscribeWildcards is the feature to allow subscribing to topics/queues using a wildcard.
 	FeatureSubscribeWildcards Feature = "SUBSCRIBE_WILDCARDS"
 	FeatureBulkPublish        Feature = "BULK_PUBSUB"
+	// FeatureDelayedDelivery is the feature to deliver messages at a later time, set with the "deliverAt" or "deliverAfter" metadata keys.
+	FeatureDelayedDelivery Feature = "DELAYED_DELIVERY"
+	// FeatureOrderedDelivery is the feature to deliver messages with the same "orderingKey" metadata key in the order they were published, one at a time.
+	FeatureOrderedDelivery Feature = "ORDERED_DELIVERY"
+	// FeatureDeadLetter is the feature to send messages that can't be delivered to the topic set with the "deadLetterTopic" subscription metadata key.
+	FeatureDeadLetter Feature = "DEAD_LETTER"
+	// FeatureReplay is the feature to start a subscription from the position set with the "startFrom" subscription metadata key.
+	// The position applies only to consumer groups (or partitions) that don't have a stored position yet: existing groups resume
+	// from where they stopped, so restarting or scaling out the subscribers doesn't deliver messages again.
+	// To replay the messages of an existing group, stop its subscribers and reset its position with the tools of the broker,
+	// or subscribe with a new consumer group.
+	FeatureReplay Feature = "REPLAY"
 )
 
 // Feature names a feature that can be implemented by PubSub components.
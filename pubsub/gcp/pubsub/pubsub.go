@@ -40,7 +40,7 @@ const (
 
 	// Metadata keys.
 	metadataProjectIDKey   = "projectId"
-	metedataOrderingKeyKey = "orderingKey"
+	metedataOrderingKeyKey = contribMetadata.OrderingKeyMetadataKey
 	metadataAckDeadlineKey = "ackDeadline"
 
 	// Defaults.
@@ -286,7 +286,12 @@ func (g *GCPPubSub) Subscribe(parentCtx context.Context, req pubsub.SubscribeReq
 		}
 		g.lock.Unlock()
 
-		subError := g.ensureSubscription(parentCtx, g.metadata.ConsumerID, req.Topic)
+		// The dead-letter topic of the subscription overrides the one of the component
+		deadLetterTopic := g.metadata.DeadLetterTopic
+		if val := req.Metadata[contribMetadata.DeadLetterTopicMetadataKey]; val != "" {
+			deadLetterTopic = val
+		}
+		subError := g.ensureSubscription(parentCtx, g.metadata.ConsumerID, req.Topic, deadLetterTopic)
 		if subError != nil {
 			return fmt.Errorf("%s could not get valid subscription - consumerID:%q, error: %v", errorMessagePrefix, g.metadata.ConsumerID, subError)
 		}
@@ -437,10 +442,10 @@ func (g *GCPPubSub) getTopic(topic string) *gcppubsub.Topic {
 	return g.client.Topic(topic)
 }
 
-func (g *GCPPubSub) ensureSubscription(parentCtx context.Context, subscription string, topic string) error {
+func (g *GCPPubSub) ensureSubscription(parentCtx context.Context, subscription string, topic string, deadLetterTopic string) error {
 	g.lock.RLock()
 	_, topicOK := g.topicCache[topic]
-	_, dlTopicOK := g.topicCache[g.metadata.DeadLetterTopic]
+	_, dlTopicOK := g.topicCache[deadLetterTopic]
 	g.lock.RUnlock()
 	if !topicOK {
 		g.lock.Lock()
@@ -468,21 +473,23 @@ func (g *GCPPubSub) ensureSubscription(parentCtx context.Context, subscription s
 			EnableMessageOrdering: g.metadata.EnableMessageOrdering,
 		}
 
-		if g.metadata.DeadLetterTopic != "" && !dlTopicOK {
-			g.lock.Lock()
-			// Double-check if the DeadLetterTopic still doesn't exist to avoid race condition
-			if _, ok := g.topicCache[g.metadata.DeadLetterTopic]; !ok {
-				subErr = g.ensureTopic(parentCtx, g.metadata.DeadLetterTopic)
-				if subErr != nil {
-					g.lock.Unlock()
-					return subErr
-				}
-				g.topicCache[g.metadata.DeadLetterTopic] = cacheEntry{
-					LastSync: time.Now(),
+		if deadLetterTopic != "" {
+			if !dlTopicOK {
+				g.lock.Lock()
+				// Double-check if the DeadLetterTopic still doesn't exist to avoid race condition
+				if _, ok := g.topicCache[deadLetterTopic]; !ok {
+					subErr = g.ensureTopic(parentCtx, deadLetterTopic)
+					if subErr != nil {
+						g.lock.Unlock()
+						return subErr
+					}
+					g.topicCache[deadLetterTopic] = cacheEntry{
+						LastSync: time.Now(),
+					}
 				}
+				g.lock.Unlock()
 			}
-			g.lock.Unlock()
-			dlTopic := fmt.Sprintf("projects/%s/topics/%s", g.metadata.ProjectID, g.metadata.DeadLetterTopic)
+			dlTopic := fmt.Sprintf("projects/%s/topics/%s", g.metadata.ProjectID, deadLetterTopic)
 			subConfig.DeadLetterPolicy = &gcppubsub.DeadLetterPolicy{
 				DeadLetterTopic:     dlTopic,
 				MaxDeliveryAttempts: g.metadata.MaxDeliveryAttempts,
@@ -510,7 +517,13 @@ func (g *GCPPubSub) Close() error {
 }
 
 func (g *GCPPubSub) Features() []pubsub.Feature {
-	return nil
+	// Messages are sent to the dead-letter topic by GCP Pub/Sub after the max delivery attempts
+	features := []pubsub.Feature{pubsub.FeatureDeadLetter}
+	// Messages are delivered in order only if ordering is enabled on the topic and subscription
+	if g.metadata != nil && g.metadata.EnableMessageOrdering {
+		features = append(features, pubsub.FeatureOrderedDelivery)
+	}
+	return features
 }
 
 // GetComponentMetadata returns the metadata of the component.
@@ -15,22 +15,51 @@ package inmemory
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"maps"
+	"reflect"
+	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/dapr/components-contrib/common/eventbus"
+	commonutils "github.com/dapr/components-contrib/common/utils"
 	"github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/components-contrib/pubsub"
 	"github.com/dapr/kit/logger"
 )
 
+const (
+	defaultMaxBulkSubCount           = 100
+	defaultMaxBulkSubAwaitDurationMs = 1000
+)
+
 type bus struct {
-	bus     eventbus.Bus
-	log     logger.Logger
-	closed  atomic.Bool
-	closeCh chan struct{}
-	wg      sync.WaitGroup
+	bus      eventbus.Bus
+	log      logger.Logger
+	metadata inMemoryMetadata
+	closed   atomic.Bool
+	closeCh  chan struct{}
+	wg       sync.WaitGroup
+}
+
+// message is a message published to the bus.
+type message struct {
+	data        []byte
+	metadata    map[string]string
+	contentType *string
+	expiresAt   time.Time
+	cloudEvent  map[string]any
+}
+
+// expired returns true if the TTL of the message has expired, or if the message is a cloud event that has expired.
+func (m *message) expired() bool {
+	if !m.expiresAt.IsZero() && time.Now().After(m.expiresAt) {
+		return true
+	}
+	return m.cloudEvent != nil && pubsub.HasExpired(m.cloudEvent)
 }
 
 func New(logger logger.Logger) pubsub.PubSub {
@@ -49,10 +78,17 @@ func (a *bus) Close() error {
 }
 
 func (a *bus) Features() []pubsub.Feature {
-	return []pubsub.Feature{pubsub.FeatureSubscribeWildcards}
+	// Each subscriber handles messages one at a time, in the order they were published, so messages are always delivered in order
+	// Failed messages are retried before the next ones are delivered, so this holds with retries too
+	return []pubsub.Feature{pubsub.FeatureSubscribeWildcards, pubsub.FeatureDelayedDelivery, pubsub.FeatureOrderedDelivery, pubsub.FeatureMessageTTL, pubsub.FeatureDeadLetter}
 }
 
 func (a *bus) Init(_ context.Context, metadata pubsub.Metadata) error {
+	err := a.metadata.InitWithMetadata(metadata)
+	if err != nil {
+		return err
+	}
+
 	a.bus = eventbus.New(true)
 
 	return nil
@@ -63,37 +99,233 @@ func (a *bus) Publish(_ context.Context, req *pubsub.PublishRequest) error {
 		return errors.New("component is closed")
 	}
 
-	a.bus.Publish(req.Topic, req.Data, req.Metadata)
+	now := time.Now()
+	msg := &message{
+		data:        req.Data,
+		metadata:    req.Metadata,
+		contentType: req.ContentType,
+	}
+
+	ttl, ok, err := metadata.TryGetTTL(req.Metadata)
+	if err != nil {
+		return err
+	}
+	if ok {
+		msg.expiresAt = now.Add(ttl)
+	}
+
+	// Cloud events with an expiration are dropped when they expire, like with brokers that don't support TTLs
+	var ce map[string]any
+	if json.Unmarshal(req.Data, &ce) == nil {
+		if _, ok := ce[pubsub.ExpirationField]; ok {
+			msg.cloudEvent = ce
+		}
+	}
+
+	deliverAt, ok, err := metadata.TryGetDeliveryTime(req.Metadata, now)
+	if err != nil {
+		return err
+	}
+	if ok {
+		if delay := time.Until(deliverAt); delay > 0 {
+			// Delayed messages are lost if the component is closed before they are delivered
+			a.wg.Add(1)
+			go func() {
+				defer a.wg.Done()
+				t := time.NewTimer(delay)
+				defer t.Stop()
+				select {
+				case <-t.C:
+					a.bus.Publish(req.Topic, msg)
+				case <-a.closeCh:
+				}
+			}()
+			return nil
+		}
+	}
+
+	a.bus.Publish(req.Topic, msg)
 
 	return nil
 }
 
+// BulkPublish publishes each entry as a separate message.
+func (a *bus) BulkPublish(ctx context.Context, req *pubsub.BulkPublishRequest) (pubsub.BulkPublishResponse, error) {
+	res := pubsub.BulkPublishResponse{}
+	var errs []error
+	for _, entry := range req.Entries {
+		md := maps.Clone(req.Metadata)
+		if md == nil {
+			md = make(map[string]string, len(entry.Metadata))
+		}
+		maps.Copy(md, entry.Metadata)
+
+		var contentType *string
+		if entry.ContentType != "" {
+			contentType = &entry.ContentType
+		}
+
+		err := a.Publish(ctx, &pubsub.PublishRequest{
+			Data:        entry.Event,
+			PubsubName:  req.PubsubName,
+			Topic:       req.Topic,
+			Metadata:    md,
+			ContentType: contentType,
+		})
+		if err != nil {
+			res.FailedEntries = append(res.FailedEntries, pubsub.BulkPublishResponseFailedEntry{
+				EntryId: entry.EntryId,
+				Error:   err,
+			})
+			errs = append(errs, err)
+		}
+	}
+
+	return res, errors.Join(errs...)
+}
+
 func (a *bus) Subscribe(ctx context.Context, req pubsub.SubscribeRequest, handler pubsub.Handler) error {
-	if a.closed.Load() {
-		return errors.New("component is closed")
+	return a.subscribe(ctx, req, func(ctx context.Context, q *queue) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-q.notify:
+			}
+
+			for {
+				msgs := q.pop(1)
+				if len(msgs) == 0 {
+					break
+				}
+				a.deliver(ctx, req, msgs, func(msgs []*message) []*message {
+					err := handler(ctx, &pubsub.NewMessage{Data: msgs[0].data, Topic: req.Topic, Metadata: msgs[0].metadata, ContentType: msgs[0].contentType})
+					if err != nil {
+						a.log.Error(err)
+						return msgs
+					}
+					return nil
+				})
+			}
+		}
+	})
+}
+
+// BulkSubscribe delivers messages in batches, which are sent when they reach the maximum size or when the maximum await duration elapses.
+func (a *bus) BulkSubscribe(ctx context.Context, req pubsub.SubscribeRequest, handler pubsub.BulkHandler) error {
+	maxCount := commonutils.GetIntValOrDefault(req.BulkSubscribeConfig.MaxMessagesCount, defaultMaxBulkSubCount)
+	maxAwait := time.Duration(commonutils.GetIntValOrDefault(req.BulkSubscribeConfig.MaxAwaitDurationMs, defaultMaxBulkSubAwaitDurationMs)) * time.Millisecond
+
+	return a.subscribe(ctx, req, func(ctx context.Context, q *queue) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-q.notify:
+			}
+
+			// Wait for the batch to be full, or for the maximum await duration
+			if q.len() < maxCount {
+				t := time.NewTimer(maxAwait)
+			wait:
+				for {
+					select {
+					case <-ctx.Done():
+						t.Stop()
+						return
+					case <-t.C:
+						break wait
+					case <-q.notify:
+						if q.len() >= maxCount {
+							t.Stop()
+							break wait
+						}
+					}
+				}
+			}
+
+			for {
+				msgs := q.pop(maxCount)
+				if len(msgs) == 0 {
+					break
+				}
+				a.deliver(ctx, req, msgs, func(msgs []*message) []*message {
+					return a.handleBulk(ctx, req.Topic, msgs, handler)
+				})
+			}
+		}
+	})
+}
+
+// handleBulk invokes the bulk handler and returns the messages that failed.
+func (a *bus) handleBulk(ctx context.Context, topic string, msgs []*message, handler pubsub.BulkHandler) []*message {
+	bulkMsg := &pubsub.BulkMessage{
+		Topic:   topic,
+		Entries: make([]pubsub.BulkMessageEntry, len(msgs)),
+	}
+	for i, msg := range msgs {
+		bulkMsg.Entries[i] = pubsub.BulkMessageEntry{
+			EntryId:  strconv.Itoa(i),
+			Event:    msg.data,
+			Metadata: msg.metadata,
+		}
+		if msg.contentType != nil {
+			bulkMsg.Entries[i].ContentType = *msg.contentType
+		}
 	}
 
-	loghandler := func(data []byte, md map[string]string) {
-		err := handler(ctx, &pubsub.NewMessage{Data: data, Topic: req.Topic, Metadata: md})
-		if err != nil {
-			a.log.Error(err)
+	res, err := handler(ctx, bulkMsg)
+	if err == nil {
+		return nil
+	}
+	a.log.Error(err)
+	if res == nil {
+		return msgs
+	}
+
+	var failed []*message
+	for _, entry := range res {
+		if entry.Error == nil {
+			continue
 		}
+		i, convErr := strconv.Atoi(entry.EntryId)
+		if convErr != nil || i < 0 || i >= len(msgs) {
+			continue
+		}
+		failed = append(failed, msgs[i])
+	}
+	return failed
+}
+
+// subscribe adds a queue for the subscription to the bus, and runs the consumer until ctx is canceled or the component is closed.
+func (a *bus) subscribe(ctx context.Context, req pubsub.SubscribeRequest, consume func(ctx context.Context, q *queue)) error {
+	if a.closed.Load() {
+		return errors.New("component is closed")
 	}
 
-	err := a.bus.SubscribeAsync(req.Topic, loghandler, true)
+	q := newQueue()
+	err := a.bus.SubscribeAsync(req.Topic, q.push, true)
 	if err != nil {
 		return err
 	}
 
+	subCtx, cancel := context.WithCancel(ctx)
+	a.wg.Add(2)
+	go func() {
+		defer a.wg.Done()
+		defer cancel()
+		consume(subCtx, q)
+	}()
+
 	// Unsubscribe when context is done
-	a.wg.Add(1)
 	go func() {
 		defer a.wg.Done()
 		select {
-		case <-ctx.Done():
+		case <-subCtx.Done():
 		case <-a.closeCh:
+			cancel()
 		}
-		err := a.bus.Unsubscribe(req.Topic, loghandler)
+		err := a.bus.Unsubscribe(req.Topic, q.push)
 		if err != nil {
 			a.log.Errorf("error while unsubscribing from topic %s: %v", req.Topic, err)
 		}
@@ -102,7 +334,100 @@ func (a *bus) Subscribe(ctx context.Context, req pubsub.SubscribeRequest, handle
 	return nil
 }
 
+// deliver invokes handle, which returns the messages that failed, until all messages are delivered or the maximum number of retries is reached.
+// Messages that could not be delivered are sent to the dead-letter topic, if any.
+func (a *bus) deliver(ctx context.Context, req pubsub.SubscribeRequest, msgs []*message, handle func(msgs []*message) []*message) {
+	for attempt := 1; ; attempt++ {
+		// Expired messages are dropped, including the ones that expired while being retried
+		pending := make([]*message, 0, len(msgs))
+		for _, msg := range msgs {
+			if !msg.expired() {
+				pending = append(pending, msg)
+			}
+		}
+		if len(pending) == 0 {
+			return
+		}
+
+		msgs = handle(pending)
+		if len(msgs) == 0 {
+			return
+		}
+		if !a.metadata.ShouldRetry(attempt) {
+			break
+		}
+
+		delay := a.metadata.RetryDelay(attempt)
+		a.log.Debugf("Retrying %d messages from topic %s in %v", len(msgs), req.Topic, delay)
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return
+		}
+	}
+
+	deadLetterTopic := a.metadata.DeadLetterTopic
+	if val := req.Metadata[metadata.DeadLetterTopicMetadataKey]; val != "" {
+		deadLetterTopic = val
+	}
+	if deadLetterTopic == "" {
+		a.log.Errorf("Dropping %d messages from topic %s after %d retries", len(msgs), req.Topic, a.metadata.MaxRetries)
+		return
+	}
+
+	a.log.Warnf("Sending %d messages from topic %s to dead-letter topic %s after %d retries", len(msgs), req.Topic, deadLetterTopic, a.metadata.MaxRetries)
+	for _, msg := range msgs {
+		a.bus.Publish(deadLetterTopic, msg)
+	}
+}
+
 // GetComponentMetadata returns the metadata of the component.
 func (a *bus) GetComponentMetadata() (metadataInfo metadata.MetadataMap) {
+	metadataStruct := inMemoryMetadata{}
+	metadata.GetMetadataInfoFromStructType(reflect.TypeOf(metadataStruct), &metadataInfo, metadata.PubSubType)
 	return
 }
+
+// queue holds the messages received by a subscription until they are delivered, so publishers are not blocked by slow subscribers.
+type queue struct {
+	lock   sync.Mutex
+	msgs   []*message
+	notify chan struct{}
+}
+
+func newQueue() *queue {
+	return &queue{
+		notify: make(chan struct{}, 1),
+	}
+}
+
+func (q *queue) push(msg *message) {
+	q.lock.Lock()
+	q.msgs = append(q.msgs, msg)
+	q.lock.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+		// Already notified
+	}
+}
+
+// pop removes up to n messages from the queue.
+func (q *queue) pop(n int) []*message {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	n = min(n, len(q.msgs))
+	msgs := q.msgs[:n:n]
+	q.msgs = q.msgs[n:]
+	return msgs
+}
+
+func (q *queue) len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.msgs)
+}
@@ -15,10 +15,15 @@ package inmemory
 
 import (
 	"context"
+	"errors"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/components-contrib/pubsub"
 	"github.com/dapr/kit/logger"
 )
@@ -100,6 +105,34 @@ func TestMessageMetadataPropagation(t *testing.T) {
 	}, <-metadataCh)
 }
 
+func TestDelayedDelivery(t *testing.T) {
+	bus := New(logger.NewLogger("test"))
+	bus.Init(t.Context(), pubsub.Metadata{})
+	defer bus.Close()
+
+	ch := make(chan []byte)
+	bus.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "demo"}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+		return publish(ch, msg)
+	})
+
+	start := time.Now()
+	err := bus.Publish(t.Context(), &pubsub.PublishRequest{Data: []byte("later"), Topic: "demo", Metadata: map[string]string{
+		"deliverAfter": "500ms",
+	}})
+	require.NoError(t, err)
+	err = bus.Publish(t.Context(), &pubsub.PublishRequest{Data: []byte("now"), Topic: "demo"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "now", string(<-ch))
+	assert.Equal(t, "later", string(<-ch))
+	assert.GreaterOrEqual(t, time.Since(start), 500*time.Millisecond)
+
+	err = bus.Publish(t.Context(), &pubsub.PublishRequest{Data: []byte("invalid"), Topic: "demo", Metadata: map[string]string{
+		"deliverAt": "tomorrow",
+	}})
+	require.Error(t, err)
+}
+
 func publish(ch chan []byte, msg *pubsub.NewMessage) error {
 	go func() { ch <- msg.Data }()
 
@@ -116,3 +149,222 @@ func publishWithMetadata(ch chan []byte, mdCh chan map[string]string, msg *pubsu
 
 	return nil
 }
+
+func TestRetries(t *testing.T) {
+	newBus := func(t *testing.T, props map[string]string) pubsub.PubSub {
+		bus := New(logger.NewLogger("test"))
+		err := bus.Init(t.Context(), pubsub.Metadata{Base: metadata.Base{Properties: props}})
+		require.NoError(t, err)
+		t.Cleanup(func() { bus.Close() })
+		return bus
+	}
+
+	t.Run("failed messages are delivered again", func(t *testing.T) {
+		bus := newBus(t, map[string]string{"maxRetries": "3", "retryBackoff": "10ms"})
+
+		var attempts atomic.Int32
+		ch := make(chan []byte, 10)
+		bus.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "demo"}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+			if attempts.Add(1) < 3 {
+				return errors.New("simulated failure")
+			}
+			ch <- msg.Data
+			return nil
+		})
+
+		err := bus.Publish(t.Context(), &pubsub.PublishRequest{Data: []byte("1"), Topic: "demo"})
+		require.NoError(t, err)
+		err = bus.Publish(t.Context(), &pubsub.PublishRequest{Data: []byte("2"), Topic: "demo"})
+		require.NoError(t, err)
+
+		// Messages are still delivered in order
+		assert.Equal(t, "1", string(<-ch))
+		assert.Equal(t, "2", string(<-ch))
+		assert.Equal(t, int32(4), attempts.Load())
+	})
+
+	t.Run("negative max retries retry forever", func(t *testing.T) {
+		bus := newBus(t, map[string]string{"maxRetries": "-1", "retryBackoff": "1ms", "maxRetryBackoff": "1ms", "deadLetterTopic": "dlq"})
+
+		var attempts atomic.Int32
+		ch := make(chan []byte, 10)
+		bus.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "demo"}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+			if attempts.Add(1) < 20 {
+				return errors.New("simulated failure")
+			}
+			ch <- msg.Data
+			return nil
+		})
+
+		err := bus.Publish(t.Context(), &pubsub.PublishRequest{Data: []byte("1"), Topic: "demo"})
+		require.NoError(t, err)
+		assert.Equal(t, "1", string(<-ch))
+		assert.Equal(t, int32(20), attempts.Load())
+	})
+
+	t.Run("messages are sent to the dead-letter topic", func(t *testing.T) {
+		bus := newBus(t, map[string]string{"maxRetries": "2", "retryBackoff": "10ms", "deadLetterTopic": "dlq"})
+
+		var attempts atomic.Int32
+		bus.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "demo"}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+			attempts.Add(1)
+			return errors.New("simulated failure")
+		})
+		ch := make(chan []byte)
+		bus.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "dlq"}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+			return publish(ch, msg)
+		})
+
+		err := bus.Publish(t.Context(), &pubsub.PublishRequest{Data: []byte("poison"), Topic: "demo"})
+		require.NoError(t, err)
+		assert.Equal(t, "poison", string(<-ch))
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("dead-letter topic of the subscription", func(t *testing.T) {
+		bus := newBus(t, map[string]string{"deadLetterTopic": "dlq"})
+
+		bus.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "demo", Metadata: map[string]string{"deadLetterTopic": "demo-dlq"}}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+			return errors.New("simulated failure")
+		})
+		ch := make(chan []byte)
+		bus.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "demo-dlq"}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+			return publish(ch, msg)
+		})
+
+		err := bus.Publish(t.Context(), &pubsub.PublishRequest{Data: []byte("poison"), Topic: "demo"})
+		require.NoError(t, err)
+		assert.Equal(t, "poison", string(<-ch))
+	})
+
+	t.Run("expired messages are not retried", func(t *testing.T) {
+		bus := newBus(t, map[string]string{"maxRetries": "10", "retryBackoff": "300ms", "deadLetterTopic": "dlq"})
+
+		var attempts atomic.Int32
+		bus.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "demo"}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+			attempts.Add(1)
+			return errors.New("simulated failure")
+		})
+		ch := make(chan []byte)
+		bus.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "dlq"}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+			return publish(ch, msg)
+		})
+
+		err := bus.Publish(t.Context(), &pubsub.PublishRequest{Data: []byte("expiring"), Topic: "demo", Metadata: map[string]string{
+			metadata.TTLMetadataKey: "1",
+		}})
+		require.NoError(t, err)
+
+		select {
+		case msg := <-ch:
+			assert.Failf(t, "unexpected message", "received %s", msg)
+		case <-time.After(1500 * time.Millisecond):
+		}
+		assert.LessOrEqual(t, attempts.Load(), int32(4))
+	})
+
+	t.Run("invalid metadata", func(t *testing.T) {
+		for key, val := range map[string]string{
+			"retryBackoff":    "0",
+			"maxRetryBackoff": "1ms",
+		} {
+			bus := New(logger.NewLogger("test"))
+			err := bus.Init(t.Context(), pubsub.Metadata{Base: metadata.Base{Properties: map[string]string{key: val}}})
+			require.Error(t, err, key)
+		}
+	})
+}
+
+func TestExpiredCloudEvents(t *testing.T) {
+	bus := New(logger.NewLogger("test"))
+	bus.Init(t.Context(), pubsub.Metadata{})
+	defer bus.Close()
+
+	ch := make(chan []byte)
+	bus.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "demo"}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+		return publish(ch, msg)
+	})
+
+	expired := `{"id":"1","expiration":"` + time.Now().Add(-time.Minute).Format(time.RFC3339) + `"}`
+	valid := `{"id":"2","expiration":"` + time.Now().Add(time.Minute).Format(time.RFC3339) + `"}`
+	bus.Publish(t.Context(), &pubsub.PublishRequest{Data: []byte(expired), Topic: "demo"})
+	bus.Publish(t.Context(), &pubsub.PublishRequest{Data: []byte(valid), Topic: "demo"})
+	assert.JSONEq(t, valid, string(<-ch))
+}
+
+func TestBulk(t *testing.T) {
+	bus := New(logger.NewLogger("test"))
+	bus.Init(t.Context(), pubsub.Metadata{Base: metadata.Base{Properties: map[string]string{
+		"maxRetries":   "1",
+		"retryBackoff": "10ms",
+	}}})
+	defer bus.Close()
+
+	var failed atomic.Bool
+	ch := make(chan *pubsub.BulkMessage, 10)
+	err := bus.(pubsub.BulkSubscriber).BulkSubscribe(t.Context(), pubsub.SubscribeRequest{
+		Topic: "demo",
+		BulkSubscribeConfig: pubsub.BulkSubscribeConfig{
+			MaxMessagesCount:   3,
+			MaxAwaitDurationMs: 100,
+		},
+	}, func(ctx context.Context, msg *pubsub.BulkMessage) ([]pubsub.BulkSubscribeResponseEntry, error) {
+		ch <- msg
+		res := make([]pubsub.BulkSubscribeResponseEntry, len(msg.Entries))
+		for i, entry := range msg.Entries {
+			res[i].EntryId = entry.EntryId
+			// The second message fails the first time only
+			if string(entry.Event) == "2" && failed.CompareAndSwap(false, true) {
+				res[i].Error = errors.New("simulated failure")
+			}
+		}
+		if failed.Load() && len(msg.Entries) == 3 {
+			return res, errors.New("some messages failed")
+		}
+		return res, nil
+	})
+	require.NoError(t, err)
+
+	res, err := bus.(pubsub.BulkPublisher).BulkPublish(t.Context(), &pubsub.BulkPublishRequest{
+		Topic:    "demo",
+		Metadata: map[string]string{"foo": "bar"},
+		Entries: []pubsub.BulkMessageEntry{
+			{EntryId: "a", Event: []byte("1"), ContentType: "text/plain"},
+			{EntryId: "b", Event: []byte("2"), Metadata: map[string]string{"foo": "baz"}},
+			{EntryId: "c", Event: []byte("3")},
+			{EntryId: "d", Event: []byte("4")},
+		},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, res.FailedEntries)
+
+	// The first batch is full, then the failed message is delivered again, then the last message is delivered after the await duration
+	msg := <-ch
+	require.Len(t, msg.Entries, 3)
+	assert.Equal(t, "demo", msg.Topic)
+	assert.Equal(t, "1", string(msg.Entries[0].Event))
+	assert.Equal(t, "text/plain", msg.Entries[0].ContentType)
+	assert.Equal(t, map[string]string{"foo": "bar"}, msg.Entries[0].Metadata)
+	assert.Equal(t, map[string]string{"foo": "baz"}, msg.Entries[1].Metadata)
+
+	msg = <-ch
+	require.Len(t, msg.Entries, 1)
+	assert.Equal(t, "2", string(msg.Entries[0].Event))
+
+	msg = <-ch
+	require.Len(t, msg.Entries, 1)
+	assert.Equal(t, "4", string(msg.Entries[0].Event))
+
+	t.Run("invalid entries fail", func(t *testing.T) {
+		res, err := bus.(pubsub.BulkPublisher).BulkPublish(t.Context(), &pubsub.BulkPublishRequest{
+			Topic: "demo",
+			Entries: []pubsub.BulkMessageEntry{
+				{EntryId: "a", Event: []byte("1")},
+				{EntryId: "b", Event: []byte("2"), Metadata: map[string]string{"ttl": "forever"}},
+			},
+		})
+		require.Error(t, err)
+		require.Len(t, res.FailedEntries, 1)
+		assert.Equal(t, "b", res.FailedEntries[0].EntryId)
+	})
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"time"
+
+	"github.com/dapr/components-contrib/pubsub"
+	kitmd "github.com/dapr/kit/metadata"
+)
+
+const (
+	defaultRetryBackoff    = 100 * time.Millisecond
+	defaultMaxRetryBackoff = 5 * time.Second
+)
+
+type inMemoryMetadata struct {
+	pubsub.RetryProperties `mapstructure:",squash"`
+
+	DeadLetterTopic string `mapstructure:"deadLetterTopic"`
+}
+
+func (m *inMemoryMetadata) InitWithMetadata(meta pubsub.Metadata) error {
+	// Reset the object
+	m.MaxRetries = 0
+	m.RetryBackoff = defaultRetryBackoff
+	m.MaxRetryBackoff = defaultMaxRetryBackoff
+	m.DeadLetterTopic = ""
+
+	// Decode the metadata
+	// The component is often initialized without any metadata in tests
+	if len(meta.Properties) > 0 {
+		err := kitmd.DecodeMetadata(meta.Properties, &m)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Validate and sanitize input
+	return m.RetryProperties.Validate()
+}
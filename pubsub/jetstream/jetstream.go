@@ -16,7 +16,11 @@ package jetstream
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
 	"reflect"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -29,6 +33,12 @@ import (
 	"github.com/dapr/kit/retry"
 )
 
+// Maximum number of deliveries of messages for subscriptions with a dead-letter topic, if maxDeliver is not set.
+const defaultDeadLetterMaxDeliver = 5
+
+// Metadata keys of requests and replies that are sent as message headers.
+var requestReplyHeaders = []string{mdutils.CorrelationIDMetadataKey, mdutils.ReplyToMetadataKey}
+
 type jetstreamPubSub struct {
 	nc   *nats.Conn
 	jsc  nats.JetStreamContext
@@ -109,7 +119,7 @@ func (js *jetstreamPubSub) Init(_ context.Context, metadata pubsub.Metadata) err
 }
 
 func (js *jetstreamPubSub) Features() []pubsub.Feature {
-	return nil
+	return []pubsub.Feature{pubsub.FeatureDeadLetter, pubsub.FeatureReplay}
 }
 
 func (js *jetstreamPubSub) Publish(ctx context.Context, req *pubsub.PublishRequest) error {
@@ -135,12 +145,85 @@ func (js *jetstreamPubSub) Publish(ctx context.Context, req *pubsub.PublishReque
 		js.l.Warn("empty message ID, Jetstream deduplication will not be possible")
 	}
 
+	msg := nats.NewMsg(req.Topic)
+	msg.Data = req.Data
+	for _, key := range requestReplyHeaders {
+		if v := req.Metadata[key]; v != "" {
+			msg.Header.Set(key, v)
+		}
+	}
+
+	// Replies to requests are sent to the inbox of the requester, which isn't part of a stream
+	if js.isInbox(req.Topic) {
+		js.l.Debugf("Publishing reply to inbox %v", req.Topic)
+		return js.nc.PublishMsg(msg)
+	}
+
 	js.l.Debugf("Publishing to topic %v id: %s", req.Topic, msgID)
-	_, err = js.jsc.Publish(req.Topic, req.Data, opts...)
+	_, err = js.jsc.PublishMsg(msg, opts...)
 
 	return err
 }
 
+// Request publishes a message with the reply-to and correlation ID headers, and waits for the reply on a NATS inbox.
+// Responders publish the reply to the inbox with the same correlation ID.
+func (js *jetstreamPubSub) Request(ctx context.Context, req *pubsub.RequestReplyRequest) (*pubsub.NewMessage, error) {
+	if js.closed.Load() {
+		return nil, errors.New("component is closed")
+	}
+
+	inbox := js.nc.NewInbox()
+	sub, err := js.nc.SubscribeSync(inbox)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to inbox: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	md := maps.Clone(req.Metadata)
+	if md == nil {
+		md = make(map[string]string, 2)
+	}
+	md[mdutils.CorrelationIDMetadataKey] = req.CorrelationID
+	md[mdutils.ReplyToMetadataKey] = inbox
+	err = js.Publish(ctx, &pubsub.PublishRequest{
+		Data:        req.Data,
+		PubsubName:  req.PubsubName,
+		Topic:       req.Topic,
+		Metadata:    md,
+		ContentType: req.ContentType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		m, err := sub.NextMsgWithContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if m.Header.Get(mdutils.CorrelationIDMetadataKey) != req.CorrelationID {
+			js.l.Debugf("Ignoring reply with a different correlation ID on inbox %s", inbox)
+			continue
+		}
+		return &pubsub.NewMessage{
+			Topic: inbox,
+			Data:  m.Data,
+			Metadata: map[string]string{
+				mdutils.CorrelationIDMetadataKey: req.CorrelationID,
+			},
+		}, nil
+	}
+}
+
+// isInbox returns true if the subject is a NATS inbox.
+func (js *jetstreamPubSub) isInbox(subject string) bool {
+	prefix := nats.InboxPrefix
+	if js.nc.Opts.InboxPrefix != "" {
+		prefix = js.nc.Opts.InboxPrefix + "."
+	}
+	return strings.HasPrefix(subject, prefix)
+}
+
 func (js *jetstreamPubSub) Subscribe(ctx context.Context, req pubsub.SubscribeRequest, handler pubsub.Handler) error {
 	if js.closed.Load() {
 		return errors.New("component is closed")
@@ -164,6 +247,19 @@ func (js *jetstreamPubSub) Subscribe(ctx context.Context, req pubsub.SubscribeRe
 		consumerConfig.OptStartSeq = v
 	}
 	consumerConfig.DeliverPolicy = js.meta.internalDeliverPolicy
+
+	// The start position of the subscription overrides the deliver policy of the component
+	startPosition, replay, err := mdutils.TryGetStartPosition(req.Metadata)
+	if err != nil {
+		return err
+	}
+	if replay {
+		err = setStartPosition(&consumerConfig, startPosition)
+		if err != nil {
+			return err
+		}
+	}
+
 	if js.meta.FlowControl {
 		consumerConfig.FlowControl = true
 	}
@@ -195,6 +291,13 @@ func (js *jetstreamPubSub) Subscribe(ctx context.Context, req pubsub.SubscribeRe
 	consumerConfig.AckPolicy = js.meta.internalAckPolicy
 	consumerConfig.FilterSubject = req.Topic
 
+	// Messages are sent to the dead-letter topic when they fail processing for the last time
+	deadLetterTopic := req.Metadata[mdutils.DeadLetterTopicMetadataKey]
+	if deadLetterTopic != "" && consumerConfig.MaxDeliver == 0 {
+		consumerConfig.MaxDeliver = defaultDeadLetterMaxDeliver
+	}
+	ackEnabled := js.meta.internalAckPolicy == nats.AckExplicitPolicy || js.meta.internalAckPolicy == nats.AckAllPolicy
+
 	natsHandler := func(m *nats.Msg) {
 		jsm, err := m.Metadata()
 		if err != nil {
@@ -204,17 +307,40 @@ func (js *jetstreamPubSub) Subscribe(ctx context.Context, req pubsub.SubscribeRe
 		}
 
 		js.l.Debugf("Processing JetStream message %s/%d", m.Subject, jsm.Sequence)
+		md := map[string]string{
+			"Topic": m.Subject,
+		}
+		for _, key := range requestReplyHeaders {
+			if v := m.Header.Get(key); v != "" {
+				md[key] = v
+			}
+		}
 		err = handler(ctx, &pubsub.NewMessage{
-			Topic: req.Topic,
-			Data:  m.Data,
-			Metadata: map[string]string{
-				"Topic": m.Subject,
-			},
+			Topic:    req.Topic,
+			Data:     m.Data,
+			Metadata: md,
 		})
 		if err != nil {
 			js.l.Errorf("Error processing JetStream message %s/%d: %v", m.Subject, jsm.Sequence, err)
 
-			if js.meta.internalAckPolicy == nats.AckExplicitPolicy || js.meta.internalAckPolicy == nats.AckAllPolicy {
+			// Without acknowledgements, or after the last delivery, JetStream won't deliver the message again
+			lastDelivery := !ackEnabled || (consumerConfig.MaxDeliver > 0 && jsm.NumDelivered >= uint64(consumerConfig.MaxDeliver)) //nolint:gosec
+			if deadLetterTopic != "" && lastDelivery {
+				err = js.deadLetter(deadLetterTopic, m)
+				if err == nil {
+					js.l.Debugf("Sent JetStream message %s/%d to dead-letter topic %s", m.Subject, jsm.Sequence, deadLetterTopic)
+					if ackEnabled {
+						err = m.Term()
+						if err != nil {
+							js.l.Errorf("Error while sending TERM for JetStream message %s/%d: %v", m.Subject, jsm.Sequence, err)
+						}
+					}
+					return
+				}
+				js.l.Errorf("Error sending JetStream message %s/%d to dead-letter topic %s: %v", m.Subject, jsm.Sequence, deadLetterTopic, err)
+			}
+
+			if ackEnabled {
 				var nakErr error
 				if js.meta.AckWait != 0 {
 					nakErr = m.NakWithDelay(js.meta.AckWait)
@@ -229,7 +355,7 @@ func (js *jetstreamPubSub) Subscribe(ctx context.Context, req pubsub.SubscribeRe
 			return
 		}
 
-		if js.meta.internalAckPolicy == nats.AckExplicitPolicy || js.meta.internalAckPolicy == nats.AckAllPolicy {
+		if ackEnabled {
 			err = m.Ack()
 			if err != nil {
 				js.l.Errorf("Error while sending ACK for JetStream message %s/%d: %v", m.Subject, jsm.Sequence, err)
@@ -252,7 +378,6 @@ func (js *jetstreamPubSub) Subscribe(ctx context.Context, req pubsub.SubscribeRe
 		}
 	}
 
-	var err error
 	streamName := js.meta.StreamName
 	if streamName == "" {
 		streamName, err = js.jsc.StreamNameBySubject(req.Topic)
@@ -262,6 +387,13 @@ func (js *jetstreamPubSub) Subscribe(ctx context.Context, req pubsub.SubscribeRe
 	}
 	var sub *nats.Subscription
 
+	if replay && consumerConfig.Durable != "" {
+		err = js.keepDurableConsumerPosition(streamName, &consumerConfig)
+		if err != nil {
+			return err
+		}
+	}
+
 	consumerInfo, err := js.jsc.AddConsumer(streamName, &consumerConfig)
 	if err != nil {
 		return err
@@ -294,6 +426,62 @@ func (js *jetstreamPubSub) Subscribe(ctx context.Context, req pubsub.SubscribeRe
 	return nil
 }
 
+// setStartPosition sets the deliver policy of a consumer to start from a position.
+// Offsets are stream sequence numbers.
+func setStartPosition(consumerConfig *nats.ConsumerConfig, pos mdutils.StartPosition) error {
+	consumerConfig.OptStartSeq = 0
+	consumerConfig.OptStartTime = nil
+
+	switch pos.From {
+	case mdutils.StartFromEarliest:
+		consumerConfig.DeliverPolicy = nats.DeliverAllPolicy
+	case mdutils.StartFromLatest:
+		consumerConfig.DeliverPolicy = nats.DeliverNewPolicy
+	case mdutils.StartFromTimestamp:
+		consumerConfig.DeliverPolicy = nats.DeliverByStartTimePolicy
+		consumerConfig.OptStartTime = &pos.Timestamp
+	case mdutils.StartFromOffset:
+		seq, err := strconv.ParseUint(pos.Offset, 10, 64)
+		if err != nil || seq == 0 {
+			return fmt.Errorf("%s value must be a positive stream sequence: actual is '%s'", mdutils.StartOffsetMetadataKey, pos.Offset)
+		}
+		consumerConfig.DeliverPolicy = nats.DeliverByStartSequencePolicy
+		consumerConfig.OptStartSeq = seq
+	}
+	return nil
+}
+
+// keepDurableConsumerPosition keeps the start position of an existing durable consumer, which resumes from where it was.
+// The start position of the subscription applies only when the consumer is created; to replay its messages, delete the consumer.
+func (js *jetstreamPubSub) keepDurableConsumerPosition(streamName string, consumerConfig *nats.ConsumerConfig) error {
+	info, err := js.jsc.ConsumerInfo(streamName, consumerConfig.Durable)
+	if errors.Is(err, nats.ErrConsumerNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	existing := info.Config
+	sameStartTime := (existing.OptStartTime == nil && consumerConfig.OptStartTime == nil) ||
+		(existing.OptStartTime != nil && consumerConfig.OptStartTime != nil && existing.OptStartTime.Equal(*consumerConfig.OptStartTime))
+	if existing.DeliverPolicy != consumerConfig.DeliverPolicy || existing.OptStartSeq != consumerConfig.OptStartSeq || !sameStartTime {
+		js.l.Infof("nats: durable consumer %s already exists and resumes from its position: delete it to start from a new position", consumerConfig.Durable)
+	}
+
+	consumerConfig.DeliverPolicy = existing.DeliverPolicy
+	consumerConfig.OptStartSeq = existing.OptStartSeq
+	consumerConfig.OptStartTime = existing.OptStartTime
+	return nil
+}
+
+// deadLetter publishes the data of a message to the dead-letter topic.
+// The message ID header is not copied, or the message could be discarded as a duplicate if the dead-letter topic is in the same stream.
+func (js *jetstreamPubSub) deadLetter(topic string, m *nats.Msg) error {
+	_, err := js.jsc.Publish(topic, m.Data)
+	return err
+}
+
 func (js *jetstreamPubSub) Close() error {
 	defer js.wg.Wait()
 	if js.closed.CompareAndSwap(false, true) {
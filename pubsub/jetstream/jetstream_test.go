@@ -15,6 +15,7 @@ package jetstream
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -165,3 +166,221 @@ func TestNewJetStream_DurableQueuePushConsumer(t *testing.T) {
 	case <-time.After(10 * time.Millisecond):
 	}
 }
+
+func TestNewJetStream_DeadLetterTopic(t *testing.T) {
+	ns, nc := setupServerAndStream(t)
+	defer ns.Shutdown()
+	defer nc.Drain()
+
+	js, err := nc.JetStream()
+	require.NoError(t, err)
+	_, err = js.AddStream(&nats.StreamConfig{
+		Name:     "dlq",
+		Subjects: []string{"dlq"},
+		Storage:  nats.MemoryStorage,
+	})
+	require.NoError(t, err)
+
+	bus := NewJetStream(logger.NewLogger("test"))
+	defer bus.Close()
+
+	err = bus.Init(t.Context(), pubsub.Metadata{
+		Base: mdata.Base{
+			Properties: map[string]string{
+				"natsURL":    ns.ClientURL(),
+				"maxDeliver": "2",
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, bus.Features(), pubsub.FeatureDeadLetter)
+
+	ctx := t.Context()
+	attempts := make(chan []byte, 10)
+	dead := make(chan []byte, 10)
+
+	err = bus.Subscribe(ctx, pubsub.SubscribeRequest{Topic: "dlq"}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+		dead <- msg.Data
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = bus.Subscribe(ctx, pubsub.SubscribeRequest{
+		Topic:    "test",
+		Metadata: map[string]string{mdata.DeadLetterTopicMetadataKey: "dlq"},
+	}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+		attempts <- msg.Data
+		return errors.New("simulated failure")
+	})
+	require.NoError(t, err)
+
+	payload := []byte(`{"id": "ABCD-DLQ", "data": "test"}`)
+	err = bus.Publish(ctx, &pubsub.PublishRequest{
+		Data:  payload,
+		Topic: "test",
+	})
+	require.NoError(t, err)
+
+	// The message is sent to the dead-letter topic after the last delivery fails.
+	select {
+	case output := <-dead:
+		assert.Equal(t, payload, output)
+	case <-time.After(5 * time.Second):
+		t.Fatal("receive timeout")
+	}
+	assert.Len(t, attempts, 2)
+}
+
+func TestNewJetStream_Replay(t *testing.T) {
+	ns, nc := setupServerAndStream(t)
+	defer ns.Shutdown()
+	defer nc.Drain()
+
+	bus := NewJetStream(logger.NewLogger("test"))
+	defer bus.Close()
+
+	err := bus.Init(t.Context(), pubsub.Metadata{
+		Base: mdata.Base{
+			Properties: map[string]string{
+				"natsURL":     ns.ClientURL(),
+				"durableName": "test",
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, bus.Features(), pubsub.FeatureReplay)
+
+	ctx := t.Context()
+	payloads := [][]byte{
+		[]byte(`{"id": "ABCD-1", "data": "test"}`),
+		[]byte(`{"id": "ABCD-2", "data": "test"}`),
+		[]byte(`{"id": "ABCD-3", "data": "test"}`),
+	}
+	for _, payload := range payloads {
+		err = bus.Publish(ctx, &pubsub.PublishRequest{
+			Data:  payload,
+			Topic: "test",
+		})
+		require.NoError(t, err)
+	}
+
+	subscribe := func(md map[string]string) (<-chan []byte, context.CancelFunc) {
+		subCtx, cancel := context.WithCancel(ctx)
+		t.Cleanup(cancel)
+
+		ch := make(chan []byte, 10)
+		err := bus.Subscribe(subCtx, pubsub.SubscribeRequest{Topic: "test", Metadata: md}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+			ch <- msg.Data
+			return nil
+		})
+		require.NoError(t, err)
+		return ch, cancel
+	}
+	receive := func(ch <-chan []byte, expected [][]byte) {
+		for _, payload := range expected {
+			select {
+			case output := <-ch:
+				assert.Equal(t, payload, output)
+			case <-time.After(time.Second):
+				t.Fatal("receive timeout")
+			}
+		}
+		select {
+		case output := <-ch:
+			t.Fatalf("unexpected message received: %s", string(output))
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	unsubscribe := func(cancel context.CancelFunc) {
+		cancel()
+		// Wait for the subscription to be removed
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// The durable consumer starts from the stream sequence
+	ch, cancel := subscribe(map[string]string{
+		mdata.StartFromMetadataKey:   "offset",
+		mdata.StartOffsetMetadataKey: "2",
+	})
+	receive(ch, payloads[1:])
+	unsubscribe(cancel)
+
+	// The existing durable consumer resumes from where it was
+	payloads = append(payloads, []byte(`{"id": "ABCD-4", "data": "test"}`))
+	err = bus.Publish(ctx, &pubsub.PublishRequest{
+		Data:  payloads[3],
+		Topic: "test",
+	})
+	require.NoError(t, err)
+	ch, cancel = subscribe(map[string]string{mdata.StartFromMetadataKey: "earliest"})
+	receive(ch, payloads[3:])
+	unsubscribe(cancel)
+
+	js, _ := nc.JetStream()
+	ci, err := js.ConsumerInfo("test", "test")
+	require.NoError(t, err)
+	assert.Equal(t, nats.DeliverByStartSequencePolicy, ci.Config.DeliverPolicy)
+
+	// Deleting the durable consumer replays the messages
+	require.NoError(t, js.DeleteConsumer("test", "test"))
+	ch, _ = subscribe(map[string]string{mdata.StartFromMetadataKey: "earliest"})
+	receive(ch, payloads)
+
+	// Invalid stream sequence
+	err = bus.Subscribe(ctx, pubsub.SubscribeRequest{Topic: "test", Metadata: map[string]string{
+		mdata.StartFromMetadataKey:   "offset",
+		mdata.StartOffsetMetadataKey: "0",
+	}}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+		return nil
+	})
+	require.Error(t, err)
+}
+
+func TestNewJetStream_Request(t *testing.T) {
+	ns, nc := setupServerAndStream(t)
+	defer ns.Shutdown()
+	defer nc.Drain()
+
+	bus := NewJetStream(logger.NewLogger("test"))
+	defer bus.Close()
+
+	err := bus.Init(t.Context(), pubsub.Metadata{
+		Base: mdata.Base{
+			Properties: map[string]string{
+				"natsURL": ns.ClientURL(),
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Implements(t, (*pubsub.Requester)(nil), bus)
+
+	ctx := t.Context()
+	err = bus.Subscribe(ctx, pubsub.SubscribeRequest{Topic: "test"}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+		// A reply with a different correlation ID is ignored by the requester
+		err := bus.Publish(ctx, &pubsub.PublishRequest{
+			Topic:    msg.Metadata[mdata.ReplyToMetadataKey],
+			Data:     []byte("other"),
+			Metadata: map[string]string{mdata.CorrelationIDMetadataKey: "other"},
+		})
+		if err != nil {
+			return err
+		}
+
+		reply, err := pubsub.NewReplyRequest(msg, append([]byte("re: "), msg.Data...))
+		if err != nil {
+			return err
+		}
+		return bus.Publish(ctx, reply)
+	})
+	require.NoError(t, err)
+
+	res, err := pubsub.Request(ctx, bus, &pubsub.RequestReplyRequest{
+		PublishRequest: pubsub.PublishRequest{Topic: "test", Data: []byte("ping")},
+		CorrelationID:  "c-1",
+		Timeout:        5 * time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "re: ping", string(res.Data))
+	assert.Equal(t, "c-1", res.Metadata[mdata.CorrelationIDMetadataKey])
+
+}
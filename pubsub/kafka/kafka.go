@@ -49,10 +49,20 @@ func (p *PubSub) Subscribe(ctx context.Context, req pubsub.SubscribeRequest, han
 	if err != nil {
 		return err
 	}
+	keySchemaType, err := kafka.GetKeySchemaType(req.Metadata)
+	if err != nil {
+		return err
+	}
+	startPosition, err := kafka.GetStartPosition(req.Metadata)
+	if err != nil {
+		return err
+	}
 	handlerConfig := kafka.SubscriptionHandlerConfig{
 		IsBulkSubscribe: false,
 		Handler:         adaptHandler(handler),
 		ValueSchemaType: valueSchemaType,
+		KeySchemaType:   keySchemaType,
+		StartPosition:   startPosition,
 	}
 
 	p.subscribeUtil(ctx, req, handlerConfig)
@@ -74,11 +84,21 @@ func (p *PubSub) BulkSubscribe(ctx context.Context, req pubsub.SubscribeRequest,
 	if err != nil {
 		return err
 	}
+	keySchemaType, err := kafka.GetKeySchemaType(req.Metadata)
+	if err != nil {
+		return err
+	}
+	startPosition, err := kafka.GetStartPosition(req.Metadata)
+	if err != nil {
+		return err
+	}
 	handlerConfig := kafka.SubscriptionHandlerConfig{
 		IsBulkSubscribe: true,
 		SubscribeConfig: subConfig,
 		BulkHandler:     adaptBulkHandler(handler),
 		ValueSchemaType: valueSchemaType,
+		KeySchemaType:   keySchemaType,
+		StartPosition:   startPosition,
 	}
 	p.subscribeUtil(ctx, req, handlerConfig)
 	return nil
@@ -139,7 +159,7 @@ func (p *PubSub) Close() (err error) {
 }
 
 func (p *PubSub) Features() []pubsub.Feature {
-	return []pubsub.Feature{pubsub.FeatureBulkPublish}
+	return []pubsub.Feature{pubsub.FeatureBulkPublish, pubsub.FeatureOrderedDelivery, pubsub.FeatureReplay}
 }
 
 func adaptHandler(handler pubsub.Handler) kafka.EventHandler {
@@ -0,0 +1,115 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgresql
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dapr/components-contrib/common/authentication/aws"
+	pgauth "github.com/dapr/components-contrib/common/authentication/postgresql"
+	"github.com/dapr/components-contrib/pubsub"
+	kitmd "github.com/dapr/kit/metadata"
+	"github.com/dapr/kit/ptr"
+)
+
+type pgTable string
+
+const (
+	pgTableMessages      pgTable = "pubsub_messages"
+	pgTableSubscriptions pgTable = "pubsub_subscriptions"
+)
+
+const (
+	defaultTimeout           = 20 * time.Second // Default timeout for network requests
+	defaultVisibilityTimeout = time.Minute
+	defaultPollInterval      = 5 * time.Second
+	defaultBatchSize         = 10
+	defaultCleanupInternal   = time.Hour
+)
+
+type pgMetadata struct {
+	pgauth.PostgresAuthMetadata `mapstructure:",squash"`
+
+	ConsumerID        string                 `mapstructure:"consumerID"`
+	TablePrefix       string                 `mapstructure:"tablePrefix"`       // Could be in the format "schema.table" or just "table"
+	MetadataTableName string                 `mapstructure:"metadataTableName"` // Could be in the format "schema.table" or just "table"
+	Timeout           time.Duration          `mapstructure:"timeout" mapstructurealiases:"timeoutInSeconds"`
+	VisibilityTimeout time.Duration          `mapstructure:"visibilityTimeout"`
+	PollInterval      time.Duration          `mapstructure:"pollInterval"`
+	BatchSize         int                    `mapstructure:"batchSize"`
+	CleanupInterval   *time.Duration         `mapstructure:"cleanupInterval"`
+	ConcurrencyMode   pubsub.ConcurrencyMode `mapstructure:"concurrencyMode"`
+
+	aws.DeprecatedPostgresIAM `mapstructure:",squash"`
+}
+
+func (m *pgMetadata) InitWithMetadata(meta pubsub.Metadata, opts pgauth.InitWithMetadataOpts) error {
+	// Reset the object
+	m.PostgresAuthMetadata.Reset()
+	m.ConsumerID = ""
+	m.TablePrefix = ""
+	m.MetadataTableName = "dapr_metadata"
+	m.Timeout = defaultTimeout
+	m.VisibilityTimeout = defaultVisibilityTimeout
+	m.PollInterval = defaultPollInterval
+	m.BatchSize = defaultBatchSize
+	m.CleanupInterval = ptr.Of(defaultCleanupInternal)
+	m.ConcurrencyMode = ""
+
+	// Decode the metadata
+	err := kitmd.DecodeMetadata(meta.Properties, &m)
+	if err != nil {
+		return err
+	}
+
+	// Validate and sanitize input
+	err = m.PostgresAuthMetadata.InitWithMetadata(meta.Properties, opts)
+	if err != nil {
+		return err
+	}
+
+	if m.Timeout < 1*time.Second {
+		return errors.New("invalid value for 'timeout': must be greater than 1s")
+	}
+	if m.VisibilityTimeout < 1*time.Second {
+		return errors.New("invalid value for 'visibilityTimeout': must be greater than 1s")
+	}
+	if m.PollInterval <= 0 {
+		return errors.New("invalid value for 'pollInterval': must be greater than 0")
+	}
+	if m.BatchSize <= 0 {
+		return errors.New("invalid value for 'batchSize': must be greater than 0")
+	}
+
+	m.ConcurrencyMode, err = pubsub.Concurrency(meta.Properties)
+	if err != nil {
+		return err
+	}
+
+	// Non-positive value from meta means disable auto cleanup.
+	// We need to do this check because an empty string and "0" are treated differently by DecodeMetadata
+	v, ok := meta.GetProperty("cleanupInterval")
+	if ok && v == "" {
+		m.CleanupInterval = ptr.Of(defaultCleanupInternal)
+	} else if (ok && v == "0") || (m.CleanupInterval != nil && *m.CleanupInterval <= 0) {
+		m.CleanupInterval = nil
+	}
+
+	return nil
+}
+
+func (m *pgMetadata) TableName(table pgTable) string {
+	return m.TablePrefix + string(table)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pgauth "github.com/dapr/components-contrib/common/authentication/postgresql"
+	"github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/components-contrib/pubsub"
+)
+
+func TestMetadata(t *testing.T) {
+	initMetadata := func(props map[string]string) (pgMetadata, error) {
+		m := pgMetadata{}
+		err := m.InitWithMetadata(pubsub.Metadata{Base: metadata.Base{Properties: props}}, pgauth.InitWithMetadataOpts{})
+		return m, err
+	}
+
+	t.Run("missing connection string", func(t *testing.T) {
+		_, err := initMetadata(map[string]string{})
+		require.ErrorContains(t, err, "connection string")
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		m, err := initMetadata(map[string]string{
+			"connectionString": "foo=bar",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "pubsub_messages", m.TableName(pgTableMessages))
+		assert.Equal(t, "pubsub_subscriptions", m.TableName(pgTableSubscriptions))
+		assert.Equal(t, "dapr_metadata", m.MetadataTableName)
+		assert.Equal(t, defaultTimeout, m.Timeout)
+		assert.Equal(t, defaultVisibilityTimeout, m.VisibilityTimeout)
+		assert.Equal(t, defaultPollInterval, m.PollInterval)
+		assert.Equal(t, defaultBatchSize, m.BatchSize)
+		assert.Equal(t, pubsub.Parallel, m.ConcurrencyMode)
+		require.NotNil(t, m.CleanupInterval)
+		assert.Equal(t, defaultCleanupInternal, *m.CleanupInterval)
+	})
+
+	t.Run("custom values", func(t *testing.T) {
+		m, err := initMetadata(map[string]string{
+			"connectionString":  "foo=bar",
+			"consumerID":        "myapp",
+			"tablePrefix":       "my_",
+			"visibilityTimeout": "30s",
+			"pollInterval":      "1s",
+			"batchSize":         "100",
+			"concurrencyMode":   "single",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "myapp", m.ConsumerID)
+		assert.Equal(t, "my_pubsub_messages", m.TableName(pgTableMessages))
+		assert.Equal(t, 30*time.Second, m.VisibilityTimeout)
+		assert.Equal(t, time.Second, m.PollInterval)
+		assert.Equal(t, 100, m.BatchSize)
+		assert.Equal(t, pubsub.Single, m.ConcurrencyMode)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		for key, val := range map[string]string{
+			"timeout":           "500ms",
+			"visibilityTimeout": "100ms",
+			"pollInterval":      "0",
+			"batchSize":         "0",
+			"concurrencyMode":   "sometimes",
+		} {
+			_, err := initMetadata(map[string]string{
+				"connectionString": "foo=bar",
+				key:                val,
+			})
+			require.Error(t, err, key)
+		}
+	})
+
+	t.Run("cleanup interval", func(t *testing.T) {
+		m, err := initMetadata(map[string]string{
+			"connectionString": "foo=bar",
+			"cleanupInterval":  "10m",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, m.CleanupInterval)
+		assert.Equal(t, 10*time.Minute, *m.CleanupInterval)
+
+		m, err = initMetadata(map[string]string{
+			"connectionString": "foo=bar",
+			"cleanupInterval":  "0",
+		})
+		require.NoError(t, err)
+		assert.Nil(t, m.CleanupInterval)
+	})
+}
@@ -0,0 +1,651 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgresql
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	awsAuth "github.com/dapr/components-contrib/common/authentication/aws"
+	pgauth "github.com/dapr/components-contrib/common/authentication/postgresql"
+	pginterfaces "github.com/dapr/components-contrib/common/component/postgresql/interfaces"
+	sqlinternal "github.com/dapr/components-contrib/common/component/sql"
+	pgmigrations "github.com/dapr/components-contrib/common/component/sql/migrations/postgres"
+	"github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/components-contrib/pubsub"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	// Metadata key to set the consumer group of a subscription, overriding the consumerID of the component.
+	metadataConsumerIDKey = "consumerID"
+
+	// Time to wait before listening for notifications again after the connection was lost.
+	listenRetryInterval = 5 * time.Second
+)
+
+// PostgreSQL is a pubsub component that uses a PostgreSQL table as a queue.
+// Every message is stored once for each consumer group subscribed to its topic, and consumers lock the messages they receive with SKIP LOCKED.
+// Messages that are not acknowledged within the visibility timeout are delivered again.
+// Consumers are woken up with LISTEN/NOTIFY when messages are published, and poll the table periodically otherwise.
+// Consumer groups stay subscribed when their subscribers stop, so messages published in the meanwhile are delivered when they subscribe again.
+type PostgreSQL struct {
+	logger   logger.Logger
+	metadata pgMetadata
+	db       pginterfaces.PGXPoolConn
+
+	gc sqlinternal.GarbageCollector
+
+	enableAzureAD bool
+	enableAWSIAM  bool
+
+	awsAuthProvider awsAuth.Provider
+
+	// Wakeup channels of the subscriptions, by topic
+	listenersLock sync.Mutex
+	listeners     map[string]map[chan struct{}]struct{}
+	listening     bool
+
+	closed  atomic.Bool
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+type Options struct {
+	// Disables support for authenticating with Azure AD
+	// This should be set to "false" when targeting different databases than PostgreSQL (such as CockroachDB)
+	NoAzureAD bool
+
+	// Disables support for authenticating with AWS IAM
+	// This should be set to "false" when targeting different databases than PostgreSQL (such as CockroachDB)
+	NoAWSIAM bool
+}
+
+// NewPostgreSQL creates a new instance of the PostgreSQL pubsub component with the default options.
+func NewPostgreSQL(logger logger.Logger) pubsub.PubSub {
+	return NewPostgreSQLWithOptions(logger, Options{})
+}
+
+// NewPostgreSQLWithOptions creates a new instance of the PostgreSQL pubsub component with options.
+func NewPostgreSQLWithOptions(logger logger.Logger, opts Options) pubsub.PubSub {
+	return &PostgreSQL{
+		logger:        logger,
+		enableAzureAD: !opts.NoAzureAD,
+		enableAWSIAM:  !opts.NoAWSIAM,
+		listeners:     map[string]map[chan struct{}]struct{}{},
+		closeCh:       make(chan struct{}),
+	}
+}
+
+// Init sets up the connection to Postgres and performs migrations.
+func (p *PostgreSQL) Init(ctx context.Context, meta pubsub.Metadata) (err error) {
+	opts := pgauth.InitWithMetadataOpts{
+		AzureADEnabled: p.enableAzureAD,
+		AWSIAMEnabled:  p.enableAWSIAM,
+	}
+
+	err = p.metadata.InitWithMetadata(meta, opts)
+	if err != nil {
+		return err
+	}
+
+	config, err := p.metadata.GetPgxPoolConfig()
+	if err != nil {
+		return err
+	}
+
+	if opts.AWSIAMEnabled && p.metadata.UseAWSIAM {
+		opts, validateErr := p.metadata.BuildAwsIamOptions(p.logger, meta.Properties)
+		if validateErr != nil {
+			return fmt.Errorf("failed to validate AWS IAM authentication fields: %w", validateErr)
+		}
+
+		var provider awsAuth.Provider
+		provider, err = awsAuth.NewProvider(ctx, *opts, awsAuth.GetConfig(*opts))
+		if err != nil {
+			return err
+		}
+		p.awsAuthProvider = provider
+		p.awsAuthProvider.UpdatePostgres(ctx, config)
+	}
+
+	connCtx, connCancel := context.WithTimeout(ctx, p.metadata.Timeout)
+	defer connCancel()
+	p.db, err = pgxpool.NewWithConfig(connCtx, config)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the database: %w", err)
+	}
+
+	pingCtx, pingCancel := context.WithTimeout(ctx, p.metadata.Timeout)
+	defer pingCancel()
+	err = p.db.Ping(pingCtx)
+	if err != nil {
+		return fmt.Errorf("failed to ping the database: %w", err)
+	}
+
+	err = p.performMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	if p.metadata.CleanupInterval != nil {
+		gc, err := sqlinternal.ScheduleGarbageCollector(sqlinternal.GCOptions{
+			Logger: p.logger,
+			UpdateLastCleanupQuery: func(arg any) (string, any) {
+				return fmt.Sprintf(
+					`INSERT INTO %[1]s (key, value)
+				VALUES ('last-cleanup-pubsub-%[2]s', now()::text)
+				ON CONFLICT (key)
+				DO UPDATE SET value = now()::text
+					WHERE (EXTRACT('epoch' FROM now() - %[1]s.value::timestamp with time zone) * 1000)::bigint > $1`,
+					p.metadata.MetadataTableName,
+					p.metadata.TablePrefix,
+				), arg
+			},
+			DeleteExpiredValuesQuery: fmt.Sprintf(
+				`DELETE FROM %s WHERE expires_at IS NOT NULL AND expires_at < now()`,
+				p.metadata.TableName(pgTableMessages),
+			),
+			CleanupInterval: *p.metadata.CleanupInterval,
+			DB:              sqlinternal.AdaptPgxConn(p.db),
+		})
+		if err != nil {
+			return err
+		}
+		p.gc = gc
+	}
+
+	return nil
+}
+
+func (p *PostgreSQL) performMigrations(ctx context.Context) error {
+	m := pgmigrations.Migrations{
+		DB:                p.db,
+		Logger:            p.logger,
+		MetadataTableName: p.metadata.MetadataTableName,
+		MetadataKey:       "migrations-pubsub-" + p.metadata.TablePrefix,
+	}
+
+	messagesTable := p.metadata.TableName(pgTableMessages)
+	subscriptionsTable := p.metadata.TableName(pgTableSubscriptions)
+
+	return m.Perform(ctx, []sqlinternal.MigrationFn{
+		// Migration 1: create the tables for subscriptions and messages
+		func(ctx context.Context) error {
+			p.logger.Infof("Creating pubsub tables: '%s' and '%s'", subscriptionsTable, messagesTable)
+			_, err := p.db.Exec(ctx,
+				fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS %[1]s (
+  topic text NOT NULL,
+  consumer_group text NOT NULL,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  PRIMARY KEY (topic, consumer_group)
+);
+
+CREATE TABLE IF NOT EXISTS %[2]s (
+  id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  topic text NOT NULL,
+  consumer_group text NOT NULL,
+  data bytea NOT NULL,
+  content_type text,
+  metadata jsonb,
+  delivery_count integer NOT NULL DEFAULT 0,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  visible_at timestamp with time zone NOT NULL DEFAULT now(),
+  expires_at timestamp with time zone
+);
+
+CREATE INDEX ON %[2]s (topic, consumer_group, visible_at);
+CREATE INDEX ON %[2]s (expires_at);
+`, subscriptionsTable, messagesTable),
+			)
+			if err != nil {
+				// Check if the error is about a duplicate key constraint violation.
+				// Note: This can occur due to a race of multiple sidecars trying to run the table creation within their own transactions.
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+					p.logger.Debugf("ignoring PostgreSQL duplicate key error for table '%s'", messagesTable)
+				} else {
+					return fmt.Errorf("failed to create pubsub tables: %w", err)
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// Features returns the features available in this pubsub component.
+func (p *PostgreSQL) Features() []pubsub.Feature {
+	return []pubsub.Feature{
+		pubsub.FeatureMessageTTL,
+		pubsub.FeatureDelayedDelivery,
+	}
+}
+
+// Publish stores the message once for each consumer group subscribed to the topic, then notifies the subscribers.
+// Messages published to topics without subscriptions are discarded.
+func (p *PostgreSQL) Publish(parentCtx context.Context, req *pubsub.PublishRequest) error {
+	if p.closed.Load() {
+		return errors.New("component is closed")
+	}
+	if req.Topic == "" {
+		return errors.New("topic is required")
+	}
+
+	var ttlMs int64
+	ttl, ok, err := metadata.TryGetTTL(req.Metadata)
+	if err != nil {
+		return err
+	}
+	if ok {
+		ttlMs = ttl.Milliseconds()
+	}
+
+	var delayMs int64
+	deliverAt, ok, err := metadata.TryGetDeliveryTime(req.Metadata, time.Now())
+	if err != nil {
+		return err
+	}
+	if ok {
+		delayMs = max(time.Until(deliverAt).Milliseconds(), 0)
+	}
+
+	// Metadata is stored as JSON, or NULL if empty
+	var md any
+	if len(req.Metadata) > 0 {
+		enc, err := json.Marshal(req.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to serialize metadata: %w", err)
+		}
+		md = string(enc)
+	}
+
+	data := req.Data
+	if data == nil {
+		data = []byte{}
+	}
+
+	query := fmt.Sprintf(`WITH inserted AS (
+  INSERT INTO %[1]s (topic, consumer_group, data, content_type, metadata, visible_at, expires_at)
+  SELECT $1, consumer_group, $2, $3, $4::jsonb,
+    now() + ($5::bigint * interval '1 millisecond'),
+    CASE WHEN $6::bigint > 0 THEN now() + ($6::bigint * interval '1 millisecond') END
+  FROM %[2]s
+  WHERE topic = $1
+  RETURNING 1
+)
+SELECT pg_notify($7, $1) FROM (SELECT 1 FROM inserted LIMIT 1) AS i`,
+		p.metadata.TableName(pgTableMessages),
+		p.metadata.TableName(pgTableSubscriptions),
+	)
+
+	ctx, cancel := context.WithTimeout(parentCtx, p.metadata.Timeout)
+	defer cancel()
+	_, err = p.db.Exec(ctx, query, req.Topic, data, req.ContentType, md, delayMs, ttlMs, p.notifyChannel())
+	if err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+	return nil
+}
+
+// Subscribe registers the consumer group for the topic and starts receiving messages in background, until ctx is canceled.
+// The consumer group is the consumerID of the component, unless it is set in the metadata of the subscription.
+func (p *PostgreSQL) Subscribe(ctx context.Context, req pubsub.SubscribeRequest, handler pubsub.Handler) error {
+	if p.closed.Load() {
+		return errors.New("component is closed")
+	}
+	if req.Topic == "" {
+		return errors.New("topic is required")
+	}
+
+	group := p.metadata.ConsumerID
+	if val := req.Metadata[metadataConsumerIDKey]; val != "" {
+		group = val
+	}
+	if group == "" {
+		return errors.New("consumerID is required to subscribe")
+	}
+
+	queryCtx, queryCancel := context.WithTimeout(ctx, p.metadata.Timeout)
+	defer queryCancel()
+	_, err := p.db.Exec(queryCtx,
+		fmt.Sprintf(`INSERT INTO %s (topic, consumer_group) VALUES ($1, $2) ON CONFLICT DO NOTHING`, p.metadata.TableName(pgTableSubscriptions)),
+		req.Topic, group,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register subscription: %w", err)
+	}
+
+	wakeCh := p.addListener(req.Topic)
+
+	subCtx, cancel := context.WithCancel(ctx)
+	p.wg.Add(2)
+	go func() {
+		defer p.wg.Done()
+		select {
+		case <-subCtx.Done():
+		case <-p.closeCh:
+			cancel()
+		}
+	}()
+	go func() {
+		defer p.wg.Done()
+		defer cancel()
+		defer p.removeListener(req.Topic, wakeCh)
+		p.consume(subCtx, req.Topic, group, handler, wakeCh)
+	}()
+
+	return nil
+}
+
+// consume receives messages until ctx is canceled.
+// A new batch is fetched right away when the previous one was full, when the subscription is woken up, or when the poll interval elapses.
+func (p *PostgreSQL) consume(ctx context.Context, topic string, group string, handler pubsub.Handler, wakeCh <-chan struct{}) {
+	t := time.NewTicker(p.metadata.PollInterval)
+	defer t.Stop()
+
+	for {
+		n, err := p.receiveBatch(ctx, topic, group, handler)
+		if err != nil && ctx.Err() == nil {
+			p.logger.Errorf("Error receiving messages from topic '%s': %v", topic, err)
+		}
+		if n == p.metadata.BatchSize {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-wakeCh:
+		case <-t.C:
+		}
+	}
+}
+
+type message struct {
+	id            int64
+	data          []byte
+	contentType   *string
+	metadata      map[string]string
+	deliveryCount int
+}
+
+// receiveBatch locks a batch of messages for the visibility timeout and invokes the handler for each of them.
+// Messages are removed once the handler succeeds; otherwise, they are delivered again when the visibility timeout expires.
+func (p *PostgreSQL) receiveBatch(ctx context.Context, topic string, group string, handler pubsub.Handler) (int, error) {
+	msgs, err := p.fetch(ctx, topic, group)
+	if err != nil {
+		return 0, err
+	}
+
+	switch p.metadata.ConcurrencyMode {
+	case pubsub.Single:
+		for _, msg := range msgs {
+			if ctx.Err() != nil {
+				// The remaining messages are delivered again after the visibility timeout
+				break
+			}
+			p.handleMessage(ctx, topic, handler, msg)
+		}
+	default:
+		var wg sync.WaitGroup
+		wg.Add(len(msgs))
+		for _, msg := range msgs {
+			go func() {
+				defer wg.Done()
+				p.handleMessage(ctx, topic, handler, msg)
+			}()
+		}
+		wg.Wait()
+	}
+
+	return len(msgs), nil
+}
+
+func (p *PostgreSQL) fetch(parentCtx context.Context, topic string, group string) ([]message, error) {
+	messagesTable := p.metadata.TableName(pgTableMessages)
+	query := fmt.Sprintf(`UPDATE %[1]s
+SET visible_at = now() + ($3::bigint * interval '1 millisecond'), delivery_count = delivery_count + 1
+WHERE id IN (
+  SELECT id FROM %[1]s
+  WHERE topic = $1 AND consumer_group = $2
+    AND visible_at <= now()
+    AND (expires_at IS NULL OR expires_at > now())
+  ORDER BY id
+  LIMIT $4
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, data, content_type, metadata, delivery_count`, messagesTable)
+
+	ctx, cancel := context.WithTimeout(parentCtx, p.metadata.Timeout)
+	defer cancel()
+	rows, err := p.db.Query(ctx, query, topic, group, p.metadata.VisibilityTimeout.Milliseconds(), p.metadata.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch messages: %w", err)
+	}
+	defer rows.Close()
+
+	msgs := make([]message, 0, p.metadata.BatchSize)
+	for rows.Next() {
+		var (
+			msg message
+			md  []byte
+		)
+		err = rows.Scan(&msg.id, &msg.data, &msg.contentType, &md, &msg.deliveryCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read message: %w", err)
+		}
+		if len(md) > 0 {
+			err = json.Unmarshal(md, &msg.metadata)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse metadata of message %d: %w", msg.id, err)
+			}
+		}
+		msgs = append(msgs, msg)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch messages: %w", err)
+	}
+
+	// RETURNING doesn't preserve the order of the subquery
+	slices.SortFunc(msgs, func(a, b message) int {
+		return cmp.Compare(a.id, b.id)
+	})
+	return msgs, nil
+}
+
+func (p *PostgreSQL) handleMessage(ctx context.Context, topic string, handler pubsub.Handler, msg message) {
+	err := handler(ctx, &pubsub.NewMessage{
+		Data:        msg.data,
+		Topic:       topic,
+		Metadata:    msg.metadata,
+		ContentType: msg.contentType,
+	})
+	if err != nil {
+		p.logger.Errorf("Error processing message %d from topic '%s' (delivery %d), it will be delivered again after the visibility timeout: %v", msg.id, topic, msg.deliveryCount, err)
+		return
+	}
+
+	// Use a separate context, so a message that was handled is acknowledged even if the subscription is being stopped
+	ackCtx, cancel := context.WithTimeout(context.Background(), p.metadata.Timeout)
+	defer cancel()
+	_, err = p.db.Exec(ackCtx, fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, p.metadata.TableName(pgTableMessages)), msg.id)
+	if err != nil {
+		p.logger.Errorf("Error acknowledging message %d from topic '%s', it will be delivered again after the visibility timeout: %v", msg.id, topic, err)
+	}
+}
+
+// notifyChannel returns the name of the channel where new messages are notified.
+func (p *PostgreSQL) notifyChannel() string {
+	h := fnv.New64a()
+	h.Write([]byte(p.metadata.TableName(pgTableMessages)))
+	return "dapr_pubsub_" + strconv.FormatUint(h.Sum64(), 16)
+}
+
+// addListener returns the channel that wakes up a subscription to the topic, and starts listening for notifications if needed.
+func (p *PostgreSQL) addListener(topic string) chan struct{} {
+	ch := make(chan struct{}, 1)
+
+	p.listenersLock.Lock()
+	defer p.listenersLock.Unlock()
+	if p.listeners[topic] == nil {
+		p.listeners[topic] = map[chan struct{}]struct{}{}
+	}
+	p.listeners[topic][ch] = struct{}{}
+
+	if !p.listening {
+		p.listening = true
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.listenLoop()
+		}()
+	}
+
+	return ch
+}
+
+func (p *PostgreSQL) removeListener(topic string, ch chan struct{}) {
+	p.listenersLock.Lock()
+	defer p.listenersLock.Unlock()
+	delete(p.listeners[topic], ch)
+	if len(p.listeners[topic]) == 0 {
+		delete(p.listeners, topic)
+	}
+}
+
+// wake wakes up the subscriptions to the topic.
+func (p *PostgreSQL) wake(topic string) {
+	p.listenersLock.Lock()
+	defer p.listenersLock.Unlock()
+	for ch := range p.listeners[topic] {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// Already woken up
+		}
+	}
+}
+
+// listenLoop listens for notifications of new messages until the component is closed.
+// If the connection is lost, subscriptions keep polling until it's established again.
+func (p *PostgreSQL) listenLoop() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-p.closeCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		err := p.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		p.logger.Errorf("Error listening for notifications, retrying in %v: %v", listenRetryInterval, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryInterval):
+		}
+	}
+}
+
+func (p *PostgreSQL) listen(ctx context.Context) error {
+	// LISTEN requires a dedicated connection, which is removed from the pool and closed when listening ends
+	pool, ok := p.db.(*pgxpool.Pool)
+	if !ok {
+		return errors.New("listening for notifications requires a connection pool")
+	}
+	poolConn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	conn := poolConn.Hijack()
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{p.notifyChannel()}.Sanitize())
+	if err != nil {
+		return fmt.Errorf("failed to listen for notifications: %w", err)
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		p.wake(notification.Payload)
+	}
+}
+
+// Ping checks the connection to the database.
+func (p *PostgreSQL) Ping(ctx context.Context) error {
+	if p.db == nil {
+		return errors.New("component is not initialized")
+	}
+	return p.db.Ping(ctx)
+}
+
+// Close stops all subscriptions and closes the connection to the database.
+func (p *PostgreSQL) Close() error {
+	if p.closed.CompareAndSwap(false, true) {
+		close(p.closeCh)
+	}
+	p.wg.Wait()
+
+	if p.db != nil {
+		p.db.Close()
+		p.db = nil
+	}
+
+	errs := make([]error, 2)
+	if p.gc != nil {
+		errs[0] = p.gc.Close()
+	}
+
+	if p.awsAuthProvider != nil {
+		errs[1] = p.awsAuthProvider.Close()
+	}
+	return errors.Join(errs...)
+}
+
+// GetComponentMetadata returns the metadata of the component.
+func (p *PostgreSQL) GetComponentMetadata() (metadataInfo metadata.MetadataMap) {
+	metadataStruct := pgMetadata{}
+	metadata.GetMetadataInfoFromStructType(reflect.TypeOf(metadataStruct), &metadataInfo, metadata.PubSubType)
+	return
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/components-contrib/pubsub"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	connectionStringEnvKey = "DAPR_TEST_POSTGRES_CONNSTRING" // Environment variable containing the connection string
+)
+
+func TestPostgreSQLIntegration(t *testing.T) {
+	connectionString := os.Getenv(connectionStringEnvKey)
+	if connectionString == "" {
+		t.Skipf("PostgreSQL pubsub integration tests skipped. To enable define the connection string using environment variable '%s' (example 'export %s=\"host=localhost user=postgres password=example port=5432 connect_timeout=10 database=dapr_test\")", connectionStringEnvKey, connectionStringEnvKey)
+	}
+
+	// Use a random table prefix so tests don't interfere with each other
+	prefix := "pubsubtest_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:8] + "_"
+	ps := NewPostgreSQL(logger.NewLogger("test")).(*PostgreSQL)
+	t.Cleanup(func() {
+		_, _ = ps.db.Exec(context.Background(), "DROP TABLE IF EXISTS "+ps.metadata.TableName(pgTableMessages))
+		_, _ = ps.db.Exec(context.Background(), "DROP TABLE IF EXISTS "+ps.metadata.TableName(pgTableSubscriptions))
+		_, _ = ps.db.Exec(context.Background(), "DELETE FROM dapr_metadata WHERE key LIKE '%"+prefix+"'")
+		ps.Close()
+	})
+	err := ps.Init(t.Context(), pubsub.Metadata{Base: metadata.Base{Properties: map[string]string{
+		"connectionString":  connectionString,
+		"tablePrefix":       prefix,
+		"consumerID":        "group1",
+		"visibilityTimeout": "2s",
+		"pollInterval":      "500ms",
+	}}})
+	require.NoError(t, err)
+
+	subscribe := func(t *testing.T, topic string, group string, handler pubsub.Handler) {
+		t.Helper()
+		ctx, cancel := context.WithCancel(t.Context())
+		t.Cleanup(cancel)
+		err := ps.Subscribe(ctx, pubsub.SubscribeRequest{Topic: topic, Metadata: map[string]string{metadataConsumerIDKey: group}}, handler)
+		require.NoError(t, err)
+	}
+
+	receive := func(t *testing.T, ch <-chan string) string {
+		t.Helper()
+		select {
+		case msg := <-ch:
+			return msg
+		case <-time.After(10 * time.Second):
+			require.Fail(t, "timed out waiting for message")
+			return ""
+		}
+	}
+
+	t.Run("each consumer group receives every message", func(t *testing.T) {
+		ch1 := make(chan string, 10)
+		ch2 := make(chan string, 10)
+		subscribe(t, "fanout", "group1", func(ctx context.Context, msg *pubsub.NewMessage) error {
+			ch1 <- string(msg.Data)
+			return nil
+		})
+		subscribe(t, "fanout", "group2", func(ctx context.Context, msg *pubsub.NewMessage) error {
+			ch2 <- string(msg.Data)
+			return nil
+		})
+
+		err := ps.Publish(t.Context(), &pubsub.PublishRequest{Topic: "fanout", Data: []byte("hello")})
+		require.NoError(t, err)
+		assert.Equal(t, "hello", receive(t, ch1))
+		assert.Equal(t, "hello", receive(t, ch2))
+	})
+
+	t.Run("failed messages are delivered again after the visibility timeout", func(t *testing.T) {
+		var attempts atomic.Int32
+		ch := make(chan string, 10)
+		subscribe(t, "retry", "group1", func(ctx context.Context, msg *pubsub.NewMessage) error {
+			if attempts.Add(1) == 1 {
+				return errors.New("simulated failure")
+			}
+			ch <- string(msg.Data)
+			return nil
+		})
+
+		err := ps.Publish(t.Context(), &pubsub.PublishRequest{Topic: "retry", Data: []byte("again")})
+		require.NoError(t, err)
+		assert.Equal(t, "again", receive(t, ch))
+		assert.Equal(t, int32(2), attempts.Load())
+	})
+
+	t.Run("expired messages are not delivered", func(t *testing.T) {
+		ch := make(chan string, 10)
+		subscribe(t, "ttl", "group1", func(ctx context.Context, msg *pubsub.NewMessage) error {
+			ch <- string(msg.Data)
+			return nil
+		})
+
+		// The first message expires before it is visible
+		err := ps.Publish(t.Context(), &pubsub.PublishRequest{Topic: "ttl", Data: []byte("expired"), Metadata: map[string]string{
+			metadata.TTLMetadataKey:          "1",
+			metadata.DeliverAfterMetadataKey: "2s",
+		}})
+		require.NoError(t, err)
+		err = ps.Publish(t.Context(), &pubsub.PublishRequest{Topic: "ttl", Data: []byte("delayed"), Metadata: map[string]string{
+			metadata.DeliverAfterMetadataKey: "2s",
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, "delayed", receive(t, ch))
+		select {
+		case msg := <-ch:
+			assert.Failf(t, "unexpected message", "received %s", msg)
+		case <-time.After(time.Second):
+		}
+	})
+}
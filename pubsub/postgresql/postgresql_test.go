@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	pgxmock "github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pgauth "github.com/dapr/components-contrib/common/authentication/postgresql"
+	"github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/components-contrib/pubsub"
+	"github.com/dapr/kit/logger"
+	"github.com/dapr/kit/ptr"
+)
+
+func newMockedComponent(t *testing.T, props map[string]string) (*PostgreSQL, pgxmock.PgxPoolIface) {
+	t.Helper()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(mock.Close)
+
+	p := NewPostgreSQL(logger.NewLogger("test")).(*PostgreSQL)
+	props["connectionString"] = "foo=bar"
+	err = p.metadata.InitWithMetadata(pubsub.Metadata{Base: metadata.Base{Properties: props}}, pgauth.InitWithMetadataOpts{})
+	require.NoError(t, err)
+	p.db = mock
+	return p, mock
+}
+
+func TestPublish(t *testing.T) {
+	t.Run("message is stored for each consumer group", func(t *testing.T) {
+		p, mock := newMockedComponent(t, map[string]string{})
+
+		contentType := ptr.Of("text/plain")
+		mock.ExpectExec(`INSERT INTO pubsub_messages .* FROM pubsub_subscriptions\s+WHERE topic = \$1`).
+			WithArgs("orders", []byte("hello"), contentType, `{"ttl":"10"}`, int64(0), int64(10000), p.notifyChannel()).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+
+		err := p.Publish(t.Context(), &pubsub.PublishRequest{
+			Topic:       "orders",
+			Data:        []byte("hello"),
+			ContentType: contentType,
+			Metadata:    map[string]string{metadata.TTLMetadataKey: "10"},
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("invalid delivery time", func(t *testing.T) {
+		p, mock := newMockedComponent(t, map[string]string{})
+
+		err := p.Publish(t.Context(), &pubsub.PublishRequest{
+			Topic:    "orders",
+			Data:     []byte("hello"),
+			Metadata: map[string]string{metadata.DeliverAfterMetadataKey: "soon"},
+		})
+		require.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReceiveBatch(t *testing.T) {
+	for _, mode := range []pubsub.ConcurrencyMode{pubsub.Single, pubsub.Parallel} {
+		t.Run(string(mode), func(t *testing.T) {
+			p, mock := newMockedComponent(t, map[string]string{
+				"concurrencyMode": string(mode),
+			})
+
+			// Rows are returned out of order
+			mock.ExpectQuery(`UPDATE pubsub_messages\s+SET visible_at .* FOR UPDATE SKIP LOCKED`).
+				WithArgs("orders", "group", int64(60000), 10).
+				WillReturnRows(pgxmock.NewRows([]string{"id", "data", "content_type", "metadata", "delivery_count"}).
+					AddRow(int64(2), []byte("fail"), nil, nil, 1).
+					AddRow(int64(1), []byte("ok"), ptr.Of("text/plain"), []byte(`{"foo":"bar"}`), 1),
+				)
+			// Only the message that was handled successfully is removed
+			mock.ExpectExec(`DELETE FROM pubsub_messages WHERE id = \$1`).
+				WithArgs(int64(1)).
+				WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+			var (
+				lock     sync.Mutex
+				received []*pubsub.NewMessage
+			)
+			n, err := p.receiveBatch(t.Context(), "orders", "group", func(ctx context.Context, msg *pubsub.NewMessage) error {
+				lock.Lock()
+				received = append(received, msg)
+				lock.Unlock()
+				if string(msg.Data) == "fail" {
+					return errors.New("handler failed")
+				}
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, 2, n)
+			require.NoError(t, mock.ExpectationsWereMet())
+
+			require.Len(t, received, 2)
+			if mode == pubsub.Single {
+				assert.Equal(t, "ok", string(received[0].Data))
+				assert.Equal(t, "fail", string(received[1].Data))
+			}
+			for _, msg := range received {
+				assert.Equal(t, "orders", msg.Topic)
+				if string(msg.Data) == "ok" {
+					assert.Equal(t, map[string]string{"foo": "bar"}, msg.Metadata)
+					assert.Equal(t, ptr.Of("text/plain"), msg.ContentType)
+				}
+			}
+		})
+	}
+}
+
+func TestSubscribeRequiresConsumerID(t *testing.T) {
+	p, mock := newMockedComponent(t, map[string]string{})
+
+	err := p.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "orders"}, func(context.Context, *pubsub.NewMessage) error {
+		return nil
+	})
+	require.ErrorContains(t, err, "consumerID")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -31,6 +31,7 @@ type pulsarMetadata struct {
 	Namespace                        string                    `mapstructure:"namespace"`
 	Persistent                       bool                      `mapstructure:"persistent"`
 	RedeliveryDelay                  time.Duration             `mapstructure:"redeliveryDelay"`
+	MaxDeliveryAttempts              uint32                    `mapstructure:"maxDeliveryAttempts"`
 	internalTopicSchemas             map[string]schemaMetadata `mapstructure:"-"`
 	PublicKey                        string                    `mapstructure:"publicKey"`
 	PrivateKey                       string                    `mapstructure:"privateKey"`
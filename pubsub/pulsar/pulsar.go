@@ -41,8 +41,8 @@ const (
 	host                    = "host"
 	consumerID              = "consumerID"
 	enableTLS               = "enableTLS"
-	deliverAt               = "deliverAt"
-	deliverAfter            = "deliverAfter"
+	deliverAt               = metadata.DeliverAtMetadataKey
+	deliverAfter            = metadata.DeliverAfterMetadataKey
 	disableBatching         = "disableBatching"
 	batchingMaxPublishDelay = "batchingMaxPublishDelay"
 	batchingMaxSize         = "batchingMaxSize"
@@ -78,6 +78,8 @@ const (
 	defaultMaxBatchSize = 128 * 1024
 	// defaultRedeliveryDelay init default for redelivery delay.
 	defaultRedeliveryDelay = 30 * time.Second
+	// defaultMaxDeliveryAttempts init default for the number of deliveries before a message is sent to the dead-letter topic.
+	defaultMaxDeliveryAttempts = 5
 	// defaultConcurrency controls the number of concurrent messages sent to the app.
 	defaultConcurrency = 100
 	// defaultReceiverQueueSize controls the number of messages the pulsar sdk pulls before dapr explicitly consumes the messages.
@@ -152,6 +154,7 @@ func parsePulsarMetadata(meta pubsub.Metadata) (*pulsarMetadata, error) {
 		BatchingMaxMessages:     defaultMaxMessages,
 		BatchingMaxSize:         defaultMaxBatchSize,
 		RedeliveryDelay:         defaultRedeliveryDelay,
+		MaxDeliveryAttempts:     defaultMaxDeliveryAttempts,
 		MaxConcurrentHandlers:   defaultConcurrency,
 		ReceiverQueueSize:       defaultReceiverQueueSize,
 	}
@@ -415,16 +418,8 @@ func parsePublishMetadata(req *pubsub.PublishRequest, schema schemaMetadata) (
 		switch name {
 		case partitionKey:
 			msg.Key = value
-		case deliverAt:
-			msg.DeliverAt, err = time.Parse(time.RFC3339, value)
-			if err != nil {
-				return nil, err
-			}
-		case deliverAfter:
-			msg.DeliverAfter, err = time.ParseDuration(value)
-			if err != nil {
-				return nil, err
-			}
+		case deliverAt, deliverAfter:
+			// Parsed below
 		default:
 			if msg.Properties == nil {
 				msg.Properties = make(map[string]string)
@@ -433,6 +428,20 @@ func parsePublishMetadata(req *pubsub.PublishRequest, schema schemaMetadata) (
 		}
 	}
 
+	// deliverAfter is a duration or a number of seconds, like for the other components that support delayed delivery
+	now := time.Now()
+	deliveryTime, ok, err := metadata.TryGetDeliveryTime(req.Metadata, now)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if req.Metadata[deliverAfter] != "" {
+			msg.DeliverAfter = deliveryTime.Sub(now)
+		} else {
+			msg.DeliverAt = deliveryTime
+		}
+	}
+
 	return msg, nil
 }
 
@@ -564,6 +573,18 @@ func (p *Pulsar) Subscribe(ctx context.Context, req pubsub.SubscribeRequest, han
 		return errors.New("component is closed")
 	}
 
+	startPosition, replay, err := metadata.TryGetStartPosition(req.Metadata)
+	if err != nil {
+		return err
+	}
+	var initialPosition pulsar.SubscriptionInitialPosition
+	if replay {
+		initialPosition, err = getStartPosition(startPosition)
+		if err != nil {
+			return err
+		}
+	}
+
 	channel := make(chan pulsar.ConsumerMessage, p.metadata.MaxConcurrentHandlers)
 
 	topic := p.formatTopic(req.Topic)
@@ -585,6 +606,14 @@ func (p *Pulsar) Subscribe(ctx context.Context, req pubsub.SubscribeRequest, han
 		ReplicateSubscriptionState:  p.metadata.ReplicateSubscriptionState,
 	}
 
+	// Messages are sent to the dead-letter topic by the client after the max delivery attempts
+	if dlt := req.Metadata[metadata.DeadLetterTopicMetadataKey]; dlt != "" {
+		options.DLQ = &pulsar.DLQPolicy{
+			MaxDeliveries:   p.metadata.MaxDeliveryAttempts,
+			DeadLetterTopic: p.formatTopic(dlt),
+		}
+	}
+
 	// Handle KeySharedPolicy for key_shared subscription type
 	if options.Type == pulsar.KeyShared {
 		options.KeySharedPolicy = &pulsar.KeySharedPolicy{
@@ -592,6 +621,11 @@ func (p *Pulsar) Subscribe(ctx context.Context, req pubsub.SubscribeRequest, han
 		}
 	}
 
+	// The start position of the subscription overrides the initial position of the component
+	if replay {
+		options.SubscriptionInitialPosition = initialPosition
+	}
+
 	if p.useConsumerEncryption() {
 		var reader crypto.KeyReader
 		if isValidPEM(p.metadata.PublicKey) {
@@ -634,6 +668,20 @@ func (p *Pulsar) Subscribe(ctx context.Context, req pubsub.SubscribeRequest, han
 	return nil
 }
 
+// getStartPosition returns the initial position of a subscription, which Pulsar applies only when the subscription is created.
+// Existing subscriptions resume from where they are, so restarting or scaling out the subscribers doesn't deliver messages again.
+// Pulsar can only create subscriptions at the earliest or latest message: other positions require resetting the cursor of the subscription with the admin tools.
+func getStartPosition(pos metadata.StartPosition) (pulsar.SubscriptionInitialPosition, error) {
+	switch pos.From {
+	case metadata.StartFromEarliest:
+		return pulsar.SubscriptionPositionEarliest, nil
+	case metadata.StartFromLatest:
+		return pulsar.SubscriptionPositionLatest, nil
+	default:
+		return 0, fmt.Errorf("%s value '%s' is not supported: Pulsar subscriptions can only start from '%s' or '%s'", metadata.StartFromMetadataKey, pos.From, metadata.StartFromEarliest, metadata.StartFromLatest)
+	}
+}
+
 func (p *Pulsar) listenMessage(ctx context.Context, req pubsub.SubscribeRequest, consumer pulsar.Consumer, handler pubsub.Handler) {
 	defer consumer.Close()
 
@@ -703,7 +751,8 @@ func (p *Pulsar) Close() error {
 }
 
 func (p *Pulsar) Features() []pubsub.Feature {
-	return nil
+	// Delayed messages are delivered only to shared and key_shared subscriptions; other subscriptions receive them immediately
+	return []pubsub.Feature{pubsub.FeatureDelayedDelivery, pubsub.FeatureDeadLetter, pubsub.FeatureReplay}
 }
 
 // formatTopic formats the topic into pulsar's structure with tenant and namespace.
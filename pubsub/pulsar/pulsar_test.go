@@ -457,18 +457,45 @@ func TestGetPulsarSchema(t *testing.T) {
 }
 
 func TestParsePublishMetadata(t *testing.T) {
-	m := &pubsub.PublishRequest{}
-	m.Metadata = map[string]string{
-		"deliverAt":    "2021-08-31T11:45:02Z",
-		"deliverAfter": "60s",
-	}
-	msg, err := parsePublishMetadata(m, schemaMetadata{})
-	require.NoError(t, err)
+	t.Run("deliverAt", func(t *testing.T) {
+		m := &pubsub.PublishRequest{}
+		m.Metadata = map[string]string{
+			"deliverAt": "2021-08-31T11:45:02Z",
+		}
+		msg, err := parsePublishMetadata(m, schemaMetadata{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "2021-08-31T11:45:02Z",
+			msg.DeliverAt.Format(time.RFC3339))
+		assert.Zero(t, msg.DeliverAfter)
+	})
+
+	t.Run("deliverAfter", func(t *testing.T) {
+		for _, v := range []string{"60s", "60"} {
+			m := &pubsub.PublishRequest{}
+			m.Metadata = map[string]string{
+				"deliverAfter": v,
+			}
+			msg, err := parsePublishMetadata(m, schemaMetadata{})
+			require.NoError(t, err)
 
-	val, _ := time.ParseDuration("60s")
-	assert.Equal(t, val, msg.DeliverAfter)
-	assert.Equal(t, "2021-08-31T11:45:02Z",
-		msg.DeliverAt.Format(time.RFC3339))
+			assert.Equal(t, time.Minute, msg.DeliverAfter)
+			assert.True(t, msg.DeliverAt.IsZero())
+			assert.Empty(t, msg.Properties)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, md := range []map[string]string{
+			{"deliverAt": "2021-08-31T11:45:02Z", "deliverAfter": "60s"},
+			{"deliverAfter": "soon"},
+			{"deliverAt": "tomorrow"},
+		} {
+			m := &pubsub.PublishRequest{Metadata: md}
+			_, err := parsePublishMetadata(m, schemaMetadata{})
+			require.Error(t, err, md)
+		}
+	})
 }
 
 func TestMissingHost(t *testing.T) {
@@ -1188,6 +1215,60 @@ func TestSubscribe_AppliesMetadataOptions(t *testing.T) {
 		"Bug: SubscriptionMode defaulted to 'Durable' instead of 'NonDurable'")
 }
 
+func TestSubscribe_StartPosition(t *testing.T) {
+	subscribe := func(md map[string]string) (pulsar.ConsumerOptions, error) {
+		var capturedOptions pulsar.ConsumerOptions
+		p := NewPulsar(logger.NewLogger("test")).(*Pulsar)
+		p.client = &MockPulsarClient{
+			SubscribeFn: func(options pulsar.ConsumerOptions) (pulsar.Consumer, error) {
+				capturedOptions = options
+				return &MockPulsarConsumer{Ch: make(chan pulsar.ConsumerMessage)}, nil
+			},
+		}
+		parsedMeta, err := parsePulsarMetadata(pubsub.Metadata{
+			Base: metadata.Base{Properties: map[string]string{
+				"host":                     "localhost:6650",
+				"subscribeInitialPosition": "latest",
+			}},
+		})
+		require.NoError(t, err)
+		p.metadata = *parsedMeta
+
+		err = p.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "my-topic", Metadata: md}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+			return nil
+		})
+		return capturedOptions, err
+	}
+
+	t.Run("not set", func(t *testing.T) {
+		options, err := subscribe(nil)
+		require.NoError(t, err)
+		assert.Equal(t, pulsar.SubscriptionPositionLatest, options.SubscriptionInitialPosition)
+	})
+
+	t.Run("earliest", func(t *testing.T) {
+		options, err := subscribe(map[string]string{"startFrom": "earliest"})
+		require.NoError(t, err)
+		assert.Equal(t, pulsar.SubscriptionPositionEarliest, options.SubscriptionInitialPosition)
+	})
+
+	t.Run("timestamp not supported", func(t *testing.T) {
+		_, err := subscribe(map[string]string{
+			"startFrom":      "timestamp",
+			"startTimestamp": "2025-01-02T03:04:05Z",
+		})
+		require.ErrorContains(t, err, "not supported")
+	})
+
+	t.Run("offset not supported", func(t *testing.T) {
+		_, err := subscribe(map[string]string{
+			"startFrom":   "offset",
+			"startOffset": "12:34:0",
+		})
+		require.ErrorContains(t, err, "not supported")
+	})
+}
+
 type MockPulsarClient struct {
 	pulsar.Client
 	SubscribeFn func(pulsar.ConsumerOptions) (pulsar.Consumer, error)
@@ -53,6 +53,8 @@ type rabbitmqMetadata struct {
 	Concurrency                        pubsub.ConcurrencyMode `mapstructure:"concurrency"`
 	DefaultQueueTTL                    *time.Duration         `mapstructure:"ttlInSeconds"`
 	PublishMessagePropertiesToMetadata bool                   `mapstructure:"publishMessagePropertiesToMetadata"`
+	EnableDelayedDelivery              bool                   `mapstructure:"enableDelayedDelivery"`
+	SingleActiveConsumer               bool                   `mapstructure:"singleActiveConsumer"`
 }
 
 const (
@@ -84,6 +86,8 @@ const (
 	metadataHeartBeatKey                          = "heartBeat"
 	metadataQueueNameKey                          = "queueName"
 	metadataPublishMessagePropertiesToMetadataKey = "publishMessagePropertiesToMetadata"
+	metadataEnableDelayedDeliveryKey              = "enableDelayedDelivery"
+	metadataSingleActiveConsumerKey               = "singleActiveConsumer"
 
 	defaultReconnectWaitSeconds = 3
 
@@ -37,6 +37,7 @@ import (
 
 const (
 	fanoutExchangeKind              = "fanout"
+	delayedMessageExchangeKind      = "x-delayed-message"
 	logMessagePrefix                = "rabbitmq pub/sub:"
 	errorMessagePrefix              = "rabbitmq pub/sub error:"
 	errorChannelNotInitialized      = "channel not initialized"
@@ -45,6 +46,10 @@ const (
 	defaultDeadLetterExchangeFormat = "dlx-%s"
 	defaultDeadLetterQueueFormat    = "dlq-%s"
 
+	// Prefixes of the names of server-named queues, where replies to requests are published with the default exchange
+	replyQueuePrefix       = "amq.gen-"
+	directReplyQueuePrefix = "amq.rabbitmq.reply-to"
+
 	publishMaxRetries       = 3
 	publishRetryWaitSeconds = 2
 	defaultHeartbeat        = 10 * time.Second
@@ -56,6 +61,9 @@ const (
 	argDeadLetterExchange              = "x-dead-letter-exchange"
 	argMaxPriority                     = "x-max-priority"
 	argSingleActiveConsumer            = "x-single-active-consumer"
+	argDelayedType                     = "x-delayed-type"
+	headerDelay                        = "x-delay"
+	headerOrderingKey                  = "x-ordering-key"
 	propertyClientName                 = "connection_name"
 	queueModeLazy                      = "lazy"
 	reqMetadataRoutingKey              = "routingKey"
@@ -91,6 +99,7 @@ type rabbitMQChannelBroker interface {
 	QueueDeclare(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp.Table) (amqp.Queue, error)
 	QueueBind(name string, key string, exchange string, noWait bool, args amqp.Table) error
 	Consume(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+	Cancel(consumer string, noWait bool) error
 	Nack(tag uint64, multiple bool, requeue bool) error
 	Ack(tag uint64, multiple bool) error
 	ExchangeDeclare(name string, kind string, durable bool, autoDelete bool, internal bool, noWait bool, args amqp.Table) error
@@ -222,14 +231,21 @@ func (r *rabbitMQ) publishSync(ctx context.Context, req *pubsub.PublishRequest)
 		return r.channel, r.connectionCount, errors.New(errorChannelNotInitialized)
 	}
 
-	if err := r.ensureExchangeDeclared(r.channel, req.Topic, r.metadata.ExchangeKind, r.metadata.Durable, r.metadata.DeleteWhenUnused); err != nil {
-		r.logger.Errorf("%s publishing to %s failed in ensureExchangeDeclared: %v", logMessagePrefix, req.Topic, err)
-
-		return r.channel, r.connectionCount, err
-	}
+	exchange := req.Topic
 	routingKey := ""
-	if val, ok := req.Metadata[reqMetadataRoutingKey]; ok && val != "" {
-		routingKey = val
+	if isReplyQueue(req.Topic) {
+		// Replies are routed to the queue of the requester by the default exchange
+		exchange = ""
+		routingKey = req.Topic
+	} else {
+		if err := r.ensureTopicExchangeDeclared(r.channel, req.Topic); err != nil {
+			r.logger.Errorf("%s publishing to %s failed in ensureExchangeDeclared: %v", logMessagePrefix, req.Topic, err)
+
+			return r.channel, r.connectionCount, err
+		}
+		if val, ok := req.Metadata[reqMetadataRoutingKey]; ok && val != "" {
+			routingKey = val
+		}
 	}
 
 	ttl, ok, err := metadata.TryGetTTL(req.Metadata)
@@ -262,7 +278,25 @@ func (r *rabbitMQ) publishSync(ctx context.Context, req *pubsub.PublishRequest)
 
 	common.ApplyMetadataToPublishing(req.Metadata, &p)
 
-	confirm, err := r.channel.PublishWithDeferredConfirmWithContext(ctx, req.Topic, routingKey, false, false, p)
+	// The delivery time is validated by Publish
+	deliverAt, ok, _ := metadata.TryGetDeliveryTime(req.Metadata, time.Now())
+	if ok {
+		if delay := time.Until(deliverAt); delay > 0 {
+			if p.Headers == nil {
+				p.Headers = amqp.Table{}
+			}
+			p.Headers[headerDelay] = delay.Milliseconds()
+		}
+	}
+
+	if orderingKey := req.Metadata[metadata.OrderingKeyMetadataKey]; orderingKey != "" {
+		if p.Headers == nil {
+			p.Headers = amqp.Table{}
+		}
+		p.Headers[headerOrderingKey] = orderingKey
+	}
+
+	confirm, err := r.channel.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, false, false, p)
 	if err != nil {
 		r.logger.Errorf("%s publishing to %s failed in channel.Publish: %v", logMessagePrefix, req.Topic, err)
 
@@ -289,6 +323,14 @@ func (r *rabbitMQ) Publish(ctx context.Context, req *pubsub.PublishRequest) erro
 
 	r.logger.Debugf("%s publishing message to %s", logMessagePrefix, req.Topic)
 
+	_, delayed, err := metadata.TryGetDeliveryTime(req.Metadata, time.Now())
+	if err != nil {
+		return err
+	}
+	if delayed && !r.metadata.EnableDelayedDelivery {
+		return errors.New("delayed delivery requires enableDelayedDelivery to be set and the delayed message exchange plugin to be installed")
+	}
+
 	attempt := 0
 	for {
 		attempt++
@@ -320,6 +362,77 @@ func (r *rabbitMQ) Publish(ctx context.Context, req *pubsub.PublishRequest) erro
 	}
 }
 
+// Request publishes a message with the reply-to and correlation ID properties, and waits for the reply on an exclusive, server-named queue.
+// Responders publish the reply to the queue with the same correlation ID.
+func (r *rabbitMQ) Request(ctx context.Context, req *pubsub.RequestReplyRequest) (*pubsub.NewMessage, error) {
+	if r.closed.Load() {
+		return nil, errors.New("component is closed")
+	}
+
+	consumerTag := "dapr-reply-" + req.CorrelationID
+	r.channelMutex.Lock()
+	channel := r.channel
+	if channel == nil {
+		r.channelMutex.Unlock()
+		return nil, errors.New(errorChannelNotInitialized)
+	}
+	q, err := channel.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		r.channelMutex.Unlock()
+		return nil, fmt.Errorf("%s failed to declare reply queue: %w", errorMessagePrefix, err)
+	}
+	msgs, err := channel.Consume(q.Name, consumerTag, true, true, false, false, nil)
+	r.channelMutex.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed to consume reply queue %s: %w", errorMessagePrefix, q.Name, err)
+	}
+	defer func() {
+		// The queue is deleted with its last consumer
+		if err := channel.Cancel(consumerTag, false); err != nil {
+			r.logger.Debugf("%s failed to cancel consumer of reply queue %s: %v", logMessagePrefix, q.Name, err)
+		}
+	}()
+
+	md := make(map[string]string, len(req.Metadata)+2)
+	for k, v := range req.Metadata {
+		md[k] = v
+	}
+	md[common.MetadataKeyCorrelationID] = req.CorrelationID
+	md[common.MetadataKeyReplyTo] = q.Name
+	err = r.Publish(ctx, &pubsub.PublishRequest{
+		Data:        req.Data,
+		PubsubName:  req.PubsubName,
+		Topic:       req.Topic,
+		Metadata:    md,
+		ContentType: req.ContentType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case d, more := <-msgs:
+			if !more {
+				return nil, fmt.Errorf("%s reply queue %s closed before the reply was received", errorMessagePrefix, q.Name)
+			}
+			if d.CorrelationId != req.CorrelationID {
+				r.logger.Debugf("%s ignoring reply with a different correlation ID on queue %s", logMessagePrefix, q.Name)
+				continue
+			}
+			return &pubsub.NewMessage{
+				Data:  d.Body,
+				Topic: q.Name,
+				Metadata: map[string]string{
+					metadata.CorrelationIDMetadataKey: d.CorrelationId,
+				},
+			}, nil
+		}
+	}
+}
+
 func (r *rabbitMQ) Subscribe(ctx context.Context, req pubsub.SubscribeRequest, handler pubsub.Handler) error {
 	if r.closed.Load() {
 		return errors.New("component is closed")
@@ -369,7 +482,7 @@ func (r *rabbitMQ) Subscribe(ctx context.Context, req pubsub.SubscribeRequest, h
 
 // this function call should be wrapped by channelMutex.
 func (r *rabbitMQ) prepareSubscription(channel rabbitMQChannelBroker, req pubsub.SubscribeRequest, queueName string) (*amqp.Queue, error) {
-	err := r.ensureExchangeDeclared(channel, req.Topic, r.metadata.ExchangeKind, r.metadata.Durable, r.metadata.DeleteWhenUnused)
+	err := r.ensureTopicExchangeDeclared(channel, req.Topic)
 	if err != nil {
 		r.logger.Errorf("%s prepareSubscription for topic/queue '%s/%s' failed in ensureExchangeDeclared: %v", logMessagePrefix, req.Topic, queueName, err)
 
@@ -378,7 +491,17 @@ func (r *rabbitMQ) prepareSubscription(channel rabbitMQChannelBroker, req pubsub
 
 	r.logger.Infof("%s declaring queue '%s'", logMessagePrefix, queueName)
 	var args amqp.Table
-	if r.metadata.EnableDeadLetter {
+	if dlt := req.Metadata[metadata.DeadLetterTopicMetadataKey]; dlt != "" {
+		// Rejected messages are routed to the exchange of the dead-letter topic, so they're received by its subscribers
+		err = r.ensureTopicExchangeDeclared(channel, dlt)
+		if err != nil {
+			r.logger.Errorf("%s prepareSubscription for topic/queue '%s/%s' failed in ensureExchangeDeclared for dead-letter topic '%s': %v", logMessagePrefix, req.Topic, queueName, dlt, err)
+
+			return nil, err
+		}
+		r.logger.Infof("%s using dead-letter topic '%s' for queue '%s'", logMessagePrefix, dlt, queueName)
+		args = amqp.Table{argDeadLetterExchange: dlt}
+	} else if r.metadata.EnableDeadLetter {
 		// declare dead letter exchange
 		dlxName := fmt.Sprintf(defaultDeadLetterExchangeFormat, queueName)
 		dlqName := fmt.Sprintf(defaultDeadLetterQueueFormat, queueName)
@@ -437,8 +560,8 @@ func (r *rabbitMQ) prepareSubscription(channel rabbitMQChannelBroker, req pubsub
 		args[amqp.QueueTypeArg] = amqp.QueueTypeClassic
 	}
 
-	// Applying x-single-active-consumer if defined at subscription level
-	if val := req.Metadata[reqMetadataSingleActiveConsumerKey]; kitstrings.IsTruthy(val) {
+	// Applying x-single-active-consumer if defined at component or subscription level
+	if val := req.Metadata[reqMetadataSingleActiveConsumerKey]; r.metadata.SingleActiveConsumer || kitstrings.IsTruthy(val) {
 		args[argSingleActiveConsumer] = true
 	}
 
@@ -548,7 +671,8 @@ func (r *rabbitMQ) subscribeForever(ctx context.Context, req pubsub.SubscribeReq
 				ackCh = nil
 			}
 
-			err = r.listenMessages(ctx, channel, msgs, req.Topic, handler)
+			deadLetter := req.Metadata[metadata.DeadLetterTopicMetadataKey] != ""
+			err = r.listenMessages(ctx, channel, msgs, req.Topic, deadLetter, handler)
 			if err != nil {
 				errFuncName = "listenMessages"
 				break
@@ -589,8 +713,10 @@ func (r *rabbitMQ) subscribeForever(ctx context.Context, req pubsub.SubscribeReq
 	}
 }
 
-func (r *rabbitMQ) listenMessages(ctx context.Context, channel rabbitMQChannelBroker, msgCh <-chan amqp.Delivery, topic string, handler pubsub.Handler) error {
+func (r *rabbitMQ) listenMessages(ctx context.Context, channel rabbitMQChannelBroker, msgCh <-chan amqp.Delivery, topic string, deadLetter bool, handler pubsub.Handler) error {
 	var err error
+	// In parallel mode, messages with the same ordering key are still handled one at a time, in the order they were received
+	dispatcher := pubsub.NewOrderedDispatcher()
 	for {
 		select {
 		case <-ctx.Done():
@@ -604,24 +730,27 @@ func (r *rabbitMQ) listenMessages(ctx context.Context, channel rabbitMQChannelBr
 
 			switch r.metadata.Concurrency {
 			case pubsub.Single:
-				err = r.handleMessage(ctx, d, topic, handler)
+				err = r.handleMessage(ctx, d, topic, deadLetter, handler)
 				if err != nil && mustReconnect(channel, err) {
 					return err
 				}
 			case pubsub.Parallel:
 				r.wg.Add(1)
-				go func(d amqp.Delivery) {
+				orderingKey, _ := d.Headers[headerOrderingKey].(string)
+				dispatcher.Dispatch(orderingKey, func() {
 					defer r.wg.Done()
-					if err := r.handleMessage(ctx, d, topic, handler); err != nil {
+					if err := r.handleMessage(ctx, d, topic, deadLetter, handler); err != nil {
 						r.logger.Errorf("%s error handling message: %v", logMessagePrefix, err)
 					}
-				}(d)
+				})
 			}
 		}
 	}
 }
 
-func (r *rabbitMQ) handleMessage(ctx context.Context, d amqp.Delivery, topic string, handler pubsub.Handler) error {
+// handleMessage delivers a message to the handler, and acknowledges it if the component doesn't auto-ack.
+// With a dead-letter topic, failed messages are requeued once at most, so they are sent to the dead-letter topic when they fail again.
+func (r *rabbitMQ) handleMessage(ctx context.Context, d amqp.Delivery, topic string, deadLetter bool, handler pubsub.Handler) error {
 	pubsubMsg := &pubsub.NewMessage{
 		Data:     d.Body,
 		Topic:    topic,
@@ -632,6 +761,12 @@ func (r *rabbitMQ) handleMessage(ctx context.Context, d amqp.Delivery, topic str
 		pubsubMsg.Metadata = addAMQPPropertiesToMetadata(d)
 	}
 
+	// Requests are always delivered with the queue and correlation ID to send the reply with
+	if d.ReplyTo != "" {
+		pubsubMsg.Metadata[metadata.ReplyToMetadataKey] = d.ReplyTo
+		pubsubMsg.Metadata[metadata.CorrelationIDMetadataKey] = d.CorrelationId
+	}
+
 	err := handler(ctx, pubsubMsg)
 
 	if err != nil {
@@ -639,8 +774,9 @@ func (r *rabbitMQ) handleMessage(ctx context.Context, d amqp.Delivery, topic str
 
 		if !r.metadata.AutoAck {
 			// if message is not auto acked we need to ack/nack
-			r.logger.Debugf("%s nacking message '%s' from topic '%s', requeue=%t", logMessagePrefix, d.MessageId, topic, r.metadata.RequeueInFailure)
-			if err = d.Nack(false, r.metadata.RequeueInFailure); err != nil {
+			requeue := r.metadata.RequeueInFailure && !(deadLetter && d.Redelivered)
+			r.logger.Debugf("%s nacking message '%s' from topic '%s', requeue=%t", logMessagePrefix, d.MessageId, topic, requeue)
+			if err = d.Nack(false, requeue); err != nil {
 				r.logger.Errorf("%s error nacking message '%s' from topic '%s', %s", logMessagePrefix, d.MessageId, topic, err)
 			}
 		}
@@ -655,11 +791,26 @@ func (r *rabbitMQ) handleMessage(ctx context.Context, d amqp.Delivery, topic str
 	return err
 }
 
+// this function call should be wrapped by channelMutex.
+func (r *rabbitMQ) ensureTopicExchangeDeclared(channel rabbitMQChannelBroker, topic string) error {
+	if r.metadata.EnableDelayedDelivery {
+		// The delayed message exchange routes messages like an exchange of the x-delayed-type kind, after the delay in their x-delay header
+		args := amqp.Table{argDelayedType: r.metadata.ExchangeKind}
+		return r.ensureExchangeDeclaredWithArgs(channel, topic, delayedMessageExchangeKind, r.metadata.Durable, r.metadata.DeleteWhenUnused, args)
+	}
+	return r.ensureExchangeDeclared(channel, topic, r.metadata.ExchangeKind, r.metadata.Durable, r.metadata.DeleteWhenUnused)
+}
+
 // this function call should be wrapped by channelMutex.
 func (r *rabbitMQ) ensureExchangeDeclared(channel rabbitMQChannelBroker, exchange, exchangeKind string, durable bool, autoDelete bool) error {
+	return r.ensureExchangeDeclaredWithArgs(channel, exchange, exchangeKind, durable, autoDelete, nil)
+}
+
+// this function call should be wrapped by channelMutex.
+func (r *rabbitMQ) ensureExchangeDeclaredWithArgs(channel rabbitMQChannelBroker, exchange, exchangeKind string, durable bool, autoDelete bool, args amqp.Table) error {
 	if !r.containsExchange(exchange) {
 		r.logger.Debugf("%s declaring exchange '%s' of kind '%s'", logMessagePrefix, exchange, exchangeKind)
-		err := channel.ExchangeDeclare(exchange, exchangeKind, durable, autoDelete, false, false, nil)
+		err := channel.ExchangeDeclare(exchange, exchangeKind, durable, autoDelete, false, false, args)
 		if err != nil {
 			r.logger.Errorf("%s ensureExchangeDeclared: channel.ExchangeDeclare failed: %v", logMessagePrefix, err)
 
@@ -728,7 +879,15 @@ func (r *rabbitMQ) Close() error {
 }
 
 func (r *rabbitMQ) Features() []pubsub.Feature {
-	return []pubsub.Feature{pubsub.FeatureMessageTTL}
+	features := []pubsub.Feature{pubsub.FeatureMessageTTL, pubsub.FeatureDeadLetter}
+	if r.metadata != nil && r.metadata.EnableDelayedDelivery {
+		features = append(features, pubsub.FeatureDelayedDelivery)
+	}
+	// Messages are in order only if a single consumer receives them, and failed messages are not requeued behind the next ones
+	if r.metadata != nil && r.metadata.SingleActiveConsumer && !r.metadata.RequeueInFailure {
+		features = append(features, pubsub.FeatureOrderedDelivery)
+	}
+	return features
 }
 
 func mustReconnect(channel rabbitMQChannelBroker, err error) bool {
@@ -750,6 +909,11 @@ func (r *rabbitMQ) GetComponentMetadata() (metadataInfo metadata.MetadataMap) {
 	return
 }
 
+// isReplyQueue returns true if the topic is the server-named queue of a requester.
+func isReplyQueue(topic string) bool {
+	return strings.HasPrefix(topic, replyQueuePrefix) || strings.HasPrefix(topic, directReplyQueuePrefix)
+}
+
 func queueTypeValid(qType string) bool {
 	return qType == amqp.QueueTypeClassic || qType == amqp.QueueTypeQuorum
 }
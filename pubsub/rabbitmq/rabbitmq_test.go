@@ -17,6 +17,8 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"slices"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -270,6 +272,137 @@ func TestPublishAndSubscribe(t *testing.T) {
 	}
 }
 
+func TestPublishDelayed(t *testing.T) {
+	t.Run("delayed delivery is disabled", func(t *testing.T) {
+		broker := newBroker()
+		pubsubRabbitMQ := newRabbitMQTest(broker)
+		err := pubsubRabbitMQ.Init(t.Context(), pubsub.Metadata{Base: mdata.Base{
+			Properties: map[string]string{
+				metadataHostnameKey:   "anyhost",
+				metadataConsumerIDKey: "consumer",
+			},
+		}})
+		require.NoError(t, err)
+		assert.NotContains(t, pubsubRabbitMQ.Features(), pubsub.FeatureDelayedDelivery)
+
+		err = pubsubRabbitMQ.Publish(t.Context(), &pubsub.PublishRequest{Topic: "mytopic", Data: []byte("hello"), Metadata: map[string]string{
+			mdata.DeliverAfterMetadataKey: "10s",
+		}})
+		require.Error(t, err)
+	})
+
+	t.Run("delayed delivery is enabled", func(t *testing.T) {
+		broker := newBroker()
+		pubsubRabbitMQ := newRabbitMQTest(broker)
+		err := pubsubRabbitMQ.Init(t.Context(), pubsub.Metadata{Base: mdata.Base{
+			Properties: map[string]string{
+				metadataHostnameKey:              "anyhost",
+				metadataConsumerIDKey:            "consumer",
+				metadataEnableDelayedDeliveryKey: "true",
+			},
+		}})
+		require.NoError(t, err)
+		assert.Contains(t, pubsubRabbitMQ.Features(), pubsub.FeatureDelayedDelivery)
+
+		err = pubsubRabbitMQ.Publish(t.Context(), &pubsub.PublishRequest{Topic: "mytopic", Data: []byte("hello"), Metadata: map[string]string{
+			mdata.DeliverAfterMetadataKey: "10s",
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, delayedMessageExchangeKind, broker.lastExchangeKind)
+		assert.Equal(t, amqp.Table{argDelayedType: fanoutExchangeKind}, broker.lastExchangeArgs)
+		require.NotNil(t, broker.lastMsgMetadata)
+		delay, ok := broker.lastMsgMetadata.Headers[headerDelay].(int64)
+		require.True(t, ok)
+		assert.InDelta(t, 10000, delay, 1000)
+
+		err = pubsubRabbitMQ.Publish(t.Context(), &pubsub.PublishRequest{Topic: "mytopic", Data: []byte("hello"), Metadata: map[string]string{
+			mdata.DeliverAtMetadataKey: "not a time",
+		}})
+		require.Error(t, err)
+	})
+}
+
+func TestOrderedDelivery(t *testing.T) {
+	broker := newBroker()
+	broker.buffer = make(chan amqp.Delivery, 10)
+	pubsubRabbitMQ := newRabbitMQTest(broker)
+	err := pubsubRabbitMQ.Init(t.Context(), pubsub.Metadata{Base: mdata.Base{
+		Properties: map[string]string{
+			metadataHostnameKey:             "anyhost",
+			metadataConsumerIDKey:           "consumer",
+			metadataSingleActiveConsumerKey: "true",
+			pubsub.ConcurrencyKey:           string(pubsub.Parallel),
+		},
+	}})
+	require.NoError(t, err)
+	assert.Contains(t, pubsubRabbitMQ.Features(), pubsub.FeatureOrderedDelivery)
+
+	// The first message with key "a" is handled only after the message with key "b", so messages with different keys are handled in parallel
+	var lock sync.Mutex
+	var received []string
+	bDone := make(chan struct{})
+	processed := make(chan struct{}, 3)
+	handler := func(ctx context.Context, msg *pubsub.NewMessage) error {
+		switch string(msg.Data) {
+		case "a1":
+			select {
+			case <-bDone:
+			case <-time.After(5 * time.Second):
+				assert.Fail(t, "messages with different keys were not handled in parallel")
+			}
+		case "b1":
+			defer close(bDone)
+		}
+		lock.Lock()
+		received = append(received, string(msg.Data))
+		lock.Unlock()
+		processed <- struct{}{}
+		return nil
+	}
+	err = pubsubRabbitMQ.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "mytopic"}, handler)
+	require.NoError(t, err)
+
+	for _, m := range []struct{ data, key string }{{"a1", "a"}, {"b1", "b"}, {"a2", "a"}} {
+		err = pubsubRabbitMQ.Publish(t.Context(), &pubsub.PublishRequest{Topic: "mytopic", Data: []byte(m.data), Metadata: map[string]string{
+			mdata.OrderingKeyMetadataKey: m.key,
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, m.key, broker.lastMsgMetadata.Headers[headerOrderingKey])
+	}
+	for range 3 {
+		select {
+		case <-processed:
+		case <-time.After(10 * time.Second):
+			require.Fail(t, "timed out waiting for messages")
+		}
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, []string{"b1", "a1", "a2"}, received)
+}
+
+func TestOrderedDeliveryFeature(t *testing.T) {
+	tests := map[string]struct {
+		props    map[string]string
+		expected bool
+	}{
+		"default":                {props: map[string]string{}, expected: false},
+		"single active consumer": {props: map[string]string{metadataSingleActiveConsumerKey: "true"}, expected: true},
+		"requeue in failure":     {props: map[string]string{metadataSingleActiveConsumerKey: "true", metadataRequeueInFailureKey: "true"}, expected: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			pubsubRabbitMQ := newRabbitMQTest(newBroker())
+			tt.props[metadataHostnameKey] = "anyhost"
+			tt.props[metadataConsumerIDKey] = "consumer"
+			err := pubsubRabbitMQ.Init(t.Context(), pubsub.Metadata{Base: mdata.Base{Properties: tt.props}})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, slices.Contains(pubsubRabbitMQ.Features(), pubsub.FeatureOrderedDelivery))
+		})
+	}
+}
+
 func TestPublishReconnect(t *testing.T) {
 	broker := newBroker()
 	pubsubRabbitMQ := newRabbitMQTest(broker)
@@ -395,6 +528,82 @@ func TestSubscribeBindRoutingKeys(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSubscribeDeadLetterTopic(t *testing.T) {
+	broker := newBroker()
+	pubsubRabbitMQ := newRabbitMQTest(broker)
+	err := pubsubRabbitMQ.Init(t.Context(), pubsub.Metadata{Base: mdata.Base{
+		Properties: map[string]string{
+			metadataHostnameKey:         "anyhost",
+			metadataConsumerIDKey:       "consumer",
+			metadataEnableDeadLetterKey: "true",
+			metadataRequeueInFailureKey: "false",
+		},
+	}})
+	require.NoError(t, err)
+	assert.Contains(t, pubsubRabbitMQ.Features(), pubsub.FeatureDeadLetter)
+
+	handler := func(ctx context.Context, msg *pubsub.NewMessage) error {
+		return nil
+	}
+
+	// The dead-letter topic of the subscription takes precedence over the dead-letter queue of the component
+	err = pubsubRabbitMQ.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "mytopic_dlt", Metadata: map[string]string{
+		mdata.DeadLetterTopicMetadataKey: "poison",
+	}}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "poison", broker.lastQueueArgs[argDeadLetterExchange])
+	assert.Contains(t, pubsubRabbitMQ.declaredExchanges, "poison")
+
+	err = pubsubRabbitMQ.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "mytopic_dlq"}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "dlx-consumer-mytopic_dlq", broker.lastQueueArgs[argDeadLetterExchange])
+}
+
+// nackRecorder is an amqp.Acknowledger that records whether nacked messages are requeued.
+type nackRecorder struct {
+	requeued []bool
+}
+
+func (r *nackRecorder) Ack(tag uint64, multiple bool) error { return nil }
+
+func (r *nackRecorder) Nack(tag uint64, multiple bool, requeue bool) error {
+	r.requeued = append(r.requeued, requeue)
+	return nil
+}
+
+func (r *nackRecorder) Reject(tag uint64, requeue bool) error { return nil }
+
+func TestHandleMessageDeadLetterRequeue(t *testing.T) {
+	broker := newBroker()
+	pubsubRabbitMQ := newRabbitMQTest(broker)
+	err := pubsubRabbitMQ.Init(t.Context(), pubsub.Metadata{Base: mdata.Base{
+		Properties: map[string]string{
+			metadataHostnameKey:         "anyhost",
+			metadataConsumerIDKey:       "consumer",
+			metadataRequeueInFailureKey: "true",
+		},
+	}})
+	require.NoError(t, err)
+
+	failing := func(ctx context.Context, msg *pubsub.NewMessage) error {
+		return errors.New("simulated failure")
+	}
+	handle := func(redelivered bool, deadLetter bool) bool {
+		ack := &nackRecorder{}
+		_ = pubsubRabbitMQ.handleMessage(t.Context(), amqp.Delivery{Acknowledger: ack, Body: []byte("data"), Redelivered: redelivered}, "mytopic", deadLetter, failing)
+		require.Len(t, ack.requeued, 1)
+		return ack.requeued[0]
+	}
+
+	// Without a dead-letter topic, failed messages are always requeued
+	assert.True(t, handle(false, false))
+	assert.True(t, handle(true, false))
+
+	// With a dead-letter topic, failed messages are requeued once, then dead-lettered
+	assert.True(t, handle(false, true))
+	assert.False(t, handle(true, true))
+}
+
 func TestSubscribeReconnect(t *testing.T) {
 	broker := newBroker()
 	pubsubRabbitMQ := newRabbitMQTest(broker)
@@ -466,6 +675,14 @@ type rabbitMQInMemoryBroker struct {
 	connectCount    atomic.Int32
 	closeCount      atomic.Int32
 	lastMsgMetadata *amqp.Publishing // Add this field to capture the last message metadata
+
+	lastExchangeKind string
+	lastExchangeArgs amqp.Table
+	lastQueueArgs    amqp.Table
+
+	// If set, called with published messages instead of delivering them to the buffer
+	onPublish         func(exchange string, key string, msg amqp.Publishing)
+	canceledConsumers []string
 }
 
 func (r *rabbitMQInMemoryBroker) Qos(prefetchCount, prefetchSize int, global bool) error {
@@ -486,10 +703,17 @@ func (r *rabbitMQInMemoryBroker) PublishWithDeferredConfirmWithContext(ctx conte
 	// Store the last message metadata for inspection in tests
 	r.lastMsgMetadata = &msg
 
+	if r.onPublish != nil {
+		r.onPublish(exchange, key, msg)
+		return nil, nil
+	}
+
 	// Use a non-blocking send or a separate goroutine to prevent deadlock
 	// when there's no consumer reading from the buffer
+	d := createAMQPMessage(msg.Body)
+	d.Headers = msg.Headers
 	select {
-	case r.buffer <- createAMQPMessage(msg.Body):
+	case r.buffer <- d:
 		// Message sent successfully
 	default:
 		// Buffer is full or there's no consumer, but we don't want to block
@@ -499,7 +723,12 @@ func (r *rabbitMQInMemoryBroker) PublishWithDeferredConfirmWithContext(ctx conte
 }
 
 func (r *rabbitMQInMemoryBroker) QueueDeclare(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp.Table) (amqp.Queue, error) {
+	if name == "" {
+		// Server-named queue
+		name = replyQueuePrefix + "test"
+	}
 	r.declaredQueues = append(r.declaredQueues, name)
+	r.lastQueueArgs = args
 	return amqp.Queue{Name: name}, nil
 }
 
@@ -511,6 +740,11 @@ func (r *rabbitMQInMemoryBroker) Consume(queue string, consumer string, autoAck
 	return r.buffer, nil
 }
 
+func (r *rabbitMQInMemoryBroker) Cancel(consumer string, noWait bool) error {
+	r.canceledConsumers = append(r.canceledConsumers, consumer)
+	return nil
+}
+
 func (r *rabbitMQInMemoryBroker) Nack(tag uint64, multiple bool, requeue bool) error {
 	return nil
 }
@@ -520,6 +754,8 @@ func (r *rabbitMQInMemoryBroker) Ack(tag uint64, multiple bool) error {
 }
 
 func (r *rabbitMQInMemoryBroker) ExchangeDeclare(name string, kind string, durable bool, autoDelete bool, internal bool, noWait bool, args amqp.Table) error {
+	r.lastExchangeKind = kind
+	r.lastExchangeArgs = args
 	return nil
 }
 
@@ -733,3 +969,72 @@ func TestPublishMessagePropertiesToMetadataFlag(t *testing.T) {
 		assert.Empty(t, receivedMsg.Metadata, "Metadata should be empty when flag is not set (defaults to false)")
 	})
 }
+
+func TestRequest(t *testing.T) {
+	broker := newBroker()
+	broker.buffer = make(chan amqp.Delivery, 10)
+	pubsubRabbitMQ := newRabbitMQTest(broker)
+	err := pubsubRabbitMQ.Init(t.Context(), pubsub.Metadata{Base: mdata.Base{
+		Properties: map[string]string{
+			metadataHostnameKey:   "anyhost",
+			metadataConsumerIDKey: "consumer",
+		},
+	}})
+	require.NoError(t, err)
+	require.Implements(t, (*pubsub.Requester)(nil), pubsubRabbitMQ)
+
+	var exchange, key string
+	broker.onPublish = func(e string, k string, msg amqp.Publishing) {
+		exchange, key = e, k
+
+		// A reply to another request is ignored by the requester
+		broker.buffer <- amqp.Delivery{Body: []byte("other"), CorrelationId: "other"}
+		broker.buffer <- amqp.Delivery{Body: append([]byte("re: "), msg.Body...), CorrelationId: msg.CorrelationId}
+	}
+
+	res, err := pubsub.Request(t.Context(), pubsubRabbitMQ, &pubsub.RequestReplyRequest{
+		PublishRequest: pubsub.PublishRequest{Topic: "commands", Data: []byte("ping")},
+		CorrelationID:  "c-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "re: ping", string(res.Data))
+	assert.Equal(t, "c-1", res.Metadata[mdata.CorrelationIDMetadataKey])
+	assert.Equal(t, "commands", exchange)
+	assert.Empty(t, key)
+	assert.Equal(t, "c-1", broker.lastMsgMetadata.CorrelationId)
+	assert.Equal(t, "amq.gen-test", broker.lastMsgMetadata.ReplyTo)
+	assert.Equal(t, []string{"dapr-reply-c-1"}, broker.canceledConsumers)
+
+	t.Run("reply is published with the default exchange", func(t *testing.T) {
+		reply, err := pubsub.NewReplyRequest(&pubsub.NewMessage{
+			Metadata: map[string]string{
+				mdata.ReplyToMetadataKey:       "amq.gen-test",
+				mdata.CorrelationIDMetadataKey: "c-1",
+			},
+		}, []byte("pong"))
+		require.NoError(t, err)
+
+		err = pubsubRabbitMQ.Publish(t.Context(), reply)
+		require.NoError(t, err)
+		assert.Empty(t, exchange)
+		assert.Equal(t, "amq.gen-test", key)
+		assert.Equal(t, "c-1", broker.lastMsgMetadata.CorrelationId)
+	})
+
+	t.Run("requests are received with the reply-to metadata", func(t *testing.T) {
+		// The test delivery can't be acknowledged
+		pubsubRabbitMQ.metadata.AutoAck = true
+
+		var msg *pubsub.NewMessage
+		err := pubsubRabbitMQ.handleMessage(t.Context(), amqp.Delivery{Body: []byte("ping"), ReplyTo: "amq.gen-test", CorrelationId: "c-1"}, "commands", false, func(ctx context.Context, m *pubsub.NewMessage) error {
+			msg = m
+			return nil
+		})
+		require.NoError(t, err)
+		require.NotNil(t, msg)
+		assert.Equal(t, map[string]string{
+			mdata.ReplyToMetadataKey:       "amq.gen-test",
+			mdata.CorrelationIDMetadataKey: "c-1",
+		}, msg.Metadata)
+	})
+}
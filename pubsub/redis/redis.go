@@ -15,15 +15,21 @@ package redis
 
 import (
 	"context"
+	"crypto/sha1" //nolint:gosec
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
+
 	rediscomponent "github.com/dapr/components-contrib/common/component/redis"
 	contribMetadata "github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/components-contrib/pubsub"
@@ -39,8 +45,56 @@ const (
 	concurrency       = "concurrency"
 	maxLenApprox      = "maxLenApprox"
 	streamTTL         = "streamTTL"
+
+	// Delayed messages are stored in a sorted set per topic, with the delivery time as score, until they are added to their stream.
+	// Keys are namespaced with the consumer ID, so each app only moves the messages it published.
+	delayedMessagesKeyPrefix    = "dapr:pubsub:delayed:"
+	delayedMessagesPollInterval = time.Second
+	delayedMessagesBatchSize    = 100
 )
 
+// moveDelayedMessageScript adds a delayed message to its stream, if it's still in its sorted set, and then removes it from the sorted set.
+// Doing both in a script makes sure the message is neither lost nor added twice when multiple instances move messages.
+// Scripts aren't rolled back on errors, so the message is removed only after it's added.
+// KEYS: sorted set, stream. ARGV: member, data, metadata (empty if none), maxLenApprox, minID (empty if none).
+const moveDelayedMessageScript = `if not redis.call("ZSCORE", KEYS[1], ARGV[1]) then
+	return 0
+end
+local args = {"XADD", KEYS[2]}
+if ARGV[4] ~= "0" then
+	args[#args+1] = "MAXLEN"
+	args[#args+1] = "~"
+	args[#args+1] = ARGV[4]
+elseif ARGV[5] ~= "" then
+	args[#args+1] = "MINID"
+	args[#args+1] = "~"
+	args[#args+1] = ARGV[5]
+end
+args[#args+1] = "*"
+args[#args+1] = "data"
+args[#args+1] = ARGV[2]
+if ARGV[3] ~= "" then
+	args[#args+1] = "metadata"
+	args[#args+1] = ARGV[3]
+end
+redis.call(unpack(args))
+redis.call("ZREM", KEYS[1], ARGV[1])
+return 1`
+
+var moveDelayedMessageScriptSHA = func() string {
+	h := sha1.Sum([]byte(moveDelayedMessageScript)) //nolint:gosec
+	return hex.EncodeToString(h[:])
+}()
+
+// delayedMessage is a message stored in the sorted set of delayed messages.
+type delayedMessage struct {
+	// ID makes members unique, so identical messages are all delivered.
+	ID       string            `json:"id"`
+	Topic    string            `json:"topic"`
+	Data     []byte            `json:"data"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
 // redisStreams handles consuming from a Redis stream using
 // `XREADGROUP` for reading new messages and `XPENDING` and
 // `XCLAIM` for redelivering messages that previously failed.
@@ -95,6 +149,12 @@ func (r *redisStreams) Init(ctx context.Context, metadata pubsub.Metadata) error
 		}()
 	}
 
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.moveDelayedMessagesLoop()
+	}()
+
 	return nil
 }
 
@@ -103,17 +163,54 @@ func (r *redisStreams) Publish(ctx context.Context, req *pubsub.PublishRequest)
 		return errors.New("component is closed")
 	}
 
-	redisPayload := map[string]interface{}{"data": req.Data}
+	deliverAt, ok, err := contribMetadata.TryGetDeliveryTime(req.Metadata, time.Now())
+	if err != nil {
+		return err
+	}
+	if ok && time.Until(deliverAt) > 0 {
+		return r.publishDelayed(ctx, req, deliverAt)
+	}
+
+	return r.addToStream(ctx, req.Topic, req.Data, req.Metadata)
+}
+
+func (r *redisStreams) addToStream(ctx context.Context, stream string, data []byte, metadata map[string]string) error {
+	redisPayload := map[string]interface{}{"data": data}
 
-	if req.Metadata != nil {
-		serializedMetadata, err := json.Marshal(req.Metadata)
+	if metadata != nil {
+		serializedMetadata, err := json.Marshal(metadata)
 		if err != nil {
 			return err
 		}
 		redisPayload["metadata"] = serializedMetadata
 	}
 
-	_, err := r.client.XAdd(ctx, req.Topic, r.clientSettings.MaxLenApprox, r.clientSettings.GetMinID(time.Now()), redisPayload)
+	_, err := r.client.XAdd(ctx, stream, r.clientSettings.MaxLenApprox, r.clientSettings.GetMinID(time.Now()), redisPayload)
+	if err != nil {
+		return fmt.Errorf("redis streams: error from publish: %s", err)
+	}
+
+	return nil
+}
+
+// publishDelayed adds the message to the sorted set of delayed messages of its topic, from which it is moved to its stream when it is due.
+func (r *redisStreams) publishDelayed(ctx context.Context, req *pubsub.PublishRequest, deliverAt time.Time) error {
+	member, err := json.Marshal(delayedMessage{
+		ID:       uuid.NewString(),
+		Topic:    req.Topic,
+		Data:     req.Data,
+		Metadata: req.Metadata,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The topic is listed before the message is added, so the message is always found
+	err = r.client.DoWrite(ctx, "SADD", r.delayedTopicsKey(), req.Topic)
+	if err != nil {
+		return fmt.Errorf("redis streams: error from publish: %s", err)
+	}
+	err = r.client.DoWrite(ctx, "ZADD", r.delayedMessagesKey(req.Topic), deliverAt.UnixMilli(), string(member))
 	if err != nil {
 		return fmt.Errorf("redis streams: error from publish: %s", err)
 	}
@@ -121,6 +218,133 @@ func (r *redisStreams) Publish(ctx context.Context, req *pubsub.PublishRequest)
 	return nil
 }
 
+// delayedTopicsKey returns the key of the set of the topics with messages delayed by this app.
+func (r *redisStreams) delayedTopicsKey() string {
+	return delayedMessagesKeyPrefix + r.clientSettings.ConsumerID
+}
+
+// delayedMessagesKey returns the key of the sorted set of the messages delayed for a topic.
+// Redis Cluster hashes only the part of a key between braces, if any: the key has the same hash tag as the stream of the topic, so the script that moves messages can access both.
+func (r *redisStreams) delayedMessagesKey(topic string) string {
+	if hasHashTag(topic) {
+		return r.delayedTopicsKey() + ":" + topic
+	}
+	return r.delayedTopicsKey() + ":{" + topic + "}"
+}
+
+// hasHashTag returns true if Redis Cluster hashes only a part of the key.
+func hasHashTag(key string) bool {
+	start := strings.IndexByte(key, '{')
+	return start >= 0 && strings.IndexByte(key[start+1:], '}') > 0
+}
+
+// moveDelayedMessagesLoop periodically moves the delayed messages that are due to their streams, until the component is closed.
+func (r *redisStreams) moveDelayedMessagesLoop() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-r.closeCh
+		cancel()
+	}()
+
+	t := time.NewTicker(delayedMessagesPollInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			r.moveDelayedMessages(ctx)
+		}
+	}
+}
+
+// moveDelayedMessages moves the delayed messages that are due to their streams.
+// Multiple instances can move messages at the same time: each message is moved only by the instance that removes it from the sorted set.
+func (r *redisStreams) moveDelayedMessages(ctx context.Context) {
+	res, err := r.client.DoRead(ctx, "SMEMBERS", r.delayedTopicsKey())
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Errorf("redis streams: error reading topics with delayed messages: %v", err)
+		}
+		return
+	}
+	topics, _ := res.([]interface{})
+	for _, t := range topics {
+		topic, ok := t.(string)
+		if !ok {
+			continue
+		}
+		r.moveDelayedTopicMessages(ctx, topic)
+	}
+}
+
+// moveDelayedTopicMessages moves the delayed messages of a topic that are due to its stream.
+func (r *redisStreams) moveDelayedTopicMessages(ctx context.Context, topic string) {
+	key := r.delayedMessagesKey(topic)
+	for {
+		res, err := r.client.DoRead(ctx, "ZRANGEBYSCORE", key, "-inf", time.Now().UnixMilli(), "LIMIT", 0, delayedMessagesBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				r.logger.Errorf("redis streams: error reading delayed messages for %s: %v", topic, err)
+			}
+			return
+		}
+		members, _ := res.([]interface{})
+		if len(members) == 0 {
+			return
+		}
+
+		for _, m := range members {
+			member, ok := m.(string)
+			if !ok {
+				continue
+			}
+			err = r.moveDelayedMessage(ctx, key, topic, member)
+			if err != nil {
+				// The message is still in the sorted set, so it's moved again the next time
+				if ctx.Err() == nil {
+					r.logger.Errorf("redis streams: error publishing delayed message to %s: %v", topic, err)
+				}
+				return
+			}
+		}
+
+		if len(members) < delayedMessagesBatchSize {
+			return
+		}
+	}
+}
+
+// moveDelayedMessage atomically removes a delayed message from its sorted set and adds it to its stream.
+func (r *redisStreams) moveDelayedMessage(ctx context.Context, key string, topic string, member string) error {
+	var msg delayedMessage
+	err := json.Unmarshal([]byte(member), &msg)
+	if err != nil {
+		r.logger.Errorf("redis streams: discarding invalid delayed message for %s: %v", topic, err)
+		return r.client.DoWrite(ctx, "ZREM", key, member)
+	}
+
+	var metadata []byte
+	if msg.Metadata != nil {
+		metadata, err = json.Marshal(msg.Metadata)
+		if err != nil {
+			return err
+		}
+	}
+
+	args := []interface{}{
+		2, key, topic,
+		member, msg.Data, metadata, r.clientSettings.MaxLenApprox, r.clientSettings.GetMinID(time.Now()),
+	}
+	err = r.client.DoWrite(ctx, append([]interface{}{"EVALSHA", moveDelayedMessageScriptSHA}, args...)...)
+	if err != nil && strings.HasPrefix(err.Error(), "NOSCRIPT") {
+		// The script isn't cached by the server yet
+		err = r.client.DoWrite(ctx, append([]interface{}{"EVAL", moveDelayedMessageScript}, args...)...)
+	}
+	return err
+}
+
 func (r *redisStreams) CreateConsumerGroup(ctx context.Context, stream string) error {
 	err := r.client.XGroupCreateMkStream(ctx, stream, r.clientSettings.ConsumerID, "0")
 	// Ignore BUSYGROUP errors
@@ -131,12 +355,79 @@ func (r *redisStreams) CreateConsumerGroup(ctx context.Context, stream string) e
 	return nil
 }
 
+// createConsumerGroupAt creates the consumer group of a stream at a start position.
+// The start position is applied only when the group is created: if it already exists, for example when the app restarts or scales out, it resumes from the last message delivered to the group.
+func (r *redisStreams) createConsumerGroupAt(ctx context.Context, stream string, pos contribMetadata.StartPosition) error {
+	start, err := getStreamStartID(pos)
+	if err != nil {
+		return err
+	}
+
+	err = r.client.XGroupCreateMkStream(ctx, stream, r.clientSettings.ConsumerID, start)
+	if err != nil && err.Error() == "BUSYGROUP Consumer Group name already exists" {
+		r.logger.Infof("redis streams: consumer group %s of stream %s already exists, so it resumes from its last delivered message instead of starting from %s", r.clientSettings.ConsumerID, stream, pos.From)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("redis streams: failed to create consumer group %s of stream %s at its start position: %w", r.clientSettings.ConsumerID, stream, err)
+	}
+	return nil
+}
+
+// getStreamStartID returns the last delivered ID to set on a consumer group, so it delivers messages from the start position.
+// Offsets are stream IDs, and the message with that ID is delivered too.
+func getStreamStartID(pos contribMetadata.StartPosition) (string, error) {
+	switch pos.From {
+	case contribMetadata.StartFromEarliest:
+		return "0", nil
+	case contribMetadata.StartFromLatest:
+		return "$", nil
+	case contribMetadata.StartFromTimestamp:
+		ms := max(pos.Timestamp.UnixMilli(), 0)
+		return previousStreamID(uint64(ms), 0), nil
+	case contribMetadata.StartFromOffset:
+		msStr, seqStr, hasSeq := strings.Cut(pos.Offset, "-")
+		ms, err := strconv.ParseUint(msStr, 10, 64)
+		var seq uint64
+		if err == nil && hasSeq {
+			seq, err = strconv.ParseUint(seqStr, 10, 64)
+		}
+		if err != nil {
+			return "", fmt.Errorf("%s value must be a stream ID: actual is '%s'", contribMetadata.StartOffsetMetadataKey, pos.Offset)
+		}
+		return previousStreamID(ms, seq), nil
+	default:
+		return "", fmt.Errorf("unsupported start position '%s'", pos.From)
+	}
+}
+
+// previousStreamID returns the greatest stream ID that is lower than the ID made of ms and seq.
+func previousStreamID(ms uint64, seq uint64) string {
+	switch {
+	case seq > 0:
+		return strconv.FormatUint(ms, 10) + "-" + strconv.FormatUint(seq-1, 10)
+	case ms > 0:
+		return strconv.FormatUint(ms-1, 10) + "-" + strconv.FormatUint(math.MaxUint64, 10)
+	default:
+		return "0"
+	}
+}
+
 func (r *redisStreams) Subscribe(ctx context.Context, req pubsub.SubscribeRequest, handler pubsub.Handler) error {
 	if r.closed.Load() {
 		return errors.New("component is closed")
 	}
 
-	if err := r.CreateConsumerGroup(ctx, req.Topic); err != nil {
+	startPosition, replay, err := contribMetadata.TryGetStartPosition(req.Metadata)
+	if err != nil {
+		return err
+	}
+	if replay {
+		err = r.createConsumerGroupAt(ctx, req.Topic, startPosition)
+	} else {
+		err = r.CreateConsumerGroup(ctx, req.Topic)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -434,7 +725,7 @@ func (r *redisStreams) Close() error {
 }
 
 func (r *redisStreams) Features() []pubsub.Feature {
-	return nil
+	return []pubsub.Feature{pubsub.FeatureDelayedDelivery, pubsub.FeatureReplay}
 }
 
 func (r *redisStreams) Ping(ctx context.Context) error {
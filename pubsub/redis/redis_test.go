@@ -21,6 +21,8 @@ import (
 	"testing"
 	"time"
 
+	miniredis "github.com/alicebob/miniredis/v2"
+	redis "github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -301,6 +303,183 @@ func TestPollNewMessagesLoopWhenXReadGroupResultReturnsRedisNil(t *testing.T) {
 	}
 }
 
+func TestPublishDelayed(t *testing.T) {
+	s := miniredis.RunT(t)
+	r := &redisStreams{
+		logger:         logger.NewLogger("test"),
+		client:         commonredis.ClientFromV8Client(redis.NewClient(&redis.Options{Addr: s.Addr()})),
+		clientSettings: &commonredis.Settings{ConsumerID: "app"},
+	}
+
+	t.Run("message is stored until it is due", func(t *testing.T) {
+		err := r.Publish(t.Context(), &pubsub.PublishRequest{
+			Topic:    "delayed",
+			Data:     []byte("hello"),
+			Metadata: map[string]string{mdata.DeliverAfterMetadataKey: "1h"},
+		})
+		require.NoError(t, err)
+
+		topics, err := s.Members("dapr:pubsub:delayed:app")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"delayed"}, topics)
+		members, err := s.ZMembers("dapr:pubsub:delayed:app:{delayed}")
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		assert.False(t, s.Exists("delayed"))
+
+		// Messages that are not due yet are not moved
+		r.moveDelayedMessages(t.Context())
+		assert.False(t, s.Exists("delayed"))
+
+		// Make the message due
+		_, err = s.ZAdd("dapr:pubsub:delayed:app:{delayed}", 0, members[0])
+		require.NoError(t, err)
+		r.moveDelayedMessages(t.Context())
+
+		assert.False(t, s.Exists("dapr:pubsub:delayed:app:{delayed}"))
+		stream, err := s.Stream("delayed")
+		require.NoError(t, err)
+		require.Len(t, stream, 1)
+		// Fields are added to the stream entry in any order
+		values := map[string]string{}
+		for i := 0; i+1 < len(stream[0].Values); i += 2 {
+			values[stream[0].Values[i]] = stream[0].Values[i+1]
+		}
+		assert.Equal(t, map[string]string{"data": "hello", "metadata": `{"deliverAfter":"1h"}`}, values)
+
+		// The message is moved only once
+		_, err = s.ZAdd("dapr:pubsub:delayed:app:{delayed}", 0, members[0])
+		require.NoError(t, err)
+		s.Del("dapr:pubsub:delayed:app:{delayed}")
+		r.moveDelayedMessages(t.Context())
+		stream, err = s.Stream("delayed")
+		require.NoError(t, err)
+		assert.Len(t, stream, 1)
+	})
+
+	t.Run("message is kept if it can't be added to the stream", func(t *testing.T) {
+		// The key of the stream has another type, so XADD fails
+		require.NoError(t, s.Set("wrongtype", "value"))
+		err := r.Publish(t.Context(), &pubsub.PublishRequest{
+			Topic:    "wrongtype",
+			Data:     []byte("hello"),
+			Metadata: map[string]string{mdata.DeliverAfterMetadataKey: "1h"},
+		})
+		require.NoError(t, err)
+		members, err := s.ZMembers("dapr:pubsub:delayed:app:{wrongtype}")
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		_, err = s.ZAdd("dapr:pubsub:delayed:app:{wrongtype}", 0, members[0])
+		require.NoError(t, err)
+
+		r.moveDelayedMessages(t.Context())
+		members, err = s.ZMembers("dapr:pubsub:delayed:app:{wrongtype}")
+		require.NoError(t, err)
+		assert.Len(t, members, 1)
+	})
+
+	t.Run("messages of other apps are not moved", func(t *testing.T) {
+		_, err := s.ZAdd("dapr:pubsub:delayed:other:{delayed}", 0, `{"id":"1","topic":"delayed","data":"aGk="}`)
+		require.NoError(t, err)
+		_, err = s.SAdd("dapr:pubsub:delayed:other", "delayed")
+		require.NoError(t, err)
+
+		r.moveDelayedMessages(t.Context())
+		assert.True(t, s.Exists("dapr:pubsub:delayed:other:{delayed}"))
+	})
+
+	t.Run("message without delay is added to the stream", func(t *testing.T) {
+		err := r.Publish(t.Context(), &pubsub.PublishRequest{
+			Topic: "immediate",
+			Data:  []byte("hello"),
+		})
+		require.NoError(t, err)
+
+		stream, err := s.Stream("immediate")
+		require.NoError(t, err)
+		assert.Len(t, stream, 1)
+	})
+
+	t.Run("invalid delay", func(t *testing.T) {
+		err := r.Publish(t.Context(), &pubsub.PublishRequest{
+			Topic:    "delayed",
+			Data:     []byte("hello"),
+			Metadata: map[string]string{mdata.DeliverAfterMetadataKey: "soon"},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestDelayedMessagesKey(t *testing.T) {
+	r := &redisStreams{clientSettings: &commonredis.Settings{ConsumerID: "app"}}
+	assert.Equal(t, "dapr:pubsub:delayed:app:{orders}", r.delayedMessagesKey("orders"))
+	// Topics with a hash tag are hashed on their tag
+	assert.Equal(t, "dapr:pubsub:delayed:app:orders{eu}", r.delayedMessagesKey("orders{eu}"))
+	assert.Equal(t, "dapr:pubsub:delayed:app:{orders{}", r.delayedMessagesKey("orders{"))
+}
+
+func TestGetStreamStartID(t *testing.T) {
+	tests := []struct {
+		name     string
+		pos      mdata.StartPosition
+		expected string
+	}{
+		{name: "earliest", pos: mdata.StartPosition{From: mdata.StartFromEarliest}, expected: "0"},
+		{name: "latest", pos: mdata.StartPosition{From: mdata.StartFromLatest}, expected: "$"},
+		{name: "timestamp", pos: mdata.StartPosition{From: mdata.StartFromTimestamp, Timestamp: time.UnixMilli(1000)}, expected: "999-18446744073709551615"},
+		{name: "offset", pos: mdata.StartPosition{From: mdata.StartFromOffset, Offset: "1000-5"}, expected: "1000-4"},
+		{name: "offset without sequence", pos: mdata.StartPosition{From: mdata.StartFromOffset, Offset: "1000"}, expected: "999-18446744073709551615"},
+		{name: "first offset", pos: mdata.StartPosition{From: mdata.StartFromOffset, Offset: "0-0"}, expected: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := getStreamStartID(tt.pos)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, id)
+		})
+	}
+
+	t.Run("invalid offset", func(t *testing.T) {
+		_, err := getStreamStartID(mdata.StartPosition{From: mdata.StartFromOffset, Offset: "latest"})
+		require.Error(t, err)
+	})
+}
+
+func TestCreateConsumerGroupAt(t *testing.T) {
+	s := miniredis.RunT(t)
+	r := &redisStreams{
+		logger:         logger.NewLogger("test"),
+		client:         commonredis.ClientFromV8Client(redis.NewClient(&redis.Options{Addr: s.Addr()})),
+		clientSettings: &commonredis.Settings{ConsumerID: "replay"},
+	}
+	for _, id := range []string{"1000-0", "2000-0", "3000-0"} {
+		_, err := s.XAdd("replay", id, []string{"data", id})
+		require.NoError(t, err)
+	}
+
+	err := r.createConsumerGroupAt(t.Context(), "replay", mdata.StartPosition{From: mdata.StartFromTimestamp, Timestamp: time.UnixMilli(2000)})
+	require.NoError(t, err)
+
+	streams, err := r.client.XReadGroupResult(t.Context(), "replay", "replay", []string{"replay", ">"}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, streams, 1)
+	require.Len(t, streams[0].Messages, 2)
+	assert.Equal(t, "2000-0", streams[0].Messages[0].ID)
+	assert.Equal(t, "3000-0", streams[0].Messages[1].ID)
+
+	// An existing group isn't moved back to the start position
+	err = r.createConsumerGroupAt(t.Context(), "replay", mdata.StartPosition{From: mdata.StartFromEarliest})
+	require.NoError(t, err)
+	_, err = s.XAdd("replay", "4000-0", []string{"data", "4000-0"})
+	require.NoError(t, err)
+
+	streams, err = r.client.XReadGroupResult(t.Context(), "replay", "replay", []string{"replay", ">"}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, streams, 1)
+	require.Len(t, streams[0].Messages, 1)
+	assert.Equal(t, "4000-0", streams[0].Messages[0].ID)
+}
+
 func generateRedisStreamTestData(messageCount int, data string, metadata string) []commonredis.RedisXMessage {
 	generateXMessage := func(id int) commonredis.RedisXMessage {
 		values := map[string]interface{}{
@@ -377,6 +556,10 @@ func (s *stubRedisClient) PingResult(context.Context) (string, error) {
 func (s *stubRedisClient) ConfigurationSubscribe(context.Context, *commonredis.ConfigurationSubscribeArgs) {
 }
 
+func (s *stubRedisClient) PSubscribe(context.Context, ...string) (<-chan commonredis.RedisPubSubMessage, error) {
+	return nil, nil
+}
+
 func (s *stubRedisClient) SetNX(context.Context, string, interface{}, time.Duration) (*bool, error) {
 	return nil, nil
 }
@@ -0,0 +1,232 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dapr/components-contrib/metadata"
+)
+
+// DefaultRequestTimeout is how long Request waits for a reply when the request doesn't set a timeout.
+const DefaultRequestTimeout = 30 * time.Second
+
+// ErrRequestTimeout is returned by Request when no reply is received before the request times out.
+var ErrRequestTimeout = errors.New("timed out waiting for the reply")
+
+// Requester is the interface for pubsub components that natively support the request/reply pattern.
+// Use the Request function to send requests with any pubsub component.
+type Requester interface {
+	// Request publishes a message and waits for the reply with the same correlation ID.
+	// The CorrelationID of the request is always set, and ctx is cancelled when the request times out.
+	Request(ctx context.Context, req *RequestReplyRequest) (*NewMessage, error)
+}
+
+// Request publishes a message and waits for the reply to it.
+// Components that implement Requester handle the request natively.
+// With other components, the reply is received with a subscription to the reply topic, which is shared by the requests waiting on it and removed when none is:
+// requests are published with the "replyTo" and "correlationID" metadata keys, and responders publish the reply created with NewReplyRequest.
+// Replies are matched to requests by correlation ID, so replies without one are discarded.
+//
+// The default reply topic is unique to the process, which is only suitable for components whose topics and subscriptions don't outlive their subscribers, such as in-memory or Redis.
+// With brokers that persist topics and subscriptions, such as Kafka, Azure Service Bus or GCP Pub/Sub, set ReplyTopic to a topic that exists and is reused for the lifetime of the app:
+// otherwise each process leaves a topic and a subscription behind, and replies published before the subscription is ready (for example, before partitions are assigned) are missed.
+func Request(ctx context.Context, ps PubSub, req *RequestReplyRequest) (*NewMessage, error) {
+	if req.Topic == "" {
+		return nil, errors.New("topic is required")
+	}
+
+	req = req.withDefaults()
+	ctx, cancel := context.WithTimeout(ctx, req.Timeout)
+	defer cancel()
+
+	var (
+		res *NewMessage
+		err error
+	)
+	if requester, ok := ps.(Requester); ok {
+		res, err = requester.Request(ctx, req)
+	} else {
+		res, err = subscribeAndRequest(ctx, ps, req)
+	}
+
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w to request %s: %w", ErrRequestTimeout, req.CorrelationID, err)
+	}
+	return res, err
+}
+
+// NewReplyRequest returns the request to publish the reply to a message received by a responder.
+// The reply is published to the topic set with the "replyTo" metadata key of the message, with the same correlation ID.
+func NewReplyRequest(msg *NewMessage, data []byte) (*PublishRequest, error) {
+	replyTo := msg.Metadata[metadata.ReplyToMetadataKey]
+	if replyTo == "" {
+		return nil, fmt.Errorf("message doesn't have the %s metadata key", metadata.ReplyToMetadataKey)
+	}
+
+	md := map[string]string{}
+	if correlationID := msg.Metadata[metadata.CorrelationIDMetadataKey]; correlationID != "" {
+		md[metadata.CorrelationIDMetadataKey] = correlationID
+	}
+	return &PublishRequest{
+		Data:     data,
+		Topic:    replyTo,
+		Metadata: md,
+	}, nil
+}
+
+// replyTopicSuffix is appended to the topic of requests to get their default reply topic, so each process uses one reply topic per request topic.
+var replyTopicSuffix = "-reply-" + uuid.New().String()
+
+// replyRouters contains the subscriptions to reply topics that requests are waiting for replies on.
+var replyRouters = struct {
+	lock    sync.Mutex
+	routers map[replyRouterKey]*replyRouter
+}{
+	routers: map[replyRouterKey]*replyRouter{},
+}
+
+type replyRouterKey struct {
+	ps    PubSub
+	topic string
+}
+
+// replyRouter delivers the replies received on a reply topic to the requests waiting for them, by correlation ID.
+// It's shared by all requests that wait for replies on the same topic, and it's unsubscribed when no request is waiting.
+type replyRouter struct {
+	pending map[string]chan *NewMessage
+	ready   chan struct{}
+	err     error
+	cancel  context.CancelFunc
+}
+
+// subscribeAndRequest subscribes to the reply topic, publishes the request, and waits for the reply with the correlation ID of the request.
+func subscribeAndRequest(ctx context.Context, ps PubSub, req *RequestReplyRequest) (*NewMessage, error) {
+	replyTopic := req.ReplyTopic
+	if replyTopic == "" {
+		replyTopic = req.Topic + replyTopicSuffix
+	}
+
+	replies, release, err := waitForReply(ctx, ps, replyTopic, req.CorrelationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to reply topic %s: %w", replyTopic, err)
+	}
+	defer release()
+
+	pubReq := req.PublishRequest
+	pubReq.Metadata = maps.Clone(req.Metadata)
+	if pubReq.Metadata == nil {
+		pubReq.Metadata = make(map[string]string, 2)
+	}
+	pubReq.Metadata[metadata.CorrelationIDMetadataKey] = req.CorrelationID
+	pubReq.Metadata[metadata.ReplyToMetadataKey] = replyTopic
+	err = ps.Publish(ctx, &pubReq)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case msg := <-replies:
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// waitForReply registers the correlation ID with the router of the reply topic, subscribing to the topic if no other request is waiting on it.
+// The returned function must be called when the request is done: it unsubscribes from the topic after the last request waiting on it.
+func waitForReply(ctx context.Context, ps PubSub, topic string, correlationID string) (<-chan *NewMessage, func(), error) {
+	key := replyRouterKey{ps: ps, topic: topic}
+	replies := make(chan *NewMessage, 1)
+
+	replyRouters.lock.Lock()
+	router, ok := replyRouters.routers[key]
+	if !ok {
+		router = &replyRouter{
+			pending: map[string]chan *NewMessage{},
+			ready:   make(chan struct{}),
+		}
+		replyRouters.routers[key] = router
+	}
+	if _, dup := router.pending[correlationID]; dup {
+		replyRouters.lock.Unlock()
+		return nil, nil, fmt.Errorf("a request with correlation ID %s is already waiting for a reply", correlationID)
+	}
+	router.pending[correlationID] = replies
+	replyRouters.lock.Unlock()
+
+	release := func() {
+		replyRouters.lock.Lock()
+		defer replyRouters.lock.Unlock()
+		delete(router.pending, correlationID)
+		if len(router.pending) == 0 && replyRouters.routers[key] == router {
+			delete(replyRouters.routers, key)
+			if router.cancel != nil {
+				router.cancel()
+			}
+		}
+	}
+
+	if !ok {
+		// The subscription outlives the request that creates it, as it's shared with the other requests
+		subCtx, cancel := context.WithCancel(context.Background())
+		router.err = ps.Subscribe(subCtx, SubscribeRequest{Topic: topic}, func(_ context.Context, msg *NewMessage) error {
+			replyRouters.lock.Lock()
+			ch, ok := router.pending[msg.Metadata[metadata.CorrelationIDMetadataKey]]
+			replyRouters.lock.Unlock()
+			if ok {
+				select {
+				case ch <- msg:
+				default:
+				}
+			}
+			return nil
+		})
+
+		replyRouters.lock.Lock()
+		if router.err != nil {
+			cancel()
+			if replyRouters.routers[key] == router {
+				delete(replyRouters.routers, key)
+			}
+		} else {
+			router.cancel = cancel
+			if replyRouters.routers[key] != router {
+				// All requests were released while subscribing
+				cancel()
+			}
+		}
+		replyRouters.lock.Unlock()
+		close(router.ready)
+	}
+
+	select {
+	case <-router.ready:
+	case <-ctx.Done():
+		release()
+		return nil, nil, ctx.Err()
+	}
+	if router.err != nil {
+		release()
+		return nil, nil, router.err
+	}
+	return replies, release, nil
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/metadata"
+)
+
+// requestTestPubSub delivers published messages to the subscribers of their topic, with the publish metadata.
+type requestTestPubSub struct {
+	lock     sync.Mutex
+	handlers map[string][]Handler
+	subMD    map[string]map[string]string
+	features []Feature
+}
+
+func newRequestTestPubSub() *requestTestPubSub {
+	return &requestTestPubSub{
+		handlers: map[string][]Handler{},
+		subMD:    map[string]map[string]string{},
+	}
+}
+
+func (p *requestTestPubSub) GetComponentMetadata() metadata.MetadataMap { return nil }
+
+func (p *requestTestPubSub) Init(context.Context, Metadata) error { return nil }
+
+func (p *requestTestPubSub) Features() []Feature { return p.features }
+
+func (p *requestTestPubSub) Close() error { return nil }
+
+func (p *requestTestPubSub) Publish(ctx context.Context, req *PublishRequest) error {
+	p.lock.Lock()
+	handlers := p.handlers[req.Topic]
+	p.lock.Unlock()
+
+	for _, h := range handlers {
+		go h(ctx, &NewMessage{Data: req.Data, Topic: req.Topic, Metadata: maps.Clone(req.Metadata)})
+	}
+	return nil
+}
+
+func (p *requestTestPubSub) Subscribe(ctx context.Context, req SubscribeRequest, handler Handler) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.handlers[req.Topic] = append(p.handlers[req.Topic], handler)
+	p.subMD[req.Topic] = req.Metadata
+	go func() {
+		<-ctx.Done()
+		p.lock.Lock()
+		delete(p.handlers, req.Topic)
+		p.lock.Unlock()
+	}()
+	return nil
+}
+
+// respond subscribes to topic and replies to each request with the result of fn.
+func (p *requestTestPubSub) respond(t *testing.T, topic string, fn func(msg *NewMessage) *PublishRequest) {
+	t.Helper()
+
+	err := p.Subscribe(t.Context(), SubscribeRequest{Topic: topic}, func(ctx context.Context, msg *NewMessage) error {
+		reply := fn(msg)
+		if reply == nil {
+			return nil
+		}
+		return p.Publish(ctx, reply)
+	})
+	require.NoError(t, err)
+}
+
+type requestTestRequester struct {
+	*requestTestPubSub
+
+	req *RequestReplyRequest
+}
+
+func (r *requestTestRequester) Request(ctx context.Context, req *RequestReplyRequest) (*NewMessage, error) {
+	r.req = req
+	if req.Topic == "slow" {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &NewMessage{Data: []byte("native"), Topic: "inbox"}, nil
+}
+
+func TestRequest(t *testing.T) {
+	echo := func(msg *NewMessage) *PublishRequest {
+		reply, err := NewReplyRequest(msg, append([]byte("re: "), msg.Data...))
+		if err != nil {
+			return nil
+		}
+		return reply
+	}
+
+	t.Run("reply on the default topic", func(t *testing.T) {
+		ps := newRequestTestPubSub()
+		ps.respond(t, "commands", echo)
+
+		res, err := Request(t.Context(), ps, &RequestReplyRequest{
+			PublishRequest: PublishRequest{Topic: "commands", Data: []byte("ping")},
+			CorrelationID:  "c-1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "re: ping", string(res.Data))
+		assert.Equal(t, "commands"+replyTopicSuffix, res.Topic)
+		assert.Equal(t, "c-1", res.Metadata[metadata.CorrelationIDMetadataKey])
+		assert.Empty(t, ps.subMD["commands"+replyTopicSuffix])
+	})
+
+	t.Run("concurrent requests share the reply subscription", func(t *testing.T) {
+		ps := newRequestTestPubSub()
+		replyTopic := "commands" + replyTopicSuffix
+		release := make(chan struct{})
+		ps.respond(t, "commands", func(msg *NewMessage) *PublishRequest {
+			<-release
+			return echo(msg)
+		})
+
+		const n = 5
+		var wg sync.WaitGroup
+		wg.Add(n)
+		for i := range n {
+			go func() {
+				defer wg.Done()
+				data := "ping " + strconv.Itoa(i)
+				res, err := Request(t.Context(), ps, &RequestReplyRequest{
+					PublishRequest: PublishRequest{Topic: "commands", Data: []byte(data)},
+				})
+				assert.NoError(t, err)
+				if assert.NotNil(t, res) {
+					assert.Equal(t, "re: "+data, string(res.Data))
+				}
+			}()
+		}
+
+		require.Eventually(t, func() bool {
+			replyRouters.lock.Lock()
+			defer replyRouters.lock.Unlock()
+			router := replyRouters.routers[replyRouterKey{ps: ps, topic: replyTopic}]
+			return router != nil && len(router.pending) == n
+		}, time.Second, 10*time.Millisecond)
+		ps.lock.Lock()
+		assert.Len(t, ps.handlers[replyTopic], 1)
+		ps.lock.Unlock()
+
+		close(release)
+		wg.Wait()
+
+		// The subscription is removed after the last request
+		require.Eventually(t, func() bool {
+			ps.lock.Lock()
+			defer ps.lock.Unlock()
+			return len(ps.handlers[replyTopic]) == 0
+		}, time.Second, 10*time.Millisecond)
+		replyRouters.lock.Lock()
+		assert.Empty(t, replyRouters.routers)
+		replyRouters.lock.Unlock()
+	})
+
+	t.Run("reply on a shared topic is filtered by correlation ID", func(t *testing.T) {
+		ps := newRequestTestPubSub()
+		ps.respond(t, "commands", func(msg *NewMessage) *PublishRequest {
+			// A reply to another request, and one without a correlation ID, are published first
+			ps.Publish(t.Context(), &PublishRequest{Topic: "replies", Data: []byte("other"), Metadata: map[string]string{metadata.CorrelationIDMetadataKey: "c-0"}})
+			ps.Publish(t.Context(), &PublishRequest{Topic: "replies", Data: []byte("none")})
+			time.Sleep(50 * time.Millisecond)
+			return echo(msg)
+		})
+
+		res, err := Request(t.Context(), ps, &RequestReplyRequest{
+			PublishRequest: PublishRequest{Topic: "commands", Data: []byte("ping")},
+			ReplyTopic:     "replies",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "re: ping", string(res.Data))
+		assert.NotEmpty(t, res.Metadata[metadata.CorrelationIDMetadataKey])
+		assert.Empty(t, ps.subMD["replies"])
+	})
+
+	t.Run("no reply times out", func(t *testing.T) {
+		ps := newRequestTestPubSub()
+
+		_, err := Request(t.Context(), ps, &RequestReplyRequest{
+			PublishRequest: PublishRequest{Topic: "commands"},
+			Timeout:        50 * time.Millisecond,
+		})
+		require.ErrorIs(t, err, ErrRequestTimeout)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// The subscription is removed
+		require.Eventually(t, func() bool {
+			ps.lock.Lock()
+			defer ps.lock.Unlock()
+			return len(ps.handlers) == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("native requester", func(t *testing.T) {
+		ps := &requestTestRequester{requestTestPubSub: newRequestTestPubSub()}
+
+		res, err := Request(t.Context(), ps, &RequestReplyRequest{
+			PublishRequest: PublishRequest{Topic: "commands"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "native", string(res.Data))
+		assert.NotEmpty(t, ps.req.CorrelationID)
+		assert.Equal(t, DefaultRequestTimeout, ps.req.Timeout)
+
+		_, err = Request(t.Context(), ps, &RequestReplyRequest{
+			PublishRequest: PublishRequest{Topic: "slow"},
+			Timeout:        50 * time.Millisecond,
+		})
+		require.ErrorIs(t, err, ErrRequestTimeout)
+	})
+
+	t.Run("topic is required", func(t *testing.T) {
+		_, err := Request(t.Context(), newRequestTestPubSub(), &RequestReplyRequest{})
+		require.Error(t, err)
+	})
+}
+
+func TestNewReplyRequest(t *testing.T) {
+	reply, err := NewReplyRequest(&NewMessage{
+		Topic: "commands",
+		Metadata: map[string]string{
+			metadata.ReplyToMetadataKey:       "replies",
+			metadata.CorrelationIDMetadataKey: "c-1",
+			"other":                           "value",
+		},
+	}, []byte("pong"))
+	require.NoError(t, err)
+	assert.Equal(t, &PublishRequest{
+		Data:     []byte("pong"),
+		Topic:    "replies",
+		Metadata: map[string]string{metadata.CorrelationIDMetadataKey: "c-1"},
+	}, reply)
+
+	_, err = NewReplyRequest(&NewMessage{Topic: "commands"}, nil)
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrRequestTimeout))
+}
@@ -17,6 +17,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 // PublishRequest is the request to publish a message.
@@ -28,6 +31,32 @@ type PublishRequest struct {
 	ContentType *string           `json:"contentType,omitempty"`
 }
 
+// RequestReplyRequest is the request to publish a message and wait for the reply to it.
+type RequestReplyRequest struct {
+	PublishRequest
+
+	// CorrelationID correlates the reply with the request. If empty, a random ID is used.
+	CorrelationID string `json:"correlationID,omitempty"`
+	// ReplyTopic is the topic the reply is published to, with components that don't support request/reply natively.
+	// If empty, the topic of the request followed by "-reply-" and an ID of the process is used, which is shared by all requests to the same topic.
+	// It should be set with brokers that persist topics and subscriptions: see Request.
+	ReplyTopic string `json:"replyTopic,omitempty"`
+	// Timeout is how long to wait for the reply. If zero, DefaultRequestTimeout is used.
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+// withDefaults returns a copy of the request with the correlation ID and timeout set.
+func (r *RequestReplyRequest) withDefaults() *RequestReplyRequest {
+	res := *r
+	if res.CorrelationID == "" {
+		res.CorrelationID = uuid.New().String()
+	}
+	if res.Timeout <= 0 {
+		res.Timeout = DefaultRequestTimeout
+	}
+	return &res
+}
+
 // BulkPublishRequest is the request to publish mutilple messages.
 type BulkPublishRequest struct {
 	Entries    []BulkMessageEntry `json:"entries"`
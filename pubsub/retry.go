@@ -0,0 +1,56 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"errors"
+	"time"
+)
+
+// RetryProperties contains the metadata properties of components that deliver messages again themselves when the handler fails to process them.
+type RetryProperties struct {
+	// MaxRetries is the maximum number of times a message is delivered again after the handler fails to process it.
+	// Negative values retry forever.
+	MaxRetries int `mapstructure:"maxRetries"`
+	// RetryBackoff is the delay before the first retry, which doubles after each attempt.
+	RetryBackoff time.Duration `mapstructure:"retryBackoff"`
+	// MaxRetryBackoff is the maximum delay between retries.
+	MaxRetryBackoff time.Duration `mapstructure:"maxRetryBackoff"`
+}
+
+// Validate validates the retry properties.
+func (p RetryProperties) Validate() error {
+	if p.RetryBackoff <= 0 {
+		return errors.New("invalid value for 'retryBackoff': must be greater than 0")
+	}
+	if p.MaxRetryBackoff < p.RetryBackoff {
+		return errors.New("invalid value for 'maxRetryBackoff': must not be less than 'retryBackoff'")
+	}
+	return nil
+}
+
+// ShouldRetry returns true if a message is delivered again after the given number of failed attempts.
+func (p RetryProperties) ShouldRetry(attempts int) bool {
+	return p.MaxRetries < 0 || attempts <= p.MaxRetries
+}
+
+// RetryDelay returns the time to wait before delivering a message again after the given number of failed attempts.
+// The delay doubles after each attempt, up to the maximum backoff.
+func (p RetryProperties) RetryDelay(attempts int) time.Duration {
+	delay := p.RetryBackoff
+	for i := 1; i < attempts && delay < p.MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxRetryBackoff)
+}
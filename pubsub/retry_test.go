@@ -0,0 +1,49 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryProperties(t *testing.T) {
+	t.Run("validate", func(t *testing.T) {
+		require.NoError(t, RetryProperties{RetryBackoff: time.Second, MaxRetryBackoff: time.Second}.Validate())
+		require.Error(t, RetryProperties{RetryBackoff: 0, MaxRetryBackoff: time.Second}.Validate())
+		require.Error(t, RetryProperties{RetryBackoff: time.Minute, MaxRetryBackoff: time.Second}.Validate())
+	})
+
+	t.Run("should retry", func(t *testing.T) {
+		p := RetryProperties{MaxRetries: 2}
+		assert.True(t, p.ShouldRetry(1))
+		assert.True(t, p.ShouldRetry(2))
+		assert.False(t, p.ShouldRetry(3))
+
+		assert.False(t, RetryProperties{MaxRetries: 0}.ShouldRetry(1))
+		assert.True(t, RetryProperties{MaxRetries: -1}.ShouldRetry(1000))
+	})
+
+	t.Run("retry delay", func(t *testing.T) {
+		p := RetryProperties{RetryBackoff: time.Second, MaxRetryBackoff: 5 * time.Second}
+		assert.Equal(t, time.Second, p.RetryDelay(1))
+		assert.Equal(t, 2*time.Second, p.RetryDelay(2))
+		assert.Equal(t, 4*time.Second, p.RetryDelay(3))
+		assert.Equal(t, 5*time.Second, p.RetryDelay(4))
+		assert.Equal(t, 5*time.Second, p.RetryDelay(100))
+	})
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	authSqlite "github.com/dapr/components-contrib/common/authentication/sqlite"
+	"github.com/dapr/components-contrib/pubsub"
+	kitmd "github.com/dapr/kit/metadata"
+)
+
+type sqliteTable string
+
+const (
+	tableMessages      sqliteTable = "pubsub_messages"
+	tableSubscriptions sqliteTable = "pubsub_subscriptions"
+	tableDeliveries    sqliteTable = "pubsub_deliveries"
+)
+
+const (
+	defaultMetadataTableName = "metadata"
+	defaultVisibilityTimeout = time.Minute
+	defaultPollInterval      = time.Second
+	defaultBatchSize         = 10
+	defaultMaxRetries        = 3
+	defaultRetryBackoff      = time.Second
+	defaultMaxRetryBackoff   = time.Minute
+	defaultCleanupInterval   = time.Hour
+)
+
+type sqliteMetadata struct {
+	authSqlite.SqliteAuthMetadata `mapstructure:",squash"`
+	pubsub.RetryProperties        `mapstructure:",squash"`
+
+	ConsumerID        string                 `mapstructure:"consumerID"`
+	TablePrefix       string                 `mapstructure:"tablePrefix"`
+	MetadataTableName string                 `mapstructure:"metadataTableName"`
+	VisibilityTimeout time.Duration          `mapstructure:"visibilityTimeout"`
+	PollInterval      time.Duration          `mapstructure:"pollInterval"`
+	BatchSize         int                    `mapstructure:"batchSize"`
+	CleanupInterval   time.Duration          `mapstructure:"cleanupInterval"` // Non-positive values disable the cleanup
+	ConcurrencyMode   pubsub.ConcurrencyMode `mapstructure:"concurrencyMode"`
+}
+
+func (m *sqliteMetadata) InitWithMetadata(meta pubsub.Metadata) error {
+	// Reset the object
+	m.reset()
+
+	// Decode the metadata
+	err := kitmd.DecodeMetadata(meta.Properties, &m)
+	if err != nil {
+		return err
+	}
+
+	// Validate and sanitize input
+	err = m.SqliteAuthMetadata.Validate()
+	if err != nil {
+		return err
+	}
+	for _, table := range []sqliteTable{tableMessages, tableSubscriptions, tableDeliveries} {
+		if !authSqlite.ValidIdentifier(m.TableName(table)) {
+			return fmt.Errorf("invalid identifier: %s", m.TableName(table))
+		}
+	}
+	if !authSqlite.ValidIdentifier(m.MetadataTableName) {
+		return fmt.Errorf("invalid identifier: %s", m.MetadataTableName)
+	}
+	if m.VisibilityTimeout < time.Second {
+		return errors.New("invalid value for 'visibilityTimeout': must be greater than 1s")
+	}
+	if m.PollInterval <= 0 {
+		return errors.New("invalid value for 'pollInterval': must be greater than 0")
+	}
+	if m.BatchSize <= 0 {
+		return errors.New("invalid value for 'batchSize': must be greater than 0")
+	}
+	err = m.RetryProperties.Validate()
+	if err != nil {
+		return err
+	}
+
+	m.ConcurrencyMode, err = pubsub.Concurrency(meta.Properties)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reset the object
+func (m *sqliteMetadata) reset() {
+	m.SqliteAuthMetadata.Reset()
+
+	m.ConsumerID = ""
+	m.TablePrefix = ""
+	m.MetadataTableName = defaultMetadataTableName
+	m.VisibilityTimeout = defaultVisibilityTimeout
+	m.PollInterval = defaultPollInterval
+	m.BatchSize = defaultBatchSize
+	m.MaxRetries = defaultMaxRetries
+	m.RetryBackoff = defaultRetryBackoff
+	m.MaxRetryBackoff = defaultMaxRetryBackoff
+	m.CleanupInterval = defaultCleanupInterval
+	m.ConcurrencyMode = ""
+}
+
+func (m *sqliteMetadata) TableName(table sqliteTable) string {
+	return m.TablePrefix + string(table)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authSqlite "github.com/dapr/components-contrib/common/authentication/sqlite"
+	"github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/components-contrib/pubsub"
+)
+
+func TestMetadata(t *testing.T) {
+	initMetadata := func(props map[string]string) (sqliteMetadata, error) {
+		m := sqliteMetadata{}
+		err := m.InitWithMetadata(pubsub.Metadata{Base: metadata.Base{Properties: props}})
+		return m, err
+	}
+
+	t.Run("missing connection string", func(t *testing.T) {
+		_, err := initMetadata(map[string]string{})
+		require.ErrorContains(t, err, "missing connection string")
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		m, err := initMetadata(map[string]string{
+			"connectionString": "file:data.db",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "pubsub_messages", m.TableName(tableMessages))
+		assert.Equal(t, "pubsub_subscriptions", m.TableName(tableSubscriptions))
+		assert.Equal(t, "pubsub_deliveries", m.TableName(tableDeliveries))
+		assert.Equal(t, defaultMetadataTableName, m.MetadataTableName)
+		assert.Equal(t, authSqlite.DefaultTimeout, m.Timeout)
+		assert.Equal(t, defaultVisibilityTimeout, m.VisibilityTimeout)
+		assert.Equal(t, defaultPollInterval, m.PollInterval)
+		assert.Equal(t, defaultBatchSize, m.BatchSize)
+		assert.Equal(t, defaultMaxRetries, m.MaxRetries)
+		assert.Equal(t, defaultRetryBackoff, m.RetryBackoff)
+		assert.Equal(t, defaultMaxRetryBackoff, m.MaxRetryBackoff)
+		assert.Equal(t, defaultCleanupInterval, m.CleanupInterval)
+		assert.Equal(t, pubsub.Parallel, m.ConcurrencyMode)
+	})
+
+	t.Run("custom values", func(t *testing.T) {
+		m, err := initMetadata(map[string]string{
+			"connectionString": "file:data.db",
+			"consumerID":       "myapp",
+			"tablePrefix":      "my_",
+			"maxRetries":       "-1",
+			"retryBackoff":     "500ms",
+			"maxRetryBackoff":  "10s",
+			"cleanupInterval":  "0",
+			"concurrencyMode":  "single",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "myapp", m.ConsumerID)
+		assert.Equal(t, "my_pubsub_messages", m.TableName(tableMessages))
+		assert.Equal(t, -1, m.MaxRetries)
+		assert.Equal(t, 500*time.Millisecond, m.RetryBackoff)
+		assert.Equal(t, 10*time.Second, m.MaxRetryBackoff)
+		assert.Equal(t, time.Duration(0), m.CleanupInterval)
+		assert.Equal(t, pubsub.Single, m.ConcurrencyMode)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		for key, val := range map[string]string{
+			"tablePrefix":       "not.valid",
+			"metadataTableName": "not.valid",
+			"timeout":           "500ms",
+			"visibilityTimeout": "100ms",
+			"pollInterval":      "0",
+			"batchSize":         "0",
+			"retryBackoff":      "0",
+			"maxRetryBackoff":   "100ms",
+			"concurrencyMode":   "sometimes",
+		} {
+			_, err := initMetadata(map[string]string{
+				"connectionString": "file:data.db",
+				key:                val,
+			})
+			require.Error(t, err, key)
+		}
+	})
+
+	t.Run("retry delay", func(t *testing.T) {
+		m, err := initMetadata(map[string]string{
+			"connectionString": "file:data.db",
+			"retryBackoff":     "1s",
+			"maxRetryBackoff":  "5s",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, time.Second, m.RetryDelay(1))
+		assert.Equal(t, 2*time.Second, m.RetryDelay(2))
+		assert.Equal(t, 4*time.Second, m.RetryDelay(3))
+		assert.Equal(t, 5*time.Second, m.RetryDelay(4))
+		assert.Equal(t, 5*time.Second, m.RetryDelay(100))
+	})
+}
@@ -0,0 +1,623 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	authSqlite "github.com/dapr/components-contrib/common/authentication/sqlite"
+	commonsql "github.com/dapr/components-contrib/common/component/sql"
+	sqlitemigrations "github.com/dapr/components-contrib/common/component/sql/migrations/sqlite"
+	"github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/components-contrib/pubsub"
+	"github.com/dapr/kit/logger"
+)
+
+// Metadata key to set the consumer group of a subscription, overriding the consumerID of the component.
+const metadataConsumerIDKey = "consumerID"
+
+// SQLite is a pubsub component that stores messages in a SQLite database, so they survive restarts and can be shared by processes on the same host.
+// Messages are appended to a log, and each subscription (a consumer group and a topic, which can end with a "*" wildcard) keeps a cursor in the log.
+// Messages read by a subscription are tracked as deliveries until they're acknowledged: failed deliveries are retried with an exponential backoff,
+// and deliveries that are not acknowledged within the visibility timeout (for example because the process crashed) are delivered again.
+// Subscriptions are woken up right away when messages are published by the same process, and poll the database otherwise.
+type SQLite struct {
+	logger   logger.Logger
+	metadata sqliteMetadata
+	db       *sql.DB
+
+	gc commonsql.GarbageCollector
+
+	// Wakeup channels of the subscriptions
+	listenersLock sync.Mutex
+	listeners     map[chan struct{}]struct{}
+
+	closed  atomic.Bool
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewSQLite creates a new instance of the SQLite pubsub component.
+func NewSQLite(logger logger.Logger) pubsub.PubSub {
+	return &SQLite{
+		logger:    logger,
+		listeners: map[chan struct{}]struct{}{},
+		closeCh:   make(chan struct{}),
+	}
+}
+
+// Init opens the database and performs migrations.
+func (s *SQLite) Init(ctx context.Context, meta pubsub.Metadata) error {
+	err := s.metadata.InitWithMetadata(meta)
+	if err != nil {
+		return err
+	}
+
+	// Foreign keys are used to remove the deliveries of messages that are deleted
+	connString, err := s.metadata.GetConnectionString(s.logger, authSqlite.GetConnectionStringOpts{
+		EnableForeignKeys: true,
+	})
+	if err != nil {
+		// Already logged
+		return err
+	}
+
+	s.db, err = sql.Open("sqlite", connString)
+	if err != nil {
+		return fmt.Errorf("failed to create connection: %w", err)
+	}
+
+	// If the database is in-memory, we can't have more than 1 open connection
+	if s.metadata.IsInMemoryDB() {
+		s.db.SetMaxOpenConns(1)
+	}
+
+	err = s.Ping(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to ping: %w", err)
+	}
+
+	err = s.performMigrations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to perform migrations: %w", err)
+	}
+
+	// Messages are removed when they expire, or once they have been received by all subscriptions
+	// If there are no subscriptions, messages are removed as no subscription would ever receive them
+	s.gc, err = commonsql.ScheduleGarbageCollector(commonsql.GCOptions{
+		Logger: s.logger,
+		UpdateLastCleanupQuery: func(arg any) (string, any) {
+			return fmt.Sprintf(`INSERT INTO %s (key, value)
+				VALUES ('last-cleanup-pubsub-%s', CURRENT_TIMESTAMP)
+				ON CONFLICT (key)
+				DO UPDATE SET value = CURRENT_TIMESTAMP
+					WHERE (unixepoch(CURRENT_TIMESTAMP) - unixepoch(value)) * 1000 > ?;`,
+				s.metadata.MetadataTableName,
+				s.metadata.TablePrefix,
+			), arg
+		},
+		DeleteExpiredValuesQuery: fmt.Sprintf(`DELETE FROM %[1]s
+		WHERE
+			(expires_at IS NOT NULL AND expires_at < unixepoch() * 1000)
+			OR (
+				id <= (SELECT COALESCE(min(cursor), (SELECT max(id) FROM %[1]s)) FROM %[2]s)
+				AND id NOT IN (SELECT message_id FROM %[3]s)
+			)`,
+			s.metadata.TableName(tableMessages),
+			s.metadata.TableName(tableSubscriptions),
+			s.metadata.TableName(tableDeliveries),
+		),
+		CleanupInterval: s.metadata.CleanupInterval,
+		DB:              commonsql.AdaptDatabaseSQLConn(s.db),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *SQLite) performMigrations(ctx context.Context) error {
+	m := sqlitemigrations.Migrations{
+		Pool:              s.db,
+		Logger:            s.logger,
+		MetadataTableName: s.metadata.MetadataTableName,
+		MetadataKey:       "migrations-pubsub-" + s.metadata.TablePrefix,
+	}
+
+	messagesTable := s.metadata.TableName(tableMessages)
+	subscriptionsTable := s.metadata.TableName(tableSubscriptions)
+	deliveriesTable := s.metadata.TableName(tableDeliveries)
+
+	return m.Perform(ctx, []commonsql.MigrationFn{
+		// Migration 0: create the tables for messages, subscriptions, and deliveries
+		func(ctx context.Context) error {
+			s.logger.Infof("Creating pubsub tables: '%s', '%s', and '%s'", messagesTable, subscriptionsTable, deliveriesTable)
+			// Times are stored as UNIX timestamps in milliseconds
+			// AUTOINCREMENT guarantees that IDs of deleted messages are not reused, so they can't be skipped by the cursors
+			for _, query := range []string{
+				fmt.Sprintf(`CREATE TABLE %s (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					topic TEXT NOT NULL,
+					data BLOB NOT NULL,
+					content_type TEXT,
+					metadata TEXT,
+					created_at INTEGER NOT NULL,
+					expires_at INTEGER
+				)`, messagesTable),
+				fmt.Sprintf(`CREATE INDEX %[1]s_expires_at ON %[1]s (expires_at)`, messagesTable),
+				fmt.Sprintf(`CREATE TABLE %s (
+					consumer_group TEXT NOT NULL,
+					topic TEXT NOT NULL,
+					cursor INTEGER NOT NULL,
+					created_at INTEGER NOT NULL,
+					PRIMARY KEY (consumer_group, topic)
+				)`, subscriptionsTable),
+				fmt.Sprintf(`CREATE TABLE %s (
+					consumer_group TEXT NOT NULL,
+					topic TEXT NOT NULL,
+					message_id INTEGER NOT NULL REFERENCES %s (id) ON DELETE CASCADE,
+					attempts INTEGER NOT NULL,
+					next_attempt_at INTEGER NOT NULL,
+					PRIMARY KEY (consumer_group, topic, message_id)
+				)`, deliveriesTable, messagesTable),
+				fmt.Sprintf(`CREATE INDEX %[1]s_message_id ON %[1]s (message_id)`, deliveriesTable),
+			} {
+				_, err := m.GetConn().ExecContext(ctx, query)
+				if err != nil {
+					return fmt.Errorf("failed to create pubsub tables: %w", err)
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// Features returns the features available in this pubsub component.
+func (s *SQLite) Features() []pubsub.Feature {
+	return []pubsub.Feature{
+		pubsub.FeatureSubscribeWildcards,
+		pubsub.FeatureMessageTTL,
+	}
+}
+
+// Publish appends the message to the log and wakes up the subscriptions of this process.
+func (s *SQLite) Publish(parentCtx context.Context, req *pubsub.PublishRequest) error {
+	if s.closed.Load() {
+		return errors.New("component is closed")
+	}
+	if req.Topic == "" {
+		return errors.New("topic is required")
+	}
+
+	now := time.Now()
+	var expiresAt *int64
+	ttl, ok, err := metadata.TryGetTTL(req.Metadata)
+	if err != nil {
+		return err
+	}
+	if ok {
+		v := now.Add(ttl).UnixMilli()
+		expiresAt = &v
+	}
+
+	// Metadata is stored as JSON, or NULL if empty
+	var md *string
+	if len(req.Metadata) > 0 {
+		enc, err := json.Marshal(req.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to serialize metadata: %w", err)
+		}
+		v := string(enc)
+		md = &v
+	}
+
+	data := req.Data
+	if data == nil {
+		data = []byte{}
+	}
+
+	ctx, cancel := context.WithTimeout(parentCtx, s.metadata.Timeout)
+	defer cancel()
+	_, err = s.db.ExecContext(ctx,
+		fmt.Sprintf(`INSERT INTO %s (topic, data, content_type, metadata, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?)`, s.metadata.TableName(tableMessages)),
+		req.Topic, data, req.ContentType, md, now.UnixMilli(), expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	s.wake()
+	return nil
+}
+
+// Subscribe registers the subscription and starts receiving messages in background, until ctx is canceled.
+// The consumer group is the consumerID of the component, unless it is set in the metadata of the subscription.
+// New subscriptions receive messages published after they're registered; existing ones resume from their cursor.
+func (s *SQLite) Subscribe(ctx context.Context, req pubsub.SubscribeRequest, handler pubsub.Handler) error {
+	if s.closed.Load() {
+		return errors.New("component is closed")
+	}
+	if req.Topic == "" {
+		return errors.New("topic is required")
+	}
+
+	group := s.metadata.ConsumerID
+	if val := req.Metadata[metadataConsumerIDKey]; val != "" {
+		group = val
+	}
+	if group == "" {
+		return errors.New("consumerID is required to subscribe")
+	}
+
+	queryCtx, queryCancel := context.WithTimeout(ctx, s.metadata.Timeout)
+	defer queryCancel()
+	_, err := s.db.ExecContext(queryCtx,
+		fmt.Sprintf(`INSERT OR IGNORE INTO %s (consumer_group, topic, cursor, created_at) SELECT ?, ?, COALESCE(max(id), 0), ? FROM %s`,
+			s.metadata.TableName(tableSubscriptions), s.metadata.TableName(tableMessages)),
+		group, req.Topic, time.Now().UnixMilli(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register subscription: %w", err)
+	}
+
+	wakeCh := s.addListener()
+
+	subCtx, cancel := context.WithCancel(ctx)
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		select {
+		case <-subCtx.Done():
+		case <-s.closeCh:
+			cancel()
+		}
+	}()
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		defer s.removeListener(wakeCh)
+		s.consume(subCtx, req.Topic, group, handler, wakeCh)
+	}()
+
+	return nil
+}
+
+// consume receives messages until ctx is canceled.
+// A new batch is fetched right away when the previous one was full, when the subscription is woken up, or when the poll interval elapses.
+func (s *SQLite) consume(ctx context.Context, topic string, group string, handler pubsub.Handler, wakeCh <-chan struct{}) {
+	t := time.NewTicker(s.metadata.PollInterval)
+	defer t.Stop()
+
+	for {
+		n, err := s.receiveBatch(ctx, topic, group, handler)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Errorf("Error receiving messages from topic '%s': %v", topic, err)
+		}
+		if n == s.metadata.BatchSize {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-wakeCh:
+		case <-t.C:
+		}
+	}
+}
+
+type message struct {
+	id          int64
+	topic       string
+	data        []byte
+	contentType *string
+	metadata    map[string]string
+	attempts    int
+}
+
+// receiveBatch claims a batch of messages for the subscription and invokes the handler for each of them.
+func (s *SQLite) receiveBatch(ctx context.Context, topic string, group string, handler pubsub.Handler) (int, error) {
+	msgs, err := s.fetch(ctx, topic, group)
+	if err != nil {
+		return 0, err
+	}
+
+	switch s.metadata.ConcurrencyMode {
+	case pubsub.Single:
+		for _, msg := range msgs {
+			if ctx.Err() != nil {
+				// The remaining messages are delivered again after the visibility timeout
+				break
+			}
+			s.handleMessage(ctx, topic, group, handler, msg)
+		}
+	default:
+		var wg sync.WaitGroup
+		wg.Add(len(msgs))
+		for _, msg := range msgs {
+			go func() {
+				defer wg.Done()
+				s.handleMessage(ctx, topic, group, handler, msg)
+			}()
+		}
+		wg.Wait()
+	}
+
+	return len(msgs), nil
+}
+
+// topicFilter returns the condition that matches the topics of the subscription, and its argument.
+// Like the in-memory pubsub, a topic ending with "*" matches all topics that start with what precedes it.
+func topicFilter(topic string) (string, string) {
+	if prefix, ok := strings.CutSuffix(topic, "*"); ok {
+		return "(topic != ?1 AND substr(topic, 1, length(?1)) = ?1)", prefix
+	}
+	return "topic = ?1", topic
+}
+
+// fetch moves the cursor of the subscription forward, adding the messages it passes as deliveries, then claims the deliveries that are due for the visibility timeout.
+// This is done in a single transaction, so messages are never lost nor claimed by more than one subscriber of the same consumer group.
+func (s *SQLite) fetch(parentCtx context.Context, topic string, group string) ([]message, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, s.metadata.Timeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	messagesTable := s.metadata.TableName(tableMessages)
+	subscriptionsTable := s.metadata.TableName(tableSubscriptions)
+	deliveriesTable := s.metadata.TableName(tableDeliveries)
+	now := time.Now().UnixMilli()
+
+	var cursor, head int64
+	err = tx.QueryRowContext(ctx,
+		fmt.Sprintf(`SELECT s.cursor, (SELECT COALESCE(max(id), 0) FROM %s) FROM %s AS s WHERE s.consumer_group = ? AND s.topic = ?`, messagesTable, subscriptionsTable),
+		group, topic,
+	).Scan(&cursor, &head)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	// Add the messages after the cursor as deliveries
+	filter, filterArg := topicFilter(topic)
+	rows, err := tx.QueryContext(ctx,
+		fmt.Sprintf(`INSERT INTO %[1]s (consumer_group, topic, message_id, attempts, next_attempt_at)
+			SELECT ?2, ?3, id, 0, 0 FROM %[2]s
+			WHERE %[3]s
+				AND id > ?4 AND id <= ?5
+				AND (expires_at IS NULL OR expires_at > ?6)
+			ORDER BY id
+			LIMIT ?7
+			RETURNING message_id`, deliveriesTable, messagesTable, filter),
+		filterArg, group, topic, cursor, head, now, s.metadata.BatchSize,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add deliveries: %w", err)
+	}
+	var added int
+	newCursor := cursor
+	for rows.Next() {
+		var id int64
+		err = rows.Scan(&id)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to add deliveries: %w", err)
+		}
+		added++
+		newCursor = max(newCursor, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to add deliveries: %w", err)
+	}
+
+	// If the batch was not full, all messages up to the head of the log were seen
+	if added < s.metadata.BatchSize {
+		newCursor = max(cursor, head)
+	}
+	if newCursor != cursor {
+		_, err = tx.ExecContext(ctx,
+			fmt.Sprintf(`UPDATE %s SET cursor = ? WHERE consumer_group = ? AND topic = ?`, subscriptionsTable),
+			newCursor, group, topic,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update cursor: %w", err)
+		}
+	}
+
+	// Claim the deliveries that are due
+	rows, err = tx.QueryContext(ctx,
+		fmt.Sprintf(`SELECT m.id, m.topic, m.data, m.content_type, m.metadata, d.attempts
+			FROM %s AS d
+			JOIN %s AS m ON m.id = d.message_id
+			WHERE d.consumer_group = ? AND d.topic = ?
+				AND d.next_attempt_at <= ?
+				AND (m.expires_at IS NULL OR m.expires_at > ?)
+			ORDER BY d.message_id
+			LIMIT ?`, deliveriesTable, messagesTable),
+		group, topic, now, now, s.metadata.BatchSize,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch messages: %w", err)
+	}
+	msgs := make([]message, 0, s.metadata.BatchSize)
+	for rows.Next() {
+		var (
+			msg message
+			md  *string
+		)
+		err = rows.Scan(&msg.id, &msg.topic, &msg.data, &msg.contentType, &md, &msg.attempts)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to read message: %w", err)
+		}
+		if md != nil {
+			err = json.Unmarshal([]byte(*md), &msg.metadata)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to parse metadata of message %d: %w", msg.id, err)
+			}
+		}
+		msg.attempts++
+		msgs = append(msgs, msg)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch messages: %w", err)
+	}
+
+	if len(msgs) > 0 {
+		args := make([]any, 0, len(msgs)+3)
+		args = append(args, now+s.metadata.VisibilityTimeout.Milliseconds(), group, topic)
+		for _, msg := range msgs {
+			args = append(args, msg.id)
+		}
+		_, err = tx.ExecContext(ctx,
+			fmt.Sprintf(`UPDATE %s SET attempts = attempts + 1, next_attempt_at = ?
+				WHERE consumer_group = ? AND topic = ? AND message_id IN (?%s)`,
+				deliveriesTable, strings.Repeat(", ?", len(msgs)-1)),
+			args...,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim messages: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return msgs, nil
+}
+
+// handleMessage invokes the handler, then removes the delivery if the message was handled successfully.
+// Otherwise, the delivery is retried after a backoff, unless the maximum number of retries was reached.
+func (s *SQLite) handleMessage(ctx context.Context, topic string, group string, handler pubsub.Handler, msg message) {
+	handlerErr := handler(ctx, &pubsub.NewMessage{
+		Data:        msg.data,
+		Topic:       msg.topic,
+		Metadata:    msg.metadata,
+		ContentType: msg.contentType,
+	})
+
+	var (
+		query string
+		args  []any
+	)
+	deliveriesTable := s.metadata.TableName(tableDeliveries)
+	switch {
+	case handlerErr == nil:
+		query = fmt.Sprintf(`DELETE FROM %s WHERE consumer_group = ? AND topic = ? AND message_id = ?`, deliveriesTable)
+		args = []any{group, topic, msg.id}
+	case !s.metadata.ShouldRetry(msg.attempts):
+		s.logger.Errorf("Error processing message %d from topic '%s' (attempt %d), dropping it as the maximum number of retries was reached: %v", msg.id, msg.topic, msg.attempts, handlerErr)
+		query = fmt.Sprintf(`DELETE FROM %s WHERE consumer_group = ? AND topic = ? AND message_id = ?`, deliveriesTable)
+		args = []any{group, topic, msg.id}
+	default:
+		delay := s.metadata.RetryDelay(msg.attempts)
+		s.logger.Errorf("Error processing message %d from topic '%s' (attempt %d), retrying in %v: %v", msg.id, msg.topic, msg.attempts, delay, handlerErr)
+		query = fmt.Sprintf(`UPDATE %s SET next_attempt_at = ? WHERE consumer_group = ? AND topic = ? AND message_id = ?`, deliveriesTable)
+		args = []any{time.Now().Add(delay).UnixMilli(), group, topic, msg.id}
+	}
+
+	// Use a separate context, so the outcome is stored even if the subscription is being stopped
+	queryCtx, cancel := context.WithTimeout(context.Background(), s.metadata.Timeout)
+	defer cancel()
+	_, err := s.db.ExecContext(queryCtx, query, args...)
+	if err != nil {
+		s.logger.Errorf("Error updating delivery of message %d from topic '%s', it will be delivered again after the visibility timeout: %v", msg.id, msg.topic, err)
+	}
+}
+
+// addListener returns the channel that wakes up a subscription.
+func (s *SQLite) addListener() chan struct{} {
+	ch := make(chan struct{}, 1)
+
+	s.listenersLock.Lock()
+	defer s.listenersLock.Unlock()
+	s.listeners[ch] = struct{}{}
+
+	return ch
+}
+
+func (s *SQLite) removeListener(ch chan struct{}) {
+	s.listenersLock.Lock()
+	defer s.listenersLock.Unlock()
+	delete(s.listeners, ch)
+}
+
+// wake wakes up all subscriptions.
+// Subscriptions to other topics only run a query that returns no messages.
+func (s *SQLite) wake() {
+	s.listenersLock.Lock()
+	defer s.listenersLock.Unlock()
+	for ch := range s.listeners {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// Already woken up
+		}
+	}
+}
+
+// Ping checks the connection to the database.
+func (s *SQLite) Ping(parentCtx context.Context) error {
+	if s.db == nil {
+		return errors.New("component is not initialized")
+	}
+	ctx, cancel := context.WithTimeout(parentCtx, s.metadata.Timeout)
+	defer cancel()
+	return s.db.PingContext(ctx)
+}
+
+// Close stops all subscriptions and closes the database.
+func (s *SQLite) Close() error {
+	if s.closed.CompareAndSwap(false, true) {
+		close(s.closeCh)
+	}
+	s.wg.Wait()
+
+	errs := make([]error, 2)
+	if s.gc != nil {
+		errs[0] = s.gc.Close()
+	}
+	if s.db != nil {
+		errs[1] = s.db.Close()
+		s.db = nil
+	}
+	return errors.Join(errs...)
+}
+
+// GetComponentMetadata returns the metadata of the component.
+func (s *SQLite) GetComponentMetadata() (metadataInfo metadata.MetadataMap) {
+	metadataStruct := sqliteMetadata{}
+	metadata.GetMetadataInfoFromStructType(reflect.TypeOf(metadataStruct), &metadataInfo, metadata.PubSubType)
+	return
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/components-contrib/pubsub"
+	"github.com/dapr/kit/logger"
+)
+
+func newComponent(t *testing.T, dbPath string, props map[string]string) *SQLite {
+	t.Helper()
+
+	s := NewSQLite(logger.NewLogger("test")).(*SQLite)
+	md := map[string]string{
+		"connectionString": dbPath,
+		"consumerID":       "group1",
+		"pollInterval":     "100ms",
+		"retryBackoff":     "100ms",
+		"maxRetryBackoff":  "100ms",
+		"cleanupInterval":  "0",
+	}
+	for k, v := range props {
+		md[k] = v
+	}
+	err := s.Init(t.Context(), pubsub.Metadata{Base: metadata.Base{Properties: md}})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.Close()
+	})
+	return s
+}
+
+func subscribe(t *testing.T, s *SQLite, topic string, group string, handler pubsub.Handler) context.CancelFunc {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+	err := s.Subscribe(ctx, pubsub.SubscribeRequest{Topic: topic, Metadata: map[string]string{metadataConsumerIDKey: group}}, handler)
+	require.NoError(t, err)
+	return cancel
+}
+
+func collect(ch chan<- *pubsub.NewMessage) pubsub.Handler {
+	return func(ctx context.Context, msg *pubsub.NewMessage) error {
+		ch <- msg
+		return nil
+	}
+}
+
+func receive(t *testing.T, ch <-chan *pubsub.NewMessage) *pubsub.NewMessage {
+	t.Helper()
+
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timed out waiting for message")
+		return nil
+	}
+}
+
+func assertNoMessage(t *testing.T, ch <-chan *pubsub.NewMessage) {
+	t.Helper()
+
+	select {
+	case msg := <-ch:
+		assert.Failf(t, "unexpected message", "received %s from topic %s", msg.Data, msg.Topic)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+func TestPubSub(t *testing.T) {
+	t.Run("each consumer group receives every message", func(t *testing.T) {
+		s := newComponent(t, filepath.Join(t.TempDir(), "pubsub.db"), nil)
+
+		ch1 := make(chan *pubsub.NewMessage, 10)
+		ch2 := make(chan *pubsub.NewMessage, 10)
+		subscribe(t, s, "orders", "group1", collect(ch1))
+		subscribe(t, s, "orders", "group2", collect(ch2))
+
+		contentType := "text/plain"
+		err := s.Publish(t.Context(), &pubsub.PublishRequest{
+			Topic:       "orders",
+			Data:        []byte("hello"),
+			ContentType: &contentType,
+			Metadata:    map[string]string{"foo": "bar"},
+		})
+		require.NoError(t, err)
+
+		for _, ch := range []chan *pubsub.NewMessage{ch1, ch2} {
+			msg := receive(t, ch)
+			assert.Equal(t, "hello", string(msg.Data))
+			assert.Equal(t, "orders", msg.Topic)
+			assert.Equal(t, map[string]string{"foo": "bar"}, msg.Metadata)
+			require.NotNil(t, msg.ContentType)
+			assert.Equal(t, contentType, *msg.ContentType)
+			assertNoMessage(t, ch)
+		}
+	})
+
+	t.Run("wildcard topics", func(t *testing.T) {
+		s := newComponent(t, filepath.Join(t.TempDir(), "pubsub.db"), nil)
+
+		ch := make(chan *pubsub.NewMessage, 10)
+		subscribe(t, s, "orders.*", "group1", collect(ch))
+
+		for _, topic := range []string{"orders.", "orders.created", "other", "orders.deleted"} {
+			err := s.Publish(t.Context(), &pubsub.PublishRequest{Topic: topic, Data: []byte(topic)})
+			require.NoError(t, err)
+		}
+
+		// Messages are handled in parallel, so they can be received in any order
+		received := []string{receive(t, ch).Topic, receive(t, ch).Topic}
+		assert.ElementsMatch(t, []string{"orders.created", "orders.deleted"}, received)
+		assertNoMessage(t, ch)
+	})
+
+	t.Run("failed messages are retried", func(t *testing.T) {
+		s := newComponent(t, filepath.Join(t.TempDir(), "pubsub.db"), nil)
+
+		var attempts atomic.Int32
+		ch := make(chan *pubsub.NewMessage, 10)
+		subscribe(t, s, "retry", "group1", func(ctx context.Context, msg *pubsub.NewMessage) error {
+			if attempts.Add(1) < 3 {
+				return errors.New("simulated failure")
+			}
+			ch <- msg
+			return nil
+		})
+
+		err := s.Publish(t.Context(), &pubsub.PublishRequest{Topic: "retry", Data: []byte("again")})
+		require.NoError(t, err)
+		assert.Equal(t, "again", string(receive(t, ch).Data))
+		assertNoMessage(t, ch)
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("messages are dropped after the maximum number of retries", func(t *testing.T) {
+		s := newComponent(t, filepath.Join(t.TempDir(), "pubsub.db"), map[string]string{
+			"maxRetries": "1",
+		})
+
+		var attempts atomic.Int32
+		subscribe(t, s, "retry", "group1", func(ctx context.Context, msg *pubsub.NewMessage) error {
+			attempts.Add(1)
+			return errors.New("simulated failure")
+		})
+
+		err := s.Publish(t.Context(), &pubsub.PublishRequest{Topic: "retry", Data: []byte("again")})
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			return attempts.Load() == 2
+		}, 5*time.Second, 50*time.Millisecond)
+		time.Sleep(500 * time.Millisecond)
+		assert.Equal(t, int32(2), attempts.Load())
+	})
+
+	t.Run("subscriptions resume from their cursor", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "pubsub.db")
+		s1 := newComponent(t, dbPath, nil)
+
+		ch := make(chan *pubsub.NewMessage, 10)
+		cancel := subscribe(t, s1, "orders", "group1", collect(ch))
+		err := s1.Publish(t.Context(), &pubsub.PublishRequest{Topic: "orders", Data: []byte("1")})
+		require.NoError(t, err)
+		assert.Equal(t, "1", string(receive(t, ch).Data))
+		cancel()
+		require.NoError(t, s1.Close())
+
+		// Messages published while the consumer group is not running are delivered when it subscribes again, by another instance
+		s2 := newComponent(t, dbPath, nil)
+		err = s2.Publish(t.Context(), &pubsub.PublishRequest{Topic: "orders", Data: []byte("2")})
+		require.NoError(t, err)
+		subscribe(t, s2, "orders", "group1", collect(ch))
+		assert.Equal(t, "2", string(receive(t, ch).Data))
+		assertNoMessage(t, ch)
+	})
+
+	t.Run("expired messages are not delivered", func(t *testing.T) {
+		s := newComponent(t, filepath.Join(t.TempDir(), "pubsub.db"), nil)
+
+		ch := make(chan *pubsub.NewMessage, 10)
+		cancel := subscribe(t, s, "ttl", "group1", collect(ch))
+		cancel()
+
+		err := s.Publish(t.Context(), &pubsub.PublishRequest{Topic: "ttl", Data: []byte("expired"), Metadata: map[string]string{
+			metadata.TTLMetadataKey: "1",
+		}})
+		require.NoError(t, err)
+		err = s.Publish(t.Context(), &pubsub.PublishRequest{Topic: "ttl", Data: []byte("valid")})
+		require.NoError(t, err)
+		time.Sleep(1100 * time.Millisecond)
+
+		subscribe(t, s, "ttl", "group1", collect(ch))
+		assert.Equal(t, "valid", string(receive(t, ch).Data))
+		assertNoMessage(t, ch)
+	})
+
+	t.Run("cleanup removes messages received by all subscriptions", func(t *testing.T) {
+		s := newComponent(t, filepath.Join(t.TempDir(), "pubsub.db"), map[string]string{
+			"cleanupInterval": "1h",
+		})
+
+		countMessages := func() (n int) {
+			err := s.db.QueryRowContext(t.Context(), "SELECT count(*) FROM pubsub_messages").Scan(&n)
+			require.NoError(t, err)
+			return n
+		}
+
+		ch := make(chan *pubsub.NewMessage, 10)
+		cancel := subscribe(t, s, "orders", "group1", collect(ch))
+		err := s.Publish(t.Context(), &pubsub.PublishRequest{Topic: "orders", Data: []byte("1")})
+		require.NoError(t, err)
+		receive(t, ch)
+		cancel()
+
+		// group2 hasn't received the message yet
+		subscribe(t, s, "orders", "group2", func(ctx context.Context, msg *pubsub.NewMessage) error {
+			return errors.New("not yet")
+		})
+		err = s.Publish(t.Context(), &pubsub.PublishRequest{Topic: "orders", Data: []byte("2")})
+		require.NoError(t, err)
+		time.Sleep(300 * time.Millisecond)
+
+		require.NoError(t, s.gc.CleanupExpired())
+		assert.Equal(t, 1, countMessages())
+	})
+}
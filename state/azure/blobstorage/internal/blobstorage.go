@@ -15,6 +15,7 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
@@ -23,6 +24,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	jsoniter "github.com/json-iterator/go"
 
@@ -63,7 +65,7 @@ func (r *StateStore) Init(ctx context.Context, metadata state.Metadata) error {
 
 // Features returns the features available in this state store.
 func (r *StateStore) Features() []state.Feature {
-	return []state.Feature{state.FeatureETag}
+	return []state.Feature{state.FeatureETag, state.FeatureStreaming}
 }
 
 // Delete the state.
@@ -81,6 +83,55 @@ func (r *StateStore) Set(ctx context.Context, req *state.SetRequest) error {
 	return r.writeFile(ctx, req)
 }
 
+// GetStream returns the state as a stream read directly from the blob.
+func (r *StateStore) GetStream(ctx context.Context, req *state.GetRequest) (*state.GetStreamResponse, error) {
+	blockBlobClient := r.containerClient.NewBlockBlobClient(r.getFileNameFn(req.Key))
+	blobDownloadResponse, err := blockBlobClient.DownloadStream(ctx, nil)
+	if err != nil {
+		if isNotFoundError(err) {
+			return &state.GetStreamResponse{}, nil
+		}
+
+		return &state.GetStreamResponse{}, err
+	}
+
+	return &state.GetStreamResponse{
+		Data:        blobDownloadResponse.Body,
+		ETag:        ptr.Of(string(*blobDownloadResponse.ETag)),
+		ContentType: blobDownloadResponse.ContentType,
+	}, nil
+}
+
+// SetStream uploads the state from a stream, in blocks, without buffering the whole value.
+func (r *StateStore) SetStream(ctx context.Context, req *state.SetStreamRequest) error {
+	if req.Value == nil {
+		return errors.New("missing value in set stream operation")
+	}
+
+	blobHTTPHeaders, err := blobstoragecommon.CreateBlobHTTPHeadersFromRequest(req.Metadata, req.ContentType, r.logger)
+	if err != nil {
+		return err
+	}
+
+	uploadOptions := blockblob.UploadStreamOptions{
+		AccessConditions: accessConditions(req.ETag, req.Options.Concurrency),
+		Metadata:         blobstoragecommon.SanitizeMetadata(r.logger, req.Metadata),
+		HTTPHeaders:      &blobHTTPHeaders,
+	}
+
+	blockBlobClient := r.containerClient.NewBlockBlobClient(r.getFileNameFn(req.Key))
+	_, err = blockBlobClient.UploadStream(ctx, req.Value, &uploadOptions)
+	if err != nil {
+		if req.HasETag() && isETagConflictError(err) {
+			return state.NewETagError(state.ETagMismatch, err)
+		}
+
+		return fmt.Errorf("error uploading blob: %w", err)
+	}
+
+	return nil
+}
+
 func (r *StateStore) Ping(ctx context.Context) error {
 	if _, err := r.containerClient.GetProperties(ctx, nil); err != nil {
 		return fmt.Errorf("error connecting to Azure Blob Storage at '%s': %w", r.containerClient.URL(), err)
@@ -124,26 +175,15 @@ func (r *StateStore) Close() error {
 }
 
 func (r *StateStore) writeFile(ctx context.Context, req *state.SetRequest) error {
-	modifiedAccessConditions := blob.ModifiedAccessConditions{}
-
-	if req.HasETag() {
-		modifiedAccessConditions.IfMatch = ptr.Of(azcore.ETag(*req.ETag))
-	}
-	if req.Options.Concurrency == state.FirstWrite && !req.HasETag() {
-		modifiedAccessConditions.IfNoneMatch = ptr.Of(azcore.ETagAny)
-	}
-
 	blobHTTPHeaders, err := blobstoragecommon.CreateBlobHTTPHeadersFromRequest(req.Metadata, req.ContentType, r.logger)
 	if err != nil {
 		return err
 	}
 
 	uploadOptions := azblob.UploadBufferOptions{
-		AccessConditions: &blob.AccessConditions{
-			ModifiedAccessConditions: &modifiedAccessConditions,
-		},
-		Metadata:    blobstoragecommon.SanitizeMetadata(r.logger, req.Metadata),
-		HTTPHeaders: &blobHTTPHeaders,
+		AccessConditions: accessConditions(req.ETag, req.Options.Concurrency),
+		Metadata:         blobstoragecommon.SanitizeMetadata(r.logger, req.Metadata),
+		HTTPHeaders:      &blobHTTPHeaders,
 	}
 
 	blockBlobClient := r.containerClient.NewBlockBlobClient(r.getFileNameFn(req.Key))
@@ -190,6 +230,23 @@ func (r *StateStore) deleteFile(ctx context.Context, req *state.DeleteRequest) e
 	return nil
 }
 
+// accessConditions returns the conditions for uploading a blob, based on the etag and concurrency mode of the request.
+func accessConditions(etag *string, concurrency string) *blob.AccessConditions {
+	modifiedAccessConditions := blob.ModifiedAccessConditions{}
+
+	hasETag := etag != nil && *etag != ""
+	if hasETag {
+		modifiedAccessConditions.IfMatch = ptr.Of(azcore.ETag(*etag))
+	}
+	if concurrency == state.FirstWrite && !hasETag {
+		modifiedAccessConditions.IfNoneMatch = ptr.Of(azcore.ETagAny)
+	}
+
+	return &blob.AccessConditions{
+		ModifiedAccessConditions: &modifiedAccessConditions,
+	}
+}
+
 func (r *StateStore) marshal(req *state.SetRequest) []byte {
 	b, ok := req.Value.([]byte)
 	if !ok {
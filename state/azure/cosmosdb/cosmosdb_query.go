@@ -17,7 +17,9 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
@@ -38,6 +40,13 @@ type Query struct {
 	limit        int
 	token        string
 	partitionKey string
+
+	// Set when the query has aggregations, to rename the fields of the results.
+	groupBy []string
+	aliases []string
+
+	// Set when the query selects fields, which are returned as "f0", "f1", ...
+	fields []string
 }
 
 func (q *Query) VisitEQ(f *query.EQ) (string, error) {
@@ -226,13 +235,110 @@ func (q *Query) Finalize(filters string, qq *query.Query) error {
 		orderBy = " ORDER BY " + strings.Join(order, ", ")
 	}
 
-	q.query.query = "SELECT * FROM c" + filter + orderBy
+	selection := "*"
+	if len(qq.Fields) > 0 {
+		q.fields = qq.Fields
+		sel := make([]string, len(qq.Fields)+3)
+		sel[0], sel[1], sel[2] = "c.id", "c._etag", "c.isBinary"
+		for i, key := range qq.Fields {
+			sel[i+3] = replaceKeywords("c.value."+key) + " AS f" + strconv.Itoa(i)
+		}
+		selection = strings.Join(sel, ", ")
+	}
+
+	q.query.query = "SELECT " + selection + " FROM c" + filter + orderBy
+	q.limit = qq.Page.Limit
+	q.token = qq.Page.Token
+
+	return nil
+}
+
+func (q *Query) VisitCOUNT(*query.COUNT) (string, error) {
+	return "COUNT(1)", nil
+}
+
+func (q *Query) VisitSUM(a *query.SUM) (string, error) {
+	return "SUM(" + replaceKeywords("c.value."+a.Key) + ")", nil
+}
+
+func (q *Query) VisitMIN(a *query.MIN) (string, error) {
+	return "MIN(" + replaceKeywords("c.value."+a.Key) + ")", nil
+}
+
+func (q *Query) VisitMAX(a *query.MAX) (string, error) {
+	return "MAX(" + replaceKeywords("c.value."+a.Key) + ")", nil
+}
+
+// FinalizeAggregate builds a GROUP BY query.
+// Fields are selected as "g0", "g1", ... for the group-by keys and "a0", "a1", ... for the aggregations, so they never clash with reserved keywords, and are renamed when the results are returned.
+func (q *Query) FinalizeAggregate(filters string, aggregations []string, qq *query.Query) error {
+	// Cosmos DB does not support ORDER BY in queries with GROUP BY
+	if len(qq.Sort) != 0 {
+		return errors.New("sorting is not supported in queries with aggregations")
+	}
+
+	var filter, groupBy string
+	if len(filters) != 0 {
+		filter = " WHERE " + filters
+	}
+
+	fields := make([]string, 0, len(qq.Aggregate.GroupBy)+len(aggregations))
+	q.groupBy = qq.Aggregate.GroupBy
+	if sz := len(q.groupBy); sz != 0 {
+		group := make([]string, sz)
+		for i, key := range q.groupBy {
+			group[i] = replaceKeywords("c.value." + key)
+			fields = append(fields, group[i]+" AS g"+strconv.Itoa(i))
+		}
+		groupBy = " GROUP BY " + strings.Join(group, ", ")
+	}
+	q.aliases = make([]string, len(aggregations))
+	for i, a := range qq.Aggregate.Aggregations {
+		fields = append(fields, aggregations[i]+" AS a"+strconv.Itoa(i))
+		q.aliases[i] = a.GetAlias()
+	}
+
+	q.query.query = "SELECT " + strings.Join(fields, ", ") + " FROM c" + filter + groupBy
 	q.limit = qq.Page.Limit
 	q.token = qq.Page.Token
 
 	return nil
 }
 
+// aggregateResult renames the fields of a result of a query with aggregations to the group-by keys and the aliases.
+func (q *Query) aggregateResult(item []byte) ([]byte, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(item, &fields); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]any, len(q.groupBy)+len(q.aliases))
+	for i, key := range q.groupBy {
+		res[key] = fields["g"+strconv.Itoa(i)]
+	}
+	for i, alias := range q.aliases {
+		res[alias] = fields["a"+strconv.Itoa(i)]
+	}
+	return json.Marshal(res)
+}
+
+// projectedValue returns the value with the selected fields, from the fields returned by the query.
+// Fields that are missing from the value are not returned by Cosmos DB.
+func (q *Query) projectedValue(item []byte) (map[string]any, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(item, &fields); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]any, len(q.fields))
+	for i, key := range q.fields {
+		if v, ok := fields["f"+strconv.Itoa(i)]; ok {
+			query.SetField(res, key, v)
+		}
+	}
+	return res, nil
+}
+
 func (q *Query) setNextParameter(val string) string {
 	pname := fmt.Sprintf("@__param__%d__", len(q.query.parameters))
 	q.query.parameters = append(q.query.parameters, azcosmos.QueryParameter{Name: pname, Value: val})
@@ -276,6 +382,7 @@ func (q *Query) execute(ctx context.Context, client *azcosmos.ContainerClient) (
 	queryPager := client.NewQueryItemsPager(q.query.query, pk, opts)
 
 	token := ""
+	aggregated := []state.QueryItem{}
 	for queryPager.More() {
 		ctxWithTimeout, cancel := context.WithTimeout(ctx, defaultTimeout)
 		queryResponse, innerErr := queryPager.NextPage(ctxWithTimeout)
@@ -290,21 +397,45 @@ func (q *Query) execute(ctx context.Context, client *azcosmos.ContainerClient) (
 			token = *queryResponse.ContinuationToken
 		}
 		for _, item := range queryResponse.Items {
+			if q.aliases != nil {
+				// Results of aggregations have no key and no etag
+				if (resultLimit != 0) && (len(aggregated) >= resultLimit) {
+					break
+				}
+				result := state.QueryItem{}
+				data, err := q.aggregateResult(item)
+				if err != nil {
+					result.Error = err.Error()
+				}
+				result.Data = data
+				aggregated = append(aggregated, result)
+				continue
+			}
 			tempItem := CosmosItem{}
 			err := json.Unmarshal(item, &tempItem)
 			if err != nil {
 				return nil, "", err
 			}
+			if len(q.fields) > 0 && !tempItem.IsBinary {
+				tempItem.Value, err = q.projectedValue(item)
+				if err != nil {
+					return nil, "", err
+				}
+			}
 			if (resultLimit != 0) && (len(items) >= resultLimit) {
 				break
 			}
 			items = append(items, tempItem)
 		}
-		if (resultLimit != 0) && (len(items) >= resultLimit) {
+		if (resultLimit != 0) && (len(items)+len(aggregated) >= resultLimit) {
 			break
 		}
 	}
 
+	if q.aliases != nil {
+		return aggregated, token, nil
+	}
+
 	ret := make([]state.QueryItem, len(items))
 	var err error
 	for i := range items {
@@ -312,6 +443,10 @@ func (q *Query) execute(ctx context.Context, client *azcosmos.ContainerClient) (
 		ret[i].ETag = &items[i].Etag
 
 		if items[i].IsBinary {
+			if len(q.fields) > 0 {
+				ret[i].Error = "only JSON objects can be projected"
+				continue
+			}
 			ret[i].Data, _ = base64.StdEncoding.DecodeString(items[i].Value.(string))
 			continue
 		}
@@ -189,3 +189,55 @@ func TestCosmosDbQuery(t *testing.T) {
 		assert.Equal(t, test.query, q.query)
 	}
 }
+
+func TestCosmosDbQueryAggregate(t *testing.T) {
+	data, err := os.ReadFile("../../../tests/state/query/q9-aggregate.json")
+	require.NoError(t, err)
+	var qq query.Query
+	err = json.Unmarshal(data, &qq)
+	require.NoError(t, err)
+
+	t.Run("sorting is not supported", func(t *testing.T) {
+		q := &Query{}
+		err = query.NewQueryBuilder(q).BuildQuery(&qq)
+		require.Error(t, err)
+	})
+
+	t.Run("group by", func(t *testing.T) {
+		qq.Sort = nil
+		q := &Query{}
+		err = query.NewQueryBuilder(q).BuildQuery(&qq)
+		require.NoError(t, err)
+		assert.Equal(t, InternalQuery{
+			query: "SELECT c['value']['status'] AS g0, c['value']['person']['org'] AS g1, COUNT(1) AS a0, SUM(c['value']['amount']) AS a1, MIN(c['value']['amount']) AS a2, MAX(c['value']['amount']) AS a3 FROM c WHERE c['value']['status'] != @__param__0__ GROUP BY c['value']['status'], c['value']['person']['org']",
+			parameters: []azcosmos.QueryParameter{
+				{
+					Name:  "@__param__0__",
+					Value: "CANCELED",
+				},
+			},
+		}, q.query)
+		assert.Equal(t, 2, q.limit)
+
+		res, err := q.aggregateResult([]byte(`{"g0":"OPEN","g1":"A","a0":2,"a1":30.5,"a2":10,"a3":20.5}`))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"status":"OPEN","person.org":"A","orders":2,"total":30.5,"smallest":10,"largest":20.5}`, string(res))
+	})
+}
+
+func TestCosmosDbQueryFields(t *testing.T) {
+	data, err := os.ReadFile("../../../tests/state/query/q10-fields.json")
+	require.NoError(t, err)
+	var qq query.Query
+	err = json.Unmarshal(data, &qq)
+	require.NoError(t, err)
+
+	q := &Query{}
+	err = query.NewQueryBuilder(q).BuildQuery(&qq)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT c.id, c._etag, c.isBinary, c['value']['person']['name'] AS f0, c['value']['state'] AS f1 FROM c WHERE c['value']['state'] = @__param__0__ ORDER BY c['value']['person']['name'] ASC", q.query.query)
+
+	res, err := q.projectedValue([]byte(`{"id":"1","_etag":"abc","isBinary":false,"f0":"Adam"}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"person": map[string]any{"name": "Adam"}}, res)
+}
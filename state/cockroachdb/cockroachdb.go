@@ -19,6 +19,7 @@ import (
 
 	pginterfaces "github.com/dapr/components-contrib/common/component/postgresql/interfaces"
 	postgresql "github.com/dapr/components-contrib/common/component/postgresql/v1"
+	pgmigrations "github.com/dapr/components-contrib/common/component/sql/migrations/postgres"
 	"github.com/dapr/components-contrib/state"
 	"github.com/dapr/kit/logger"
 )
@@ -167,6 +168,18 @@ value text NOT NULL
 		}
 	}
 
+	// Indexes of indexed fields
+	m := pgmigrations.Migrations{
+		DB:             db,
+		Logger:         opts.Logger,
+		StateTableName: opts.StateTableName,
+		IndexedFields:  opts.IndexedFields,
+	}
+	err = m.EnsureIndexedFields(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update indexed fields: %w", err)
+	}
+
 	return nil
 }
 
@@ -30,6 +30,9 @@ const (
 
 var ErrKeysLikeEmptyPattern = errors.New("keys like pattern cannot be empty")
 
+// ErrConditionNotMet is returned when a transaction is aborted because a condition is not met.
+var ErrConditionNotMet = errors.New("transaction condition not met")
+
 // ETagError is a custom error type for etag exceptions.
 type ETagError struct {
 	err  error
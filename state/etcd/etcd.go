@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -45,6 +46,7 @@ type Etcd struct {
 	logger        logger.Logger
 	schema        schemaMarshaller
 	maxTxnOps     int
+	wg            sync.WaitGroup
 }
 
 type etcdConfig struct {
@@ -78,6 +80,7 @@ func newETCD(logger logger.Logger, schema schemaMarshaller) state.Store {
 			state.FeatureTransactional,
 			state.FeatureTTL,
 			state.FeatureKeysLike,
+			state.FeatureWatch,
 		},
 	}
 	s.BulkStore = state.NewDefaultBulkStore(s)
@@ -307,6 +310,93 @@ func (e *Etcd) doValidateEtag(key string, etag *string, concurrency string) erro
 	return nil
 }
 
+// Watch invokes the handler for every change to the keys selected by the request, until ctx is canceled.
+func (e *Etcd) Watch(ctx context.Context, req *state.WatchRequest, handler state.WatchHandler) error {
+	if req == nil {
+		return errors.New("request object is nil")
+	}
+	if handler == nil {
+		return errors.New("handler is nil")
+	}
+
+	// Watch a single key directly, otherwise watch the whole prefix and filter the events
+	watchReq := *req
+	keyPrefix := e.keyPrefixPath + "/"
+	opts := []clientv3.OpOption{clientv3.WithCreatedNotify()}
+	var watchKey string
+	if len(watchReq.Keys) == 1 {
+		watchKey = keyPrefix + watchReq.Keys[0]
+	} else {
+		watchKey = keyPrefix + watchReq.Prefix
+		opts = append(opts, clientv3.WithPrefix())
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	watchCh := e.client.Watch(watchCtx, watchKey, opts...)
+
+	// Wait for the watch to be created
+	select {
+	case res, ok := <-watchCh:
+		if !ok {
+			cancel()
+			return errors.New("watch was closed before being created")
+		}
+		if err := res.Err(); err != nil {
+			cancel()
+			return fmt.Errorf("couldn't watch key %s: %w", watchKey, err)
+		}
+	case <-ctx.Done():
+		cancel()
+		return ctx.Err()
+	}
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		defer cancel()
+
+		// The channel is closed when the context is canceled or the client is closed
+		for res := range watchCh {
+			if err := res.Err(); err != nil {
+				e.logger.Errorf("Error watching key %s: %v", watchKey, err)
+				continue
+			}
+
+			for _, ev := range res.Events {
+				key := strings.TrimPrefix(string(ev.Kv.Key), keyPrefix)
+				if !watchReq.Matches(key) {
+					continue
+				}
+
+				we := &state.WatchEvent{
+					Key: key,
+				}
+				switch ev.Type {
+				case clientv3.EventTypePut:
+					data, metadata, err := e.schema.decode(ev.Kv.Value)
+					if err != nil {
+						e.logger.Errorf("Error decoding value of key %s: %v", key, err)
+						continue
+					}
+					we.Operation = state.OperationUpsert
+					we.Data = data
+					we.ETag = ptr.Of(strconv.FormatInt(ev.Kv.ModRevision, 10))
+					we.Metadata = metadata
+				case clientv3.EventTypeDelete:
+					we.Operation = state.OperationDelete
+				}
+
+				err := handler(ctx, we)
+				if err != nil {
+					e.logger.Errorf("Error invoking watch handler for key %s: %v", key, err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
 func (e *Etcd) GetComponentMetadata() (metadataInfo metadata.MetadataMap) {
 	metadataStruct := etcdConfig{}
 	metadata.GetMetadataInfoFromStructType(reflect.TypeOf(metadataStruct), &metadataInfo, metadata.StateStoreType)
@@ -318,6 +408,7 @@ func (e *Etcd) Close() error {
 		return nil
 	}
 
+	defer e.wg.Wait()
 	return e.client.Close()
 }
 
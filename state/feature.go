@@ -32,6 +32,16 @@ const (
 	FeaturePartitionKey Feature = "PARTITION_KEY"
 	// FeatureKeysLike is the feature that supports keys like list operation.
 	FeatureKeysLike Feature = "KEYS_LIKE"
+	// FeatureStreaming is the feature that supports reading and writing values as streams.
+	FeatureStreaming Feature = "STREAMING"
+	// FeatureWatch is the feature that supports watching keys for changes.
+	FeatureWatch Feature = "WATCH"
+	// FeatureListKeys is the feature that supports listing all keys with pagination.
+	FeatureListKeys Feature = "LIST_KEYS"
+	// FeatureAtomicIncrement is the feature that supports incrementing counters atomically.
+	FeatureAtomicIncrement Feature = "ATOMIC_INCREMENT"
+	// FeatureTransactionConditions is the feature that supports conditions in transactions.
+	FeatureTransactionConditions Feature = "TRANSACTION_CONDITIONS"
 )
 
 // Feature names a feature that can be implemented by state store components.
@@ -31,6 +31,7 @@ import (
 
 	"github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/components-contrib/state/query"
 	"github.com/dapr/components-contrib/state/utils"
 	"github.com/dapr/kit/logger"
 	"github.com/dapr/kit/ptr"
@@ -48,6 +49,11 @@ type InMemoryStore struct {
 	closeCh chan struct{}
 	closed  atomic.Bool
 	wg      sync.WaitGroup
+
+	watchers   map[uint64]*inMemWatcher
+	watcherIdx uint64
+	watchLock  sync.Mutex
+	watchWg    sync.WaitGroup
 }
 
 func NewInMemoryStateStore(log logger.Logger) state.Store {
@@ -56,10 +62,11 @@ func NewInMemoryStateStore(log logger.Logger) state.Store {
 
 func newStateStore(log logger.Logger) *InMemoryStore {
 	s := &InMemoryStore{
-		items:   map[string]*inMemStateStoreItem{},
-		log:     log,
-		closeCh: make(chan struct{}),
-		clock:   clock.RealClock{},
+		items:    map[string]*inMemStateStoreItem{},
+		log:      log,
+		closeCh:  make(chan struct{}),
+		clock:    clock.RealClock{},
+		watchers: map[uint64]*inMemWatcher{},
 	}
 	s.BulkStore = state.NewDefaultBulkStore(s)
 	return s
@@ -80,6 +87,9 @@ func (store *InMemoryStore) Close() error {
 		close(store.closeCh)
 	}
 
+	// wait for watchers to stop before acquiring the lock, as their handlers may be using the store
+	store.watchWg.Wait()
+
 	// release memory reference
 	store.lock.Lock()
 	defer store.lock.Unlock()
@@ -99,6 +109,11 @@ func (store *InMemoryStore) Features() []state.Feature {
 		state.FeatureTTL,
 		state.FeatureDeleteWithPrefix,
 		state.FeatureKeysLike,
+		state.FeatureWatch,
+		state.FeatureListKeys,
+		state.FeatureAtomicIncrement,
+		state.FeatureTransactionConditions,
+		state.FeatureQueryAPI,
 	}
 }
 
@@ -143,7 +158,7 @@ func (store *InMemoryStore) DeleteWithPrefix(ctx context.Context, req state.Dele
 			// The string contains the prefix, now we check to make sure there aren't more || after
 			longerPrefix := strings.Contains(key[len(req.Prefix):], "||")
 			if !longerPrefix {
-				delete(store.items, key)
+				store.doDelete(ctx, key)
 				count++
 			}
 		}
@@ -179,7 +194,15 @@ func (store *InMemoryStore) doValidateEtag(key string, etag *string, concurrency
 }
 
 func (store *InMemoryStore) doDelete(ctx context.Context, key string) {
+	if _, ok := store.items[key]; !ok {
+		return
+	}
+
 	delete(store.items, key)
+	store.notifyWatchers(&state.WatchEvent{
+		Key:       key,
+		Operation: state.OperationDelete,
+	})
 }
 
 func (store *InMemoryStore) Get(ctx context.Context, req *state.GetRequest) (*state.GetResponse, error) {
@@ -247,7 +270,7 @@ func (store *InMemoryStore) getAndExpire(key string) *inMemStateStoreItem {
 		return nil
 	}
 	if item.isExpired(store.clock.Now()) {
-		delete(store.items, key)
+		store.doDelete(context.Background(), key)
 		return nil
 	}
 	return item
@@ -327,20 +350,38 @@ func doParseTTLInSeconds(metadata map[string]string) (int, error) {
 }
 
 func (store *InMemoryStore) doSet(ctx context.Context, key string, data []byte, ttlInSeconds int) {
+	var expire *time.Time
+	if ttlInSeconds > 0 {
+		expire = ptr.Of(store.clock.Now().Add(time.Duration(ttlInSeconds) * time.Second))
+	}
+	store.doSetWithExpire(ctx, key, data, expire)
+}
+
+func (store *InMemoryStore) doSetWithExpire(ctx context.Context, key string, data []byte, expire *time.Time) {
 	etag := uuid.New().String()
 	el := &inMemStateStoreItem{
-		data: data,
-		etag: &etag,
-		idx:  store.idx,
+		data:   data,
+		etag:   &etag,
+		idx:    store.idx,
+		expire: expire,
 	}
 
 	store.idx++
 
-	if ttlInSeconds > 0 {
-		el.expire = ptr.Of(store.clock.Now().Add(time.Duration(ttlInSeconds) * time.Second))
-	}
-
 	store.items[key] = el
+
+	e := &state.WatchEvent{
+		Key:       key,
+		Operation: state.OperationUpsert,
+		Data:      data,
+		ETag:      el.etag,
+	}
+	if el.expire != nil {
+		e.Metadata = map[string]string{
+			state.GetRespMetaKeyTTLExpireTime: el.expire.UTC().Format(time.RFC3339),
+		}
+	}
+	store.notifyWatchers(e)
 }
 
 // innerSetRequest is only used to pass ttlInSeconds and data with SetRequest.
@@ -364,6 +405,71 @@ func (r innerSetRequest) GetMetadata() map[string]string {
 	return r.req.Metadata
 }
 
+type innerIncrementRequest struct {
+	req  state.IncrementRequest
+	data []byte
+}
+
+// Implements state.TransactionalStateOperation
+func (innerIncrementRequest) Operation() state.OperationType {
+	return "_internal"
+}
+
+// Implements state.StateRequest
+func (r innerIncrementRequest) GetKey() string {
+	return r.req.Key
+}
+
+func (r innerIncrementRequest) GetMetadata() map[string]string {
+	return r.req.Metadata
+}
+
+// Increment adds delta to the counter stored at key, preserving its TTL.
+func (store *InMemoryStore) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	if key == "" {
+		return 0, errors.New("missing key in increment operation")
+	}
+
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	var current []byte
+	item := store.getAndExpire(key)
+	if item != nil {
+		current = item.data
+	}
+	n, err := incrementCounter(key, current, delta)
+	if err != nil {
+		return 0, err
+	}
+
+	store.doSetCounter(ctx, key, []byte(strconv.FormatInt(n, 10)))
+	return n, nil
+}
+
+// doSetCounter stores the new value of a counter, preserving its TTL.
+// This must be invoked while holding the store's write lock.
+func (store *InMemoryStore) doSetCounter(ctx context.Context, key string, data []byte) {
+	var expire *time.Time
+	if item := store.getAndExpire(key); item != nil {
+		expire = item.expire
+	}
+	store.doSetWithExpire(ctx, key, data, expire)
+}
+
+// incrementCounter returns the value of the counter stored in data after adding delta.
+func incrementCounter(key string, data []byte, delta int64) (int64, error) {
+	var n int64
+	if len(data) > 0 {
+		var err error
+		n, err = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("value of key %s is not an integer", key)
+		}
+	}
+	return n + delta, nil
+}
+
 func (store *InMemoryStore) Multi(ctx context.Context, request *state.TransactionalStateRequest) error {
 	if len(request.Operations) == 0 {
 		return nil
@@ -393,6 +499,16 @@ func (store *InMemoryStore) Multi(ctx context.Context, request *state.Transactio
 			if err != nil {
 				return err
 			}
+		case state.IncrementRequest:
+			if req.Key == "" {
+				return errors.New("missing key in increment operation")
+			}
+			request.Operations[i] = &innerIncrementRequest{req: req}
+		case state.ConditionRequest:
+			err := req.Validate()
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -400,7 +516,20 @@ func (store *InMemoryStore) Multi(ctx context.Context, request *state.Transactio
 	store.lock.Lock()
 	defer store.lock.Unlock()
 
-	// step2: validate etag if needed
+	// step2: validate etag and conditions if needed, and compute the new value of counters
+	// pending contains the values of the keys changed by the previous operations in the transaction, with nil for deleted keys
+	pending := map[string][]byte{}
+	current := func(key string) (data []byte, etag *string, exists bool) {
+		if data, ok := pending[key]; ok {
+			// ETags of keys changed in this transaction are not known yet
+			return data, nil, data != nil
+		}
+		item := store.items[key]
+		if item == nil || item.isExpired(store.clock.Now()) {
+			return nil, nil, false
+		}
+		return item.data, item.etag, true
+	}
 	for _, o := range request.Operations {
 		switch req := o.(type) {
 		case *innerSetRequest:
@@ -408,11 +537,27 @@ func (store *InMemoryStore) Multi(ctx context.Context, request *state.Transactio
 			if err != nil {
 				return err
 			}
+			pending[req.req.Key] = req.data
 		case state.DeleteRequest:
 			err := store.doValidateEtag(req.Key, req.ETag, req.Options.Concurrency)
 			if err != nil {
 				return err
 			}
+			pending[req.Key] = nil
+		case *innerIncrementRequest:
+			data, _, _ := current(req.req.Key)
+			n, err := incrementCounter(req.req.Key, data, req.req.Delta)
+			if err != nil {
+				return err
+			}
+			req.data = []byte(strconv.FormatInt(n, 10))
+			pending[req.req.Key] = req.data
+		case state.ConditionRequest:
+			_, etag, exists := current(req.Key)
+			err := req.Check(exists, etag)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -424,6 +569,8 @@ func (store *InMemoryStore) Multi(ctx context.Context, request *state.Transactio
 			store.doSet(ctx, req.req.Key, req.data, req.ttl)
 		case state.DeleteRequest:
 			store.doDelete(ctx, req.Key)
+		case *innerIncrementRequest:
+			store.doSetCounter(ctx, req.req.Key, req.data)
 		}
 	}
 	return nil
@@ -542,6 +689,91 @@ func (store *InMemoryStore) KeysLike(ctx context.Context, req *state.KeysLikeReq
 	}, nil
 }
 
+// ListKeys returns the keys in the store in lexicographic order, one page at a time.
+// The continuation token is the last key returned, so keys written during the iteration don't affect the keys that are returned next.
+func (store *InMemoryStore) ListKeys(ctx context.Context, req *state.ListKeysRequest) (*state.ListKeysResponse, error) {
+	if req == nil {
+		return nil, errors.New("request object is nil")
+	}
+
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	now := store.clock.Now()
+	keys := make([]string, 0, len(store.items))
+	for k, item := range store.items {
+		if !strings.HasPrefix(k, req.Prefix) || item.isExpired(now) {
+			continue
+		}
+		if req.ContinuationToken != nil && k <= *req.ContinuationToken {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := &state.ListKeysResponse{
+		Keys: keys,
+	}
+	if req.PageSize != nil && *req.PageSize > 0 && len(keys) > int(*req.PageSize) {
+		res.Keys = keys[:*req.PageSize]
+		res.ContinuationToken = ptr.Of(res.Keys[len(res.Keys)-1])
+	}
+
+	return res, nil
+}
+
+// Query executes a query against the values in the store that are JSON objects, evaluating it in-process.
+func (store *InMemoryStore) Query(ctx context.Context, req *state.QueryRequest) (*state.QueryResponse, error) {
+	if req == nil {
+		return nil, errors.New("request object is nil")
+	}
+
+	store.lock.RLock()
+	now := store.clock.Now()
+	items := make([]query.EvaluateItem[state.QueryItem], 0, len(store.items))
+	for k, item := range store.items {
+		if item.isExpired(now) {
+			continue
+		}
+		var value map[string]any
+		if json.Unmarshal(item.data, &value) != nil {
+			// Values that are not JSON objects cannot be queried
+			continue
+		}
+		items = append(items, query.EvaluateItem[state.QueryItem]{
+			Key:   k,
+			Value: value,
+			Data: state.QueryItem{
+				Key:  k,
+				Data: item.data,
+				ETag: item.etag,
+			},
+		})
+	}
+	store.lock.RUnlock()
+
+	results, token, err := query.Evaluate(&req.Query, items)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Query.Fields) > 0 {
+		for i := range results {
+			projected, perr := query.ProjectJSON(results[i].Data, req.Query.Fields)
+			if perr != nil {
+				results[i].Error = perr.Error()
+				continue
+			}
+			results[i].Data = projected
+		}
+	}
+
+	return &state.QueryResponse{
+		Results: results,
+		Token:   token,
+	}, nil
+}
+
 func likeToRegex(pattern string) (*regexp.Regexp, error) {
 	var b strings.Builder
 	b.Grow(len(pattern) + 4)
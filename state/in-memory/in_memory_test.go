@@ -14,6 +14,8 @@ limitations under the License.
 package inmemory
 
 import (
+	"context"
+	"encoding/json"
 	"sort"
 	"testing"
 	"time"
@@ -25,6 +27,7 @@ import (
 
 	"github.com/dapr/components-contrib/state"
 	"github.com/dapr/kit/logger"
+	"github.com/dapr/kit/ptr"
 )
 
 func TestReadAndWrite(t *testing.T) {
@@ -181,3 +184,378 @@ func TestReadAndWrite(t *testing.T) {
 func Test_KeyLike(t *testing.T) {
 	var _ state.KeysLiker = NewInMemoryStateStore(nil).(*InMemoryStore)
 }
+
+func TestWatch(t *testing.T) {
+	store := NewInMemoryStateStore(logger.NewLogger("test")).(*InMemoryStore)
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	store.clock = fakeClock
+	require.NoError(t, store.Init(t.Context(), state.Metadata{}))
+	t.Cleanup(func() {
+		require.NoError(t, store.Close())
+	})
+
+	watch := func(t *testing.T, req *state.WatchRequest) <-chan *state.WatchEvent {
+		t.Helper()
+		ch := make(chan *state.WatchEvent, 10)
+		err := store.Watch(t.Context(), req, func(_ context.Context, e *state.WatchEvent) error {
+			ch <- e
+			return nil
+		})
+		require.NoError(t, err)
+		return ch
+	}
+
+	receive := func(t *testing.T, ch <-chan *state.WatchEvent) *state.WatchEvent {
+		t.Helper()
+		select {
+		case e := <-ch:
+			return e
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timed out waiting for watch event")
+			return nil
+		}
+	}
+
+	assertNoEvent := func(t *testing.T, ch <-chan *state.WatchEvent) {
+		t.Helper()
+		select {
+		case e := <-ch:
+			require.Failf(t, "unexpected watch event", "key: %s", e.Key)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	t.Run("watch keys", func(t *testing.T) {
+		ch := watch(t, &state.WatchRequest{Keys: []string{"watched"}})
+
+		require.NoError(t, store.Set(t.Context(), &state.SetRequest{Key: "not-watched", Value: "a"}))
+		require.NoError(t, store.Set(t.Context(), &state.SetRequest{Key: "watched", Value: "b"}))
+
+		e := receive(t, ch)
+		assert.Equal(t, "watched", e.Key)
+		assert.Equal(t, state.OperationUpsert, e.Operation)
+		assert.Equal(t, `"b"`, string(e.Data))
+		require.NotNil(t, e.ETag)
+
+		res, err := store.Get(t.Context(), &state.GetRequest{Key: "watched"})
+		require.NoError(t, err)
+		assert.Equal(t, *res.ETag, *e.ETag)
+
+		require.NoError(t, store.Delete(t.Context(), &state.DeleteRequest{Key: "watched"}))
+		e = receive(t, ch)
+		assert.Equal(t, "watched", e.Key)
+		assert.Equal(t, state.OperationDelete, e.Operation)
+		assert.Nil(t, e.Data)
+
+		// Deleting a key that doesn't exist does not trigger an event
+		require.NoError(t, store.Delete(t.Context(), &state.DeleteRequest{Key: "watched"}))
+		assertNoEvent(t, ch)
+	})
+
+	t.Run("watch prefix", func(t *testing.T) {
+		ch := watch(t, &state.WatchRequest{Prefix: "app||"})
+
+		require.NoError(t, store.Multi(t.Context(), &state.TransactionalStateRequest{
+			Operations: []state.TransactionalStateOperation{
+				state.SetRequest{Key: "app||1", Value: "1"},
+				state.SetRequest{Key: "other||1", Value: "1"},
+				state.SetRequest{Key: "app||2", Value: "2"},
+			},
+		}))
+
+		assert.Equal(t, "app||1", receive(t, ch).Key)
+		assert.Equal(t, "app||2", receive(t, ch).Key)
+		assertNoEvent(t, ch)
+	})
+
+	t.Run("expired keys are reported as deleted", func(t *testing.T) {
+		ch := watch(t, &state.WatchRequest{Keys: []string{"ttl"}})
+
+		require.NoError(t, store.Set(t.Context(), &state.SetRequest{
+			Key:      "ttl",
+			Value:    "a",
+			Metadata: map[string]string{"ttlInSeconds": "1"},
+		}))
+		e := receive(t, ch)
+		assert.Equal(t, state.OperationUpsert, e.Operation)
+		assert.NotEmpty(t, e.Metadata[state.GetRespMetaKeyTTLExpireTime])
+
+		fakeClock.Step(2 * time.Second)
+		res, err := store.Get(t.Context(), &state.GetRequest{Key: "ttl"})
+		require.NoError(t, err)
+		assert.Nil(t, res.Data)
+
+		e = receive(t, ch)
+		assert.Equal(t, "ttl", e.Key)
+		assert.Equal(t, state.OperationDelete, e.Operation)
+	})
+
+	t.Run("canceling the context stops the watch", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		ch := make(chan *state.WatchEvent, 10)
+		err := store.Watch(ctx, &state.WatchRequest{Keys: []string{"cancel"}}, func(_ context.Context, e *state.WatchEvent) error {
+			ch <- e
+			return nil
+		})
+		require.NoError(t, err)
+
+		cancel()
+		assert.Eventually(t, func() bool {
+			store.watchLock.Lock()
+			defer store.watchLock.Unlock()
+			for _, w := range store.watchers {
+				if w.req.Matches("cancel") {
+					return false
+				}
+			}
+			return true
+		}, 5*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, store.Set(t.Context(), &state.SetRequest{Key: "cancel", Value: "a"}))
+		assertNoEvent(t, ch)
+	})
+
+	t.Run("handler can use the store", func(t *testing.T) {
+		done := make(chan struct{})
+		err := store.Watch(t.Context(), &state.WatchRequest{Keys: []string{"source"}}, func(ctx context.Context, e *state.WatchEvent) error {
+			defer close(done)
+			return store.Set(ctx, &state.SetRequest{Key: "copy", Value: e.Data})
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, store.Set(t.Context(), &state.SetRequest{Key: "source", Value: "a"}))
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timed out waiting for watch handler")
+		}
+
+		res, err := store.Get(t.Context(), &state.GetRequest{Key: "copy"})
+		require.NoError(t, err)
+		assert.Equal(t, `"a"`, string(res.Data))
+	})
+}
+
+func TestListKeys(t *testing.T) {
+	store := NewInMemoryStateStore(logger.NewLogger("test")).(*InMemoryStore)
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	store.clock = fakeClock
+
+	for _, k := range []string{"app||c", "app||a", "app||b", "other||a"} {
+		require.NoError(t, store.Set(t.Context(), &state.SetRequest{Key: k, Value: "v"}))
+	}
+	require.NoError(t, store.Set(t.Context(), &state.SetRequest{
+		Key:      "app||expired",
+		Value:    "v",
+		Metadata: map[string]string{"ttlInSeconds": "1"},
+	}))
+	fakeClock.Step(2 * time.Second)
+
+	t.Run("all keys", func(t *testing.T) {
+		res, err := store.ListKeys(t.Context(), &state.ListKeysRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"app||a", "app||b", "app||c", "other||a"}, res.Keys)
+		assert.Nil(t, res.ContinuationToken)
+	})
+
+	t.Run("pages with prefix", func(t *testing.T) {
+		res, err := store.ListKeys(t.Context(), &state.ListKeysRequest{Prefix: "app||", PageSize: ptr.Of(uint32(2))})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"app||a", "app||b"}, res.Keys)
+		require.NotNil(t, res.ContinuationToken)
+
+		// Keys added before the token are not returned
+		require.NoError(t, store.Set(t.Context(), &state.SetRequest{Key: "app||0", Value: "v"}))
+
+		res, err = store.ListKeys(t.Context(), &state.ListKeysRequest{Prefix: "app||", PageSize: ptr.Of(uint32(2)), ContinuationToken: res.ContinuationToken})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"app||c"}, res.Keys)
+		assert.Nil(t, res.ContinuationToken)
+	})
+}
+
+func TestQuery(t *testing.T) {
+	store := NewInMemoryStateStore(logger.NewLogger("test")).(*InMemoryStore)
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	store.clock = fakeClock
+
+	for k, v := range map[string]any{
+		"a": map[string]any{"color": "red", "size": 3},
+		"b": map[string]any{"color": "blue", "size": 1},
+		"c": map[string]any{"color": "red", "size": 2},
+		"d": "not an object",
+		"e": []byte{0x1, 0x2},
+	} {
+		require.NoError(t, store.Set(t.Context(), &state.SetRequest{Key: k, Value: v}))
+	}
+	require.NoError(t, store.Set(t.Context(), &state.SetRequest{
+		Key:      "expired",
+		Value:    map[string]any{"color": "red", "size": 0},
+		Metadata: map[string]string{"ttlInSeconds": "1"},
+	}))
+	fakeClock.Step(2 * time.Second)
+
+	var req state.QueryRequest
+	err := json.Unmarshal([]byte(`{"filter":{"EQ":{"color":"red"}},"sort":[{"key":"size"}],"page":{"limit":1}}`), &req.Query)
+	require.NoError(t, err)
+
+	res, err := store.Query(t.Context(), &req)
+	require.NoError(t, err)
+	require.Len(t, res.Results, 1)
+	assert.Equal(t, "c", res.Results[0].Key)
+	assert.JSONEq(t, `{"color":"red","size":2}`, string(res.Results[0].Data))
+	require.NotNil(t, res.Results[0].ETag)
+	assert.Equal(t, "1", res.Token)
+
+	req.Query.Page.Token = res.Token
+	res, err = store.Query(t.Context(), &req)
+	require.NoError(t, err)
+	require.Len(t, res.Results, 1)
+	assert.Equal(t, "a", res.Results[0].Key)
+	assert.Empty(t, res.Token)
+
+	req = state.QueryRequest{}
+	err = json.Unmarshal([]byte(`{"filter":{"EQ":{"color":"blue"}},"fields":["size","missing"]}`), &req.Query)
+	require.NoError(t, err)
+	res, err = store.Query(t.Context(), &req)
+	require.NoError(t, err)
+	require.Len(t, res.Results, 1)
+	assert.Equal(t, "b", res.Results[0].Key)
+	assert.JSONEq(t, `{"size":1}`, string(res.Results[0].Data))
+}
+
+func TestIncrement(t *testing.T) {
+	store := NewInMemoryStateStore(logger.NewLogger("test")).(*InMemoryStore)
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	store.clock = fakeClock
+
+	t.Run("increment creates and updates the counter", func(t *testing.T) {
+		n, err := store.Increment(t.Context(), "counter", 5)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), n)
+
+		n, err = store.Increment(t.Context(), "counter", -2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), n)
+
+		res, err := store.Get(t.Context(), &state.GetRequest{Key: "counter"})
+		require.NoError(t, err)
+		assert.Equal(t, "3", string(res.Data))
+	})
+
+	t.Run("increment preserves the TTL", func(t *testing.T) {
+		require.NoError(t, store.Set(t.Context(), &state.SetRequest{
+			Key:      "ttl-counter",
+			Value:    1,
+			Metadata: map[string]string{"ttlInSeconds": "10"},
+		}))
+
+		fakeClock.Step(5 * time.Second)
+		n, err := store.Increment(t.Context(), "ttl-counter", 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		fakeClock.Step(6 * time.Second)
+		res, err := store.Get(t.Context(), &state.GetRequest{Key: "ttl-counter"})
+		require.NoError(t, err)
+		assert.Nil(t, res.Data)
+	})
+
+	t.Run("increment of a value that is not an integer fails", func(t *testing.T) {
+		require.NoError(t, store.Set(t.Context(), &state.SetRequest{Key: "not-a-number", Value: "a"}))
+		_, err := store.Increment(t.Context(), "not-a-number", 1)
+		require.Error(t, err)
+	})
+
+	t.Run("increment in transaction", func(t *testing.T) {
+		err := store.Multi(t.Context(), &state.TransactionalStateRequest{
+			Operations: []state.TransactionalStateOperation{
+				state.SetRequest{Key: "tx-counter", Value: 10},
+				state.IncrementRequest{Key: "tx-counter", Delta: 2},
+				state.IncrementRequest{Key: "tx-counter", Delta: 3},
+			},
+		})
+		require.NoError(t, err)
+
+		res, err := store.Get(t.Context(), &state.GetRequest{Key: "tx-counter"})
+		require.NoError(t, err)
+		assert.Equal(t, "15", string(res.Data))
+
+		// The transaction is not applied if an increment fails
+		err = store.Multi(t.Context(), &state.TransactionalStateRequest{
+			Operations: []state.TransactionalStateOperation{
+				state.IncrementRequest{Key: "tx-counter", Delta: 1},
+				state.SetRequest{Key: "tx-counter-2", Value: "a"},
+				state.IncrementRequest{Key: "tx-counter-2", Delta: 1},
+			},
+		})
+		require.Error(t, err)
+
+		res, err = store.Get(t.Context(), &state.GetRequest{Key: "tx-counter"})
+		require.NoError(t, err)
+		assert.Equal(t, "15", string(res.Data))
+	})
+}
+
+func TestMultiWithConditions(t *testing.T) {
+	store := NewInMemoryStateStore(logger.NewLogger("test")).(*InMemoryStore)
+	require.NoError(t, store.Set(t.Context(), &state.SetRequest{Key: "existing", Value: "a"}))
+	res, err := store.Get(t.Context(), &state.GetRequest{Key: "existing"})
+	require.NoError(t, err)
+	etag := res.ETag
+
+	multi := func(ops ...state.TransactionalStateOperation) error {
+		return store.Multi(t.Context(), &state.TransactionalStateRequest{Operations: ops})
+	}
+	assertMissing := func(t *testing.T, key string) {
+		t.Helper()
+		res, err := store.Get(t.Context(), &state.GetRequest{Key: key})
+		require.NoError(t, err)
+		assert.Nil(t, res.Data)
+	}
+
+	t.Run("conditions met", func(t *testing.T) {
+		err := multi(
+			state.ConditionRequest{Key: "existing", Condition: state.ConditionExists},
+			state.ConditionRequest{Key: "existing", Condition: state.ConditionETagMatch, ETag: etag},
+			state.ConditionRequest{Key: "missing", Condition: state.ConditionNotExists},
+			state.SetRequest{Key: "written", Value: "b"},
+		)
+		require.NoError(t, err)
+
+		res, err := store.Get(t.Context(), &state.GetRequest{Key: "written"})
+		require.NoError(t, err)
+		assert.Equal(t, `"b"`, string(res.Data))
+	})
+
+	t.Run("conditions not met", func(t *testing.T) {
+		for _, cond := range []state.ConditionRequest{
+			{Key: "missing", Condition: state.ConditionExists},
+			{Key: "existing", Condition: state.ConditionNotExists},
+			{Key: "existing", Condition: state.ConditionETagMatch, ETag: ptr.Of("bad-etag")},
+		} {
+			err := multi(state.SetRequest{Key: "not-written", Value: "b"}, cond)
+			require.ErrorIs(t, err, state.ErrConditionNotMet)
+			assertMissing(t, "not-written")
+		}
+	})
+
+	t.Run("conditions see the previous operations in the transaction", func(t *testing.T) {
+		err := multi(
+			state.DeleteRequest{Key: "existing"},
+			state.ConditionRequest{Key: "existing", Condition: state.ConditionExists},
+		)
+		require.ErrorIs(t, err, state.ErrConditionNotMet)
+
+		err = multi(
+			state.SetRequest{Key: "new", Value: "b"},
+			state.ConditionRequest{Key: "new", Condition: state.ConditionExists},
+		)
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid condition", func(t *testing.T) {
+		err := multi(state.ConditionRequest{Key: "existing", Condition: state.ConditionETagMatch})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/kit/logger"
+)
+
+// Watch invokes the handler for every change to the keys selected by the request, until ctx is canceled.
+func (store *InMemoryStore) Watch(ctx context.Context, req *state.WatchRequest, handler state.WatchHandler) error {
+	if req == nil {
+		return errors.New("request object is nil")
+	}
+	if handler == nil {
+		return errors.New("handler is nil")
+	}
+	if store.closed.Load() {
+		return errors.New("state store is closed")
+	}
+
+	w := &inMemWatcher{
+		req:     *req,
+		handler: handler,
+		wakeCh:  make(chan struct{}, 1),
+	}
+
+	store.watchLock.Lock()
+	id := store.watcherIdx
+	store.watcherIdx++
+	store.watchers[id] = w
+	store.watchLock.Unlock()
+
+	store.watchWg.Add(1)
+	go func() {
+		defer store.watchWg.Done()
+		w.run(ctx, store.closeCh, store.log)
+
+		store.watchLock.Lock()
+		delete(store.watchers, id)
+		store.watchLock.Unlock()
+	}()
+
+	return nil
+}
+
+// notifyWatchers queues an event for all watchers interested in the key.
+// This must be invoked while holding the store's write lock, so events are queued in the order changes are applied.
+func (store *InMemoryStore) notifyWatchers(e *state.WatchEvent) {
+	store.watchLock.Lock()
+	defer store.watchLock.Unlock()
+
+	for _, w := range store.watchers {
+		w.enqueue(e)
+	}
+}
+
+type inMemWatcher struct {
+	req     state.WatchRequest
+	handler state.WatchHandler
+
+	lock   sync.Mutex
+	queue  []*state.WatchEvent
+	wakeCh chan struct{}
+}
+
+func (w *inMemWatcher) enqueue(e *state.WatchEvent) {
+	if !w.req.Matches(e.Key) {
+		return
+	}
+
+	w.lock.Lock()
+	w.queue = append(w.queue, e)
+	w.lock.Unlock()
+
+	// Wake up the watcher if it's not already awake
+	select {
+	case w.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+func (w *inMemWatcher) drain() []*state.WatchEvent {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	events := w.queue
+	w.queue = nil
+	return events
+}
+
+func (w *inMemWatcher) run(ctx context.Context, closeCh <-chan struct{}, log logger.Logger) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-closeCh:
+			return
+		case <-w.wakeCh:
+		}
+
+		// Handlers are invoked without holding any lock, so they can safely use the store
+		for _, e := range w.drain() {
+			err := w.handler(ctx, e)
+			if err != nil {
+				log.Errorf("Error invoking watch handler for key '%s': %v", e.Key, err)
+			}
+		}
+	}
+}
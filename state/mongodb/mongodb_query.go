@@ -33,6 +33,13 @@ type Query struct {
 	query  string
 	filter interface{}
 	opts   *options.FindOptions
+
+	// Set when the query has aggregations, which are executed as an aggregation pipeline.
+	pipeline mongo.Pipeline
+	groupBy  []string
+	aliases  []string
+	limit    int64
+	skip     int64
 }
 
 func (q *Query) VisitEQ(f *query.EQ) (string, error) {
@@ -198,6 +205,15 @@ func (q *Query) Finalize(filters string, qq *query.Query) error {
 	}
 	q.opts = options.Find()
 
+	// projection
+	if len(qq.Fields) > 0 {
+		projection := bson.D{{Key: etag, Value: 1}}
+		for _, key := range qq.Fields {
+			projection = append(projection, bson.E{Key: value + "." + key, Value: 1})
+		}
+		q.opts.SetProjection(projection)
+	}
+
 	// sorting
 	if len(qq.Sort) > 0 {
 		sort := bson.D{}
@@ -225,7 +241,135 @@ func (q *Query) Finalize(filters string, qq *query.Query) error {
 	return nil
 }
 
+func (q *Query) VisitCOUNT(*query.COUNT) (string, error) {
+	return `{ "$sum": 1 }`, nil
+}
+
+func (q *Query) VisitSUM(a *query.SUM) (string, error) {
+	return fmt.Sprintf(`{ "$sum": "$value.%s" }`, a.Key), nil
+}
+
+func (q *Query) VisitMIN(a *query.MIN) (string, error) {
+	return fmt.Sprintf(`{ "$min": "$value.%s" }`, a.Key), nil
+}
+
+func (q *Query) VisitMAX(a *query.MAX) (string, error) {
+	return fmt.Sprintf(`{ "$max": "$value.%s" }`, a.Key), nil
+}
+
+// FinalizeAggregate builds an aggregation pipeline that groups the documents selected by the filters.
+// The values of the group-by keys are stored in the "_id" of each group as "g0", "g1", ..., because keys can contain dots.
+func (q *Query) FinalizeAggregate(filters string, aggregations []string, qq *query.Query) error {
+	stages := []string{}
+	if len(filters) > 0 {
+		stages = append(stages, fmt.Sprintf(`{ "$match": %s }`, filters))
+	}
+
+	q.groupBy = qq.Aggregate.GroupBy
+	q.aliases = make([]string, len(qq.Aggregate.Aggregations))
+	sortFields := make(map[string]string, len(q.groupBy)+len(q.aliases))
+	group := `{ "$group": { "_id": null`
+	if len(q.groupBy) > 0 {
+		ids := make([]string, len(q.groupBy))
+		for i, key := range q.groupBy {
+			ids[i] = fmt.Sprintf(`"g%d": "$value.%s"`, i, key)
+			sortFields[key] = "_id.g" + strconv.Itoa(i)
+		}
+		group = `{ "$group": { "_id": { ` + strings.Join(ids, ", ") + ` }`
+	}
+	for i, a := range qq.Aggregate.Aggregations {
+		q.aliases[i] = a.GetAlias()
+		sortFields[a.GetAlias()] = a.GetAlias()
+		group += fmt.Sprintf(`, "%s": %s`, a.GetAlias(), aggregations[i])
+	}
+	stages = append(stages, group+" } }")
+
+	// sorting
+	if len(qq.Sort) > 0 {
+		sort := make([]string, len(qq.Sort))
+		for i, s := range qq.Sort {
+			order := 1 // ascending
+			if s.Order == query.DESC {
+				order = -1
+			}
+			sort[i] = fmt.Sprintf(`"%s": %d`, sortFields[s.Key], order)
+		}
+		stages = append(stages, `{ "$sort": { `+strings.Join(sort, ", ")+` } }`)
+	}
+	// pagination
+	if len(qq.Page.Token) != 0 {
+		skip, err := strconv.ParseInt(qq.Page.Token, 10, 64)
+		if err != nil {
+			return err
+		}
+		q.skip = skip
+		stages = append(stages, fmt.Sprintf(`{ "$skip": %d }`, skip))
+	}
+	if qq.Page.Limit > 0 {
+		q.limit = int64(qq.Page.Limit)
+		stages = append(stages, fmt.Sprintf(`{ "$limit": %d }`, qq.Page.Limit))
+	}
+
+	q.query = "[ " + strings.Join(stages, ", ") + " ]"
+	q.pipeline = make(mongo.Pipeline, len(stages))
+	for i, stage := range stages {
+		if err := bson.UnmarshalExtJSON([]byte(stage), false, &q.pipeline[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (q *Query) executeAggregate(ctx context.Context, collection *mongo.Collection) ([]state.QueryItem, string, error) {
+	cur, err := collection.Aggregate(ctx, q.pipeline)
+	if err != nil {
+		return nil, "", err
+	}
+	defer cur.Close(ctx)
+	ret := []state.QueryItem{}
+	for cur.Next(ctx) {
+		// Results have the values of the group-by keys first, then the values of the aggregations, named after the keys and the aliases
+		doc := make(bson.D, 0, len(q.groupBy)+len(q.aliases))
+		for i, key := range q.groupBy {
+			doc = append(doc, bson.E{Key: key, Value: lookupRaw(cur.Current, "_id", "g"+strconv.Itoa(i))})
+		}
+		for _, alias := range q.aliases {
+			doc = append(doc, bson.E{Key: alias, Value: lookupRaw(cur.Current, alias)})
+		}
+
+		var result state.QueryItem
+		if result.Data, err = bson.MarshalExtJSON(doc, false, true); err != nil {
+			result.Error = err.Error()
+		}
+		ret = append(ret, result)
+	}
+	if err = cur.Err(); err != nil {
+		return nil, "", err
+	}
+	// set next query token only if limit is specified
+	var token string
+	if q.limit != 0 {
+		token = strconv.FormatInt(q.skip+int64(len(ret)), 10)
+	}
+
+	return ret, token, nil
+}
+
+// lookupRaw returns the value at the given path in the document, or nil if it does not exist.
+func lookupRaw(doc bson.Raw, path ...string) any {
+	val, err := doc.LookupErr(path...)
+	if err != nil {
+		return nil
+	}
+	return val
+}
+
 func (q *Query) execute(ctx context.Context, collection *mongo.Collection) ([]state.QueryItem, string, error) {
+	if q.pipeline != nil {
+		return q.executeAggregate(ctx, collection)
+	}
+
 	cur, err := collection.Find(ctx, q.filter, []*options.FindOptions{q.opts}...)
 	if err != nil {
 		return nil, "", err
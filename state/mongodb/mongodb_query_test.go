@@ -20,6 +20,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 
 	"github.com/dapr/components-contrib/state/query"
 )
@@ -72,3 +73,43 @@ func TestMongoQuery(t *testing.T) {
 		assert.Equal(t, test.query, q.query)
 	}
 }
+
+func TestMongoQueryAggregate(t *testing.T) {
+	data, err := os.ReadFile("../../tests/state/query/q9-aggregate.json")
+	require.NoError(t, err)
+	var qq query.Query
+	err = json.Unmarshal(data, &qq)
+	require.NoError(t, err)
+
+	q := &Query{}
+	qbuilder := query.NewQueryBuilder(q)
+	err = qbuilder.BuildQuery(&qq)
+	require.NoError(t, err)
+	assert.Equal(t, `[ { "$match": { "value.status": {"$ne": "CANCELED"} } }, `+
+		`{ "$group": { "_id": { "g0": "$value.status", "g1": "$value.person.org" }, "orders": { "$sum": 1 }, "total": { "$sum": "$value.amount" }, "smallest": { "$min": "$value.amount" }, "largest": { "$max": "$value.amount" } } }, `+
+		`{ "$sort": { "total": -1, "_id.g0": 1 } }, `+
+		`{ "$limit": 2 } ]`, q.query)
+	require.Len(t, q.pipeline, 4)
+	assert.Equal(t, "$group", q.pipeline[1][0].Key)
+	assert.Equal(t, []string{"status", "person.org"}, q.groupBy)
+	assert.Equal(t, []string{"orders", "total", "smallest", "largest"}, q.aliases)
+}
+
+func TestMongoQueryFields(t *testing.T) {
+	data, err := os.ReadFile("../../tests/state/query/q10-fields.json")
+	require.NoError(t, err)
+	var qq query.Query
+	err = json.Unmarshal(data, &qq)
+	require.NoError(t, err)
+
+	q := &Query{}
+	qbuilder := query.NewQueryBuilder(q)
+	err = qbuilder.BuildQuery(&qq)
+	require.NoError(t, err)
+	assert.Equal(t, `{ "value.state": "CA" }`, q.query)
+	assert.Equal(t, bson.D{
+		{Key: "_etag", Value: 1},
+		{Key: "value.person.name", Value: 1},
+		{Key: "value.state", Value: 1},
+	}, q.opts.Projection)
+}
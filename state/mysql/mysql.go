@@ -232,6 +232,8 @@ func (m *MySQL) Features() []state.Feature {
 		state.FeatureTransactional,
 		state.FeatureTTL,
 		state.FeatureKeysLike,
+		state.FeatureListKeys,
+		state.FeatureTransactionConditions,
 	}
 }
 
@@ -817,11 +819,40 @@ func (m *MySQL) execMultiOperation(ctx context.Context, op state.TransactionalSt
 		return m.setValue(ctx, db, &req)
 	case state.DeleteRequest:
 		return m.deleteValue(ctx, db, &req)
+	case state.ConditionRequest:
+		return m.checkCondition(ctx, db, &req)
 	default:
 		return fmt.Errorf("unsupported operation: %s", op.Operation())
 	}
 }
 
+// checkCondition returns an error if the condition is not met.
+// The row is locked until the end of the transaction, so the condition can't change before the transaction is committed.
+func (m *MySQL) checkCondition(parentCtx context.Context, querier querier, req *state.ConditionRequest) error {
+	err := req.Validate()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(parentCtx, m.timeout)
+	defer cancel()
+	// Concatenation is required for table name because sql.DB does not substitute parameters for table names
+	//nolint:gosec
+	query := `SELECT eTag FROM ` + m.tableName + ` WHERE id = ?
+			AND (expiredate IS NULL OR expiredate > CURRENT_TIMESTAMP) FOR UPDATE`
+
+	var etag string
+	err = querier.QueryRowContext(ctx, query, req.Key).Scan(&etag)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return req.Check(false, nil)
+	case err != nil:
+		return err
+	default:
+		return req.Check(true, &etag)
+	}
+}
+
 // Close implements io.Closer.
 func (m *MySQL) Close() error {
 	if m.db == nil {
@@ -959,3 +990,72 @@ func (m *MySQL) KeysLike(ctx context.Context, req *state.KeysLikeRequest) (*stat
 
 	return resp, nil
 }
+
+// ListKeys returns the keys in the state table ordered by key, one page at a time.
+// The continuation token is the last key returned, so rows written during the iteration don't affect the keys that are returned next.
+func (m *MySQL) ListKeys(ctx context.Context, req *state.ListKeysRequest) (*state.ListKeysResponse, error) {
+	if req == nil {
+		return nil, errors.New("request object is nil")
+	}
+
+	var args []any
+	whereParts := []string{
+		"(expiredate IS NULL OR expiredate > CURRENT_TIMESTAMP)",
+	}
+
+	if req.Prefix != "" {
+		whereParts = append(whereParts, "id LIKE ?")
+		args = append(args, utils.EscapeLikePattern(req.Prefix)+"%")
+	}
+	if req.ContinuationToken != nil {
+		whereParts = append(whereParts, "id > ?")
+		args = append(args, *req.ContinuationToken)
+	}
+
+	limitClause := ""
+	var pageSize uint32
+	if req.PageSize != nil && *req.PageSize > 0 {
+		pageSize = *req.PageSize
+		// fetch one extra to detect "has next"
+		limitClause = " LIMIT ?"
+		args = append(args, pageSize+1)
+	}
+
+	//nolint:gosec
+	query := `
+		SELECT id
+		FROM ` + m.tableName + `
+		WHERE ` + strings.Join(whereParts, " AND ") + `
+		ORDER BY id ASC` + limitClause
+
+	runCtx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	rows, err := m.db.QueryContext(runCtx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resp := &state.ListKeysResponse{
+		Keys: make([]string, 0, min(pageSize, 1024)),
+	}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		resp.Keys = append(resp.Keys, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	//nolint:gosec
+	if pageSize > 0 && uint32(len(resp.Keys)) > pageSize {
+		resp.Keys = resp.Keys[:pageSize]
+		resp.ContinuationToken = ptr.Of(resp.Keys[pageSize-1])
+	}
+
+	return resp, nil
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/dapr/components-contrib/state"
 	"github.com/dapr/components-contrib/state/utils"
 	"github.com/dapr/kit/logger"
+	"github.com/dapr/kit/ptr"
 )
 
 const (
@@ -771,6 +772,57 @@ func TestValidMultiDeleteRequest(t *testing.T) {
 	})
 }
 
+func TestMultiWithConditions(t *testing.T) {
+	// Arrange
+	t.Parallel()
+	m, _ := mockDatabase(t)
+
+	t.Run("condition met", func(t *testing.T) {
+		ops := []state.TransactionalStateOperation{
+			state.ConditionRequest{Key: "existing", Condition: state.ConditionExists},
+			createDeleteRequest(),
+		}
+
+		m.mock1.ExpectBegin()
+		m.mock1.ExpectQuery("SELECT eTag FROM state").
+			WithArgs("existing").
+			WillReturnRows(sqlmock.NewRows([]string{"eTag"}).AddRow("etag"))
+		m.mock1.ExpectExec("DELETE FROM").WillReturnResult(sqlmock.NewResult(0, 1))
+		m.mock1.ExpectCommit()
+
+		// Act
+		err := m.mySQL.Multi(t.Context(), &state.TransactionalStateRequest{
+			Operations: ops,
+		})
+
+		// Assert
+		require.NoError(t, err)
+		require.NoError(t, m.mock1.ExpectationsWereMet())
+	})
+
+	t.Run("condition not met", func(t *testing.T) {
+		ops := []state.TransactionalStateOperation{
+			state.ConditionRequest{Key: "existing", Condition: state.ConditionETagMatch, ETag: ptr.Of("other-etag")},
+			createDeleteRequest(),
+		}
+
+		m.mock1.ExpectBegin()
+		m.mock1.ExpectQuery("SELECT eTag FROM state").
+			WithArgs("existing").
+			WillReturnRows(sqlmock.NewRows([]string{"eTag"}).AddRow("etag"))
+		m.mock1.ExpectRollback()
+
+		// Act
+		err := m.mySQL.Multi(t.Context(), &state.TransactionalStateRequest{
+			Operations: ops,
+		})
+
+		// Assert
+		require.ErrorIs(t, err, state.ErrConditionNotMet)
+		require.NoError(t, m.mock1.ExpectationsWereMet())
+	})
+}
+
 func TestInvalidMultiDeleteRequestNoKey(t *testing.T) {
 	// Arrange
 	t.Parallel()
@@ -936,3 +988,25 @@ func Test_KeysLike(t *testing.T) {
 	m, _ := mockDatabase(t)
 	var _ state.KeysLiker = m.mySQL
 }
+
+func TestListKeys(t *testing.T) {
+	m, _ := mockDatabase(t)
+	defer m.mySQL.Close()
+
+	m.mock1.ExpectQuery(`SELECT id\s+FROM state\s+WHERE .* AND id LIKE \? AND id > \?\s+ORDER BY id ASC LIMIT \?`).
+		WithArgs(`app\_1||%`, "app_1||a", uint32(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("app_1||b").AddRow("app_1||c").AddRow("app_1||d"))
+
+	pageSize := uint32(2)
+	token := "app_1||a"
+	res, err := m.mySQL.ListKeys(t.Context(), &state.ListKeysRequest{
+		Prefix:            "app_1||",
+		ContinuationToken: &token,
+		PageSize:          &pageSize,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app_1||b", "app_1||c"}, res.Keys)
+	require.NotNil(t, res.ContinuationToken)
+	assert.Equal(t, "app_1||c", *res.ContinuationToken)
+	require.NoError(t, m.mock1.ExpectationsWereMet())
+}
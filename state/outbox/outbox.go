@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package outbox implements the transactional outbox pattern on top of state stores and pubsub components.
+// Events are written to the state store as outbox records, in the same transaction as the state they belong to; a Relay then publishes them and removes them from the outbox.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dapr/components-contrib/state"
+)
+
+const (
+	// DefaultKeyPrefix is the default prefix of the keys of outbox records.
+	DefaultKeyPrefix = "outbox||"
+
+	// MetadataKeyDedupKey is the metadata key that contains the deduplication key of events published by the relay.
+	// Events can be published more than once, so subscribers should use it to discard duplicates.
+	MetadataKeyDedupKey = "dedupKey"
+)
+
+// Event is an event to publish when a transaction is committed.
+type Event struct {
+	// ID is the deduplication key of the event. If empty, a random ID is generated.
+	ID          string
+	PubsubName  string
+	Topic       string
+	Data        []byte
+	ContentType *string
+	Metadata    map[string]string
+}
+
+// Record is an event stored in the outbox.
+type Record struct {
+	ID          string            `json:"id"`
+	PubsubName  string            `json:"pubsubName,omitempty"`
+	Topic       string            `json:"topic"`
+	Data        []byte            `json:"data"`
+	ContentType *string           `json:"contentType,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+}
+
+// Store is a state store that can be used for the outbox: it must support transactions and listing keys.
+type Store interface {
+	state.Store
+	state.TransactionalStore
+	state.KeyLister
+}
+
+// Options contains the options for the outbox.
+type Options struct {
+	// KeyPrefix is the prefix of the keys of outbox records. Defaults to DefaultKeyPrefix.
+	KeyPrefix string
+}
+
+// Outbox writes events to a state store, in the same transaction as the state they belong to.
+type Outbox struct {
+	store     Store
+	keyPrefix string
+	clock     func() time.Time
+}
+
+// New returns a new Outbox that stores events in the given state store.
+func New(store state.Store, opts Options) (*Outbox, error) {
+	s, ok := store.(Store)
+	if !ok {
+		return nil, errors.New("state store must support transactions and listing keys to be used as outbox")
+	}
+	if opts.KeyPrefix == "" {
+		opts.KeyPrefix = DefaultKeyPrefix
+	}
+	return &Outbox{
+		store:     s,
+		keyPrefix: opts.KeyPrefix,
+		clock:     time.Now,
+	}, nil
+}
+
+// Multi executes the transaction, storing the events in the outbox as part of it.
+func (o *Outbox) Multi(ctx context.Context, req *state.TransactionalStateRequest, events ...Event) error {
+	ops, err := o.Operations(events...)
+	if err != nil {
+		return err
+	}
+	return o.store.Multi(ctx, &state.TransactionalStateRequest{
+		Operations: append(slices.Clone(req.Operations), ops...),
+		Metadata:   req.Metadata,
+	})
+}
+
+// Operations returns the operations that store the events in the outbox, for callers that execute the transaction themselves.
+func (o *Outbox) Operations(events ...Event) ([]state.TransactionalStateOperation, error) {
+	now := o.clock()
+	ops := make([]state.TransactionalStateOperation, len(events))
+	for i, e := range events {
+		if e.Topic == "" {
+			return nil, errors.New("events must have a topic")
+		}
+		id := e.ID
+		if id == "" {
+			u, err := uuid.NewRandom()
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate event ID: %w", err)
+			}
+			id = u.String()
+		}
+		ops[i] = state.SetRequest{
+			Key: o.recordKey(now, i, id),
+			Value: &Record{
+				ID:          id,
+				PubsubName:  e.PubsubName,
+				Topic:       e.Topic,
+				Data:        e.Data,
+				ContentType: e.ContentType,
+				Metadata:    e.Metadata,
+				CreatedAt:   now,
+			},
+		}
+	}
+	return ops, nil
+}
+
+// recordKey returns the key of an outbox record.
+// Keys start with the time the event was stored and its position in the transaction, so stores that list keys in order return the oldest events first.
+func (o *Outbox) recordKey(t time.Time, seq int, id string) string {
+	return fmt.Sprintf("%s%020d-%06d-%s", o.keyPrefix, t.UnixNano(), seq, id)
+}
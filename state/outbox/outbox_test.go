@@ -0,0 +1,205 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/components-contrib/pubsub"
+	inmemorypubsub "github.com/dapr/components-contrib/pubsub/in-memory"
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/components-contrib/state/sqlite"
+	"github.com/dapr/kit/logger"
+	"github.com/dapr/kit/ptr"
+)
+
+func newTestStore(t *testing.T) state.Store {
+	t.Helper()
+	store := sqlite.NewSQLiteStateStore(logger.NewLogger("test"))
+	err := store.Init(t.Context(), state.Metadata{Base: metadata.Base{
+		Properties: map[string]string{"connectionString": ":memory:"},
+	}})
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func newTestPubSub(t *testing.T) (pubsub.PubSub, <-chan *pubsub.NewMessage) {
+	t.Helper()
+	ps := inmemorypubsub.New(logger.NewLogger("test"))
+	require.NoError(t, ps.Init(t.Context(), pubsub.Metadata{}))
+	t.Cleanup(func() { ps.Close() })
+
+	ch := make(chan *pubsub.NewMessage, 10)
+	err := ps.Subscribe(t.Context(), pubsub.SubscribeRequest{Topic: "orders"}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+		ch <- msg
+		return nil
+	})
+	require.NoError(t, err)
+	return ps, ch
+}
+
+func receive(t *testing.T, ch <-chan *pubsub.NewMessage) *pubsub.NewMessage {
+	t.Helper()
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timed out waiting for message")
+		return nil
+	}
+}
+
+// failingPubSub fails to publish the events with the given data, or all events if it's empty.
+type failingPubSub struct {
+	pubsub.PubSub
+	data string
+}
+
+func (f *failingPubSub) Publish(ctx context.Context, req *pubsub.PublishRequest) error {
+	if f.data == "" || f.data == string(req.Data) {
+		return errors.New("publish failed")
+	}
+	return f.PubSub.Publish(ctx, req)
+}
+
+func TestOutbox(t *testing.T) {
+	t.Run("store must support transactions and listing keys", func(t *testing.T) {
+		_, err := New(struct{ state.Store }{}, Options{})
+		require.Error(t, err)
+	})
+
+	t.Run("events are published after the transaction", func(t *testing.T) {
+		store := newTestStore(t)
+		ps, ch := newTestPubSub(t)
+		o, err := New(store, Options{})
+		require.NoError(t, err)
+		now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		o.clock = func() time.Time { return now }
+
+		err = o.Multi(t.Context(), &state.TransactionalStateRequest{
+			Operations: []state.TransactionalStateOperation{
+				state.SetRequest{Key: "order-1", Value: map[string]any{"status": "placed"}},
+			},
+		}, Event{ID: "evt-1", Topic: "orders", Data: []byte(`{"order":1}`), Metadata: map[string]string{"foo": "bar"}})
+		require.NoError(t, err)
+
+		res, err := store.Get(t.Context(), &state.GetRequest{Key: "order-1"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"status":"placed"}`, string(res.Data))
+
+		keys, err := o.store.ListKeys(t.Context(), &state.ListKeysRequest{Prefix: DefaultKeyPrefix})
+		require.NoError(t, err)
+		assert.Equal(t, []string{DefaultKeyPrefix + "01735787045000000000-000000-evt-1"}, keys.Keys)
+
+		relay := o.NewRelay(ps, RelayOptions{PageSize: 1})
+		n, err := relay.Drain(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		msg := receive(t, ch)
+		assert.Equal(t, `{"order":1}`, string(msg.Data))
+		assert.Equal(t, "evt-1", msg.Metadata[MetadataKeyDedupKey])
+		assert.Equal(t, "bar", msg.Metadata["foo"])
+
+		// The outbox is empty after the events are published
+		keys, err = o.store.ListKeys(t.Context(), &state.ListKeysRequest{Prefix: DefaultKeyPrefix})
+		require.NoError(t, err)
+		assert.Empty(t, keys.Keys)
+		n, err = relay.Drain(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("events are not stored if the transaction fails", func(t *testing.T) {
+		store := newTestStore(t)
+		o, err := New(store, Options{KeyPrefix: "box||"})
+		require.NoError(t, err)
+
+		err = o.Multi(t.Context(), &state.TransactionalStateRequest{
+			Operations: []state.TransactionalStateOperation{
+				state.SetRequest{Key: "order-1", Value: "placed", ETag: ptr.Of("1234")},
+			},
+		}, Event{Topic: "orders", Data: []byte("1")})
+		require.Error(t, err)
+
+		keys, err := o.store.ListKeys(t.Context(), &state.ListKeysRequest{Prefix: "box||"})
+		require.NoError(t, err)
+		assert.Empty(t, keys.Keys)
+	})
+
+	t.Run("events stay in the outbox if they cannot be published", func(t *testing.T) {
+		store := newTestStore(t)
+		ps, ch := newTestPubSub(t)
+		o, err := New(store, Options{})
+		require.NoError(t, err)
+
+		err = o.Multi(t.Context(), &state.TransactionalStateRequest{}, Event{Topic: "orders", Data: []byte("1")})
+		require.NoError(t, err)
+
+		_, err = o.NewRelay(&failingPubSub{PubSub: ps}, RelayOptions{}).Drain(t.Context())
+		require.Error(t, err)
+
+		n, err := o.NewRelay(ps, RelayOptions{}).Drain(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		msg := receive(t, ch)
+		assert.Equal(t, "1", string(msg.Data))
+		assert.NotEmpty(t, msg.Metadata[MetadataKeyDedupKey])
+	})
+
+	t.Run("events that cannot be published don't stop the others", func(t *testing.T) {
+		store := newTestStore(t)
+		ps, ch := newTestPubSub(t)
+		o, err := New(store, Options{})
+		require.NoError(t, err)
+
+		// Events are published in the order they were stored
+		now := time.Now()
+		for i, data := range []string{"1", "2", "3"} {
+			o.clock = func() time.Time { return now.Add(time.Duration(i) * time.Millisecond) }
+			err = o.Multi(t.Context(), &state.TransactionalStateRequest{}, Event{Topic: "orders", Data: []byte(data)})
+			require.NoError(t, err)
+		}
+		o.clock = func() time.Time { return now.Add(3 * time.Millisecond) }
+		err = o.Multi(t.Context(), &state.TransactionalStateRequest{}, Event{Topic: "orders", Data: []byte("4")}, Event{Topic: "orders", Data: []byte("5")})
+		require.NoError(t, err)
+
+		n, err := o.NewRelay(&failingPubSub{PubSub: ps, data: "2"}, RelayOptions{PageSize: 2}).Drain(t.Context())
+		require.ErrorContains(t, err, "publish failed")
+		assert.Equal(t, 4, n)
+		for _, data := range []string{"1", "3", "4", "5"} {
+			assert.Equal(t, data, string(receive(t, ch).Data))
+		}
+
+		n, err = o.NewRelay(ps, RelayOptions{}).Drain(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, "2", string(receive(t, ch).Data))
+	})
+
+	t.Run("events must have a topic", func(t *testing.T) {
+		o, err := New(newTestStore(t), Options{})
+		require.NoError(t, err)
+		_, err = o.Operations(Event{Data: []byte("1")})
+		require.Error(t, err)
+	})
+}
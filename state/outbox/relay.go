@@ -0,0 +1,175 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/dapr/components-contrib/pubsub"
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	defaultRelayInterval = 5 * time.Second
+	defaultRelayPageSize = 100
+)
+
+// RelayOptions contains the options for the relay.
+type RelayOptions struct {
+	// Interval between runs of the relay. Defaults to 5s.
+	Interval time.Duration
+	// PageSize is the number of keys of outbox records listed at once. Defaults to 100.
+	PageSize uint32
+	Logger   logger.Logger
+}
+
+// Relay publishes the events in the outbox, then removes them from the outbox.
+// Delivery is at-least-once: an event is published again if it could not be removed from the outbox after it was published, or if multiple relays run at the same time.
+// Each event is published with its ID in the MetadataKeyDedupKey metadata key, so subscribers can discard duplicates.
+type Relay struct {
+	outbox   *Outbox
+	pubsub   pubsub.PubSub
+	interval time.Duration
+	pageSize uint32
+	logger   logger.Logger
+}
+
+// NewRelay returns a new relay that publishes the events in the outbox to the pubsub component.
+func (o *Outbox) NewRelay(ps pubsub.PubSub, opts RelayOptions) *Relay {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultRelayInterval
+	}
+	if opts.PageSize == 0 {
+		opts.PageSize = defaultRelayPageSize
+	}
+	if opts.Logger == nil {
+		opts.Logger = logger.NewLogger("dapr.state.outbox")
+	}
+	return &Relay{
+		outbox:   o,
+		pubsub:   ps,
+		interval: opts.Interval,
+		pageSize: opts.PageSize,
+		logger:   opts.Logger,
+	}
+}
+
+// Run drains the outbox periodically, until the context is canceled.
+func (r *Relay) Run(ctx context.Context) error {
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+
+	for {
+		n, err := r.Drain(ctx)
+		if err != nil {
+			r.logger.Errorf("Failed to drain outbox: %v", err)
+		} else if n > 0 {
+			r.logger.Debugf("Published %d events from the outbox", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Drain publishes all the events that are in the outbox and returns the number of events published.
+// Events that cannot be published don't stop the others, and are published again by the next run; the errors are returned joined.
+// Records that cannot be read are logged and left in the outbox.
+func (r *Relay) Drain(ctx context.Context) (int, error) {
+	var (
+		count int
+		token *string
+		errs  []error
+	)
+	for {
+		res, err := r.outbox.store.ListKeys(ctx, &state.ListKeysRequest{
+			Prefix:            r.outbox.keyPrefix,
+			ContinuationToken: token,
+			PageSize:          &r.pageSize,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list outbox records: %w", err))
+			return count, errors.Join(errs...)
+		}
+
+		for _, key := range res.Keys {
+			published, err := r.relay(ctx, key)
+			if published {
+				count++
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		if res.ContinuationToken == nil {
+			return count, errors.Join(errs...)
+		}
+		token = res.ContinuationToken
+	}
+}
+
+// relay publishes the event stored with the given key, then removes it from the outbox.
+func (r *Relay) relay(ctx context.Context, key string) (bool, error) {
+	res, err := r.outbox.store.Get(ctx, &state.GetRequest{Key: key})
+	if err != nil {
+		return false, fmt.Errorf("failed to read outbox record %s: %w", key, err)
+	}
+	if res == nil || len(res.Data) == 0 {
+		// The record was removed by another relay
+		return false, nil
+	}
+
+	var rec Record
+	err = json.Unmarshal(res.Data, &rec)
+	if err != nil {
+		r.logger.Errorf("Invalid outbox record %s: %v", key, err)
+		return false, nil
+	}
+
+	md := make(map[string]string, len(rec.Metadata)+1)
+	maps.Copy(md, rec.Metadata)
+	md[MetadataKeyDedupKey] = rec.ID
+	err = r.pubsub.Publish(ctx, &pubsub.PublishRequest{
+		PubsubName:  rec.PubsubName,
+		Topic:       rec.Topic,
+		Data:        rec.Data,
+		ContentType: rec.ContentType,
+		Metadata:    md,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to publish outbox record %s: %w", key, err)
+	}
+
+	// If the record can't be removed, it is published again the next time.
+	// The ETag makes sure that a record that was written again after it was read is not removed
+	err = r.outbox.store.Delete(ctx, &state.DeleteRequest{Key: key, ETag: res.ETag})
+	var etagErr *state.ETagError
+	if errors.As(err, &etagErr) {
+		return true, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("failed to remove outbox record %s: %w", key, err)
+	}
+	return true, nil
+}
@@ -36,6 +36,8 @@ func performMigrations(ctx context.Context, db pginterfaces.PGXPoolConn, opts po
 		Logger:            opts.Logger,
 		MetadataTableName: opts.MetadataTableName,
 		MetadataKey:       "migrations",
+		StateTableName:    opts.StateTableName,
+		IndexedFields:     opts.IndexedFields,
 	}
 
 	return m.Perform(ctx, []commonsql.MigrationFn{
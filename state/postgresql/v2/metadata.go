@@ -42,6 +42,8 @@ type pgMetadata struct {
 	MetadataTableName string         `mapstructure:"metadataTableName"` // Could be in the format "schema.table" or just "table"
 	Timeout           time.Duration  `mapstructure:"timeout" mapstructurealiases:"timeoutInSeconds"`
 	CleanupInterval   *time.Duration `mapstructure:"cleanupInterval" mapstructurealiases:"cleanupIntervalInSeconds"`
+	// Creates a trigger on the state table to notify changes, which is required to watch keys
+	EnableWatch bool `mapstructure:"enableWatch"`
 
 	aws.DeprecatedPostgresIAM `mapstructure:",squash"`
 }
@@ -53,6 +55,7 @@ func (m *pgMetadata) InitWithMetadata(meta state.Metadata, opts pgauth.InitWithM
 	m.MetadataTableName = "dapr_metadata"
 	m.CleanupInterval = ptr.Of(defaultCleanupInternal)
 	m.Timeout = defaultTimeout
+	m.EnableWatch = false
 
 	// Decode the metadata
 	err := metadata.DecodeMetadata(meta.Properties, &m)
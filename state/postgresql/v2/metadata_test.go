@@ -257,4 +257,20 @@ func TestMetadata(t *testing.T) {
 		_ = assert.NotNil(t, m.CleanupInterval) &&
 			assert.Equal(t, defaultCleanupInternal, *m.CleanupInterval)
 	})
+	t.Run("watch disabled by default", func(t *testing.T) {
+		m := pgMetadata{}
+		props := map[string]string{
+			"connectionString": "foo=bar",
+		}
+
+		opts := postgresql.InitWithMetadataOpts{}
+		err := m.InitWithMetadata(state.Metadata{Base: metadata.Base{Properties: props}}, opts)
+		require.NoError(t, err)
+		assert.False(t, m.EnableWatch)
+
+		props["enableWatch"] = "true"
+		err = m.InitWithMetadata(state.Metadata{Base: metadata.Base{Properties: props}}, opts)
+		require.NoError(t, err)
+		assert.True(t, m.EnableWatch)
+	})
 }
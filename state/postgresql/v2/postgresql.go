@@ -22,6 +22,8 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -40,6 +42,7 @@ import (
 	"github.com/dapr/components-contrib/state"
 	stateutils "github.com/dapr/components-contrib/state/utils"
 	"github.com/dapr/kit/logger"
+	"github.com/dapr/kit/ptr"
 )
 
 // PostgreSQL state store.
@@ -52,10 +55,16 @@ type PostgreSQL struct {
 
 	gc sqlinternal.GarbageCollector
 
-	enableAzureAD bool
-	enableAWSIAM  bool
+	enableAzureAD   bool
+	enableAWSIAM    bool
+	enableWatch     bool
+	enableIncrement bool
 
 	awsAuthProvider awsAuth.Provider
+
+	closed  atomic.Bool
+	closeCh chan struct{}
+	wg      sync.WaitGroup
 }
 
 type Options struct {
@@ -66,6 +75,14 @@ type Options struct {
 	// Disables support for authenticating with AWS IAM
 	// This should be set to "false" when targeting different databases than PostgreSQL (such as CockroachDB)
 	NoAWSIAM bool
+
+	// Disables support for watching keys, which relies on LISTEN/NOTIFY
+	// This should be set to "true" when targeting different databases than PostgreSQL (such as CockroachDB)
+	NoWatch bool
+
+	// Disables support for atomically incrementing counters
+	// This should be set to "true" when targeting different databases than PostgreSQL (such as CockroachDB)
+	NoIncrement bool
 }
 
 // NewPostgreSQLStateStore creates a new instance of PostgreSQL state store v2 with the default options.
@@ -78,14 +95,27 @@ func NewPostgreSQLStateStore(logger logger.Logger) state.Store {
 // NewPostgreSQLStateStoreWithOptions creates a new instance of PostgreSQL state store with options.
 func NewPostgreSQLStateStoreWithOptions(logger logger.Logger, opts Options) state.Store {
 	s := &PostgreSQL{
-		logger:        logger,
-		enableAzureAD: !opts.NoAzureAD,
-		enableAWSIAM:  !opts.NoAWSIAM,
+		logger:          logger,
+		enableAzureAD:   !opts.NoAzureAD,
+		enableAWSIAM:    !opts.NoAWSIAM,
+		enableWatch:     !opts.NoWatch,
+		enableIncrement: !opts.NoIncrement,
+		closeCh:         make(chan struct{}),
 	}
 	s.BulkStore = state.NewDefaultBulkStore(s)
 	return s
 }
 
+// NewCockroachDBStateStore creates a new instance of PostgreSQL state store v2 for CockroachDB, which disables the features CockroachDB doesn't support.
+func NewCockroachDBStateStore(logger logger.Logger) state.Store {
+	return NewPostgreSQLStateStoreWithOptions(logger, Options{
+		NoAzureAD:   true,
+		NoAWSIAM:    true,
+		NoWatch:     true,
+		NoIncrement: true,
+	})
+}
+
 // Init sets up Postgres connection and performs migrations
 func (p *PostgreSQL) Init(ctx context.Context, meta state.Metadata) (err error) {
 	opts := pgauth.InitWithMetadataOpts{
@@ -180,7 +210,7 @@ func (p *PostgreSQL) performMigrations(ctx context.Context) error {
 
 	stateTable := p.metadata.TableName(pgTableState)
 
-	return m.Perform(ctx, []sqlinternal.MigrationFn{
+	err := m.Perform(ctx, []sqlinternal.MigrationFn{
 		// Migration 1: create the table for state
 		func(ctx context.Context) error {
 			p.logger.Infof("Creating state table: '%s'", stateTable)
@@ -319,16 +349,37 @@ WHERE r.key = t.key
 			return nil
 		},
 	})
+	if err != nil {
+		return err
+	}
+
+	// The trigger for watching keys is created or dropped depending on the metadata, so it can be enabled at any time
+	if p.enableWatch {
+		err = p.migrateWatchTrigger(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // Features returns the features available in this state store.
 func (p *PostgreSQL) Features() []state.Feature {
-	return []state.Feature{
+	features := []state.Feature{
 		state.FeatureETag,
 		state.FeatureTransactional,
 		state.FeatureTTL,
 		state.FeatureKeysLike,
+		state.FeatureListKeys,
+	}
+	if p.enableIncrement {
+		features = append(features, state.FeatureAtomicIncrement)
 	}
+	if p.watchEnabled() {
+		features = append(features, state.FeatureWatch)
+	}
+	return features
 }
 
 func (p *PostgreSQL) GetDB() *pgxpool.Pool {
@@ -637,12 +688,64 @@ func (p *PostgreSQL) Multi(parentCtx context.Context, request *state.Transaction
 	}
 }
 
+// Increment adds delta to the counter stored at key, preserving its TTL.
+func (p *PostgreSQL) Increment(parentCtx context.Context, key string, delta int64) (int64, error) {
+	if !p.enableIncrement {
+		return 0, errors.New("increment is not supported by this database")
+	}
+
+	ctx, cancel := context.WithTimeout(parentCtx, p.metadata.Timeout)
+	defer cancel()
+	return p.doIncrement(ctx, p.db, key, delta)
+}
+
+func (p *PostgreSQL) doIncrement(ctx context.Context, db pginterfaces.DBQuerier, key string, delta int64) (int64, error) {
+	if key == "" {
+		return 0, errors.New("missing key in increment operation")
+	}
+
+	// Values are stored as bytes, so counters are stored as the text representation of the number
+	// Rows that have expired but haven't been garbage-collected yet are treated as missing
+	query := `
+INSERT INTO ` + p.metadata.TableName(pgTableState) + ` AS t
+  (key, value, etag)
+VALUES
+  ($1, convert_to($2::bigint::text, 'UTF8'), gen_random_uuid())
+ON CONFLICT (key)
+DO UPDATE SET
+  value = convert_to((
+    CASE WHEN t.expires_at IS NOT NULL AND t.expires_at < now() THEN 0
+    ELSE convert_from(t.value, 'UTF8')::bigint END + $2::bigint
+  )::text, 'UTF8'),
+  updated_at = now(),
+  etag = gen_random_uuid(),
+  expires_at = CASE WHEN t.expires_at IS NOT NULL AND t.expires_at < now() THEN NULL ELSE t.expires_at END
+RETURNING convert_from(value, 'UTF8')::bigint`
+
+	var n int64
+	err := db.QueryRow(ctx, query, key, delta).Scan(&n)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.InvalidTextRepresentation {
+			return 0, fmt.Errorf("value of key %s is not an integer", key)
+		}
+		return 0, fmt.Errorf("failed to increment key %s: %w", key, err)
+	}
+	return n, nil
+}
+
 func (p *PostgreSQL) execMultiOperation(ctx context.Context, op state.TransactionalStateOperation, db pginterfaces.DBQuerier) error {
 	switch x := op.(type) {
 	case state.SetRequest:
 		return p.doSet(ctx, db, x)
 	case state.DeleteRequest:
 		return p.doDelete(ctx, db, x)
+	case state.IncrementRequest:
+		if !p.enableIncrement {
+			return fmt.Errorf("unsupported operation: %s", op.Operation())
+		}
+		_, err := p.doIncrement(ctx, db, x.Key, x.Delta)
+		return err
 	default:
 		return fmt.Errorf("unsupported operation: %s", op.Operation())
 	}
@@ -657,6 +760,12 @@ func (p *PostgreSQL) CleanupExpired() error {
 
 // Close implements io.Close.
 func (p *PostgreSQL) Close() error {
+	// Stop all watches, which use dedicated connections
+	if p.closeCh != nil && p.closed.CompareAndSwap(false, true) {
+		close(p.closeCh)
+	}
+	p.wg.Wait()
+
 	if p.db != nil {
 		p.db.Close()
 		p.db = nil
@@ -768,6 +877,72 @@ func (p *PostgreSQL) KeysLike(ctx context.Context, req *state.KeysLikeRequest) (
 	return resp, nil
 }
 
+// ListKeys returns the keys in the state table ordered by key, one page at a time.
+// The continuation token is the last key returned, so rows written during the iteration don't affect the keys that are returned next.
+func (p *PostgreSQL) ListKeys(ctx context.Context, req *state.ListKeysRequest) (*state.ListKeysResponse, error) {
+	if req == nil {
+		return nil, errors.New("request object is nil")
+	}
+
+	where := []string{
+		"(expires_at IS NULL OR expires_at > now())",
+	}
+	args := []any{}
+
+	if req.Prefix != "" {
+		args = append(args, stateutils.EscapeLikePattern(req.Prefix)+"%")
+		where = append(where, fmt.Sprintf("key LIKE $%d", len(args)))
+	}
+	if req.ContinuationToken != nil {
+		args = append(args, *req.ContinuationToken)
+		where = append(where, fmt.Sprintf("key > $%d", len(args)))
+	}
+
+	limitClause := ""
+	var pageSize uint32
+	if req.PageSize != nil && *req.PageSize > 0 {
+		pageSize = *req.PageSize
+		// fetch one extra to detect "has next"
+		args = append(args, pageSize+1)
+		limitClause = fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT key
+		FROM %s
+		WHERE %s
+		ORDER BY key ASC%s
+	`, p.metadata.TableName(pgTableState), strings.Join(where, " AND "), limitClause)
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resp := &state.ListKeysResponse{
+		Keys: make([]string, 0, min(pageSize, 1024)),
+	}
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
+		}
+		resp.Keys = append(resp.Keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	//nolint:gosec
+	if pageSize > 0 && uint32(len(resp.Keys)) > pageSize {
+		resp.Keys = resp.Keys[:pageSize]
+		resp.ContinuationToken = ptr.Of(resp.Keys[pageSize-1])
+	}
+
+	return resp, nil
+}
+
 func quoteIdent(s string) string {
 	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
 }
@@ -32,6 +32,7 @@ import (
 	"github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/components-contrib/state"
 	"github.com/dapr/kit/logger"
+	"github.com/dapr/kit/ptr"
 )
 
 const (
@@ -741,6 +742,62 @@ func Test_KeysLiker(t *testing.T) {
 	var _ state.KeysLiker = m.pg
 }
 
+func TestIncrement(t *testing.T) {
+	m, _ := mockDatabase(t)
+	t.Cleanup(m.db.Close)
+
+	t.Run("increment", func(t *testing.T) {
+		m.db.ExpectQuery("INSERT INTO").
+			WithArgs("counter", int64(5)).
+			WillReturnRows(pgxmock.NewRows([]string{"value"}).AddRow(int64(8)))
+
+		n, err := m.pg.Increment(t.Context(), "counter", 5)
+		require.NoError(t, err)
+		assert.Equal(t, int64(8), n)
+	})
+
+	t.Run("increment in transaction", func(t *testing.T) {
+		m.db.ExpectBegin()
+		m.db.ExpectExec("INSERT INTO").
+			WithArgs("counter", []byte("1")).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		m.db.ExpectQuery("INSERT INTO").
+			WithArgs("counter", int64(2)).
+			WillReturnRows(pgxmock.NewRows([]string{"value"}).AddRow(int64(3)))
+		m.db.ExpectCommit()
+
+		err := m.pg.Multi(t.Context(), &state.TransactionalStateRequest{
+			Operations: []state.TransactionalStateOperation{
+				state.SetRequest{Key: "counter", Value: 1},
+				state.IncrementRequest{Key: "counter", Delta: 2},
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	require.NoError(t, m.db.ExpectationsWereMet())
+}
+
+func TestListKeys(t *testing.T) {
+	m, _ := mockDatabase(t)
+	t.Cleanup(m.db.Close)
+
+	m.db.ExpectQuery(`SELECT key\s+FROM state\s+WHERE .* AND key LIKE \$1 AND key > \$2\s+ORDER BY key ASC LIMIT \$3`).
+		WithArgs(`app\_1||%`, "app_1||a", uint32(3)).
+		WillReturnRows(pgxmock.NewRows([]string{"key"}).AddRow("app_1||b").AddRow("app_1||c").AddRow("app_1||d"))
+
+	res, err := m.pg.ListKeys(t.Context(), &state.ListKeysRequest{
+		Prefix:            "app_1||",
+		ContinuationToken: ptr.Of("app_1||a"),
+		PageSize:          ptr.Of(uint32(2)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app_1||b", "app_1||c"}, res.Keys)
+	require.NotNil(t, res.ContinuationToken)
+	assert.Equal(t, "app_1||c", *res.ContinuationToken)
+	require.NoError(t, m.db.ExpectationsWereMet())
+}
+
 func createSetRequest() state.SetRequest {
 	return state.SetRequest{
 		Key:   randomKey(),
@@ -766,8 +823,9 @@ func mockDatabase(t *testing.T) (*mocks, error) {
 		metadata: pgMetadata{
 			Timeout: 30 * time.Second,
 		},
-		logger: logger,
-		db:     db,
+		logger:          logger,
+		db:              db,
+		enableIncrement: true,
 	}
 
 	return &mocks{
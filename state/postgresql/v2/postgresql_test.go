@@ -0,0 +1,76 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/kit/logger"
+)
+
+func TestOptions(t *testing.T) {
+	log := logger.NewLogger("test")
+
+	t.Run("default options", func(t *testing.T) {
+		s := NewPostgreSQLStateStore(log)
+		assert.True(t, state.FeatureAtomicIncrement.IsPresent(s.Features()))
+	})
+
+	t.Run("watch enabled in the metadata", func(t *testing.T) {
+		s := NewPostgreSQLStateStore(log).(*PostgreSQL)
+		assert.False(t, state.FeatureWatch.IsPresent(s.Features()))
+		err := s.Watch(t.Context(), &state.WatchRequest{}, func(context.Context, *state.WatchEvent) error { return nil })
+		require.ErrorContains(t, err, "enableWatch")
+
+		s.metadata.EnableWatch = true
+		assert.True(t, state.FeatureWatch.IsPresent(s.Features()))
+	})
+
+	t.Run("watch disabled", func(t *testing.T) {
+		s := NewPostgreSQLStateStoreWithOptions(log, Options{NoWatch: true}).(*PostgreSQL)
+		s.metadata.EnableWatch = true
+		assert.False(t, state.FeatureWatch.IsPresent(s.Features()))
+		err := s.Watch(t.Context(), &state.WatchRequest{}, func(context.Context, *state.WatchEvent) error { return nil })
+		require.ErrorContains(t, err, "not supported")
+	})
+
+	t.Run("increment disabled", func(t *testing.T) {
+		s := NewPostgreSQLStateStoreWithOptions(log, Options{NoIncrement: true}).(*PostgreSQL)
+		assert.False(t, state.FeatureAtomicIncrement.IsPresent(s.Features()))
+		_, err := s.Increment(t.Context(), "counter", 1)
+		require.ErrorContains(t, err, "not supported")
+
+		// Increments in transactions are rejected too
+		err = s.Multi(t.Context(), &state.TransactionalStateRequest{
+			Operations: []state.TransactionalStateOperation{
+				state.IncrementRequest{Key: "counter", Delta: 1},
+			},
+		})
+		require.ErrorContains(t, err, "unsupported operation")
+	})
+
+	t.Run("CockroachDB", func(t *testing.T) {
+		s := NewCockroachDBStateStore(log).(*PostgreSQL)
+		s.metadata.EnableWatch = true
+		features := s.Features()
+		assert.False(t, state.FeatureAtomicIncrement.IsPresent(features))
+		assert.False(t, state.FeatureWatch.IsPresent(features))
+		assert.True(t, state.FeatureTransactional.IsPresent(features))
+	})
+}
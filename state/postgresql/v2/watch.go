@@ -0,0 +1,234 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	pgtransactions "github.com/dapr/components-contrib/common/component/postgresql/transactions"
+	"github.com/dapr/components-contrib/state"
+)
+
+// Maximum size of the keys that can be watched: PostgreSQL limits the payload of notifications to 8000 bytes.
+const maxWatchKeySize = 7900
+
+type watchNotification struct {
+	Key       string `json:"key"`
+	Operation string `json:"op"`
+}
+
+// Watch invokes the handler for every change to the keys selected by the request, until ctx is canceled.
+// Changes are detected using a trigger on the state table that sends notifications with NOTIFY, which is created during migrations when the "enableWatch" metadata property is true.
+// Keys that have expired are reported as deleted when they are removed by the garbage collector.
+func (p *PostgreSQL) Watch(ctx context.Context, req *state.WatchRequest, handler state.WatchHandler) error {
+	if !p.enableWatch {
+		return errors.New("watch is not supported by this database")
+	}
+	if !p.metadata.EnableWatch {
+		return errors.New("watch is disabled: set the 'enableWatch' metadata property to true to enable it")
+	}
+	if req == nil {
+		return errors.New("request object is nil")
+	}
+	if handler == nil {
+		return errors.New("handler is nil")
+	}
+
+	// LISTEN requires a dedicated connection, which is removed from the pool and closed when the watch ends
+	pool, ok := p.db.(*pgxpool.Pool)
+	if !ok {
+		return errors.New("watch requires a connection pool")
+	}
+	poolConn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	conn := poolConn.Hijack()
+
+	_, err = conn.Exec(ctx, "LISTEN "+quoteIdent(p.watchChannel()))
+	if err != nil {
+		conn.Close(context.Background())
+		return fmt.Errorf("failed to listen for changes: %w", err)
+	}
+
+	watchReq := *req
+	watchCtx, cancel := context.WithCancel(ctx)
+	p.wg.Add(2)
+	go func() {
+		defer p.wg.Done()
+		select {
+		case <-watchCtx.Done():
+		case <-p.closeCh:
+			cancel()
+		}
+	}()
+	go func() {
+		defer p.wg.Done()
+		defer cancel()
+		defer conn.Close(context.Background())
+
+		for {
+			notification, err := conn.WaitForNotification(watchCtx)
+			if err != nil {
+				if watchCtx.Err() == nil {
+					p.logger.Errorf("Error waiting for notifications, watch stopped: %v", err)
+				}
+				return
+			}
+
+			p.handleWatchNotification(watchCtx, &watchReq, handler, notification)
+		}
+	}()
+
+	return nil
+}
+
+func (p *PostgreSQL) handleWatchNotification(ctx context.Context, req *state.WatchRequest, handler state.WatchHandler, notification *pgconn.Notification) {
+	var n watchNotification
+	err := json.Unmarshal([]byte(notification.Payload), &n)
+	if err != nil {
+		p.logger.Errorf("Error parsing notification payload: %v", err)
+		return
+	}
+	if !req.Matches(n.Key) {
+		return
+	}
+
+	e := &state.WatchEvent{
+		Key: n.Key,
+	}
+	switch n.Operation {
+	case "delete":
+		e.Operation = state.OperationDelete
+	case "upsert":
+		// Notifications contain the key only, as values can be larger than the maximum payload size
+		res, err := p.Get(ctx, &state.GetRequest{Key: n.Key})
+		if err != nil {
+			p.logger.Errorf("Error retrieving value of key %s: %v", n.Key, err)
+			return
+		}
+		if res.Data == nil {
+			// The key was deleted or has expired in the meanwhile
+			return
+		}
+		e.Operation = state.OperationUpsert
+		e.Data = res.Data
+		e.ETag = res.ETag
+		e.Metadata = res.Metadata
+	default:
+		p.logger.Warnf("Ignoring notification with unknown operation '%s'", n.Operation)
+		return
+	}
+
+	err = handler(ctx, e)
+	if err != nil {
+		p.logger.Errorf("Error invoking watch handler for key %s: %v", n.Key, err)
+	}
+}
+
+// watchChannel returns the name of the channel where changes to the state table are notified.
+func (p *PostgreSQL) watchChannel() string {
+	h := fnv.New64a()
+	h.Write([]byte(p.metadata.TableName(pgTableState)))
+	return "dapr_state_" + strconv.FormatUint(h.Sum64(), 16)
+}
+
+// watchEnabled returns true if the database supports watching keys and the notify trigger is enabled in the metadata.
+func (p *PostgreSQL) watchEnabled() bool {
+	return p.enableWatch && p.metadata.EnableWatch
+}
+
+// migrateWatchTrigger creates the trigger that notifies changes to the state table when watching keys is enabled, or drops it when it's not.
+// The trigger makes every write send a notification, so it's not created unless needed.
+func (p *PostgreSQL) migrateWatchTrigger(ctx context.Context) error {
+	stateTable := p.metadata.TableName(pgTableState)
+	channel := p.watchChannel()
+	funcName := channel + "_notify"
+
+	_, err := pgtransactions.ExecuteInTransaction[struct{}](ctx, p.logger, p.db, p.metadata.Timeout, func(ctx context.Context, tx pgx.Tx) (res struct{}, err error) {
+		// Serialize the changes to the trigger across multiple instances
+		h := fnv.New64a()
+		h.Write([]byte(funcName))
+		//nolint:gosec
+		_, err = tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", int64(h.Sum64()))
+		if err != nil {
+			return res, fmt.Errorf("failed to acquire lock: %w", err)
+		}
+
+		var exists bool
+		err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM pg_trigger WHERE tgrelid = to_regclass($1) AND tgname = $2)`, stateTable, funcName).Scan(&exists)
+		if err != nil {
+			return res, fmt.Errorf("failed to check if notify trigger exists: %w", err)
+		}
+
+		if !p.metadata.EnableWatch {
+			if !exists {
+				return res, nil
+			}
+			p.logger.Infof("Dropping notify trigger from state table '%s' as watching keys is disabled", stateTable)
+			_, err = tx.Exec(ctx, fmt.Sprintf(`DROP TRIGGER IF EXISTS %[1]s ON %[2]s; DROP FUNCTION IF EXISTS %[1]s()`, funcName, stateTable))
+			if err != nil {
+				return res, fmt.Errorf("failed to drop notify trigger: %w", err)
+			}
+			return res, nil
+		}
+
+		_, err = tx.Exec(ctx, fmt.Sprintf(`
+CREATE OR REPLACE FUNCTION %[1]s() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    IF octet_length(OLD.key) <= %[3]d THEN
+      PERFORM pg_notify('%[2]s', json_build_object('key', OLD.key, 'op', 'delete')::text);
+    END IF;
+    RETURN OLD;
+  END IF;
+  IF octet_length(NEW.key) <= %[3]d THEN
+    PERFORM pg_notify('%[2]s', json_build_object('key', NEW.key, 'op', 'upsert')::text);
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;`, funcName, channel, maxWatchKeySize))
+		if err != nil {
+			return res, fmt.Errorf("failed to create notify function: %w", err)
+		}
+		if exists {
+			return res, nil
+		}
+
+		p.logger.Infof("Creating notify trigger on state table '%s'", stateTable)
+		_, err = tx.Exec(ctx, fmt.Sprintf(
+			`CREATE TRIGGER %[1]s AFTER INSERT OR UPDATE OR DELETE ON %[2]s FOR EACH ROW EXECUTE FUNCTION %[1]s()`,
+			funcName, stateTable,
+		))
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.DuplicateObject {
+				return res, nil
+			}
+			return res, fmt.Errorf("failed to create notify trigger: %w", err)
+		}
+		return res, nil
+	})
+	return err
+}
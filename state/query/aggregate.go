@@ -0,0 +1,161 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// Aliases are used as names of fields in the native queries, so they are restricted to identifiers.
+var aliasRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// AggregateFields is the "aggregate" section of a query, as it appears in JSON.
+// When a query has an "aggregate" section, it returns one item for each group, instead of the items selected by the filter.
+// The data of each item is a JSON object with the values of the group-by keys and of the aggregations, using the aliases as names.
+type AggregateFields struct {
+	GroupBy []string         `json:"groupBy,omitempty"`
+	Fields  []AggregateField `json:"fields"`
+}
+
+// AggregateField is an aggregation, as it appears in JSON.
+type AggregateField struct {
+	// Op is one of COUNT, SUM, MIN, and MAX.
+	Op string `json:"op"`
+	// Key of the field to aggregate. It is not used with COUNT.
+	Key string `json:"key,omitempty"`
+	// As is the alias of the result.
+	As string `json:"as"`
+}
+
+// Aggregate is the aggregation of the items selected by a query.
+type Aggregate struct {
+	// GroupBy is the list of keys of the fields to group items by.
+	// If it is empty, the query returns a single item that aggregates all the items selected by the filter.
+	GroupBy []string
+	// Aggregations is the list of aggregations to compute for each group.
+	Aggregations []Aggregation
+}
+
+// HasKey returns true if the key is a group-by key or the alias of an aggregation.
+// With aggregations, these are the only keys that can be used to sort results.
+func (a *Aggregate) HasKey(key string) bool {
+	if slices.Contains(a.GroupBy, key) {
+		return true
+	}
+	for _, agg := range a.Aggregations {
+		if agg.GetAlias() == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Aggregation is an aggregation computed over a group of items.
+type Aggregation interface {
+	// GetAlias returns the name of the result of the aggregation.
+	GetAlias() string
+}
+
+// COUNT counts the items in a group.
+type COUNT struct {
+	Alias string
+}
+
+func (a *COUNT) GetAlias() string {
+	return a.Alias
+}
+
+// SUM sums the numeric values of a field.
+type SUM struct {
+	Key   string
+	Alias string
+}
+
+func (a *SUM) GetAlias() string {
+	return a.Alias
+}
+
+// MIN returns the minimum of the numeric values of a field.
+type MIN struct {
+	Key   string
+	Alias string
+}
+
+func (a *MIN) GetAlias() string {
+	return a.Alias
+}
+
+// MAX returns the maximum of the numeric values of a field.
+type MAX struct {
+	Key   string
+	Alias string
+}
+
+func (a *MAX) GetAlias() string {
+	return a.Alias
+}
+
+// ParseAggregate parses the "aggregate" section of a query.
+func ParseAggregate(fields *AggregateFields) (*Aggregate, error) {
+	if len(fields.Fields) == 0 {
+		return nil, errors.New("aggregate must contain at least one field")
+	}
+
+	res := &Aggregate{
+		GroupBy:      make([]string, len(fields.GroupBy)),
+		Aggregations: make([]Aggregation, len(fields.Fields)),
+	}
+	names := make(map[string]struct{}, len(fields.GroupBy)+len(fields.Fields))
+	for i, key := range fields.GroupBy {
+		if key == "" {
+			return nil, errors.New("group-by keys must not be empty")
+		}
+		if _, ok := names[key]; ok {
+			return nil, fmt.Errorf("duplicate group-by key %q", key)
+		}
+		names[key] = struct{}{}
+		res.GroupBy[i] = key
+	}
+
+	for i, f := range fields.Fields {
+		if !aliasRegex.MatchString(f.As) {
+			return nil, fmt.Errorf("invalid alias %q: aliases must start with a letter and can only contain letters, numbers, and underscores", f.As)
+		}
+		if _, ok := names[f.As]; ok {
+			return nil, fmt.Errorf("duplicate alias %q", f.As)
+		}
+		names[f.As] = struct{}{}
+
+		switch f.Op {
+		case "COUNT":
+			res.Aggregations[i] = &COUNT{Alias: f.As}
+		case "SUM":
+			res.Aggregations[i] = &SUM{Key: f.Key, Alias: f.As}
+		case "MIN":
+			res.Aggregations[i] = &MIN{Key: f.Key, Alias: f.As}
+		case "MAX":
+			res.Aggregations[i] = &MAX{Key: f.Key, Alias: f.As}
+		default:
+			return nil, fmt.Errorf("unsupported aggregation %q", f.Op)
+		}
+		if f.Op != "COUNT" && f.Key == "" {
+			return nil, fmt.Errorf("%s aggregation %q must have a key", f.Op, f.As)
+		}
+	}
+
+	return res, nil
+}
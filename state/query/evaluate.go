@@ -0,0 +1,232 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// EvaluateItem is an item that is evaluated in-process by Evaluate.
+type EvaluateItem[T any] struct {
+	// Key of the item, used to sort items that are otherwise equal, so pagination is stable.
+	Key string
+	// Value of the item, decoded from JSON.
+	Value any
+	// Data is returned as-is.
+	Data T
+}
+
+// Evaluate filters, sorts, and paginates the items in-process, for state stores that do not have a native query language.
+// Values are compared using the types they have in JSON: numbers are compared numerically and strings lexicographically, and values of different types never match.
+// Fields that are missing from a value never match any filter, including NEQ.
+// The token is the number of items to skip, and it is returned only if there are more items after the current page.
+// Aggregations are not supported.
+func Evaluate[T any](q *Query, items []EvaluateItem[T]) (res []T, token string, err error) {
+	if q.Aggregate != nil {
+		return nil, "", errors.New("aggregations are not supported by this state store")
+	}
+
+	matched := make([]EvaluateItem[T], 0, len(items))
+	for _, item := range items {
+		var ok bool
+		ok, err = Match(q.Filter, item.Value)
+		if err != nil {
+			return nil, "", err
+		}
+		if ok {
+			matched = append(matched, item)
+		}
+	}
+
+	slices.SortStableFunc(matched, func(a, b EvaluateItem[T]) int {
+		for _, s := range q.Sort {
+			c := compareForSort(lookupField(a.Value, s.Key), lookupField(b.Value, s.Key))
+			if strings.EqualFold(s.Order, DESC) {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	var skip int
+	if q.Page.Token != "" {
+		skip, err = strconv.Atoi(q.Page.Token)
+		if err != nil || skip < 0 {
+			return nil, "", fmt.Errorf("invalid pagination token: %s", q.Page.Token)
+		}
+	}
+	if skip > len(matched) {
+		skip = len(matched)
+	}
+	matched = matched[skip:]
+	if q.Page.Limit > 0 && len(matched) > q.Page.Limit {
+		matched = matched[:q.Page.Limit]
+		token = strconv.Itoa(skip + q.Page.Limit)
+	}
+
+	res = make([]T, len(matched))
+	for i, item := range matched {
+		res[i] = item.Data
+	}
+	return res, token, nil
+}
+
+// Match returns true if the value, decoded from JSON, is selected by the filter.
+// A nil filter matches all values.
+func Match(filter Filter, value any) (bool, error) {
+	switch f := filter.(type) {
+	case nil:
+		return true, nil
+	case *EQ:
+		return matchField(value, f.Key, f.Val, func(c int) bool { return c == 0 })
+	case *NEQ:
+		return matchField(value, f.Key, f.Val, func(c int) bool { return c != 0 })
+	case *GT:
+		return matchField(value, f.Key, f.Val, func(c int) bool { return c > 0 })
+	case *GTE:
+		return matchField(value, f.Key, f.Val, func(c int) bool { return c >= 0 })
+	case *LT:
+		return matchField(value, f.Key, f.Val, func(c int) bool { return c < 0 })
+	case *LTE:
+		return matchField(value, f.Key, f.Val, func(c int) bool { return c <= 0 })
+	case *IN:
+		if len(f.Vals) == 0 {
+			return false, fmt.Errorf("empty IN operator for key %q", f.Key)
+		}
+		for _, v := range f.Vals {
+			ok, err := matchField(value, f.Key, v, func(c int) bool { return c == 0 })
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case *AND:
+		for _, sub := range f.Filters {
+			ok, err := Match(sub, value)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case *OR:
+		for _, sub := range f.Filters {
+			ok, err := Match(sub, value)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("unsupported filter type %#v", filter)
+	}
+}
+
+func matchField(value any, key string, expect any, fn func(int) bool) (bool, error) {
+	switch expect.(type) {
+	case map[string]any, []any:
+		return false, errors.New("filter values must be strings, numbers, booleans, or null")
+	}
+
+	c, ok := compareValues(lookupField(value, key), expect)
+	if !ok {
+		return false, nil
+	}
+	return fn(c), nil
+}
+
+// lookupField returns the value of the field with the given key, in dot notation, or nil if the field does not exist.
+func lookupField(value any, key string) any {
+	v, _ := lookupFieldOK(value, key)
+	return v
+}
+
+// compareValues compares two scalar values of the same type.
+// It returns false if the values cannot be compared.
+func compareValues(a, b any) (int, bool) {
+	switch av := a.(type) {
+	case float64:
+		bv, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		return cmp.Compare(av, bv), true
+	case string:
+		bv, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(av, bv), true
+	case bool:
+		bv, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case av == bv:
+			return 0, true
+		case !av:
+			return -1, true
+		default:
+			return 1, true
+		}
+	default:
+		return 0, false
+	}
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// compareForSort compares any two values, ordering values of different types by type: missing fields and nulls first, then booleans, numbers, strings, and all other values.
+func compareForSort(a, b any) int {
+	ra, rb := sortRank(a), sortRank(b)
+	if ra != rb {
+		return cmp.Compare(ra, rb)
+	}
+	c, _ := compareValues(a, b)
+	return c
+}
+
+func sortRank(v any) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case float64:
+		return 2
+	case string:
+		return 3
+	default:
+		return 4
+	}
+}
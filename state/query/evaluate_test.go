@@ -0,0 +1,102 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	values := map[string]string{
+		"1": `{"person":{"org":"A","name":"Zoe"},"state":"CA"}`,
+		"2": `{"person":{"org":"A","name":"Adam"},"state":"WA"}`,
+		"3": `{"person":{"org":"B","name":"Bob"},"state":"CA"}`,
+		"4": `{"person":{"org":"B","name":"Carl"},"state":"TX"}`,
+		"5": `{"person":{"org":"C","name":"Dan"},"state":"WA"}`,
+		"6": `{"person":{"org":124,"name":"Eve"},"state":"OR"}`,
+		"7": `{"person":{"org":5,"name":"Fay"},"state":"WA"}`,
+		"8": `"not an object"`,
+	}
+	items := make([]EvaluateItem[string], 0, len(values))
+	for k, v := range values {
+		var val any
+		require.NoError(t, json.Unmarshal([]byte(v), &val))
+		items = append(items, EvaluateItem[string]{Key: k, Value: val, Data: k})
+	}
+
+	loadQuery := func(t *testing.T, file string) *Query {
+		t.Helper()
+		data, err := os.ReadFile("../../tests/state/query/" + file)
+		require.NoError(t, err)
+		var q Query
+		require.NoError(t, json.Unmarshal(data, &q))
+		return &q
+	}
+
+	t.Run("filter, sort, and paginate", func(t *testing.T) {
+		q := loadQuery(t, "q4.json")
+		res, token, err := Evaluate(q, items)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2", "3"}, res)
+		assert.Equal(t, "2", token)
+
+		q.Page.Token = token
+		res, token, err = Evaluate(q, items)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1"}, res)
+		assert.Empty(t, token)
+	})
+
+	t.Run("numeric comparisons", func(t *testing.T) {
+		q := loadQuery(t, "q8.json")
+		q.Page.Limit = 0
+		res, token, err := Evaluate(q, items)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"7", "6"}, res)
+		assert.Empty(t, token)
+	})
+
+	t.Run("no filter", func(t *testing.T) {
+		res, _, err := Evaluate(&Query{}, items)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "2", "3", "4", "5", "6", "7", "8"}, res)
+	})
+
+	t.Run("NEQ does not match missing fields", func(t *testing.T) {
+		res, _, err := Evaluate(&Query{Filter: &NEQ{Key: "state", Val: "CA"}}, items)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2", "4", "5", "6", "7"}, res)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		q := &Query{QueryFields: QueryFields{Page: Pagination{Limit: 1, Token: "abc"}}}
+		_, _, err := Evaluate(q, items)
+		require.Error(t, err)
+	})
+
+	t.Run("aggregations are not supported", func(t *testing.T) {
+		_, _, err := Evaluate(loadQuery(t, "q9-aggregate.json"), items)
+		require.Error(t, err)
+	})
+
+	t.Run("empty IN", func(t *testing.T) {
+		_, _, err := Evaluate(&Query{Filter: &IN{Key: "state"}}, items)
+		require.Error(t, err)
+	})
+}
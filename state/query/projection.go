@@ -0,0 +1,94 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ValidateFields validates the list of keys of the fields to return, in dot notation.
+// Keys must not be empty and must not overlap, for example "person" and "person.org" cannot be selected together.
+func ValidateFields(fields []string) error {
+	for i, key := range fields {
+		if key == "" || slices.Contains(strings.Split(key, "."), "") {
+			return fmt.Errorf("invalid field %q", key)
+		}
+		for _, other := range fields[:i] {
+			if key == other || strings.HasPrefix(key, other+".") || strings.HasPrefix(other, key+".") {
+				return fmt.Errorf("field %q overlaps with field %q", key, other)
+			}
+		}
+	}
+	return nil
+}
+
+// Project returns a JSON object that contains only the fields of the value with the given keys, in dot notation.
+// Fields keep their position in the value, so "person.org" is returned as {"person":{"org":...}}.
+// Fields that are missing from the value are omitted.
+func Project(value any, fields []string) map[string]any {
+	res := make(map[string]any, len(fields))
+	for _, key := range fields {
+		v, ok := lookupFieldOK(value, key)
+		if ok {
+			SetField(res, key, v)
+		}
+	}
+	return res
+}
+
+// ProjectJSON is like Project, but it works with values encoded as JSON.
+func ProjectJSON(data []byte, fields []string) ([]byte, error) {
+	var value any
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := value.(map[string]any); !ok {
+		return nil, errors.New("only JSON objects can be projected")
+	}
+	return json.Marshal(Project(value, fields))
+}
+
+// SetField sets the field with the given key, in dot notation, in the object, creating the objects that contain it if needed.
+func SetField(obj map[string]any, key string, value any) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := obj[part].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			obj[part] = next
+		}
+		obj = next
+	}
+	obj[parts[len(parts)-1]] = value
+}
+
+// lookupFieldOK returns the value of the field with the given key, in dot notation, and whether the field exists.
+func lookupFieldOK(value any, key string) (any, bool) {
+	for _, part := range strings.Split(key, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		value, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFields(t *testing.T) {
+	require.NoError(t, ValidateFields([]string{"person.name", "person.org", "state"}))
+	require.Error(t, ValidateFields([]string{""}))
+	require.Error(t, ValidateFields([]string{"person..name"}))
+	require.Error(t, ValidateFields([]string{"state", "state"}))
+	require.Error(t, ValidateFields([]string{"person", "person.name"}))
+	require.Error(t, ValidateFields([]string{"person.name", "person"}))
+}
+
+func TestProjectJSON(t *testing.T) {
+	data := []byte(`{"person":{"org":"A","name":"Zoe","id":null},"state":"CA","amount":10}`)
+
+	t.Run("nested fields", func(t *testing.T) {
+		res, err := ProjectJSON(data, []string{"person.name", "person.id", "amount"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"person":{"name":"Zoe","id":null},"amount":10}`, string(res))
+	})
+
+	t.Run("missing fields are omitted", func(t *testing.T) {
+		res, err := ProjectJSON(data, []string{"person.age", "state.code", "missing"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{}`, string(res))
+	})
+
+	t.Run("values that are not objects", func(t *testing.T) {
+		_, err := ProjectJSON([]byte(`"CA"`), []string{"state"})
+		require.Error(t, err)
+	})
+}
@@ -15,15 +15,18 @@ package query
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 )
 
 const (
-	FILTER = "filter"
-	SORT   = "sort"
-	PAGE   = "page"
-	ASC    = "ASC"
-	DESC   = "DESC"
+	FILTER    = "filter"
+	SORT      = "sort"
+	PAGE      = "page"
+	AGGREGATE = "aggregate"
+	FIELDS    = "fields"
+	ASC       = "ASC"
+	DESC      = "DESC"
 )
 
 type Sorting struct {
@@ -38,9 +41,11 @@ type Pagination struct {
 
 // used only for intermediate query value.
 type QueryFields struct {
-	Filters map[string]interface{} `json:"filter"`
-	Sort    []Sorting              `json:"sort"`
-	Page    Pagination             `json:"page"`
+	Filters   map[string]interface{} `json:"filter"`
+	Sort      []Sorting              `json:"sort"`
+	Page      Pagination             `json:"page"`
+	Aggregate *AggregateFields       `json:"aggregate,omitempty"`
+	Fields    []string               `json:"fields,omitempty"`
 }
 
 type Query struct {
@@ -48,6 +53,9 @@ type Query struct {
 
 	// derived from Filters
 	Filter Filter
+
+	// derived from Aggregate
+	Aggregate *Aggregate
 }
 
 type Visitor interface {
@@ -73,6 +81,22 @@ type Visitor interface {
 	Finalize(string, *Query) error
 }
 
+// AggregationVisitor is implemented by visitors that support aggregations.
+type AggregationVisitor interface {
+	Visitor
+
+	// returns "count" expression
+	VisitCOUNT(*COUNT) (string, error)
+	// returns "sum" expression
+	VisitSUM(*SUM) (string, error)
+	// returns "min" expression
+	VisitMIN(*MIN) (string, error)
+	// returns "max" expression
+	VisitMAX(*MAX) (string, error)
+	// receives concatenated filters and the aggregation expressions, in the same order as the aggregations in the query, and finalizes the native query
+	FinalizeAggregate(string, []string, *Query) error
+}
+
 type Builder struct {
 	visitor Visitor
 }
@@ -89,9 +113,39 @@ func (h *Builder) BuildQuery(q *Query) error {
 		return err
 	}
 
+	if q.Aggregate != nil {
+		visitor, ok := h.visitor.(AggregationVisitor)
+		if !ok {
+			return errors.New("aggregations are not supported by this state store")
+		}
+		aggregations := make([]string, len(q.Aggregate.Aggregations))
+		for i, a := range q.Aggregate.Aggregations {
+			aggregations[i], err = h.buildAggregation(visitor, a)
+			if err != nil {
+				return err
+			}
+		}
+		return visitor.FinalizeAggregate(filters, aggregations, q)
+	}
+
 	return h.visitor.Finalize(filters, q)
 }
 
+func (h *Builder) buildAggregation(visitor AggregationVisitor, aggregation Aggregation) (string, error) {
+	switch a := aggregation.(type) {
+	case *COUNT:
+		return visitor.VisitCOUNT(a)
+	case *SUM:
+		return visitor.VisitSUM(a)
+	case *MIN:
+		return visitor.VisitMIN(a)
+	case *MAX:
+		return visitor.VisitMAX(a)
+	default:
+		return "", fmt.Errorf("unsupported aggregation type %#v", aggregation)
+	}
+}
+
 func (h *Builder) buildFilter(filter Filter) (string, error) {
 	if filter == nil {
 		return "", nil
@@ -125,15 +179,38 @@ func (q *Query) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	if len(q.QueryFields.Filters) == 0 {
-		return nil
+
+	if len(q.QueryFields.Filters) != 0 {
+		q.Filter, err = ParseFilter(q.QueryFields.Filters)
+		if err != nil {
+			return err
+		}
 	}
 
-	filter, err := ParseFilter(q.QueryFields.Filters)
-	if err != nil {
-		return err
+	if len(q.QueryFields.Fields) != 0 {
+		err = ValidateFields(q.QueryFields.Fields)
+		if err != nil {
+			return err
+		}
+	}
+
+	if q.QueryFields.Aggregate != nil {
+		// Results of aggregations are not the values of the items, so they cannot be projected
+		if len(q.QueryFields.Fields) != 0 {
+			return errors.New("fields cannot be selected in queries with aggregations")
+		}
+		q.Aggregate, err = ParseAggregate(q.QueryFields.Aggregate)
+		if err != nil {
+			return err
+		}
+
+		// Results of aggregations can only be sorted by the group-by keys and the aliases
+		for _, s := range q.QueryFields.Sort {
+			if !q.Aggregate.HasKey(s.Key) {
+				return fmt.Errorf("cannot sort aggregation results by %q: sort keys must be group-by keys or aliases", s.Key)
+			}
+		}
 	}
 
-	q.Filter = filter
 	return nil
 }
@@ -141,3 +141,80 @@ func TestQuery(t *testing.T) {
 		assert.Equal(t, test.query, q)
 	}
 }
+
+func TestQueryAggregate(t *testing.T) {
+	t.Run("valid aggregate", func(t *testing.T) {
+		data, err := os.ReadFile("../../tests/state/query/q9-aggregate.json")
+		require.NoError(t, err)
+		var q Query
+		err = json.Unmarshal(data, &q)
+		require.NoError(t, err)
+
+		assert.Equal(t, &NEQ{Key: "status", Val: "CANCELED"}, q.Filter)
+		assert.Equal(t, &Aggregate{
+			GroupBy: []string{"status", "person.org"},
+			Aggregations: []Aggregation{
+				&COUNT{Alias: "orders"},
+				&SUM{Key: "amount", Alias: "total"},
+				&MIN{Key: "amount", Alias: "smallest"},
+				&MAX{Key: "amount", Alias: "largest"},
+			},
+		}, q.Aggregate)
+	})
+
+	t.Run("invalid aggregates", func(t *testing.T) {
+		tests := map[string]string{
+			"no fields":           `{"aggregate":{"groupBy":["status"]}}`,
+			"unsupported op":      `{"aggregate":{"fields":[{"op":"AVG","key":"amount","as":"avg"}]}}`,
+			"missing key":         `{"aggregate":{"fields":[{"op":"SUM","as":"total"}]}}`,
+			"invalid alias":       `{"aggregate":{"fields":[{"op":"COUNT","as":"my count"}]}}`,
+			"duplicate alias":     `{"aggregate":{"fields":[{"op":"COUNT","as":"n"},{"op":"SUM","key":"amount","as":"n"}]}}`,
+			"alias is group key":  `{"aggregate":{"groupBy":["n"],"fields":[{"op":"COUNT","as":"n"}]}}`,
+			"empty group key":     `{"aggregate":{"groupBy":[""],"fields":[{"op":"COUNT","as":"n"}]}}`,
+			"sort by other field": `{"aggregate":{"groupBy":["status"],"fields":[{"op":"COUNT","as":"n"}]},"sort":[{"key":"amount"}]}`,
+		}
+		for name, input := range tests {
+			t.Run(name, func(t *testing.T) {
+				var q Query
+				err := json.Unmarshal([]byte(input), &q)
+				require.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("visitor without aggregations", func(t *testing.T) {
+		var q Query
+		err := json.Unmarshal([]byte(`{"aggregate":{"fields":[{"op":"COUNT","as":"n"}]}}`), &q)
+		require.NoError(t, err)
+		err = NewQueryBuilder(&noAggregationVisitor{}).BuildQuery(&q)
+		require.ErrorContains(t, err, "aggregations are not supported")
+	})
+}
+
+type noAggregationVisitor struct{}
+
+func (v *noAggregationVisitor) VisitEQ(*EQ) (string, error)   { return "", nil }
+func (v *noAggregationVisitor) VisitNEQ(*NEQ) (string, error) { return "", nil }
+func (v *noAggregationVisitor) VisitGT(*GT) (string, error)   { return "", nil }
+func (v *noAggregationVisitor) VisitGTE(*GTE) (string, error) { return "", nil }
+func (v *noAggregationVisitor) VisitLT(*LT) (string, error)   { return "", nil }
+func (v *noAggregationVisitor) VisitLTE(*LTE) (string, error) { return "", nil }
+func (v *noAggregationVisitor) VisitIN(*IN) (string, error)   { return "", nil }
+func (v *noAggregationVisitor) VisitAND(*AND) (string, error) { return "", nil }
+func (v *noAggregationVisitor) VisitOR(*OR) (string, error)   { return "", nil }
+func (v *noAggregationVisitor) Finalize(string, *Query) error { return nil }
+
+func TestQueryFields(t *testing.T) {
+	data, err := os.ReadFile("../../tests/state/query/q10-fields.json")
+	require.NoError(t, err)
+	var q Query
+	err = json.Unmarshal(data, &q)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"person.name", "state"}, q.Fields)
+
+	err = json.Unmarshal([]byte(`{"fields":["person","person.name"]}`), &q)
+	require.Error(t, err)
+
+	err = json.Unmarshal([]byte(`{"fields":["state"],"aggregate":{"fields":[{"op":"COUNT","as":"n"}]}}`), &q)
+	require.Error(t, err)
+}
@@ -21,6 +21,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 
 	jsoniter "github.com/json-iterator/go"
@@ -83,6 +84,13 @@ const (
 	else
 	  return error("failed to delete " .. KEYS[1])
 	end`
+	incrDefaultQuery = `
+	local val = redis.call("HINCRBY", KEYS[1], "data", ARGV[1]);
+	redis.call("HINCRBY", KEYS[1], "version", 1);
+	if tonumber(ARGV[2]) > 0 then
+	  redis.call("EXPIRE", KEYS[1], ARGV[2]);
+	end;
+	return val`
 	connectedSlavesReplicas  = "connected_slaves:"
 	infoReplicationDelimiter = "\r\n"
 	ttlInSeconds             = "ttlInSeconds"
@@ -103,7 +111,10 @@ type StateStore struct {
 	querySchemas                   querySchemas
 	suppressActorStateStoreWarning atomic.Bool
 
-	logger logger.Logger
+	logger  logger.Logger
+	wg      sync.WaitGroup
+	closed  atomic.Bool
+	closeCh chan struct{}
 }
 
 // NewRedisStateStore returns a new redis state store.
@@ -118,6 +129,7 @@ func newStateStore(log logger.Logger) *StateStore {
 		json:                           jsoniter.ConfigFastest,
 		logger:                         log,
 		suppressActorStateStoreWarning: atomic.Bool{},
+		closeCh:                        make(chan struct{}),
 	}
 }
 
@@ -156,15 +168,23 @@ func (r *StateStore) Init(ctx context.Context, metadata state.Metadata) error {
 
 	r.clientHasJSON = rediscomponent.ClientHasJSONSupport(r.client)
 
+	if r.clientSettings.EnableKeyspaceEvents {
+		err = r.enableKeyspaceEvents(ctx)
+		if err != nil {
+			// Managed Redis services often disable the CONFIG command, but notifications may have been enabled by the administrator
+			r.logger.Warnf("Failed to enable keyspace notifications, make sure '%s' includes '%s' to watch keys: %v", keyspaceEventsConfig, keyspaceEventsFlags, err)
+		}
+	}
+
 	return nil
 }
 
 // Features returns the features available in this state store.
 func (r *StateStore) Features() []state.Feature {
 	if r.clientHasJSON {
-		return []state.Feature{state.FeatureETag, state.FeatureTransactional, state.FeatureTTL, state.FeatureQueryAPI, state.FeatureKeysLike}
+		return []state.Feature{state.FeatureETag, state.FeatureTransactional, state.FeatureTTL, state.FeatureQueryAPI, state.FeatureKeysLike, state.FeatureWatch, state.FeatureListKeys, state.FeatureAtomicIncrement}
 	} else {
-		return []state.Feature{state.FeatureETag, state.FeatureTransactional, state.FeatureTTL, state.FeatureKeysLike}
+		return []state.Feature{state.FeatureETag, state.FeatureTransactional, state.FeatureTTL, state.FeatureKeysLike, state.FeatureWatch, state.FeatureListKeys, state.FeatureAtomicIncrement}
 	}
 }
 
@@ -394,6 +414,32 @@ func (r *StateStore) Set(ctx context.Context, req *state.SetRequest) error {
 	return nil
 }
 
+// Increment adds delta to the counter stored at key.
+// The global TTL is applied to the key, if configured; otherwise its TTL is preserved.
+func (r *StateStore) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	if key == "" {
+		return 0, errors.New("missing key in increment operation")
+	}
+
+	res, parseErr, err := r.client.EvalInt(ctx, incrDefaultQuery, []string{key}, delta, r.globalTTL())
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment key %s: %w", key, err)
+	}
+	if parseErr != nil || res == nil {
+		return 0, fmt.Errorf("failed to parse the value of key %s after increment: %w", key, parseErr)
+	}
+
+	return int64(*res), nil
+}
+
+// globalTTL returns the global TTL in seconds, or 0 if it's not configured.
+func (r *StateStore) globalTTL() int {
+	if r.clientSettings.TTLInSeconds == nil {
+		return 0
+	}
+	return *r.clientSettings.TTLInSeconds
+}
+
 // Multi performs a transactional operation. succeeds only if all operations succeed, and fails if one or more operations fail.
 func (r *StateStore) Multi(ctx context.Context, request *state.TransactionalStateRequest) error {
 	if r.suppressActorStateStoreWarning.CompareAndSwap(false, true) {
@@ -436,6 +482,14 @@ func (r *StateStore) Multi(ctx context.Context, request *state.TransactionalStat
 				pipe.Do(ctx, "PERSIST", req.Key)
 			}
 
+		case state.IncrementRequest:
+			isReqJSON := isJSON ||
+				(len(req.Metadata) > 0 && req.Metadata[daprmetadata.ContentType] == contenttype.JSONContentType)
+			if isReqJSON {
+				return errors.New("increment operations are not supported for JSON values")
+			}
+			pipe.Do(ctx, "EVAL", incrDefaultQuery, 1, req.Key, req.Delta, r.globalTTL())
+
 		case state.DeleteRequest:
 			if !req.HasETag() {
 				req.ETag = ptr.Of("0")
@@ -589,6 +643,15 @@ func (r *StateStore) Query(ctx context.Context, req *state.QueryRequest) (*state
 }
 
 func (r *StateStore) Close() error {
+	// Stop the watches before closing the client they use
+	if r.closed.CompareAndSwap(false, true) {
+		close(r.closeCh)
+	}
+	r.wg.Wait()
+
+	if r.client == nil {
+		return nil
+	}
 	return r.client.Close()
 }
 
@@ -613,42 +676,10 @@ func (r *StateStore) KeysLike(ctx context.Context, req *state.KeysLikeRequest) (
 	keys := make([]string, 0, 256)
 
 	for {
-		res, err := r.client.DoRead(ctx, "SCAN", cursor, "MATCH", glob, "COUNT", 1000)
+		cursor, keys, err = r.scan(ctx, cursor, glob, 1000, keys)
 		if err != nil {
-			return nil, fmt.Errorf("redis SCAN failed: %w", err)
-		}
-		if res == nil {
-			break
-		}
-
-		arr, ok := res.([]any)
-		if !ok || len(arr) != 2 {
-			return nil, errors.New("unexpected SCAN response")
-		}
-
-		// next cursor
-		if s, ok := toString(arr[0]); ok {
-			cursor = s
-		} else {
-			return nil, errors.New("unexpected SCAN cursor type")
-		}
-
-		// keys
-		switch ks := arr[1].(type) {
-		case []any:
-			for _, v := range ks {
-				if s, ok := toString(v); ok {
-					keys = append(keys, s)
-				}
-			}
-		case []string:
-			keys = append(keys, ks...)
-		default:
-			if s, ok := toString(arr[1]); ok && s != "" {
-				keys = append(keys, s)
-			}
+			return nil, err
 		}
-
 		if cursor == "0" {
 			break
 		}
@@ -696,6 +727,90 @@ func (r *StateStore) KeysLike(ctx context.Context, req *state.KeysLikeRequest) (
 	}, nil
 }
 
+// ListKeys returns the keys in the database one page at a time, using SCAN.
+// The continuation token is the SCAN cursor: keys that exist for the whole iteration are always returned, but they may be returned more than once.
+// PageSize is used as a hint, and pages may contain slightly more keys.
+func (r *StateStore) ListKeys(ctx context.Context, req *state.ListKeysRequest) (*state.ListKeysResponse, error) {
+	if req == nil {
+		return nil, errors.New("request object is nil")
+	}
+
+	cursor := "0"
+	if req.ContinuationToken != nil {
+		if _, err := strconv.ParseUint(*req.ContinuationToken, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid continue token: %w", err)
+		}
+		cursor = *req.ContinuationToken
+	}
+
+	count := 1000
+	if req.PageSize != nil && *req.PageSize > 0 {
+		count = int(*req.PageSize)
+	}
+
+	// SCAN can return fewer keys than COUNT, including none, so keep scanning until the page is full
+	glob := escapeRedisGlob(req.Prefix) + "*"
+	keys := make([]string, 0, min(count, 1024))
+	for {
+		var err error
+		cursor, keys, err = r.scan(ctx, cursor, glob, count-len(keys), keys)
+		if err != nil {
+			return nil, err
+		}
+		if cursor == "0" || len(keys) >= count {
+			break
+		}
+	}
+
+	res := &state.ListKeysResponse{
+		Keys: keys,
+	}
+	if cursor != "0" {
+		res.ContinuationToken = &cursor
+	}
+	return res, nil
+}
+
+// scan performs a single SCAN call, appending the keys it returns to keys.
+func (r *StateStore) scan(ctx context.Context, cursor string, match string, count int, keys []string) (string, []string, error) {
+	res, err := r.client.DoRead(ctx, "SCAN", cursor, "MATCH", match, "COUNT", count)
+	if err != nil {
+		return "", nil, fmt.Errorf("redis SCAN failed: %w", err)
+	}
+	if res == nil {
+		return "0", keys, nil
+	}
+
+	arr, ok := res.([]any)
+	if !ok || len(arr) != 2 {
+		return "", nil, errors.New("unexpected SCAN response")
+	}
+
+	// next cursor
+	next, ok := toString(arr[0])
+	if !ok {
+		return "", nil, errors.New("unexpected SCAN cursor type")
+	}
+
+	// keys
+	switch ks := arr[1].(type) {
+	case []any:
+		for _, v := range ks {
+			if s, ok := toString(v); ok {
+				keys = append(keys, s)
+			}
+		}
+	case []string:
+		keys = append(keys, ks...)
+	default:
+		if s, ok := toString(arr[1]); ok && s != "" {
+			keys = append(keys, s)
+		}
+	}
+
+	return next, keys, nil
+}
+
 func likeToRedisGlob(pat string) (string, error) {
 	var b strings.Builder
 	b.Grow(len(pat))
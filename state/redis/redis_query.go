@@ -33,6 +33,7 @@ type Query struct {
 	query      []interface{}
 	limit      int
 	offset     int64
+	fields     []string
 }
 
 func NewQuery(schemaName string, aliases map[string]string) *Query {
@@ -266,6 +267,7 @@ func (q *Query) Finalize(filters string, qq *query.Query) error {
 		filters = "*"
 	}
 	q.query = []interface{}{filters}
+	q.fields = qq.Fields
 
 	// sorting
 	if len(qq.Sort) > 0 {
@@ -319,6 +321,10 @@ func (q *Query) execute(ctx context.Context, client rediscomponent.RedisClient)
 		}
 	}
 
+	if len(q.fields) > 0 {
+		q.project(res)
+	}
+
 	// set next query token only if limit is specified
 	var token string
 	if q.limit > 0 && len(res) > 0 {
@@ -328,6 +334,22 @@ func (q *Query) execute(ctx context.Context, client rediscomponent.RedisClient)
 	return res, token, err
 }
 
+// project selects the fields of the results.
+// Values are stored as a whole, so fields are selected after they are returned.
+func (q *Query) project(res []state.QueryItem) {
+	for i := range res {
+		if res[i].Error != "" {
+			continue
+		}
+		projected, err := query.ProjectJSON(res[i].Data, q.fields)
+		if err != nil {
+			res[i].Error = err.Error()
+			continue
+		}
+		res[i].Data = projected
+	}
+}
+
 // parseQueryResponsePost28 parses the query Do response from redisearch 2.8+.
 func parseQueryResponsePost28(ret any) ([]state.QueryItem, bool, error) {
 	aarr, ok := ret.(map[any]any)
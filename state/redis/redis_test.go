@@ -585,3 +585,97 @@ func Test_KeyList(t *testing.T) {
 	_, ok := s.(state.KeysLiker)
 	require.True(t, ok)
 }
+
+func TestListKeys(t *testing.T) {
+	s, c := setupMiniredis()
+	defer s.Close()
+
+	ss := &StateStore{
+		client:         c,
+		clientSettings: &rediscomponent.Settings{},
+		json:           jsoniter.ConfigFastest,
+		logger:         logger.NewLogger("test"),
+	}
+
+	expect := make([]string, 10)
+	for i := range expect {
+		expect[i] = "app*||" + strconv.Itoa(i)
+		require.NoError(t, ss.Set(t.Context(), &state.SetRequest{Key: expect[i], Value: "v"}))
+	}
+	// The "*" in the prefix must not be treated as a wildcard
+	require.NoError(t, ss.Set(t.Context(), &state.SetRequest{Key: "appX||0", Value: "v"}))
+
+	found := map[string]struct{}{}
+	var token *string
+	for {
+		res, err := ss.ListKeys(t.Context(), &state.ListKeysRequest{
+			Prefix:            "app*||",
+			PageSize:          ptr.Of(uint32(3)),
+			ContinuationToken: token,
+		})
+		require.NoError(t, err)
+		for _, k := range res.Keys {
+			found[k] = struct{}{}
+		}
+		if res.ContinuationToken == nil {
+			break
+		}
+		token = res.ContinuationToken
+	}
+
+	keys := make([]string, 0, len(found))
+	for k := range found {
+		keys = append(keys, k)
+	}
+	assert.ElementsMatch(t, expect, keys)
+
+	_, err := ss.ListKeys(t.Context(), &state.ListKeysRequest{ContinuationToken: ptr.Of("invalid")})
+	require.Error(t, err)
+}
+
+func TestIncrement(t *testing.T) {
+	s, c := setupMiniredis()
+	defer s.Close()
+
+	ss := &StateStore{
+		client:         c,
+		clientSettings: &rediscomponent.Settings{},
+		json:           jsoniter.ConfigFastest,
+		logger:         logger.NewLogger("test"),
+	}
+
+	t.Run("increment creates and updates the counter", func(t *testing.T) {
+		n, err := ss.Increment(t.Context(), "counter", 5)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), n)
+
+		n, err = ss.Increment(t.Context(), "counter", -2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), n)
+
+		res, err := ss.Get(t.Context(), &state.GetRequest{Key: "counter"})
+		require.NoError(t, err)
+		assert.Equal(t, "3", string(res.Data))
+		assert.Equal(t, "2", *res.ETag)
+	})
+
+	t.Run("increment of a value that is not an integer fails", func(t *testing.T) {
+		require.NoError(t, ss.Set(t.Context(), &state.SetRequest{Key: "not-a-number", Value: "a"}))
+		_, err := ss.Increment(t.Context(), "not-a-number", 1)
+		require.Error(t, err)
+	})
+
+	t.Run("increment in transaction", func(t *testing.T) {
+		err := ss.Multi(t.Context(), &state.TransactionalStateRequest{
+			Operations: []state.TransactionalStateOperation{
+				state.SetRequest{Key: "tx-counter", Value: 10},
+				state.IncrementRequest{Key: "tx-counter", Delta: 2},
+			},
+		})
+		require.NoError(t, err)
+
+		res, err := ss.Get(t.Context(), &state.GetRequest{Key: "tx-counter"})
+		require.NoError(t, err)
+		assert.Equal(t, "12", string(res.Data))
+	})
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dapr/components-contrib/contenttype"
+	daprmetadata "github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/components-contrib/state"
+)
+
+const (
+	keyspaceEventsConfig = "notify-keyspace-events"
+	// K: keyspace events, g: generic commands (DEL, EXPIRE, JSON commands), h: hash commands, x: expired events, e: evicted events
+	keyspaceEventsFlags = "Kghxe"
+)
+
+// Watch invokes the handler for every change to the keys selected by the request, until ctx is canceled or the component is closed.
+// Changes are detected using Redis keyspace notifications, which must be enabled on the server, or with the "enableKeyspaceEvents" metadata property.
+// Keyspace notifications are not propagated across the nodes of a Redis Cluster.
+func (r *StateStore) Watch(ctx context.Context, req *state.WatchRequest, handler state.WatchHandler) error {
+	if r.closed.Load() {
+		return errors.New("component is closed")
+	}
+	if req == nil {
+		return errors.New("request object is nil")
+	}
+	if handler == nil {
+		return errors.New("handler is nil")
+	}
+
+	r.checkKeyspaceEvents(ctx)
+
+	channelPrefix := "__keyspace@" + strconv.Itoa(r.clientSettings.DB) + "__:"
+	var patterns []string
+	if len(req.Keys) > 0 {
+		patterns = make([]string, len(req.Keys))
+		for i, k := range req.Keys {
+			patterns[i] = channelPrefix + escapeRedisGlob(k)
+		}
+	} else {
+		patterns = []string{channelPrefix + escapeRedisGlob(req.Prefix) + "*"}
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	msgCh, err := r.client.PSubscribe(watchCtx, patterns...)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("redis store: error subscribing to keyspace notifications: %w", err)
+	}
+
+	watchReq := *req
+	r.wg.Add(2)
+	go func() {
+		defer r.wg.Done()
+		select {
+		case <-watchCtx.Done():
+		case <-r.closeCh:
+			cancel()
+		}
+	}()
+	go func() {
+		defer r.wg.Done()
+		defer cancel()
+
+		// The channel is closed when watchCtx is canceled
+		for msg := range msgCh {
+			key := strings.TrimPrefix(msg.Channel, channelPrefix)
+			if !watchReq.Matches(key) {
+				continue
+			}
+
+			e, err := r.watchEventFromNotification(watchCtx, key, msg.Payload)
+			if err != nil {
+				if watchCtx.Err() == nil {
+					r.logger.Errorf("Error processing keyspace notification for key %s: %v", key, err)
+				}
+				continue
+			}
+			if e == nil || watchCtx.Err() != nil {
+				continue
+			}
+
+			err = handler(watchCtx, e)
+			if err != nil {
+				r.logger.Errorf("Error invoking watch handler for key %s: %v", key, err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// watchEventFromNotification returns the WatchEvent for a keyspace notification.
+// It returns nil for notifications that do not represent a complete change to the key.
+func (r *StateStore) watchEventFromNotification(ctx context.Context, key string, event string) (*state.WatchEvent, error) {
+	var getReq *state.GetRequest
+	switch event {
+	case "del", "expired", "evicted", "json.del":
+		return &state.WatchEvent{
+			Key:       key,
+			Operation: state.OperationDelete,
+		}, nil
+	case "hincrby":
+		// Values are stored in a hash with HSET, then the version is incremented with HINCRBY as last step
+		getReq = &state.GetRequest{Key: key}
+	case "json.set":
+		getReq = &state.GetRequest{
+			Key: key,
+			Metadata: map[string]string{
+				daprmetadata.ContentType: contenttype.JSONContentType,
+			},
+		}
+	default:
+		return nil, nil
+	}
+
+	res, err := r.Get(ctx, getReq)
+	if err != nil {
+		return nil, err
+	}
+	if res.Data == nil {
+		// The key was deleted in the meanwhile, and there'll be another notification for that
+		return nil, nil
+	}
+
+	return &state.WatchEvent{
+		Key:       key,
+		Operation: state.OperationUpsert,
+		Data:      res.Data,
+		ETag:      res.ETag,
+		Metadata:  res.Metadata,
+	}, nil
+}
+
+// checkKeyspaceEvents logs a warning if the keyspace notifications required to watch keys are not enabled on the server.
+func (r *StateStore) checkKeyspaceEvents(ctx context.Context) {
+	current, err := r.getKeyspaceEventsFlags(ctx)
+	if err != nil {
+		// Managed Redis services often disable the CONFIG command
+		r.logger.Debugf("Failed to check if keyspace notifications are enabled: %v", err)
+		return
+	}
+	if mergeKeyspaceEventsFlags(current, keyspaceEventsFlags) != current {
+		r.logger.Warnf("Keyspace notifications required to watch keys are not enabled: make sure '%s' includes '%s' on the server, or set the 'enableKeyspaceEvents' metadata property to true", keyspaceEventsConfig, keyspaceEventsFlags)
+	}
+}
+
+// enableKeyspaceEvents adds the flags required to watch keys to the server's configuration, preserving the existing flags.
+// It's invoked during initialization only if the "enableKeyspaceEvents" metadata property is true, as it changes the configuration of the whole server.
+func (r *StateStore) enableKeyspaceEvents(ctx context.Context) error {
+	current, err := r.getKeyspaceEventsFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	flags := mergeKeyspaceEventsFlags(current, keyspaceEventsFlags)
+	if flags == current {
+		return nil
+	}
+	return r.client.DoWrite(ctx, "CONFIG", "SET", keyspaceEventsConfig, flags)
+}
+
+// getKeyspaceEventsFlags returns the keyspace notifications that are enabled on the server.
+func (r *StateStore) getKeyspaceEventsFlags(ctx context.Context) (string, error) {
+	res, err := r.client.DoRead(ctx, "CONFIG", "GET", keyspaceEventsConfig)
+	if err != nil {
+		return "", err
+	}
+
+	var current string
+	switch x := res.(type) {
+	case []any:
+		if len(x) == 2 {
+			current, _ = toString(x[1])
+		}
+	case map[any]any:
+		current, _ = toString(x[keyspaceEventsConfig])
+	case map[string]any:
+		current, _ = toString(x[keyspaceEventsConfig])
+	}
+	return current, nil
+}
+
+// mergeKeyspaceEventsFlags returns the flags in current with the missing flags in required added.
+func mergeKeyspaceEventsFlags(current string, required string) string {
+	// "A" is an alias for all event classes, except keyspace/keyevent and key-miss/new-key
+	const allAlias = "g$lshzxetd"
+
+	res := current
+	for _, f := range required {
+		if strings.ContainsRune(res, f) || (strings.ContainsRune(res, 'A') && strings.ContainsRune(allAlias, f)) {
+			continue
+		}
+		res += string(f)
+	}
+	return res
+}
+
+func escapeRedisGlob(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
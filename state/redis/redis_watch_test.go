@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rediscomponent "github.com/dapr/components-contrib/common/component/redis"
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/kit/logger"
+)
+
+func TestWatch(t *testing.T) {
+	s, c := setupMiniredis()
+	defer s.Close()
+
+	ss := newStateStore(logger.NewLogger("test"))
+	ss.client = c
+	ss.clientSettings = &rediscomponent.Settings{}
+
+	ch := make(chan *state.WatchEvent, 10)
+	err := ss.Watch(t.Context(), &state.WatchRequest{Prefix: "app||"}, func(_ context.Context, e *state.WatchEvent) error {
+		ch <- e
+		return nil
+	})
+	require.NoError(t, err)
+
+	receive := func(t *testing.T) *state.WatchEvent {
+		t.Helper()
+		select {
+		case e := <-ch:
+			return e
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timed out waiting for watch event")
+			return nil
+		}
+	}
+
+	// miniredis does not emit keyspace notifications, so they are published manually
+	require.NoError(t, ss.Set(t.Context(), &state.SetRequest{Key: "app||key", Value: "value"}))
+	s.Publish("__keyspace@0__:app||key", "hset")
+	s.Publish("__keyspace@0__:app||key", "hincrby")
+	s.Publish("__keyspace@0__:other||key", "hincrby")
+	s.Publish("__keyspace@0__:app||key", "del")
+
+	e := receive(t)
+	assert.Equal(t, "app||key", e.Key)
+	assert.Equal(t, state.OperationUpsert, e.Operation)
+	assert.Equal(t, `"value"`, string(e.Data))
+	require.NotNil(t, e.ETag)
+	assert.Equal(t, "1", *e.ETag)
+
+	e = receive(t)
+	assert.Equal(t, "app||key", e.Key)
+	assert.Equal(t, state.OperationDelete, e.Operation)
+
+	select {
+	case e = <-ch:
+		require.Failf(t, "unexpected watch event", "key: %s", e.Key)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The watch is stopped when the component is closed
+	require.NoError(t, ss.Close())
+	s.Publish("__keyspace@0__:app||key", "del")
+	select {
+	case e = <-ch:
+		require.Failf(t, "unexpected watch event after close", "key: %s", e.Key)
+	case <-time.After(100 * time.Millisecond):
+	}
+	err = ss.Watch(t.Context(), &state.WatchRequest{Prefix: "app||"}, func(context.Context, *state.WatchEvent) error { return nil })
+	require.ErrorContains(t, err, "closed")
+}
+
+func TestMergeKeyspaceEventsFlags(t *testing.T) {
+	tests := []struct {
+		current  string
+		expected string
+	}{
+		{current: "", expected: "Kghxe"},
+		{current: "Kg$xe", expected: "Kg$xeh"},
+		{current: "Ex", expected: "ExKghe"},
+		{current: "KA", expected: "KA"},
+		{current: "EA", expected: "EAK"},
+		{current: "Kghxe", expected: "Kghxe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mergeKeyspaceEventsFlags(tt.current, keyspaceEventsFlags))
+		})
+	}
+}
+
+func TestEscapeRedisGlob(t *testing.T) {
+	assert.Equal(t, "app||key", escapeRedisGlob("app||key"))
+	assert.Equal(t, `a\*b\?c\[d\]e\\f`, escapeRedisGlob(`a*b?c[d]e\f`))
+}
@@ -15,6 +15,9 @@ package state
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"slices"
 	"strings"
 
 	"github.com/dapr/components-contrib/state/query"
@@ -121,6 +124,130 @@ func (r SetRequest) Operation() OperationType {
 	return OperationUpsert
 }
 
+// IncrementRequest is the object describing a transactional operation that increments a counter.
+// It can only be used with state stores that support FeatureAtomicIncrement.
+type IncrementRequest struct {
+	Key      string            `json:"key"`
+	Delta    int64             `json:"delta"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// GetKey gets the Key on an IncrementRequest.
+func (r IncrementRequest) GetKey() string {
+	return r.Key
+}
+
+// GetMetadata gets the Metadata on an IncrementRequest.
+func (r IncrementRequest) GetMetadata() map[string]string {
+	return r.Metadata
+}
+
+// Operation returns the operation type for IncrementRequest, implementing TransactionalStateOperationRequest.
+func (r IncrementRequest) Operation() OperationType {
+	return OperationIncrement
+}
+
+// ConditionType describes the condition checked by a ConditionRequest.
+type ConditionType string
+
+const (
+	// ConditionExists is met when the key exists.
+	ConditionExists ConditionType = "exists"
+	// ConditionNotExists is met when the key does not exist.
+	ConditionNotExists ConditionType = "not-exists"
+	// ConditionETagMatch is met when the key exists and its ETag matches the one in the request.
+	ConditionETagMatch ConditionType = "etag-match"
+)
+
+// ConditionRequest is the object describing a transactional operation that does not modify state, but aborts the transaction if a condition on a key is not met.
+// It can only be used with state stores that support FeatureTransactionConditions.
+type ConditionRequest struct {
+	Key       string            `json:"key"`
+	Condition ConditionType     `json:"condition"`
+	ETag      *string           `json:"etag,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// GetKey gets the Key on a ConditionRequest.
+func (r ConditionRequest) GetKey() string {
+	return r.Key
+}
+
+// GetMetadata gets the Metadata on a ConditionRequest.
+func (r ConditionRequest) GetMetadata() map[string]string {
+	return r.Metadata
+}
+
+// Operation returns the operation type for ConditionRequest, implementing TransactionalStateOperationRequest.
+func (r ConditionRequest) Operation() OperationType {
+	return OperationCondition
+}
+
+// Validate returns an error if the request is not valid.
+func (r ConditionRequest) Validate() error {
+	if r.Key == "" {
+		return errors.New("missing key in condition operation")
+	}
+	switch r.Condition {
+	case ConditionExists, ConditionNotExists:
+		return nil
+	case ConditionETagMatch:
+		if r.ETag == nil || *r.ETag == "" {
+			return errors.New("missing etag in condition operation")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid condition '%s'", r.Condition)
+	}
+}
+
+// Check returns an error wrapping ErrConditionNotMet if the condition is not met by the current state of the key.
+// etag is the current ETag of the key, and it is ignored if the key does not exist.
+func (r ConditionRequest) Check(exists bool, etag *string) error {
+	switch r.Condition {
+	case ConditionExists:
+		if !exists {
+			return fmt.Errorf("%w: key %s does not exist", ErrConditionNotMet, r.Key)
+		}
+	case ConditionNotExists:
+		if exists {
+			return fmt.Errorf("%w: key %s exists", ErrConditionNotMet, r.Key)
+		}
+	case ConditionETagMatch:
+		if !exists || etag == nil || r.ETag == nil || *etag != *r.ETag {
+			return fmt.Errorf("%w: etag of key %s does not match", ErrConditionNotMet, r.Key)
+		}
+	default:
+		return fmt.Errorf("invalid condition '%s'", r.Condition)
+	}
+	return nil
+}
+
+// SetStreamRequest is the object describing an upsert request whose value is read from a stream.
+type SetStreamRequest struct {
+	Key         string            `json:"key"`
+	Value       io.Reader         `json:"-"`
+	ETag        *string           `json:"etag,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Options     SetStateOption    `json:"options,omitempty"`
+	ContentType *string           `json:"contentType,omitempty"`
+}
+
+// GetKey gets the Key on a SetStreamRequest.
+func (r SetStreamRequest) GetKey() string {
+	return r.Key
+}
+
+// GetMetadata gets the Metadata on a SetStreamRequest.
+func (r SetStreamRequest) GetMetadata() map[string]string {
+	return r.Metadata
+}
+
+// HasETag returns true if the request has a non-empty ETag.
+func (r SetStreamRequest) HasETag() bool {
+	return r.ETag != nil && *r.ETag != ""
+}
+
 // SetStateOption controls how a state store reacts to a set request.
 type SetStateOption struct {
 	Concurrency string // first-write, last-write
@@ -135,10 +262,14 @@ const (
 	OperationUpsert OperationType = "upsert"
 	// OperationDelete is a delete transactional operation.
 	OperationDelete OperationType = "delete"
+	// OperationIncrement is an increment transactional operation.
+	OperationIncrement OperationType = "increment"
+	// OperationCondition is a transactional operation that checks a condition without modifying state.
+	OperationCondition OperationType = "condition"
 )
 
 // TransactionalStateRequest describes a transactional operation against a state store that comprises multiple types of operations
-// The Request field is either a DeleteRequest or SetRequest.
+// The Request field is either a DeleteRequest, SetRequest, or IncrementRequest.
 type TransactionalStateRequest struct {
 	Operations []TransactionalStateOperation
 	Metadata   map[string]string
@@ -162,6 +293,42 @@ type QueryRequest struct {
 	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
+// WatchRequest is the object describing a request to watch keys for changes.
+// If neither Keys nor Prefix are set, all keys are watched.
+type WatchRequest struct {
+	// Keys is an optional list of keys to watch.
+	Keys []string `json:"keys,omitempty"`
+
+	// Prefix is an optional prefix of the keys to watch.
+	Prefix string `json:"prefix,omitempty"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Matches returns true if the key is selected by the watch request.
+func (r WatchRequest) Matches(key string) bool {
+	if len(r.Keys) == 0 {
+		return strings.HasPrefix(key, r.Prefix)
+	}
+	if r.Prefix != "" && !strings.HasPrefix(key, r.Prefix) {
+		return false
+	}
+	return slices.Contains(r.Keys, key)
+}
+
+// ListKeysRequest is the request object for listing keys.
+type ListKeysRequest struct {
+	// Prefix is an optional parameter to list only the keys that start with it.
+	Prefix string `json:"prefix,omitempty"`
+
+	// ContinuationToken is the token returned with the previous page, if any.
+	ContinuationToken *string `json:"continuationToken,omitempty"`
+
+	// PageSize is an optional parameter to indicate the maximum number of keys
+	// to return.
+	PageSize *uint32 `json:"pageSize,omitempty"`
+}
+
 type KeysLikeRequest struct {
 	// Pattern is the SQL LIKE pattern to match keys against.
 	Pattern string `json:"pattern"`
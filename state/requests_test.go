@@ -0,0 +1,85 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchRequestMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     WatchRequest
+		key     string
+		matches bool
+	}{
+		{name: "all keys", req: WatchRequest{}, key: "foo", matches: true},
+		{name: "key in list", req: WatchRequest{Keys: []string{"foo", "bar"}}, key: "bar", matches: true},
+		{name: "key not in list", req: WatchRequest{Keys: []string{"foo", "bar"}}, key: "baz", matches: false},
+		{name: "key with prefix", req: WatchRequest{Prefix: "app||"}, key: "app||foo", matches: true},
+		{name: "key without prefix", req: WatchRequest{Prefix: "app||"}, key: "other||foo", matches: false},
+		{name: "key in list with prefix", req: WatchRequest{Keys: []string{"app||foo"}, Prefix: "app||"}, key: "app||foo", matches: true},
+		{name: "key in list without prefix", req: WatchRequest{Keys: []string{"foo"}, Prefix: "app||"}, key: "foo", matches: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.matches, tt.req.Matches(tt.key))
+		})
+	}
+}
+
+func TestConditionRequest(t *testing.T) {
+	etag := "1"
+	otherEtag := "2"
+
+	t.Run("validate", func(t *testing.T) {
+		require.NoError(t, ConditionRequest{Key: "foo", Condition: ConditionExists}.Validate())
+		require.NoError(t, ConditionRequest{Key: "foo", Condition: ConditionNotExists}.Validate())
+		require.NoError(t, ConditionRequest{Key: "foo", Condition: ConditionETagMatch, ETag: &etag}.Validate())
+		require.Error(t, ConditionRequest{Condition: ConditionExists}.Validate())
+		require.Error(t, ConditionRequest{Key: "foo", Condition: ConditionETagMatch}.Validate())
+		require.Error(t, ConditionRequest{Key: "foo", Condition: "foo"}.Validate())
+	})
+
+	tests := []struct {
+		name   string
+		req    ConditionRequest
+		exists bool
+		etag   *string
+		met    bool
+	}{
+		{name: "exists and key exists", req: ConditionRequest{Key: "foo", Condition: ConditionExists}, exists: true, etag: &etag, met: true},
+		{name: "exists and key does not exist", req: ConditionRequest{Key: "foo", Condition: ConditionExists}, met: false},
+		{name: "not-exists and key exists", req: ConditionRequest{Key: "foo", Condition: ConditionNotExists}, exists: true, etag: &etag, met: false},
+		{name: "not-exists and key does not exist", req: ConditionRequest{Key: "foo", Condition: ConditionNotExists}, met: true},
+		{name: "etag-match and etag matches", req: ConditionRequest{Key: "foo", Condition: ConditionETagMatch, ETag: &etag}, exists: true, etag: &etag, met: true},
+		{name: "etag-match and etag does not match", req: ConditionRequest{Key: "foo", Condition: ConditionETagMatch, ETag: &etag}, exists: true, etag: &otherEtag, met: false},
+		{name: "etag-match and key does not exist", req: ConditionRequest{Key: "foo", Condition: ConditionETagMatch, ETag: &etag}, met: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Check(tt.exists, tt.etag)
+			if tt.met {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrConditionNotMet)
+			}
+		})
+	}
+}
@@ -13,6 +13,10 @@ limitations under the License.
 
 package state
 
+import (
+	"io"
+)
+
 const (
 	// GetRespMetaKeyTTLExpireTime is the key for the metadata value of the TTL
 	// expire time. Value is a RFC3339 formatted string.
@@ -27,6 +31,16 @@ type GetResponse struct {
 	ContentType *string           `json:"contentType,omitempty"`
 }
 
+// GetStreamResponse is the response object for getting state as a stream.
+// If the key does not exist, Data is nil.
+// Callers must close Data when they are done reading from it.
+type GetStreamResponse struct {
+	Data        io.ReadCloser     `json:"-"`
+	ETag        *string           `json:"etag,omitempty"`
+	Metadata    map[string]string `json:"metadata"`
+	ContentType *string           `json:"contentType,omitempty"`
+}
+
 // BulkGetResponse is the response object for bulk get response.
 type BulkGetResponse struct {
 	Key         string            `json:"key"`
@@ -53,11 +67,35 @@ type QueryItem struct {
 	ContentType *string `json:"contentType,omitempty"`
 }
 
+// WatchEvent is the object describing a change to a watched key.
+type WatchEvent struct {
+	Key string `json:"key"`
+
+	// Operation is OperationUpsert when the key was created or updated, and OperationDelete when the key was deleted or expired.
+	Operation OperationType `json:"operation"`
+
+	// Data and ETag contain the value of the key after an upsert.
+	// They are empty for deletions.
+	Data []byte  `json:"data,omitempty"`
+	ETag *string `json:"etag,omitempty"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
 // DeleteWithPrefixResponse is the object representing a delete with prefix state response containing the number of items removed.
 type DeleteWithPrefixResponse struct {
 	Count int64 `json:"count"` // count of items removed
 }
 
+// ListKeysResponse is the response object for listing keys.
+type ListKeysResponse struct {
+	Keys []string `json:"keys"`
+
+	// ContinuationToken is the token to request the next page.
+	// It is nil when there are no more keys to list.
+	ContinuationToken *string `json:"continuationToken,omitempty"`
+}
+
 // KeysLikeResponse is the response object for getting keys like a pattern.
 type KeysLikeResponse struct {
 	Keys []string `json:"keys"`
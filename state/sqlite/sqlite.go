@@ -15,6 +15,7 @@ package sqlite
 
 import (
 	"context"
+	"errors"
 	"reflect"
 
 	"github.com/dapr/components-contrib/metadata"
@@ -50,6 +51,9 @@ func newSQLiteStateStore(logger logger.Logger, dba DBAccess) *SQLiteStore {
 			state.FeatureTransactional,
 			state.FeatureTTL,
 			state.FeatureKeysLike,
+			state.FeatureListKeys,
+			state.FeatureTransactionConditions,
+			state.FeatureQueryAPI,
 		},
 		dbaccess: dba,
 	}
@@ -89,6 +93,21 @@ func (s *SQLiteStore) KeysLike(ctx context.Context, req *state.KeysLikeRequest)
 	return s.dbaccess.KeysLike(ctx, req)
 }
 
+func (s *SQLiteStore) ListKeys(ctx context.Context, req *state.ListKeysRequest) (*state.ListKeysResponse, error) {
+	if req == nil {
+		return nil, errors.New("request object is nil")
+	}
+	return s.dbaccess.ListKeys(ctx, req)
+}
+
+// Query executes a query against store.
+func (s *SQLiteStore) Query(ctx context.Context, req *state.QueryRequest) (*state.QueryResponse, error) {
+	if req == nil {
+		return nil, errors.New("request object is nil")
+	}
+	return s.dbaccess.Query(ctx, req)
+}
+
 // BulkGet performs a bulks get operations.
 // Options are ignored because this component requests all values in a single query.
 func (s *SQLiteStore) BulkGet(ctx context.Context, req []state.GetRequest, _ state.BulkGetOpts) ([]state.BulkGetResponse, error) {
@@ -33,6 +33,7 @@ import (
 	commonsql "github.com/dapr/components-contrib/common/component/sql"
 	sqltransactions "github.com/dapr/components-contrib/common/component/sql/transactions"
 	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/components-contrib/state/query"
 	stateutils "github.com/dapr/components-contrib/state/utils"
 	"github.com/dapr/kit/logger"
 	"github.com/dapr/kit/ptr"
@@ -48,6 +49,8 @@ type DBAccess interface {
 	BulkGet(ctx context.Context, req []state.GetRequest) ([]state.BulkGetResponse, error)
 	ExecuteMulti(ctx context.Context, reqs []state.TransactionalStateOperation) error
 	KeysLike(ctx context.Context, req *state.KeysLikeRequest) (*state.KeysLikeResponse, error)
+	ListKeys(ctx context.Context, req *state.ListKeysRequest) (*state.ListKeysResponse, error)
+	Query(ctx context.Context, req *state.QueryRequest) (*state.QueryResponse, error)
 	Close() error
 }
 
@@ -106,6 +109,7 @@ func (a *sqliteDBAccess) Init(ctx context.Context, md state.Metadata) error {
 	err = performMigrations(ctx, a.db, a.logger, migrationOptions{
 		StateTableName:    a.metadata.TableName,
 		MetadataTableName: a.metadata.MetadataTableName,
+		IndexedFields:     a.metadata.indexedFields,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to perform migrations: %w", err)
@@ -448,11 +452,40 @@ func (a *sqliteDBAccess) execMultiOperation(parentCtx context.Context, op state.
 		return a.doSet(parentCtx, db, &req)
 	case state.DeleteRequest:
 		return a.doDelete(parentCtx, db, &req)
+	case state.ConditionRequest:
+		return a.doCheckCondition(parentCtx, db, &req)
 	default:
 		return fmt.Errorf("unsupported operation: %s", op.Operation())
 	}
 }
 
+func (a *sqliteDBAccess) doCheckCondition(parentCtx context.Context, db querier, req *state.ConditionRequest) error {
+	err := req.Validate()
+	if err != nil {
+		return err
+	}
+
+	// Concatenation is required for table name because sql.DB does not substitute parameters for table names
+	//nolint:gosec
+	stmt := `SELECT etag FROM ` + a.metadata.TableName + `
+		WHERE
+			key = ?
+			AND (expiration_time IS NULL OR expiration_time > CURRENT_TIMESTAMP)`
+	ctx, cancel := context.WithTimeout(parentCtx, a.metadata.Timeout)
+	defer cancel()
+
+	var etag string
+	err = db.QueryRowContext(ctx, stmt, req.Key).Scan(&etag)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return req.Check(false, nil)
+	case err != nil:
+		return err
+	default:
+		return req.Check(true, &etag)
+	}
+}
+
 // Close implements io.Closer.
 func (a *sqliteDBAccess) Close() (err error) {
 	errs := make([]error, 0)
@@ -613,3 +646,141 @@ func (a *sqliteDBAccess) KeysLike(ctx context.Context, req *state.KeysLikeReques
 
 	return resp, nil
 }
+
+// ListKeys returns the keys in the table ordered by key, one page at a time.
+// The continuation token is the last key returned, so rows written during the iteration don't affect the keys that are returned next.
+func (a *sqliteDBAccess) ListKeys(ctx context.Context, req *state.ListKeysRequest) (*state.ListKeysResponse, error) {
+	where := []string{
+		`(expiration_time IS NULL OR expiration_time > CURRENT_TIMESTAMP)`,
+	}
+	args := []any{}
+
+	if req.Prefix != "" {
+		where = append(where, `key LIKE ? ESCAPE '\'`)
+		args = append(args, stateutils.EscapeLikePattern(req.Prefix)+"%")
+	}
+	if req.ContinuationToken != nil {
+		where = append(where, `key > ?`)
+		args = append(args, *req.ContinuationToken)
+	}
+
+	limitClause := ``
+	var pageSize uint32
+	if req.PageSize != nil && *req.PageSize > 0 {
+		pageSize = *req.PageSize
+		// Fetch one extra row to detect if there's a next page
+		limitClause = ` LIMIT ?`
+		args = append(args, pageSize+1)
+	}
+
+	//nolint:gosec
+	query := fmt.Sprintf(`
+		SELECT key
+		FROM %s
+		WHERE %s
+		ORDER BY key ASC%s`,
+		a.metadata.TableName,
+		strings.Join(where, " AND "),
+		limitClause,
+	)
+
+	rows, err := a.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resp := &state.ListKeysResponse{
+		Keys: make([]string, 0, min(pageSize, 1024)),
+	}
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
+		}
+		resp.Keys = append(resp.Keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if pageSize > 0 && uint32(len(resp.Keys)) > pageSize { //nolint:gosec
+		resp.Keys = resp.Keys[:pageSize]
+		resp.ContinuationToken = ptr.Of(resp.Keys[pageSize-1])
+	}
+
+	return resp, nil
+}
+
+// Query executes a query against the values in the table that are JSON objects.
+// SQLite doesn't have a native query language for JSON documents, so the query is evaluated in-process after loading the values.
+// Filters on indexed fields are also applied in the database, so only the values that can match are loaded.
+func (a *sqliteDBAccess) Query(parentCtx context.Context, req *state.QueryRequest) (*state.QueryResponse, error) {
+	//nolint:gosec
+	stmt := `SELECT key, value, etag FROM ` + a.metadata.TableName + `
+		WHERE
+			NOT is_binary
+			AND json_valid(value) AND json_type(value) = 'object'
+			AND (expiration_time IS NULL OR expiration_time > CURRENT_TIMESTAMP)`
+	cond, args := indexedFilter(req.Query.Filter, commonsql.IndexedColumns(a.metadata.indexedFields))
+	if cond != "" {
+		stmt += "\n\t\t\tAND " + cond
+	}
+	ctx, cancel := context.WithTimeout(parentCtx, a.metadata.Timeout)
+	defer cancel()
+	rows, err := a.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []query.EvaluateItem[state.QueryItem]{}
+	for rows.Next() {
+		var (
+			key, etag string
+			data      []byte
+			value     map[string]any
+		)
+		err = rows.Scan(&key, &data, &etag)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(data, &value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse value of key %s: %w", key, err)
+		}
+		items = append(items, query.EvaluateItem[state.QueryItem]{
+			Key:   key,
+			Value: value,
+			Data: state.QueryItem{
+				Key:  key,
+				Data: data,
+				ETag: ptr.Of(etag),
+			},
+		})
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	results, token, err := query.Evaluate(&req.Query, items)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Query.Fields) > 0 {
+		for i := range results {
+			projected, perr := query.ProjectJSON(results[i].Data, req.Query.Fields)
+			if perr != nil {
+				results[i].Error = perr.Error()
+				continue
+			}
+			results[i].Data = projected
+		}
+	}
+
+	return &state.QueryResponse{
+		Results: results,
+		Token:   token,
+	}, nil
+}
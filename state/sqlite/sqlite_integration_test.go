@@ -18,16 +18,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	commonsql "github.com/dapr/components-contrib/common/component/sql"
 	"github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/components-contrib/state"
 	"github.com/dapr/kit/logger"
+	"github.com/dapr/kit/ptr"
 )
 
 const (
@@ -160,6 +165,259 @@ func TestSqliteIntegration(t *testing.T) {
 		assert.NotEmpty(t, res.ETag)
 		assert.Equal(t, "🤖", string(res.Data))
 	})
+
+	t.Run("List keys", func(t *testing.T) {
+		listKeys(t, s.(state.KeyLister))
+	})
+
+	t.Run("Multi with conditions", func(t *testing.T) {
+		multiWithConditions(t, s)
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		queryItems(t, s.(*SQLiteStore))
+	})
+}
+
+// queryItems validates that queries are evaluated against the values that are JSON objects.
+func queryItems(t *testing.T, s *SQLiteStore) {
+	prefix := randomKey()
+	for k, v := range map[string]any{
+		"a":      map[string]any{"prefix": prefix, "size": 3},
+		"b":      map[string]any{"prefix": prefix, "size": 1},
+		"c":      map[string]any{"prefix": prefix, "size": 2},
+		"str":    "not an object",
+		"binary": []byte(`{"prefix":"` + prefix + `"}`),
+	} {
+		err := s.Set(t.Context(), &state.SetRequest{Key: prefix + k, Value: v})
+		require.NoError(t, err)
+	}
+
+	var req state.QueryRequest
+	err := json.Unmarshal([]byte(`{"filter":{"AND":[{"EQ":{"prefix":"`+prefix+`"}},{"GT":{"size":1}}]},"sort":[{"key":"size","order":"DESC"}],"page":{"limit":1}}`), &req.Query)
+	require.NoError(t, err)
+
+	res, err := s.Query(t.Context(), &req)
+	require.NoError(t, err)
+	require.Len(t, res.Results, 1)
+	assert.Equal(t, prefix+"a", res.Results[0].Key)
+	assert.JSONEq(t, `{"prefix":"`+prefix+`","size":3}`, string(res.Results[0].Data))
+	require.NotNil(t, res.Results[0].ETag)
+	assert.Equal(t, "1", res.Token)
+
+	req.Query.Page.Token = res.Token
+	res, err = s.Query(t.Context(), &req)
+	require.NoError(t, err)
+	require.Len(t, res.Results, 1)
+	assert.Equal(t, prefix+"c", res.Results[0].Key)
+	assert.Empty(t, res.Token)
+}
+
+func TestSqliteIndexedFields(t *testing.T) {
+	connectionString := "file:" + filepath.Join(t.TempDir(), "indexes.db")
+	colorField := commonsql.IndexedField{Path: "color"}
+	nestedField := commonsql.IndexedField{Path: "nested.value"}
+
+	initStore := func(t *testing.T, indexedFields string) state.Store {
+		t.Helper()
+		s := NewSQLiteStateStore(logger.NewLogger("test"))
+		err := s.Init(t.Context(), state.Metadata{
+			Base: metadata.Base{
+				Properties: map[string]string{
+					"connectionString": connectionString,
+					"tableName":        "indexed_state",
+					"indexedFields":    indexedFields,
+				},
+			},
+		})
+		require.NoError(t, err)
+		return s
+	}
+
+	listColumns := func(t *testing.T, s state.Store) []string {
+		t.Helper()
+		db := s.(interface{ GetDBAccess() *sqliteDBAccess }).GetDBAccess().db
+		rows, err := db.QueryContext(t.Context(), `SELECT name FROM pragma_table_xinfo('indexed_state') WHERE name LIKE 'dapr_idx_%' ORDER BY name`)
+		require.NoError(t, err)
+		defer rows.Close()
+		res := []string{}
+		for rows.Next() {
+			var name string
+			require.NoError(t, rows.Scan(&name))
+			res = append(res, name)
+		}
+		require.NoError(t, rows.Err())
+		return res
+	}
+
+	t.Run("create generated columns", func(t *testing.T) {
+		s := initStore(t, "color,nested.value")
+		defer s.Close()
+
+		expect := []string{colorField.ColumnName(), nestedField.ColumnName()}
+		sort.Strings(expect)
+		assert.Equal(t, expect, listColumns(t, s))
+
+		err := s.Set(t.Context(), &state.SetRequest{Key: "json", Value: map[string]any{"color": "red", "nested": map[string]any{"value": 42}}})
+		require.NoError(t, err)
+		err = s.Set(t.Context(), &state.SetRequest{Key: "binary", Value: []byte("not json")})
+		require.NoError(t, err)
+
+		db := s.(interface{ GetDBAccess() *sqliteDBAccess }).GetDBAccess().db
+		var color sql.NullString
+		err = db.QueryRowContext(t.Context(), "SELECT "+colorField.ColumnName()+" FROM indexed_state WHERE key = 'json'").Scan(&color)
+		require.NoError(t, err)
+		assert.Equal(t, "red", color.String)
+		err = db.QueryRowContext(t.Context(), "SELECT "+colorField.ColumnName()+" FROM indexed_state WHERE key = 'binary'").Scan(&color)
+		require.NoError(t, err)
+		assert.False(t, color.Valid)
+
+		// The query planner uses the index
+		var (
+			id, parent, notUsed int
+			plan                string
+		)
+		err = db.QueryRowContext(t.Context(), "EXPLAIN QUERY PLAN SELECT key FROM indexed_state WHERE "+colorField.ColumnName()+" = 'red'").Scan(&id, &parent, &notUsed, &plan)
+		require.NoError(t, err)
+		assert.Contains(t, plan, commonsql.IndexName(colorField.ColumnName(), "indexed_state"))
+	})
+
+	t.Run("keep generated columns that are not indexed anymore", func(t *testing.T) {
+		s := initStore(t, "color")
+		defer s.Close()
+
+		expect := []string{colorField.ColumnName(), nestedField.ColumnName()}
+		sort.Strings(expect)
+		assert.Equal(t, expect, listColumns(t, s))
+
+		res, err := s.Get(t.Context(), &state.GetRequest{Key: "json"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"color":"red","nested":{"value":42}}`, string(res.Data))
+	})
+
+	t.Run("query filters on indexed fields", func(t *testing.T) {
+		// The "json" item set by the previous tests is also returned
+		s := initStore(t, "color,nested.value")
+		defer s.Close()
+
+		for k, v := range map[string]any{
+			"q-red":    map[string]any{"color": "red", "size": "large", "nested": map[string]any{"value": 1}},
+			"q-blue":   map[string]any{"color": "blue", "size": "small", "nested": map[string]any{"value": 5}},
+			"q-green":  map[string]any{"color": "green", "size": "large", "nested": map[string]any{"value": "5"}},
+			"q-true":   map[string]any{"color": true, "nested": map[string]any{"value": 1.5}},
+			"q-number": map[string]any{"color": 1, "nested": map[string]any{"value": 10}},
+		} {
+			err := s.Set(t.Context(), &state.SetRequest{Key: k, Value: v})
+			require.NoError(t, err)
+		}
+
+		for filter, expect := range map[string][]string{
+			`{"EQ":{"color":"red"}}`:                                         {"json", "q-red"},
+			`{"EQ":{"color":true}}`:                                          {"q-true"},
+			`{"EQ":{"color":1}}`:                                             {"q-number"},
+			`{"IN":{"color":["red","blue"]}}`:                                {"json", "q-blue", "q-red"},
+			`{"GTE":{"nested.value":5}}`:                                     {"json", "q-blue", "q-number"},
+			`{"LT":{"nested.value":"6"}}`:                                    {"q-green"},
+			`{"AND":[{"GT":{"nested.value":1}},{"LTE":{"nested.value":5}}]}`: {"q-blue", "q-true"},
+			`{"AND":[{"EQ":{"size":"large"}},{"LT":{"color":"h"}}]}`:         {"q-green"},
+			`{"OR":[{"EQ":{"color":"red"}},{"EQ":{"size":"small"}}]}`:        {"json", "q-blue", "q-red"},
+			`{"NEQ":{"color":"red"}}`:                                        {"q-blue", "q-green"},
+		} {
+			var req state.QueryRequest
+			err := json.Unmarshal([]byte(`{"filter":`+filter+`}`), &req.Query)
+			require.NoError(t, err)
+
+			res, err := s.(state.Querier).Query(t.Context(), &req)
+			require.NoError(t, err, filter)
+			keys := make([]string, len(res.Results))
+			for i, item := range res.Results {
+				keys[i] = item.Key
+			}
+			sort.Strings(keys)
+			assert.Equal(t, expect, keys, filter)
+		}
+	})
+
+	t.Run("invalid indexed fields", func(t *testing.T) {
+		s := NewSQLiteStateStore(logger.NewLogger("test"))
+		defer s.Close()
+		err := s.Init(t.Context(), state.Metadata{
+			Base: metadata.Base{
+				Properties: map[string]string{
+					"connectionString": connectionString,
+					"indexedFields":    "color') --",
+				},
+			},
+		})
+		require.ErrorContains(t, err, "indexedFields")
+	})
+}
+
+// multiWithConditions validates that transactions are aborted when conditions are not met.
+func multiWithConditions(t *testing.T, s state.Store) {
+	existingKey := randomKey()
+	setItem(t, s, existingKey, randomJSON(), nil)
+	res, _ := getItem(t, s, existingKey)
+	missingKey := randomKey()
+
+	multi := func(ops ...state.TransactionalStateOperation) error {
+		return s.(state.TransactionalStore).Multi(t.Context(), &state.TransactionalStateRequest{Operations: ops})
+	}
+
+	newKey := randomKey()
+	err := multi(
+		state.ConditionRequest{Key: existingKey, Condition: state.ConditionExists},
+		state.ConditionRequest{Key: existingKey, Condition: state.ConditionETagMatch, ETag: res.ETag},
+		state.ConditionRequest{Key: missingKey, Condition: state.ConditionNotExists},
+		state.SetRequest{Key: newKey, Value: randomJSON()},
+	)
+	require.NoError(t, err)
+	assert.True(t, storeItemExists(t, s, newKey))
+
+	for _, cond := range []state.ConditionRequest{
+		{Key: missingKey, Condition: state.ConditionExists},
+		{Key: existingKey, Condition: state.ConditionNotExists},
+		{Key: existingKey, Condition: state.ConditionETagMatch, ETag: ptr.Of("bad-etag")},
+	} {
+		notWrittenKey := randomKey()
+		err = multi(state.SetRequest{Key: notWrittenKey, Value: randomJSON()}, cond)
+		require.ErrorIs(t, err, state.ErrConditionNotMet)
+		assert.False(t, storeItemExists(t, s, notWrittenKey))
+	}
+}
+
+// listKeys validates listing keys with a prefix in multiple pages.
+func listKeys(t *testing.T, s state.KeyLister) {
+	// The "%" and "_" characters must not be treated as wildcards in the prefix
+	prefix := randomKey() + "_%||"
+	expect := make([]string, 5)
+	for i := range expect {
+		expect[i] = prefix + strconv.Itoa(i)
+		err := s.(state.Store).Set(t.Context(), &state.SetRequest{Key: expect[i], Value: "v"})
+		require.NoError(t, err)
+	}
+	err := s.(state.Store).Set(t.Context(), &state.SetRequest{Key: strings.Replace(prefix, "_%", "ab", 1) + "0", Value: "v"})
+	require.NoError(t, err)
+
+	var (
+		keys  []string
+		token *string
+	)
+	for {
+		res, err := s.ListKeys(t.Context(), &state.ListKeysRequest{
+			Prefix:            prefix,
+			PageSize:          ptr.Of(uint32(2)),
+			ContinuationToken: token,
+		})
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(res.Keys), 2)
+		keys = append(keys, res.Keys...)
+		if res.ContinuationToken == nil {
+			break
+		}
+		token = res.ContinuationToken
+	}
+	assert.Equal(t, expect, keys)
 }
 
 // setGetUpdateDeleteOneItem validates setting one item, getting it, and deleting it.
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	authSqlite "github.com/dapr/components-contrib/common/authentication/sqlite"
+	commonsql "github.com/dapr/components-contrib/common/component/sql"
 	"github.com/dapr/components-contrib/state"
 	"github.com/dapr/kit/metadata"
 )
@@ -34,6 +35,9 @@ type sqliteMetadataStruct struct {
 	TableName         string        `mapstructure:"tableName"`
 	MetadataTableName string        `mapstructure:"metadataTableName"`
 	CleanupInterval   time.Duration `mapstructure:"cleanupInterval" mapstructurealiases:"cleanupIntervalInSeconds"`
+	IndexedFields     []string      `mapstructure:"indexedFields"`
+
+	indexedFields []commonsql.IndexedField
 }
 
 func (m *sqliteMetadataStruct) InitWithMetadata(meta state.Metadata) error {
@@ -57,6 +61,10 @@ func (m *sqliteMetadataStruct) InitWithMetadata(meta state.Metadata) error {
 	if !authSqlite.ValidIdentifier(m.MetadataTableName) {
 		return fmt.Errorf("invalid identifier: %s", m.MetadataTableName)
 	}
+	m.indexedFields, err = commonsql.ParseIndexedFields(m.IndexedFields)
+	if err != nil {
+		return fmt.Errorf("invalid value for 'indexedFields': %w", err)
+	}
 
 	return nil
 }
@@ -68,4 +76,6 @@ func (m *sqliteMetadataStruct) reset() {
 	m.TableName = defaultTableName
 	m.MetadataTableName = defaultMetadataTableName
 	m.CleanupInterval = defaultCleanupInterval
+	m.IndexedFields = nil
+	m.indexedFields = nil
 }
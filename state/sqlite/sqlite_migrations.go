@@ -26,6 +26,7 @@ import (
 type migrationOptions struct {
 	StateTableName    string
 	MetadataTableName string
+	IndexedFields     []commonsql.IndexedField
 }
 
 // Perform the required migrations
@@ -35,6 +36,8 @@ func performMigrations(ctx context.Context, db *sql.DB, logger logger.Logger, op
 		Logger:            logger,
 		MetadataTableName: opts.MetadataTableName,
 		MetadataKey:       "migrations",
+		StateTableName:    opts.StateTableName,
+		IndexedFields:     opts.IndexedFields,
 	}
 
 	return m.Perform(ctx, []commonsql.MigrationFn{
@@ -0,0 +1,137 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlite
+
+import (
+	"math"
+	"strings"
+
+	"github.com/dapr/components-contrib/state/query"
+)
+
+// Numbers are compared as float64 in-process, so only numbers that are represented exactly are compared in the database.
+const maxExactFloat = 1 << 53
+
+// indexedFilter returns a condition on the generated columns of the indexed fields that is true for every value selected by the filter.
+// It's used to skip the rows that can't match using the indexes, before the filter is evaluated in-process on the remaining rows.
+// Filters on fields that are not indexed, and NEQ filters, are not pushed down; the condition is empty if nothing can be pushed down.
+// The condition can select more rows than the filter (for example, JSON true is stored as 1), but never fewer.
+func indexedFilter(filter query.Filter, columns map[string]string) (string, []any) {
+	switch f := filter.(type) {
+	case *query.EQ:
+		return indexedComparison(columns, f.Key, "=", f.Val, false)
+	case *query.GT:
+		return indexedComparison(columns, f.Key, ">", f.Val, true)
+	case *query.GTE:
+		return indexedComparison(columns, f.Key, ">=", f.Val, true)
+	case *query.LT:
+		return indexedComparison(columns, f.Key, "<", f.Val, true)
+	case *query.LTE:
+		return indexedComparison(columns, f.Key, "<=", f.Val, true)
+	case *query.IN:
+		column, ok := columns[f.Key]
+		if !ok || len(f.Vals) == 0 {
+			return "", nil
+		}
+		args := make([]any, len(f.Vals))
+		for i, v := range f.Vals {
+			args[i], ok = indexedValue(v, false)
+			if !ok {
+				return "", nil
+			}
+		}
+		return column + " IN (?" + strings.Repeat(", ?", len(args)-1) + ")", args
+	case *query.AND:
+		// Sub-filters that can't be pushed down are left out, as they only restrict the result further
+		var (
+			conds []string
+			args  []any
+		)
+		for _, sub := range f.Filters {
+			cond, subArgs := indexedFilter(sub, columns)
+			if cond != "" {
+				conds = append(conds, cond)
+				args = append(args, subArgs...)
+			}
+		}
+		return joinConditions(conds, " AND "), args
+	case *query.OR:
+		// All sub-filters must be pushed down, or rows selected by the others would be skipped
+		var (
+			conds []string
+			args  []any
+		)
+		for _, sub := range f.Filters {
+			cond, subArgs := indexedFilter(sub, columns)
+			if cond == "" {
+				return "", nil
+			}
+			conds = append(conds, cond)
+			args = append(args, subArgs...)
+		}
+		return joinConditions(conds, " OR "), args
+	default:
+		return "", nil
+	}
+}
+
+func indexedComparison(columns map[string]string, key string, op string, val any, ordered bool) (string, []any) {
+	column, ok := columns[key]
+	if !ok {
+		return "", nil
+	}
+	arg, ok := indexedValue(val, ordered)
+	if !ok {
+		return "", nil
+	}
+	return column + " " + op + " ?", []any{arg}
+}
+
+// indexedValue returns the value to compare the generated columns with, which contain the values returned by json_extract.
+// Strings compare like in-process, and numbers too as long as they are represented exactly.
+// Booleans are stored as 0 and 1, so they can only be compared for equality.
+// Null never matches a filter.
+func indexedValue(val any, ordered bool) (any, bool) {
+	switch v := val.(type) {
+	case string:
+		return v, true
+	case float64:
+		return v, math.Abs(v) < maxExactFloat
+	case int:
+		return int64(v), v > -maxExactFloat && v < maxExactFloat
+	case int64:
+		return v, v > -maxExactFloat && v < maxExactFloat
+	case bool:
+		if ordered {
+			return nil, false
+		}
+		if v {
+			return int64(1), true
+		}
+		return int64(0), true
+	default:
+		return nil, false
+	}
+}
+
+func joinConditions(conds []string, sep string) string {
+	switch len(conds) {
+	case 0:
+		return ""
+	case 1:
+		return conds[0]
+	default:
+		return "(" + strings.Join(conds, sep) + ")"
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlite
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/state/query"
+)
+
+func TestIndexedFilter(t *testing.T) {
+	columns := map[string]string{
+		"color":        "dapr_idx_color",
+		"nested.value": "dapr_idx_nested_value",
+	}
+
+	tests := []struct {
+		name       string
+		filter     string
+		expectCond string
+		expectArgs []any
+	}{
+		{
+			name:       "EQ string",
+			filter:     `{"EQ": {"color": "red"}}`,
+			expectCond: "dapr_idx_color = ?",
+			expectArgs: []any{"red"},
+		},
+		{
+			name:       "EQ number",
+			filter:     `{"EQ": {"nested.value": 42}}`,
+			expectCond: "dapr_idx_nested_value = ?",
+			expectArgs: []any{float64(42)},
+		},
+		{
+			name:       "EQ bool",
+			filter:     `{"EQ": {"color": true}}`,
+			expectCond: "dapr_idx_color = ?",
+			expectArgs: []any{int64(1)},
+		},
+		{
+			name:   "EQ number not represented exactly",
+			filter: `{"EQ": {"nested.value": 9007199254740993}}`,
+		},
+		{
+			name:   "EQ null",
+			filter: `{"EQ": {"color": null}}`,
+		},
+		{
+			name:   "EQ not indexed",
+			filter: `{"EQ": {"size": "large"}}`,
+		},
+		{
+			name:   "NEQ",
+			filter: `{"NEQ": {"color": "red"}}`,
+		},
+		{
+			name:       "IN",
+			filter:     `{"IN": {"color": ["red", "blue"]}}`,
+			expectCond: "dapr_idx_color IN (?, ?)",
+			expectArgs: []any{"red", "blue"},
+		},
+		{
+			name:   "IN with null",
+			filter: `{"IN": {"color": ["red", null]}}`,
+		},
+		{
+			name:       "range",
+			filter:     `{"AND": [{"GT": {"nested.value": 1}}, {"LTE": {"nested.value": 10}}]}`,
+			expectCond: "(dapr_idx_nested_value > ? AND dapr_idx_nested_value <= ?)",
+			expectArgs: []any{float64(1), float64(10)},
+		},
+		{
+			name:   "range on bool",
+			filter: `{"GTE": {"color": false}}`,
+		},
+		{
+			name:       "AND with field not indexed",
+			filter:     `{"AND": [{"EQ": {"size": "large"}}, {"LT": {"color": "m"}}]}`,
+			expectCond: "dapr_idx_color < ?",
+			expectArgs: []any{"m"},
+		},
+		{
+			name:       "OR",
+			filter:     `{"OR": [{"EQ": {"color": "red"}}, {"GTE": {"nested.value": 5}}]}`,
+			expectCond: "(dapr_idx_color = ? OR dapr_idx_nested_value >= ?)",
+			expectArgs: []any{"red", float64(5)},
+		},
+		{
+			name:   "OR with field not indexed",
+			filter: `{"OR": [{"EQ": {"color": "red"}}, {"EQ": {"size": "large"}}]}`,
+		},
+		{
+			name:       "nested AND and OR",
+			filter:     `{"AND": [{"EQ": {"size": "large"}}, {"OR": [{"EQ": {"color": "red"}}, {"IN": {"color": ["blue"]}}]}]}`,
+			expectCond: "(dapr_idx_color = ? OR dapr_idx_color IN (?))",
+			expectArgs: []any{"red", "blue"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj any
+			require.NoError(t, json.Unmarshal([]byte(tt.filter), &obj))
+			filter, err := query.ParseFilter(obj)
+			require.NoError(t, err)
+
+			cond, args := indexedFilter(filter, columns)
+			assert.Equal(t, tt.expectCond, cond)
+			assert.Equal(t, tt.expectArgs, args)
+		})
+	}
+}
@@ -358,6 +358,14 @@ func (m *fakeDBaccess) KeysLike(ctx context.Context, req *state.KeysLikeRequest)
 	return nil, nil
 }
 
+func (m *fakeDBaccess) ListKeys(ctx context.Context, req *state.ListKeysRequest) (*state.ListKeysResponse, error) {
+	return nil, nil
+}
+
+func (m *fakeDBaccess) Query(ctx context.Context, req *state.QueryRequest) (*state.QueryResponse, error) {
+	return nil, nil
+}
+
 func (m *fakeDBaccess) Close() error {
 	return nil
 }
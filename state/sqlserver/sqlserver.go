@@ -70,6 +70,7 @@ func New(logger logger.Logger) state.Store {
 			state.FeatureETag,
 			state.FeatureTransactional,
 			state.FeatureTTL,
+			state.FeatureTransactionConditions,
 		},
 		logger:          logger,
 		migratorFactory: newMigration,
@@ -96,6 +97,7 @@ type SQLServer struct {
 	itemRefTableTypeName     string
 	upsertCommand            string
 	getCommand               string
+	getETagCommand           string
 	deleteWithETagCommand    string
 	deleteWithoutETagCommand string
 
@@ -122,6 +124,7 @@ func (s *SQLServer) Init(ctx context.Context, metadata state.Metadata) error {
 	s.itemRefTableTypeName = mr.itemRefTableTypeName
 	s.upsertCommand = mr.upsertProcFullName
 	s.getCommand = mr.getCommand
+	s.getETagCommand = mr.getETagCommand
 	s.deleteWithETagCommand = mr.deleteWithETagCommand
 	s.deleteWithoutETagCommand = mr.deleteWithoutETagCommand
 
@@ -206,11 +209,33 @@ func (s *SQLServer) execMultiOperation(ctx context.Context, op state.Transaction
 		return s.executeSet(ctx, db, &req)
 	case state.DeleteRequest:
 		return s.executeDelete(ctx, db, &req)
+	case state.ConditionRequest:
+		return s.executeCheckCondition(ctx, db, &req)
 	default:
 		return fmt.Errorf("unsupported operation: %s", op.Operation())
 	}
 }
 
+// executeCheckCondition returns an error if the condition is not met.
+func (s *SQLServer) executeCheckCondition(ctx context.Context, db dbExecutor, req *state.ConditionRequest) error {
+	err := req.Validate()
+	if err != nil {
+		return err
+	}
+
+	// The query uses UPDLOCK and HOLDLOCK to lock the row (or the range, if the row doesn't exist) until the end of the transaction
+	var rowVersion []byte
+	err = db.QueryRowContext(ctx, s.getETagCommand, sql.Named(keyColumnName, req.Key)).Scan(&rowVersion)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return req.Check(false, nil)
+	case err != nil:
+		return err
+	default:
+		return req.Check(true, ptr.Of(hex.EncodeToString(rowVersion)))
+	}
+}
+
 // Delete removes an entity from the store.
 func (s *SQLServer) Delete(ctx context.Context, req *state.DeleteRequest) error {
 	return s.executeDelete(ctx, s.db, req)
@@ -302,6 +327,7 @@ func (s *SQLServer) Set(ctx context.Context, req *state.SetRequest) error {
 // dbExecutor implements a common functionality implemented by db or tx.
 type dbExecutor interface {
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
 func (s *SQLServer) executeSet(ctx context.Context, db dbExecutor, req *state.SetRequest) error {
@@ -482,6 +482,40 @@ func testMultiOperations(t *testing.T) {
 
 				assertUserCountIsEqualTo(t, store, totalUsers)
 			})
+
+			t.Run("Condition not met, should abort update", func(t *testing.T) {
+				toCheck := loadedUsers[userIndex]
+				toModify := loadedUsers[userIndex+1]
+				modified := toModify.user
+				modified.FavoriteBeverage = beverageTea
+
+				err = store.Multi(t.Context(), &state.TransactionalStateRequest{
+					Operations: []state.TransactionalStateOperation{
+						state.ConditionRequest{Key: toCheck.ID, Condition: state.ConditionNotExists},
+						state.SetRequest{Key: modified.ID, Value: modified},
+					},
+				})
+
+				require.ErrorIs(t, err, state.ErrConditionNotMet)
+				assertLoadedUserIsEqual(t, store, toModify.ID, toModify.user)
+			})
+
+			t.Run("Condition met, should update", func(t *testing.T) {
+				toCheck := loadedUsers[userIndex]
+				toModify := loadedUsers[userIndex+1]
+				modified := toModify.user
+				modified.FavoriteBeverage = beverageTea
+
+				err = store.Multi(t.Context(), &state.TransactionalStateRequest{
+					Operations: []state.TransactionalStateOperation{
+						state.ConditionRequest{Key: toCheck.ID, Condition: state.ConditionETagMatch, ETag: &toCheck.etag},
+						state.SetRequest{Key: modified.ID, Value: modified},
+					},
+				})
+
+				require.NoError(t, err)
+				assertLoadedUserIsEqual(t, store, toModify.ID, modified)
+			})
 		})
 	}
 }
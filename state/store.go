@@ -77,3 +77,34 @@ type DeleteWithPrefix interface {
 type KeysLiker interface {
 	KeysLike(ctx context.Context, req *KeysLikeRequest) (*KeysLikeResponse, error)
 }
+
+// KeyLister is an optional interface to enumerate state keys one page at a time.
+// Keys that exist for the whole iteration are always returned, even if other keys are written concurrently;
+// keys that are added or removed during the iteration may or may not be returned.
+type KeyLister interface {
+	ListKeys(ctx context.Context, req *ListKeysRequest) (*ListKeysResponse, error)
+}
+
+// Incrementer is an optional interface for state stores that can increment counters atomically.
+// Increment adds delta to the integer value stored at key, which is created with value 0 if it doesn't exist, and returns the new value.
+// Stores that implement this interface also accept IncrementRequest operations in transactions.
+type Incrementer interface {
+	Increment(ctx context.Context, key string, delta int64) (int64, error)
+}
+
+// StreamingStore is an optional interface for state stores that can read and
+// write values as streams, so callers don't need to hold large values in memory.
+type StreamingStore interface {
+	GetStream(ctx context.Context, req *GetRequest) (*GetStreamResponse, error)
+	SetStream(ctx context.Context, req *SetStreamRequest) error
+}
+
+// Watcher is an optional interface for state stores that can notify when keys change.
+// Watch returns once the watch has been established, then invokes the handler for every change until ctx is canceled.
+// Delivery is at-least-once: handlers may be invoked more than once for the same change.
+type Watcher interface {
+	Watch(ctx context.Context, req *WatchRequest, handler WatchHandler) error
+}
+
+// WatchHandler is the handler invoked when a watched key changes.
+type WatchHandler func(ctx context.Context, e *WatchEvent) error
@@ -72,3 +72,10 @@ func JSONStringify(value any) ([]byte, error) {
 		return bytes.TrimSuffix(buf.Bytes(), []byte{0xa}), err
 	}
 }
+
+// EscapeLikePattern escapes the wildcards in s so it can be used in a SQL LIKE pattern with '\' as escape character.
+func EscapeLikePattern(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
@@ -17,6 +17,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
 	"slices"
 	"sort"
@@ -37,36 +38,51 @@ import (
 )
 
 const (
-	defaultPubsubName             = "pubusub"
-	defaultTopicName              = "testTopic"
-	defaultTopicNameBulk          = "testTopicBulk"
-	defaultMultiTopic1Name        = "multiTopic1"
-	defaultMultiTopic2Name        = "multiTopic2"
-	defaultMessageCount           = 10
-	defaultMaxReadDuration        = 60 * time.Second
-	defaultWaitDurationToPublish  = 5 * time.Second
-	defaultCheckInOrderProcessing = true
-	defaultMaxBulkCount           = 5
-	defaultMaxBulkAwaitDurationMs = 500
-	bulkSubStartingKey            = 1000
-	defaultProjectID              = "conformance-test-prj"
+	defaultPubsubName                = "pubusub"
+	defaultTopicName                 = "testTopic"
+	defaultTopicNameBulk             = "testTopicBulk"
+	defaultMultiTopic1Name           = "multiTopic1"
+	defaultMultiTopic2Name           = "multiTopic2"
+	defaultDelayedTopicName          = "delayedTopic"
+	defaultDeliveryDelay             = 5 * time.Second
+	defaultOrderedTopicName          = "orderedTopic"
+	defaultOrderingKeyCount          = 3
+	defaultDeadLetterSourceTopicName = "deadLetterSourceTopic"
+	defaultDeadLetterTopicName       = "deadLetterTopic"
+	defaultDeadLetterMessageCount    = 3
+	defaultReplayTopicName           = "replayTopic"
+	defaultReplayMessageCount        = 3
+	defaultMessageCount              = 10
+	defaultMaxReadDuration           = 60 * time.Second
+	defaultWaitDurationToPublish     = 5 * time.Second
+	defaultCheckInOrderProcessing    = true
+	defaultMaxBulkCount              = 5
+	defaultMaxBulkAwaitDurationMs    = 500
+	bulkSubStartingKey               = 1000
+	defaultProjectID                 = "conformance-test-prj"
 )
 
 type TestConfig struct {
 	utils.CommonConfig
-	PubsubName             string            `mapstructure:"pubsubName"`
-	TestTopicName          string            `mapstructure:"testTopicName"`
-	TestTopicForBulkSub    string            `mapstructure:"testTopicForBulkSub"`
-	TestMultiTopic1Name    string            `mapstructure:"testMultiTopic1Name"`
-	TestMultiTopic2Name    string            `mapstructure:"testMultiTopic2Name"`
-	PublishMetadata        map[string]string `mapstructure:"publishMetadata"`
-	SubscribeMetadata      map[string]string `mapstructure:"subscribeMetadata"`
-	BulkSubscribeMetadata  map[string]string `mapstructure:"bulkSubscribeMetadata"`
-	MessageCount           int               `mapstructure:"messageCount"`
-	MaxReadDuration        time.Duration     `mapstructure:"maxReadDuration"`
-	WaitDurationToPublish  time.Duration     `mapstructure:"waitDurationToPublish"`
-	CheckInOrderProcessing bool              `mapstructure:"checkInOrderProcessing"`
-	TestProjectID          string            `mapstructure:"testProjectID"`
+	PubsubName                    string            `mapstructure:"pubsubName"`
+	TestTopicName                 string            `mapstructure:"testTopicName"`
+	TestTopicForBulkSub           string            `mapstructure:"testTopicForBulkSub"`
+	TestMultiTopic1Name           string            `mapstructure:"testMultiTopic1Name"`
+	TestMultiTopic2Name           string            `mapstructure:"testMultiTopic2Name"`
+	TestDelayedTopicName          string            `mapstructure:"testDelayedTopicName"`
+	DeliveryDelay                 time.Duration     `mapstructure:"deliveryDelay"`
+	TestOrderedTopicName          string            `mapstructure:"testOrderedTopicName"`
+	TestDeadLetterSourceTopicName string            `mapstructure:"testDeadLetterSourceTopicName"`
+	TestDeadLetterTopicName       string            `mapstructure:"testDeadLetterTopicName"`
+	TestReplayTopicName           string            `mapstructure:"testReplayTopicName"`
+	PublishMetadata               map[string]string `mapstructure:"publishMetadata"`
+	SubscribeMetadata             map[string]string `mapstructure:"subscribeMetadata"`
+	BulkSubscribeMetadata         map[string]string `mapstructure:"bulkSubscribeMetadata"`
+	MessageCount                  int               `mapstructure:"messageCount"`
+	MaxReadDuration               time.Duration     `mapstructure:"maxReadDuration"`
+	WaitDurationToPublish         time.Duration     `mapstructure:"waitDurationToPublish"`
+	CheckInOrderProcessing        bool              `mapstructure:"checkInOrderProcessing"`
+	TestProjectID                 string            `mapstructure:"testProjectID"`
 }
 
 func NewTestConfig(componentName string, operations []string, configMap map[string]interface{}) (TestConfig, error) {
@@ -77,19 +93,25 @@ func NewTestConfig(componentName string, operations []string, configMap map[stri
 			ComponentName: componentName,
 			Operations:    utils.NewStringSet(operations...),
 		},
-		PubsubName:             defaultPubsubName,
-		TestTopicName:          defaultTopicName,
-		TestMultiTopic1Name:    defaultMultiTopic1Name,
-		TestMultiTopic2Name:    defaultMultiTopic2Name,
-		MessageCount:           defaultMessageCount,
-		MaxReadDuration:        defaultMaxReadDuration,
-		WaitDurationToPublish:  defaultWaitDurationToPublish,
-		PublishMetadata:        map[string]string{},
-		SubscribeMetadata:      map[string]string{},
-		BulkSubscribeMetadata:  map[string]string{},
-		CheckInOrderProcessing: defaultCheckInOrderProcessing,
-		TestTopicForBulkSub:    defaultTopicNameBulk,
-		TestProjectID:          defaultProjectID,
+		PubsubName:                    defaultPubsubName,
+		TestTopicName:                 defaultTopicName,
+		TestMultiTopic1Name:           defaultMultiTopic1Name,
+		TestMultiTopic2Name:           defaultMultiTopic2Name,
+		TestDelayedTopicName:          defaultDelayedTopicName,
+		DeliveryDelay:                 defaultDeliveryDelay,
+		TestOrderedTopicName:          defaultOrderedTopicName,
+		TestDeadLetterSourceTopicName: defaultDeadLetterSourceTopicName,
+		TestDeadLetterTopicName:       defaultDeadLetterTopicName,
+		TestReplayTopicName:           defaultReplayTopicName,
+		MessageCount:                  defaultMessageCount,
+		MaxReadDuration:               defaultMaxReadDuration,
+		WaitDurationToPublish:         defaultWaitDurationToPublish,
+		PublishMetadata:               map[string]string{},
+		SubscribeMetadata:             map[string]string{},
+		BulkSubscribeMetadata:         map[string]string{},
+		CheckInOrderProcessing:        defaultCheckInOrderProcessing,
+		TestTopicForBulkSub:           defaultTopicNameBulk,
+		TestProjectID:                 defaultProjectID,
 	}
 
 	err := config.Decode(configMap, &tc)
@@ -564,6 +586,186 @@ func ConformanceTests(t *testing.T, props map[string]string, ps pubsub.PubSub, c
 			}
 		})
 	})
+
+	// Delayed delivery
+	if config.HasOperation("delayeddelivery") {
+		t.Run("delayed delivery", func(t *testing.T) {
+			require.Contains(t, ps.Features(), pubsub.FeatureDelayedDelivery, "component %s does not support delayed delivery", config.ComponentName)
+
+			receivedCh := make(chan string, 1)
+			subscribeCtx, subscribeCancel := context.WithCancel(t.Context())
+			defer subscribeCancel()
+			createMultiSubscriber(t, subscribeCtx, receivedCh, ps, config.TestDelayedTopicName, config.SubscribeMetadata, dataPrefix)
+			time.Sleep(config.WaitDurationToPublish)
+
+			md := maps.Clone(config.PublishMetadata)
+			if md == nil {
+				md = map[string]string{}
+			}
+			md[metadata.DeliverAfterMetadataKey] = config.DeliveryDelay.String()
+			data := dataPrefix + "delayed"
+			published := time.Now()
+			err := ps.Publish(ctx, &pubsub.PublishRequest{
+				Data:       []byte(data),
+				PubsubName: config.PubsubName,
+				Topic:      config.TestDelayedTopicName,
+				Metadata:   md,
+			})
+			require.NoError(t, err, "expected no error on publishing delayed data on topic %s", config.TestDelayedTopicName)
+
+			select {
+			case received := <-receivedCh:
+				assert.Equal(t, data, received)
+				// Brokers may schedule messages with a coarse resolution, so allow deliveries slightly before the delay elapsed
+				assert.GreaterOrEqual(t, time.Since(published), config.DeliveryDelay-time.Second, "message delivered before the delay elapsed")
+			case <-time.After(config.DeliveryDelay + config.MaxReadDuration):
+				assert.Fail(t, "timeout while waiting for the delayed message")
+			}
+		})
+	}
+
+	// Ordered delivery
+	if config.HasOperation("ordereddelivery") {
+		t.Run("ordered delivery", func(t *testing.T) {
+			require.Contains(t, ps.Features(), pubsub.FeatureOrderedDelivery, "component %s does not support ordered delivery", config.ComponentName)
+
+			receivedCh := make(chan string, config.MessageCount*defaultOrderingKeyCount)
+			subscribeCtx, subscribeCancel := context.WithCancel(t.Context())
+			defer subscribeCancel()
+			createMultiSubscriber(t, subscribeCtx, receivedCh, ps, config.TestOrderedTopicName, config.SubscribeMetadata, dataPrefix)
+			time.Sleep(config.WaitDurationToPublish)
+
+			// Messages are published with interleaved ordering keys; each one contains its key and its sequence number for that key
+			expected := make(map[string][]string, defaultOrderingKeyCount)
+			for k := range config.MessageCount * defaultOrderingKeyCount {
+				key := "key" + strconv.Itoa(k%defaultOrderingKeyCount)
+				data := fmt.Sprintf("%sordered-%s-%d", dataPrefix, key, k/defaultOrderingKeyCount)
+				md := maps.Clone(config.PublishMetadata)
+				if md == nil {
+					md = map[string]string{}
+				}
+				md[metadata.OrderingKeyMetadataKey] = key
+				err := ps.Publish(ctx, &pubsub.PublishRequest{
+					Data:       []byte(data),
+					PubsubName: config.PubsubName,
+					Topic:      config.TestOrderedTopicName,
+					Metadata:   md,
+				})
+				require.NoError(t, err, "expected no error on publishing data %s on topic %s", data, config.TestOrderedTopicName)
+				expected[key] = append(expected[key], data)
+			}
+
+			received := make(map[string][]string, defaultOrderingKeyCount)
+			timeout := time.After(config.MaxReadDuration)
+			for range config.MessageCount * defaultOrderingKeyCount {
+				select {
+				case data := <-receivedCh:
+					key := strings.SplitN(strings.TrimPrefix(data, dataPrefix+"ordered-"), "-", 2)[0]
+					received[key] = append(received[key], data)
+				case <-timeout:
+					require.Fail(t, "timeout while waiting for ordered messages", "received=%v expected=%v", received, expected)
+				}
+			}
+			for key, msgs := range expected {
+				assert.Equal(t, msgs, received[key], "messages with ordering key %s received out of order", key)
+			}
+		})
+	}
+
+	// Dead-letter topic
+	if config.HasOperation("deadletter") {
+		t.Run("dead letter", func(t *testing.T) {
+			require.Contains(t, ps.Features(), pubsub.FeatureDeadLetter, "component %s does not support dead-letter topics", config.ComponentName)
+
+			deadLetterCh := make(chan string, defaultDeadLetterMessageCount)
+			subscribeCtx, subscribeCancel := context.WithCancel(t.Context())
+			defer subscribeCancel()
+			createMultiSubscriber(t, subscribeCtx, deadLetterCh, ps, config.TestDeadLetterTopicName, config.SubscribeMetadata, dataPrefix)
+
+			// The application fails to process every message, so they are all sent to the dead-letter topic
+			md := maps.Clone(config.SubscribeMetadata)
+			if md == nil {
+				md = map[string]string{}
+			}
+			md[metadata.DeadLetterTopicMetadataKey] = config.TestDeadLetterTopicName
+			err := ps.Subscribe(subscribeCtx, pubsub.SubscribeRequest{
+				Topic:    config.TestDeadLetterSourceTopicName,
+				Metadata: md,
+			}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+				return errors.New("simulated failure")
+			})
+			require.NoError(t, err, "expected no error on subscribing to topic %s", config.TestDeadLetterSourceTopicName)
+			time.Sleep(config.WaitDurationToPublish)
+
+			expected := make([]string, 0, defaultDeadLetterMessageCount)
+			for i := range defaultDeadLetterMessageCount {
+				data := dataPrefix + "deadletter-" + strconv.Itoa(i)
+				err = ps.Publish(ctx, &pubsub.PublishRequest{
+					Data:       []byte(data),
+					PubsubName: config.PubsubName,
+					Topic:      config.TestDeadLetterSourceTopicName,
+					Metadata:   config.PublishMetadata,
+				})
+				require.NoError(t, err, "expected no error on publishing data %s on topic %s", data, config.TestDeadLetterSourceTopicName)
+				expected = append(expected, data)
+			}
+
+			received := make([]string, 0, defaultDeadLetterMessageCount)
+			timeout := time.After(config.MaxReadDuration)
+			for range defaultDeadLetterMessageCount {
+				select {
+				case data := <-deadLetterCh:
+					received = append(received, data)
+				case <-timeout:
+					require.Fail(t, "timeout while waiting for dead-lettered messages", "received=%v expected=%v", received, expected)
+				}
+			}
+			assert.ElementsMatch(t, expected, received)
+		})
+	}
+
+	// Verify that a subscription receives messages published before it was created
+	if config.HasOperation("replay") {
+		t.Run("replay", func(t *testing.T) {
+			require.Contains(t, ps.Features(), pubsub.FeatureReplay, "component %s does not support replay", config.ComponentName)
+
+			expected := make([]string, 0, defaultReplayMessageCount)
+			for i := range defaultReplayMessageCount {
+				data := dataPrefix + "replay-" + strconv.Itoa(i)
+				err := ps.Publish(ctx, &pubsub.PublishRequest{
+					Data:       []byte(data),
+					PubsubName: config.PubsubName,
+					Topic:      config.TestReplayTopicName,
+					Metadata:   config.PublishMetadata,
+				})
+				require.NoError(t, err, "expected no error on publishing data %s on topic %s", data, config.TestReplayTopicName)
+				expected = append(expected, data)
+			}
+
+			// The replay topic has no subscription yet, so the new subscription starts from the earliest message and receives the messages published before it
+			md := maps.Clone(config.SubscribeMetadata)
+			if md == nil {
+				md = map[string]string{}
+			}
+			md[metadata.StartFromMetadataKey] = string(metadata.StartFromEarliest)
+			replayCh := make(chan string, defaultReplayMessageCount)
+			subscribeCtx, subscribeCancel := context.WithCancel(t.Context())
+			defer subscribeCancel()
+			createMultiSubscriber(t, subscribeCtx, replayCh, ps, config.TestReplayTopicName, md, dataPrefix)
+
+			received := make([]string, 0, defaultReplayMessageCount)
+			timeout := time.After(config.MaxReadDuration)
+			for range defaultReplayMessageCount {
+				select {
+				case data := <-replayCh:
+					received = append(received, data)
+				case <-timeout:
+					require.Fail(t, "timeout while waiting for replayed messages", "received=%v expected=%v", received, expected)
+				}
+			}
+			assert.ElementsMatch(t, expected, received)
+		})
+	}
 }
 
 func receiveInBackground(t *testing.T, timeout time.Duration, received1Ch <-chan string, received2Ch <-chan string, sent1Ch <-chan string, sent2Ch <-chan string, allSentCh <-chan bool) <-chan struct{} {
@@ -33,10 +33,12 @@ import (
 	p_kafka "github.com/dapr/components-contrib/pubsub/kafka"
 	p_kubemq "github.com/dapr/components-contrib/pubsub/kubemq"
 	p_mqtt3 "github.com/dapr/components-contrib/pubsub/mqtt3"
+	p_postgresql "github.com/dapr/components-contrib/pubsub/postgresql"
 	p_pulsar "github.com/dapr/components-contrib/pubsub/pulsar"
 	p_rabbitmq "github.com/dapr/components-contrib/pubsub/rabbitmq"
 	p_redis "github.com/dapr/components-contrib/pubsub/redis"
 	p_solaceamqp "github.com/dapr/components-contrib/pubsub/solace/amqp"
+	p_sqlite "github.com/dapr/components-contrib/pubsub/sqlite"
 	conf_pubsub "github.com/dapr/components-contrib/tests/conformance/pubsub"
 )
 
@@ -85,12 +87,16 @@ func loadPubSub(name string) pubsub.PubSub {
 		return p_kafka.NewKafka(testLogger)
 	case "pulsar":
 		return p_pulsar.NewPulsar(testLogger)
+	case "postgresql.docker":
+		return p_postgresql.NewPostgreSQL(testLogger)
 	case "mqtt3":
 		return p_mqtt3.NewMQTTPubSub(testLogger)
 	case "rabbitmq":
 		return p_rabbitmq.NewRabbitMQ(testLogger)
 	case "in-memory":
 		return p_inmemory.New(testLogger)
+	case "sqlite":
+		return p_sqlite.NewSQLite(testLogger)
 	case "aws.snssqs.terraform":
 		return p_snssqs.NewSnsSqs(testLogger)
 	case "aws.snssqs.docker":
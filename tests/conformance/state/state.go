@@ -14,13 +14,17 @@ limitations under the License.
 package state
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -2006,6 +2010,592 @@ func ConformanceTests(t *testing.T, props map[string]string, statestore state.St
 			require.False(t, state.FeatureKeysLike.IsPresent(features))
 		})
 	}
+
+	if config.HasOperation("streaming") {
+		var store state.StreamingStore
+		t.Run("component implements StreamingStore interface", func(t *testing.T) {
+			var ok bool
+			store, ok = statestore.(state.StreamingStore)
+			require.True(t, ok)
+		})
+
+		t.Run("Streaming feature present", func(t *testing.T) {
+			features := statestore.Features()
+			require.True(t, state.FeatureStreaming.IsPresent(features))
+		})
+
+		require.False(t, t.Failed(), "Cannot continue if previous test failed")
+
+		streamKey := key + "-stream"
+		// Use a value larger than the block sizes used by the blob-backed stores, so values are split
+		streamValue := make([]byte, 5*1024*1024)
+		for i := range streamValue {
+			streamValue[i] = byte(i % 251)
+		}
+
+		readStream := func(t *testing.T, res *state.GetStreamResponse) []byte {
+			t.Helper()
+			require.NotNil(t, res)
+			require.NotNil(t, res.Data)
+			defer res.Data.Close()
+			data, err := io.ReadAll(res.Data)
+			require.NoError(t, err)
+			return data
+		}
+
+		t.Run("get stream of missing key", func(t *testing.T) {
+			res, err := store.GetStream(t.Context(), &state.GetRequest{Key: streamKey + "-missing"})
+			require.NoError(t, err)
+			require.NotNil(t, res)
+			assert.Nil(t, res.Data)
+		})
+
+		t.Run("set stream and get stream", func(t *testing.T) {
+			err := store.SetStream(t.Context(), &state.SetStreamRequest{
+				Key:   streamKey,
+				Value: bytes.NewReader(streamValue),
+			})
+			require.NoError(t, err)
+
+			res, err := store.GetStream(t.Context(), &state.GetRequest{Key: streamKey})
+			require.NoError(t, err)
+			assert.Equal(t, streamValue, readStream(t, res))
+			if config.HasOperation("etag") {
+				require.NotNil(t, res.ETag)
+				assert.NotEmpty(t, *res.ETag)
+			}
+		})
+
+		t.Run("set stream and get", func(t *testing.T) {
+			res, err := statestore.Get(t.Context(), &state.GetRequest{Key: streamKey})
+			require.NoError(t, err)
+			assert.Equal(t, streamValue, res.Data)
+		})
+
+		t.Run("set and get stream", func(t *testing.T) {
+			err := statestore.Set(t.Context(), &state.SetRequest{
+				Key:   streamKey,
+				Value: []byte("hello world"),
+			})
+			require.NoError(t, err)
+
+			res, err := store.GetStream(t.Context(), &state.GetRequest{Key: streamKey})
+			require.NoError(t, err)
+			assert.Equal(t, []byte("hello world"), readStream(t, res))
+		})
+
+		if config.HasOperation("etag") {
+			t.Run("set stream with etag", func(t *testing.T) {
+				res, err := store.GetStream(t.Context(), &state.GetRequest{Key: streamKey})
+				require.NoError(t, err)
+				readStream(t, res)
+				require.NotNil(t, res.ETag)
+
+				// Update with the right etag
+				err = store.SetStream(t.Context(), &state.SetStreamRequest{
+					Key:   streamKey,
+					Value: bytes.NewReader(streamValue),
+					ETag:  res.ETag,
+				})
+				require.NoError(t, err)
+
+				// Updating with the same etag again should fail
+				err = store.SetStream(t.Context(), &state.SetStreamRequest{
+					Key:   streamKey,
+					Value: strings.NewReader("should not be stored"),
+					ETag:  res.ETag,
+				})
+				require.Error(t, err)
+				var etagErr *state.ETagError
+				require.ErrorAs(t, err, &etagErr)
+				assert.Equal(t, state.ETagMismatch, etagErr.Kind())
+
+				res, err = store.GetStream(t.Context(), &state.GetRequest{Key: streamKey})
+				require.NoError(t, err)
+				assert.Equal(t, streamValue, readStream(t, res))
+			})
+		}
+
+		if config.HasOperation("first-write") {
+			t.Run("set stream with first-write", func(t *testing.T) {
+				err := store.SetStream(t.Context(), &state.SetStreamRequest{
+					Key:   streamKey,
+					Value: strings.NewReader("should not be stored"),
+					Options: state.SetStateOption{
+						Concurrency: state.FirstWrite,
+					},
+				})
+				require.Error(t, err)
+			})
+		}
+
+		t.Run("set stream without value should error", func(t *testing.T) {
+			err := store.SetStream(t.Context(), &state.SetStreamRequest{
+				Key: streamKey,
+			})
+			require.Error(t, err)
+		})
+
+		t.Run("delete streamed key", func(t *testing.T) {
+			err := statestore.Delete(t.Context(), &state.DeleteRequest{Key: streamKey})
+			require.NoError(t, err)
+
+			res, err := store.GetStream(t.Context(), &state.GetRequest{Key: streamKey})
+			require.NoError(t, err)
+			assert.Nil(t, res.Data)
+		})
+	} else {
+		t.Run("component does not implement StreamingStore interface", func(t *testing.T) {
+			_, ok := statestore.(state.StreamingStore)
+			require.False(t, ok)
+		})
+
+		t.Run("Streaming feature not present", func(t *testing.T) {
+			features := statestore.Features()
+			require.False(t, state.FeatureStreaming.IsPresent(features))
+		})
+	}
+
+	if config.HasOperation("watch") {
+		var watcher state.Watcher
+		t.Run("component implements Watcher interface", func(t *testing.T) {
+			var ok bool
+			watcher, ok = statestore.(state.Watcher)
+			require.True(t, ok)
+		})
+
+		t.Run("Watch feature present", func(t *testing.T) {
+			features := statestore.Features()
+			require.True(t, state.FeatureWatch.IsPresent(features))
+		})
+
+		require.False(t, t.Failed(), "Cannot continue if previous test failed")
+
+		watchPrefix := key + "-watch-"
+
+		// Watches deliver events at-least-once, so waitForEvent skips events until it finds the expected one
+		startWatch := func(t *testing.T, req *state.WatchRequest) func(t *testing.T, key string, op state.OperationType) *state.WatchEvent {
+			t.Helper()
+			ch := make(chan *state.WatchEvent, 100)
+			err := watcher.Watch(t.Context(), req, func(_ context.Context, e *state.WatchEvent) error {
+				ch <- e
+				return nil
+			})
+			require.NoError(t, err)
+
+			return func(t *testing.T, key string, op state.OperationType) *state.WatchEvent {
+				t.Helper()
+				timeout := time.After(20 * time.Second)
+				for {
+					select {
+					case e := <-ch:
+						require.True(t, req.Matches(e.Key), "received event for key %s that does not match the request", e.Key)
+						if e.Key == key && e.Operation == op {
+							return e
+						}
+					case <-timeout:
+						require.Failf(t, "timed out waiting for watch event", "key: %s, operation: %s", key, op)
+						return nil
+					}
+				}
+			}
+		}
+
+		t.Run("watch keys", func(t *testing.T) {
+			watchKey := watchPrefix + "key"
+			waitForEvent := startWatch(t, &state.WatchRequest{Keys: []string{watchKey}})
+
+			// Changes to other keys must not be delivered
+			err := statestore.Set(t.Context(), &state.SetRequest{Key: watchPrefix + "other", Value: []byte("other")})
+			require.NoError(t, err)
+
+			err = statestore.Set(t.Context(), &state.SetRequest{Key: watchKey, Value: []byte("value1")})
+			require.NoError(t, err)
+			e := waitForEvent(t, watchKey, state.OperationUpsert)
+			assert.Equal(t, []byte("value1"), e.Data)
+			if config.HasOperation("etag") {
+				require.NotNil(t, e.ETag)
+				assert.NotEmpty(t, *e.ETag)
+			}
+
+			err = statestore.Delete(t.Context(), &state.DeleteRequest{Key: watchKey})
+			require.NoError(t, err)
+			waitForEvent(t, watchKey, state.OperationDelete)
+		})
+
+		t.Run("watch prefix", func(t *testing.T) {
+			prefix := watchPrefix + "prefix-"
+			waitForEvent := startWatch(t, &state.WatchRequest{Prefix: prefix})
+
+			err := statestore.Set(t.Context(), &state.SetRequest{Key: watchPrefix + "other", Value: []byte("other")})
+			require.NoError(t, err)
+
+			for i := range 3 {
+				err = statestore.Set(t.Context(), &state.SetRequest{Key: prefix + strconv.Itoa(i), Value: []byte("value" + strconv.Itoa(i))})
+				require.NoError(t, err)
+			}
+			for i := range 3 {
+				e := waitForEvent(t, prefix+strconv.Itoa(i), state.OperationUpsert)
+				assert.Equal(t, []byte("value"+strconv.Itoa(i)), e.Data)
+			}
+
+			for i := range 3 {
+				err = statestore.Delete(t.Context(), &state.DeleteRequest{Key: prefix + strconv.Itoa(i)})
+				require.NoError(t, err)
+				waitForEvent(t, prefix+strconv.Itoa(i), state.OperationDelete)
+			}
+		})
+
+		t.Run("cleanup watched keys", func(t *testing.T) {
+			err := statestore.Delete(t.Context(), &state.DeleteRequest{Key: watchPrefix + "other"})
+			require.NoError(t, err)
+		})
+	} else {
+		t.Run("component does not implement Watcher interface", func(t *testing.T) {
+			_, ok := statestore.(state.Watcher)
+			require.False(t, ok)
+		})
+
+		t.Run("Watch feature not present", func(t *testing.T) {
+			features := statestore.Features()
+			require.False(t, state.FeatureWatch.IsPresent(features))
+		})
+	}
+
+	if config.HasOperation("listkeys") {
+		var lister state.KeyLister
+		t.Run("component implements KeyLister interface", func(t *testing.T) {
+			var ok bool
+			lister, ok = statestore.(state.KeyLister)
+			require.True(t, ok)
+		})
+
+		t.Run("ListKeys feature present", func(t *testing.T) {
+			features := statestore.Features()
+			require.True(t, state.FeatureListKeys.IsPresent(features))
+		})
+
+		require.False(t, t.Failed(), "Cannot continue if previous test failed")
+
+		listPrefix := key + "-list-"
+		stableKeys := make([]string, 30)
+		for i := range stableKeys {
+			stableKeys[i] = fmt.Sprintf("%sstable-%02d", listPrefix, i)
+		}
+
+		listAll := func(t *testing.T, pageSize uint32, afterPage func()) []string {
+			t.Helper()
+			var (
+				keys  []string
+				token *string
+			)
+			for {
+				res, err := lister.ListKeys(t.Context(), &state.ListKeysRequest{
+					Prefix:            listPrefix,
+					PageSize:          &pageSize,
+					ContinuationToken: token,
+				})
+				require.NoError(t, err)
+				keys = append(keys, res.Keys...)
+				if res.ContinuationToken == nil {
+					return keys
+				}
+				token = res.ContinuationToken
+				if afterPage != nil {
+					afterPage()
+				}
+			}
+		}
+
+		t.Run("list keys with prefix", func(t *testing.T) {
+			for _, k := range stableKeys {
+				err := statestore.Set(t.Context(), &state.SetRequest{Key: k, Value: []byte("v")})
+				require.NoError(t, err)
+			}
+			// Keys without the prefix must not be listed
+			err := statestore.Set(t.Context(), &state.SetRequest{Key: key + "-other-list", Value: []byte("v")})
+			require.NoError(t, err)
+
+			keys := listAll(t, 7, nil)
+			assert.ElementsMatch(t, stableKeys, slices.Compact(slices.Sorted(slices.Values(keys))))
+		})
+
+		t.Run("stable iteration under concurrent writes", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(t.Context())
+			writerDone := make(chan struct{})
+			go func() {
+				defer close(writerDone)
+				for i := 0; ctx.Err() == nil; i++ {
+					// Update the stable keys, and add and remove other keys with the same prefix
+					_ = statestore.Set(ctx, &state.SetRequest{Key: stableKeys[i%len(stableKeys)], Value: []byte(strconv.Itoa(i))})
+					volatileKey := fmt.Sprintf("%svolatile-%02d", listPrefix, i%20)
+					if i%2 == 0 {
+						_ = statestore.Set(ctx, &state.SetRequest{Key: volatileKey, Value: []byte("v")})
+					} else {
+						_ = statestore.Delete(ctx, &state.DeleteRequest{Key: volatileKey})
+					}
+				}
+			}()
+
+			keys := listAll(t, 4, func() {
+				// Give the writer a chance to make changes between pages
+				time.Sleep(20 * time.Millisecond)
+			})
+			cancel()
+			<-writerDone
+
+			// All keys that existed for the whole iteration must be listed
+			for _, k := range stableKeys {
+				assert.Contains(t, keys, k)
+			}
+			for _, k := range keys {
+				assert.True(t, strings.HasPrefix(k, listPrefix), "key %s does not have the prefix", k)
+			}
+		})
+
+		t.Run("cleanup listed keys", func(t *testing.T) {
+			keys := listAll(t, 100, nil)
+			keys = append(keys, key+"-other-list")
+			for _, k := range keys {
+				err := statestore.Delete(t.Context(), &state.DeleteRequest{Key: k})
+				require.NoError(t, err)
+			}
+		})
+	} else {
+		t.Run("component does not implement KeyLister interface", func(t *testing.T) {
+			_, ok := statestore.(state.KeyLister)
+			require.False(t, ok)
+		})
+
+		t.Run("ListKeys feature not present", func(t *testing.T) {
+			features := statestore.Features()
+			require.False(t, state.FeatureListKeys.IsPresent(features))
+		})
+	}
+
+	if config.HasOperation("increment") {
+		var incrementer state.Incrementer
+		t.Run("component implements Incrementer interface", func(t *testing.T) {
+			var ok bool
+			incrementer, ok = statestore.(state.Incrementer)
+			require.True(t, ok)
+		})
+
+		t.Run("AtomicIncrement feature present", func(t *testing.T) {
+			features := statestore.Features()
+			require.True(t, state.FeatureAtomicIncrement.IsPresent(features))
+		})
+
+		require.False(t, t.Failed(), "Cannot continue if previous test failed")
+
+		counterKey := key + "-counter"
+
+		assertCounter := func(t *testing.T, key string, expect int64) {
+			t.Helper()
+			res, err := statestore.Get(t.Context(), &state.GetRequest{Key: key})
+			require.NoError(t, err)
+			assert.Equal(t, strconv.FormatInt(expect, 10), string(res.Data))
+		}
+
+		t.Run("increment creates and updates the counter", func(t *testing.T) {
+			n, err := incrementer.Increment(t.Context(), counterKey, 5)
+			require.NoError(t, err)
+			assert.Equal(t, int64(5), n)
+
+			n, err = incrementer.Increment(t.Context(), counterKey, -7)
+			require.NoError(t, err)
+			assert.Equal(t, int64(-2), n)
+
+			assertCounter(t, counterKey, -2)
+		})
+
+		t.Run("increment a value set with Set", func(t *testing.T) {
+			err := statestore.Set(t.Context(), &state.SetRequest{Key: counterKey, Value: 40})
+			require.NoError(t, err)
+
+			n, err := incrementer.Increment(t.Context(), counterKey, 2)
+			require.NoError(t, err)
+			assert.Equal(t, int64(42), n)
+		})
+
+		t.Run("concurrent increments", func(t *testing.T) {
+			err := statestore.Delete(t.Context(), &state.DeleteRequest{Key: counterKey})
+			require.NoError(t, err)
+
+			const workers, increments = 10, 10
+			var wg sync.WaitGroup
+			errs := make(chan error, workers*increments)
+			for range workers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range increments {
+						_, err := incrementer.Increment(t.Context(), counterKey, 1)
+						if err != nil {
+							errs <- err
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				require.NoError(t, err)
+			}
+
+			assertCounter(t, counterKey, workers*increments)
+		})
+
+		t.Run("increment a value that is not an integer", func(t *testing.T) {
+			err := statestore.Set(t.Context(), &state.SetRequest{Key: counterKey + "-nan", Value: "not a number"})
+			require.NoError(t, err)
+
+			_, err = incrementer.Increment(t.Context(), counterKey+"-nan", 1)
+			require.Error(t, err)
+		})
+
+		if config.HasOperation("transaction") {
+			t.Run("increment in transaction", func(t *testing.T) {
+				err := statestore.(state.TransactionalStore).Multi(t.Context(), &state.TransactionalStateRequest{
+					Operations: []state.TransactionalStateOperation{
+						state.SetRequest{Key: counterKey, Value: 10},
+						state.IncrementRequest{Key: counterKey, Delta: 5},
+						state.IncrementRequest{Key: counterKey + "-tx", Delta: 3},
+					},
+				})
+				require.NoError(t, err)
+
+				assertCounter(t, counterKey, 15)
+				assertCounter(t, counterKey+"-tx", 3)
+			})
+		}
+
+		t.Run("cleanup counters", func(t *testing.T) {
+			for _, k := range []string{counterKey, counterKey + "-nan", counterKey + "-tx"} {
+				err := statestore.Delete(t.Context(), &state.DeleteRequest{Key: k})
+				require.NoError(t, err)
+			}
+		})
+	} else {
+		t.Run("component does not implement Incrementer interface", func(t *testing.T) {
+			_, ok := statestore.(state.Incrementer)
+			require.False(t, ok)
+		})
+
+		t.Run("AtomicIncrement feature not present", func(t *testing.T) {
+			features := statestore.Features()
+			require.False(t, state.FeatureAtomicIncrement.IsPresent(features))
+		})
+	}
+
+	if config.HasOperation("conditions") {
+		t.Run("TransactionConditions feature present", func(t *testing.T) {
+			features := statestore.Features()
+			require.True(t, state.FeatureTransactionConditions.IsPresent(features))
+		})
+
+		require.False(t, t.Failed(), "Cannot continue if previous test failed")
+
+		transactionStore, ok := statestore.(state.TransactionalStore)
+		require.True(t, ok, "conditions require the component to implement TransactionalStore")
+
+		condKey := key + "-cond"
+		targetKey := key + "-cond-target"
+
+		assertTarget := func(t *testing.T, expect any) {
+			t.Helper()
+			res, err := statestore.Get(t.Context(), &state.GetRequest{Key: targetKey})
+			require.NoError(t, err)
+			if expect == nil {
+				assert.Nil(t, res.Data)
+				return
+			}
+			assertEquals(t, expect, res)
+		}
+
+		t.Run("not-exists condition met", func(t *testing.T) {
+			err := transactionStore.Multi(t.Context(), &state.TransactionalStateRequest{
+				Operations: []state.TransactionalStateOperation{
+					state.ConditionRequest{Key: condKey, Condition: state.ConditionNotExists},
+					state.SetRequest{Key: condKey, Value: "cond"},
+					state.SetRequest{Key: targetKey, Value: "v1"},
+				},
+			})
+			require.NoError(t, err)
+			assertTarget(t, "v1")
+		})
+
+		t.Run("not-exists condition not met", func(t *testing.T) {
+			err := transactionStore.Multi(t.Context(), &state.TransactionalStateRequest{
+				Operations: []state.TransactionalStateOperation{
+					state.ConditionRequest{Key: condKey, Condition: state.ConditionNotExists},
+					state.SetRequest{Key: targetKey, Value: "v2"},
+				},
+			})
+			require.ErrorIs(t, err, state.ErrConditionNotMet)
+			assertTarget(t, "v1")
+		})
+
+		t.Run("exists condition met", func(t *testing.T) {
+			err := transactionStore.Multi(t.Context(), &state.TransactionalStateRequest{
+				Operations: []state.TransactionalStateOperation{
+					state.ConditionRequest{Key: condKey, Condition: state.ConditionExists},
+					state.SetRequest{Key: targetKey, Value: "v3"},
+				},
+			})
+			require.NoError(t, err)
+			assertTarget(t, "v3")
+		})
+
+		t.Run("exists condition not met", func(t *testing.T) {
+			err := transactionStore.Multi(t.Context(), &state.TransactionalStateRequest{
+				Operations: []state.TransactionalStateOperation{
+					state.ConditionRequest{Key: condKey + "-missing", Condition: state.ConditionExists},
+					state.DeleteRequest{Key: targetKey},
+				},
+			})
+			require.ErrorIs(t, err, state.ErrConditionNotMet)
+			assertTarget(t, "v3")
+		})
+
+		if config.HasOperation("etag") {
+			t.Run("etag-match condition", func(t *testing.T) {
+				res, err := statestore.Get(t.Context(), &state.GetRequest{Key: condKey})
+				require.NoError(t, err)
+				require.NotNil(t, res.ETag)
+
+				err = transactionStore.Multi(t.Context(), &state.TransactionalStateRequest{
+					Operations: []state.TransactionalStateOperation{
+						state.ConditionRequest{Key: condKey, Condition: state.ConditionETagMatch, ETag: &config.BadEtag},
+						state.SetRequest{Key: targetKey, Value: "v4"},
+					},
+				})
+				require.ErrorIs(t, err, state.ErrConditionNotMet)
+				assertTarget(t, "v3")
+
+				err = transactionStore.Multi(t.Context(), &state.TransactionalStateRequest{
+					Operations: []state.TransactionalStateOperation{
+						state.ConditionRequest{Key: condKey, Condition: state.ConditionETagMatch, ETag: res.ETag},
+						state.SetRequest{Key: targetKey, Value: "v4"},
+					},
+				})
+				require.NoError(t, err)
+				assertTarget(t, "v4")
+			})
+		}
+
+		t.Run("cleanup conditions", func(t *testing.T) {
+			for _, k := range []string{condKey, targetKey} {
+				err := statestore.Delete(t.Context(), &state.DeleteRequest{Key: k})
+				require.NoError(t, err)
+			}
+		})
+	} else {
+		t.Run("TransactionConditions feature not present", func(t *testing.T) {
+			features := statestore.Features()
+			require.False(t, state.FeatureTransactionConditions.IsPresent(features))
+		})
+	}
 }
 
 func assertEquals(t *testing.T, value any, res *state.GetResponse) {
@@ -130,7 +130,7 @@ func loadStateStore(name string) state.Store {
 	case "cockroachdb.v2":
 		// v2 of the component is an alias for the PostgreSQL state store
 		// We still have a conformance test to validate that the component works with CockroachDB
-		return s_postgresql_v2.NewPostgreSQLStateStoreWithOptions(testLogger, s_postgresql_v2.Options{NoAzureAD: true, NoAWSIAM: true})
+		return s_postgresql_v2.NewCockroachDBStateStore(testLogger)
 	case "memcached":
 		return s_memcached.NewMemCacheStateStore(testLogger)
 	case "rethinkdb":